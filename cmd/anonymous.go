@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ratnesh-maurya/flo/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// flagAnonymous is --anonymous; see config.Config.Anonymous.
+var flagAnonymous bool
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&flagAnonymous, "anonymous", false,
+		"read public questions through the Stack Exchange API without logging in anywhere; gists, sync and shared cache writes are off")
+}
+
+// applyAnonymous makes anonymous mode read through the Stack Exchange
+// API, whose key-based quota needs no login, and keeps the shared
+// cache token to itself.
+func applyAnonymous(cmd *cobra.Command) error {
+	if cmd.Flags().Changed("anonymous") {
+		cfg.Anonymous = flagAnonymous
+	}
+	if !cfg.Anonymous {
+		return nil
+	}
+	if cmd.Flags().Changed("backend") && cfg.Backend != config.BackendAPI {
+		return withExitCode(exitUsage, errors.New("--anonymous reads through the Stack Exchange API; drop --backend"))
+	}
+	cfg.Backend = config.BackendAPI
+	cfg.Cache.Token = ""
+	return nil
+}
+
+// requireAccount fails in anonymous mode, which never acts under the
+// user's accounts; what names the feature that would have.
+func requireAccount(what string) error {
+	if !cfg.Anonymous {
+		return nil
+	}
+	return withExitCode(exitAuthRequired, fmt.Errorf("%s is off in anonymous mode", what))
+}
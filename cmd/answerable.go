@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+	"github.com/ratnesh-maurya/flo/pkg/subscriptions"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	answerableTags      []string
+	answerableMinViews  int
+	answerableNoAnswers bool
+	answerableLimit     int
+)
+
+// defaultAnswerableLimit is how many questions flo answerable lists
+// without --limit.
+const defaultAnswerableLimit = 20
+
+var answerableCmd = &cobra.Command{
+	Use:   "answerable",
+	Short: "List the unanswered questions in your tags most worth answering",
+	Long: `For those who answer rather than ask: list the open questions in your
+tags that have no upvoted or accepted answer yet, the most answerable
+first.  New questions rank higher, and so do questions many have viewed
+and questions others voted up; negatively scored ones sink.
+
+  flo answerable                          your subscribed tags (see flo tags)
+  flo answerable --tag go --tag rust      these tags instead
+  flo answerable --tag go --min-views 100 --no-answers
+
+--no-answers keeps to questions nobody has answered at all.`,
+	Args: cobra.NoArgs,
+	RunE: runAnswerable,
+}
+
+func init() {
+	answerableCmd.Flags().StringSliceVar(&answerableTags, "tag", nil, "tags to look in, repeated or comma-separated (default: your subscribed tags)")
+	answerableCmd.Flags().IntVar(&answerableMinViews, "min-views", 0, "leave out questions viewed fewer times")
+	answerableCmd.Flags().BoolVar(&answerableNoAnswers, "no-answers", false, "only questions with no answer at all")
+	answerableCmd.Flags().IntVarP(&answerableLimit, "limit", "n", defaultAnswerableLimit, "most questions listed")
+	_ = answerableCmd.RegisterFlagCompletionFunc("tag", completeTags)
+	rootCmd.AddCommand(answerableCmd)
+}
+
+// runAnswerable implements `flo answerable`.
+func runAnswerable(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	tags, err := tagsOrSubscribed(answerableTags)
+	if err != nil {
+		printError("Answerable failed", err.Error())
+		return err
+	}
+	if len(tags) == 0 {
+		return fmt.Errorf("no tags to look in: run flo tags subscribe go, or pass --tag go")
+	}
+
+	p, release, err := connect(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	status(spinnerSty, "🔎 Looking for unanswered questions...", "finding answerable questions",
+		"tags", len(tags), "min_views", answerableMinViews, "no_answers", answerableNoAnswers)
+	all := &mcp.SOResponse{}
+	var lastErr error
+	for _, tag := range tags {
+		tctx, cancel := context.WithTimeout(ctx, cfg.Timeouts.SearchTimeout())
+		resp, err := provider.Unanswered(tctx, p, tag, answerableNoAnswers, 0)
+		cancel()
+		switch {
+		case err == nil:
+			all.Items = append(all.Items, resp.Items...)
+		case errors.Is(err, provider.ErrNotFound):
+		case ctx.Err() != nil:
+			return ctx.Err()
+		default:
+			slog.Warn("answerable tag failed", "tag", tag, "err", err)
+			lastErr = err
+		}
+	}
+	if len(all.Items) == 0 && lastErr != nil {
+		printError("Answerable failed", lastErr.Error())
+		return lastErr
+	}
+
+	now := time.Now()
+	found := mcp.Answerable(all, answerableMinViews, answerableLimit, now)
+	if len(found) == 0 {
+		fmt.Fprintln(chrome(), dimSty.Render("No unanswered questions match; try fewer --min-views, or more tags."))
+		return errNoResults
+	}
+	if ui.Current().Styled() {
+		for i := range found {
+			printAnswerable(i+1, &found[i], now)
+		}
+	} else {
+		printDoc(ui.Document{Markdown: formatAnswerable(found, now), Data: found})
+	}
+	if lastErr != nil {
+		fmt.Fprintln(chrome(), spinnerSty.Render("⚠ Some tags could not be searched; see the log."))
+	}
+	return nil
+}
+
+// tagsOrSubscribed returns the tags given with a --tag flag, or else the
+// subscribed ones (see digestSubscriptions).
+func tagsOrSubscribed(given []string) ([]string, error) {
+	var tags []string
+	if len(given) == 0 {
+		subs, err := digestSubscriptions()
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range subs {
+			tags = append(tags, s.Tag)
+		}
+		return tags, nil
+	}
+	for _, t := range given {
+		tag, err := subscriptions.NormalizeTag(t)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// printAnswerable prints the nth question found, asked how long before
+// now, with its link.
+func printAnswerable(n int, q *mcp.QuestionData, now time.Time) {
+	meta, link := answerableMeta(q, now)
+	fmt.Printf("%3d. %s\n", n, html.UnescapeString(q.Title))
+	fmt.Println(dimSty.Render("     " + meta))
+	fmt.Println(dimSty.Render("     " + link))
+}
+
+// formatAnswerable renders the questions found as Markdown, for the
+// formats other than ansi.
+func formatAnswerable(found []mcp.QuestionData, now time.Time) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# Answerable questions (%d)\n\n", len(found)))
+	for i := range found {
+		q := &found[i]
+		meta, link := answerableMeta(q, now)
+		b.WriteString(fmt.Sprintf("%d. **%s**  \n   %s  \n   %s\n\n", i+1, html.UnescapeString(q.Title), meta, link))
+	}
+	return b.String()
+}
+
+// answerableMeta returns the line describing q, asked how long before
+// now, and its link.
+func answerableMeta(q *mcp.QuestionData, now time.Time) (meta, link string) {
+	answers := fmt.Sprintf("%d answers", q.AnswerCount)
+	if q.AnswerCount == 1 {
+		answers = "1 answer"
+	}
+	meta = fmt.Sprintf("%+d · %d views · %s · %s ago", q.Score, q.ViewCount, answers,
+		shortAge(now.Sub(time.Unix(q.CreationDate, 0))))
+	if len(q.Tags) > 0 {
+		meta += " · [" + q.Tags[0] + "]"
+	}
+	link = q.Link
+	if link == "" {
+		link = postURL("q", q.QuestionID)
+	}
+	return meta, link
+}
+
+// shortAge renders d in its largest whole unit: "45m", "5h", "3d".
+func shortAge(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(max(d, 0)/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	}
+	return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+}
@@ -3,164 +3,363 @@ package cmd
 import (
 	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/manifoldco/promptui"
+	"github.com/ratnesh-maurya/flo/pkg/clipboard"
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
 	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
 	"github.com/ratnesh-maurya/flo/pkg/ui"
+	"github.com/ratnesh-maurya/flo/pkg/usage"
 	"github.com/spf13/cobra"
 )
 
-// maxAnswersToShow limits the number of answers in the selection list.
+// maxAnswersToShow is the default number of answers per page of the
+// selection list; --answers overrides it and [a] lists them all.
 const maxAnswersToShow = 5
 
+var (
+	// askVerbatim disables query normalization (--verbatim).
+	askVerbatim bool
+	// askAnswers is the answers listed per page (--answers).
+	askAnswers int
+	// askClip takes the query from the clipboard (--clip).
+	askClip bool
+	// askRaw prints the MCP server's replies instead of the results
+	// (--raw).
+	askRaw bool
+	// askVersion is the --version flag; versionTarget is its parsed
+	// form, nil when unset.
+	askVersion    string
+	versionTarget *mcp.VersionTarget
+)
+
 var askCmd = &cobra.Command{
 	Use:   `ask [query]`,
 	Short: "Search Stack Overflow for a question",
 	Long: `Search Stack Overflow directly from your terminal.
 
   One-shot:     flo ask "how to reverse a string in go"
+  Several:      flo ask -q "go reverse string" -q "golang rune slice reverse"
+  Clipboard:    flo ask --clip   (e.g. an error message copied from your IDE)
+  Version:      flo ask --version go1.22 "iterate over a map in order"
+  Raw reply:    flo ask --raw "reverse a string in go"   (see flo raw)
   Interactive:  flo ask   (or just: flo)`,
 	RunE: runAsk,
 }
 
 func init() {
+	askCmd.Flags().BoolVar(&noCache, "no-cache", false, "always query the server, bypassing the response cache")
+	askCmd.Flags().BoolVar(&askVerbatim, "verbatim", false, "send the query exactly as typed, without normalization")
+	askCmd.Flags().BoolVar(&flagNoQuestion, "no-question", false, "skip the question body and open the best answer directly")
+	askCmd.Flags().StringArrayVarP(&askQueries, "query", "q", nil, "search this query too; repeat to run several searches at once")
+	askCmd.Flags().BoolVar(&askClip, "clip", false, "use the clipboard (e.g. a copied error message) as the query")
+	askCmd.Flags().IntVar(&askAnswers, "answers", maxAnswersToShow, "number of answers to list per page (0 for all at once)")
+	askCmd.Flags().BoolVar(&askRaw, "raw", false, "print the MCP server's reply to the search, unparsed, instead of the results")
+	askCmd.Flags().StringVar(&askVersion, "version", "", "prefer posts for this language version and hide ones needing another (e.g. go1.22, python3.12)")
 	rootCmd.AddCommand(askCmd)
 }
 
 // ---------- styles ----------
 
+// Built from the active palette by buildStyles.
 var (
-	spinnerSty = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD700")).Bold(true)
-	successSty = lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00")).Bold(true)
-	promptSty  = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6600")).Bold(true)
-	dimSty     = lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+	spinnerSty lipgloss.Style
+	successSty lipgloss.Style
+	promptSty  lipgloss.Style
+	dimSty     lipgloss.Style
+	matchSty   lipgloss.Style
 )
 
 // ---------- entry point ----------
 
 // runAsk handles both one-shot (with args) and REPL (no args) modes.
-// It connects to the official Stack Overflow MCP server once and reuses
-// the connection across queries.
+// It connects to the configured backend once and reuses the connection
+// across queries.
 func runAsk(cmd *cobra.Command, args []string) error {
+	if askRaw {
+		queries := askQueries
+		if len(args) > 0 {
+			queries = append([]string{strings.Join(args, " ")}, queries...)
+		}
+		if len(queries) == 0 {
+			err := errors.New("--raw needs a query")
+			printError(i18n.T("No query"), i18n.T("Give the search to make, e.g. flo ask --raw \"reverse a string in go\"."))
+			return withExitCode(exitUsage, err)
+		}
+		return askRawSearch(cmd.Context(), queries)
+	}
+
 	// Banner
-	fmt.Println(lipgloss.NewStyle().Bold(true).
-		Foreground(lipgloss.Color("#FF6600")).
-		Render("⚡ flo — Stack Overflow in your terminal"))
-	fmt.Println()
+	fmt.Fprintln(chrome(), promptSty.Render(i18n.T("⚡ flo — Stack Overflow in your terminal")))
+	fmt.Fprintln(chrome())
 
-	// Connect to MCP server (reused across REPL iterations).
-	// The mcp-remote bridge communicates over stdin/stdout JSON-RPC.
-	// First run opens a browser for OAuth; subsequent runs reuse the token.
-	fmt.Println(spinnerSty.Render("⏳ Connecting to Stack Overflow MCP server..."))
-	fmt.Println(dimSty.Render("  (first run may open a browser for Stack Overflow login)"))
+	ctx := cmd.Context()
+	if askVersion != "" {
+		t, err := mcp.ParseVersionTarget(askVersion)
+		if err != nil {
+			printError(i18n.T("Invalid --version"), err.Error())
+			return err
+		}
+		versionTarget = t
+	}
+	if askClip {
+		query, err := clipboardQuery(ctx)
+		if err != nil {
+			return err
+		}
+		args = append(args, query)
+	}
 
-	connectCtx, connectCancel := context.WithTimeout(context.Background(), 3*time.Minute)
-	defer connectCancel()
+	if len(args) == 0 && len(askQueries) == 0 {
+		if err := requireInteractive("the interactive prompt"); err != nil {
+			printError(i18n.T("No query"), i18n.T("Give a query, e.g. flo --non-interactive ask \"reverse a string in go\"."))
+			return err
+		}
+		firstRunHint()
+	}
 
-	client, err := mcp.NewClient(connectCtx)
+	// Connect once; the connection is reused across REPL iterations.
+	p, release, err := connect(ctx, true)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			printError("Node.js not found",
-				"flo requires Node.js (npx).\n\n"+
-					"  macOS:   brew install node\n"+
-					"  Ubuntu:  sudo apt install nodejs npm\n"+
-					"  Windows: choco install nodejs")
-			return fmt.Errorf("npx not found")
-		}
-		printError("Connection failed", err.Error())
 		return err
 	}
-	defer client.Close()
+	defer release()
 
-	fmt.Println(successSty.Render("✅ Connected!"))
-	fmt.Println()
+	// Several queries at once: -q, plus the arguments if any.
+	if len(askQueries) > 0 {
+		queries := askQueries
+		if len(args) > 0 {
+			queries = append([]string{strings.Join(args, " ")}, queries...)
+		}
+		return multiSearch(ctx, p, queries)
+	}
 
 	// One-shot mode: query provided as arguments.
 	if len(args) > 0 {
 		query := strings.Join(args, " ")
-		return searchAndDisplay(client, query)
+		return searchAndDisplay(ctx, p, query)
 	}
 
-	// REPL mode: keep asking questions until the user quits.
-	return replLoop(client)
+	// REPL mode: keep asking questions until the user quits.  Idle-time
+	// pings detect a dead bridge before the next query needs it.
+	if k, ok := p.(keepaliver); ok {
+		k.StartKeepalive(cfg.MCP.KeepaliveInterval())
+	}
+	return replLoop(ctx, p)
+}
+
+// clipboardQuery reads the clipboard and reduces it to the line that
+// states the error, for --clip; normalization happens in the search.
+func clipboardQuery(ctx context.Context) (string, error) {
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	text, err := clipboard.Read(cctx)
+	if err != nil {
+		printError(i18n.T("Could not read the clipboard"), err.Error())
+		return "", err
+	}
+	query := mcp.ErrorLine(text)
+	if query == "" {
+		printError(i18n.T("Clipboard is empty"), i18n.T("Copy an error message or a question first."))
+		return "", errors.New("clipboard is empty")
+	}
+	fmt.Println(dimSty.Render(i18n.Tf("  📋 From clipboard: %s", query)))
+	return query, nil
 }
 
 // ---------- REPL ----------
 
 // replLoop reads questions from stdin in a loop and displays results
-// interactively.  The MCP connection is shared across iterations.
-func replLoop(client *mcp.Client) error {
-	reader := bufio.NewReader(os.Stdin)
+// interactively.  The backend connection is shared across iterations.
+// On a terminal, Tab completes commands, tags and alias names.
+func replLoop(ctx context.Context, p provider.Provider) error {
+	if path, stale := loadKnownTags(); stale {
+		go refreshTagList(ctx, path)
+	}
+	input := newReplInput(promptSty.Render(i18n.T("❓ Ask: ")))
+	defer input.Close()
 
 	for {
-		fmt.Print(promptSty.Render("❓ Ask: "))
-
-		line, err := reader.ReadString('\n')
+		query, err := input.ReadLine()
 		if err != nil {
 			break // EOF or read error
 		}
-		query := strings.TrimSpace(line)
 
 		if query == "" {
 			continue
 		}
-		if query == "quit" || query == "exit" || query == "q" {
+		if query == "quit" || query == "exit" || replKey(query) == actQuit {
 			break
 		}
+		if query == "/reset" {
+			lastSearch = nil
+			fmt.Println(dimSty.Render(i18n.T("  Context cleared — the next question starts fresh.")))
+			continue
+		}
 
-		_ = searchAndDisplay(client, query)
+		if a := replKey(query); a == actBack || a == actForward {
+			step := navBack
+			if a == actForward {
+				step = navForward
+			}
+			_ = browse(ctx, p, navigate(step), true)
+			fmt.Println()
+			continue
+		}
+
+		if refinement, ok := strings.CutPrefix(query, ">"); ok {
+			_ = refineLast(ctx, p, refinement)
+		} else {
+			_ = searchAndDisplay(ctx, p, query)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		fmt.Println()
 	}
 
-	fmt.Println(dimSty.Render("\n👋 Goodbye!"))
+	fmt.Println(dimSty.Render("\n" + i18n.T("👋 Goodbye!")))
 	return nil
 }
 
 // ---------- search + display ----------
 
 // searchAndDisplay is the core flow:
-//  1. Call so_search via MCP JSON-RPC to find relevant questions.
-//  2. Parse the structured JSON response.
-//  3. Pick the best question (prefer ones with embedded answers).
-//  4. If no embedded answers, fetch accepted answer via get_content.
-//  5. Render the question, then show interactive answer selection.
-func searchAndDisplay(client *mcp.Client, query string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+//  1. Search the backend for relevant questions.
+//  2. Pick the best question (prefer ones with embedded answers).
+//  3. If no embedded answers, fetch the accepted answer.
+//  4. Render the question, then show interactive answer selection.
+//
+// When nothing matches, alternative queries are offered instead.
+func searchAndDisplay(parent context.Context, p provider.Provider, query string) error {
+	return searchWith(parent, p, query, !askVerbatim)
+}
+
+// searchWith is searchAndDisplay with query normalization chosen by the
+// caller; suggested alternatives are already in final form.
+func searchWith(parent context.Context, p provider.Provider, query string, normalize bool) error {
+	ctx, cancel := context.WithTimeout(parent, cfg.Timeouts.SearchTimeout())
 	defer cancel()
 
-	fmt.Println(spinnerSty.Render(fmt.Sprintf("\n🔍 Searching for: %q\n", query)))
+	// Strip filler and error-message noise so natural questions and
+	// pasted stack traces match (see mcp.DefaultQueryPipeline).
+	searchQuery := translateQuery(ctx, query)
+	hintQuery := query
+	if searchQuery != query {
+		hintQuery += " " + searchQuery
+	}
+	if normalize {
+		searchQuery = mcp.NormalizeQuery(searchQuery)
+	}
+	status(spinnerSty, "\n"+i18n.Tf("🔍 Searching for: %q", searchQuery)+"\n", "searching",
+		"query", query, "normalized", searchQuery)
 
-	// MCP tool call: so_search
-	// JSON-RPC: {"jsonrpc":"2.0","id":N,"method":"tools/call",
-	//   "params":{"name":"so_search","arguments":{"query":"<text>"}}}
-	searchResult, err := client.CallTool(ctx, "so_search", map[string]any{"query": query})
+	hits := cacheHits()
+	resp, err := p.Search(ctx, searchQuery)
+	if err == nil || errors.Is(err, provider.ErrNotFound) {
+		recordUsage(usage.Event{Kind: usage.KindSearch, Query: query, Cached: cacheHits() > hits})
+	}
+	if errors.Is(err, provider.ErrNotFound) {
+		slog.Info("search returned no items", "query", searchQuery)
+		return suggestAlternatives(parent, p, searchQuery)
+	}
 	if err != nil {
-		printError("Search failed", err.Error())
+		if parent.Err() != nil {
+			return parent.Err()
+		}
+		slog.Error("search failed", "query", query, "err", err)
+		reportSearchError(ctx, err)
 		return err
 	}
+	slog.Debug("search results parsed", "query", query, "items", len(resp.Items))
+	warnDrift(resp)
 
-	searchText := mcp.ExtractText(searchResult)
-	if searchText == "" {
-		printError("No results", "No results found for your query.")
-		return nil
+	// Remember the results so the REPL can refine them ("> ...").
+	lastSearch = &searchContext{query: searchQuery, resp: resp}
+
+	// The view outlives this search's deadline: the user may browse it
+	// for a while and fetch more answers.
+	return showResults(parent, p, resp, detectTagHints(hintQuery))
+}
+
+// versionMatches returns the results of resp that mention a version
+// versionTarget has, sharing resp's items.
+func versionMatches(resp *mcp.SOResponse) *mcp.SOResponse {
+	matches := &mcp.SOResponse{}
+	for i := range resp.Items {
+		if c, _ := versionTarget.QuestionCompat(&resp.Items[i]); c == mcp.VersionMatch {
+			matches.Items = append(matches.Items, resp.Items[i])
+		}
 	}
+	return matches
+}
 
-	resp, parseErr := mcp.ParseResponse(searchText)
-	if parseErr != nil || resp == nil || len(resp.Items) == 0 {
-		printError("No results", "Could not parse search results.")
-		return nil
+// reportSearchError explains a failed search.  ctx is the search's own
+// context, so a deadline it hit is reported as a timeout.
+func reportSearchError(ctx context.Context, err error) {
+	if ctx.Err() == context.DeadlineExceeded {
+		printError(i18n.T("Search timed out"), i18n.Tf(
+			"No reply within %s. Try again, or raise timeouts.search in the config.",
+			cfg.Timeouts.SearchTimeout()))
+		return
+	}
+	if errors.Is(err, mcp.ErrUnauthorized) {
+		reportLoginExpired()
+		return
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.Is(err, mcp.ErrUnexpectedShape) || errors.As(err, &syntaxErr) {
+		printError(i18n.T("Could not read the reply"), err.Error()+
+			"\n\n"+i18n.T("The server may have changed its format. flo raw shows the reply as it came."))
+		return
+	}
+	printError(i18n.T("Search failed"), err.Error())
+}
+
+// driftWarned is set once the user has been told the server's replies
+// changed shape, which is said once a run.
+var driftWarned bool
+
+// warnDrift says, once a run, that resp was read from a reply not in
+// the shape flo expects, so what is shown may be incomplete.
+func warnDrift(resp *mcp.SOResponse) {
+	if resp.Drift == nil || driftWarned {
+		return
 	}
+	driftWarned = true
+	fmt.Println(spinnerSty.Render(i18n.Tf("  ⚠ The server's reply has changed shape (%s); showing what flo could read.", resp.Drift.Summary())))
+	fmt.Println(dimSty.Render(i18n.T("  flo raw --check lists what differs.")))
+}
 
-	tagHints := detectTagHints(query)
+// showResults picks the best question in resp, fetching its accepted
+// answer if needed, adds it to the session history and shows it.
+func showResults(parent context.Context, p provider.Provider, resp *mcp.SOResponse, tagHints []string) error {
+	var best *mcp.QuestionData
+	if versionTarget != nil {
+		if n := mcp.FilterByVersion(resp, versionTarget); n > 0 {
+			fmt.Println(dimSty.Render(i18n.Tf("  Hid %d result(s) for versions incompatible with %s.", n, versionTarget)))
+		}
+		tagHints = append(tagHints, versionTarget.Tag())
+		best = mcp.BestQuestionWithAnswers(versionMatches(resp), tagHints)
+	}
 
 	// Strategy 1: Find a question that already has embedded answers
 	// (so_search sometimes includes full answer bodies in the response).
-	best := mcp.BestQuestionWithAnswers(resp, tagHints)
+	if best == nil {
+		best = mcp.BestQuestionWithAnswers(resp, tagHints)
+	}
 
 	if best == nil {
 		// Strategy 2: Find the best question by score/tags,
@@ -168,149 +367,501 @@ func searchAndDisplay(client *mcp.Client, query string) error {
 		best = mcp.BestQuestion(resp, tagHints)
 		if best == nil {
 			// Strategy 3: Show a list of search results.
-			md := mcp.FormatSearchResults(resp, 10)
-			renderAndPrint(md)
-			return nil
+			e := &navEntry{results: resp}
+			history.push(e)
+			return browse(parent, p, e, false)
 		}
-		// Fetch the accepted answer via get_content "SO_A<id>".
+		// Fetch the accepted answer separately.
 		if best.AcceptedAnswerID > 0 {
-			fmt.Println(spinnerSty.Render("📖 Fetching accepted answer..."))
-			fetchAcceptedAnswer(ctx, client, best)
+			status(spinnerSty, i18n.T("📖 Fetching accepted answer..."), "fetching accepted answer",
+				"question_id", best.QuestionID, "answer_id", best.AcceptedAnswerID)
+			fetchAcceptedAnswer(parent, p, best)
 		}
 	}
 
-	// Display question header (title, meta, tags, body).
-	header := mcp.FormatQuestionHeader(best)
-	renderAndPrint(header)
+	best = resolveDuplicate(parent, p, best)
+	e := &navEntry{question: best, from: resp, tagHints: tagHints}
+	history.push(e)
+	return browse(parent, p, e, false)
+}
 
-	// Interactive answer selection with arrow-key navigation.
-	if len(best.Answers) > 0 {
-		return answerSelectionLoop(best.Answers)
-	}
+// fetchAcceptedAnswer fetches the accepted answer and appends it to the
+// question's Answers slice.  A failure leaves the question without it.
+func fetchAcceptedAnswer(parent context.Context, p provider.Provider, q *mcp.QuestionData) {
+	ctx, cancel := context.WithTimeout(parent, cfg.Timeouts.FetchTimeout())
+	defer cancel()
 
-	// No answers could be fetched.
-	if best.Link != "" {
-		fmt.Println(dimSty.Render(fmt.Sprintf("  View on Stack Overflow: %s\n", best.Link)))
+	ans, err := p.GetAnswer(ctx, q.AcceptedAnswerID)
+	if err != nil {
+		slog.Warn("fetch accepted answer failed", "answer_id", q.AcceptedAnswerID, "err", err)
+		if ctx.Err() == context.DeadlineExceeded {
+			fmt.Println(dimSty.Render(i18n.T("  Timed out fetching the accepted answer.")))
+		}
+		return
 	}
-	return nil
+	q.Answers = append(q.Answers, *ans)
 }
 
-// fetchAcceptedAnswer calls get_content for the accepted answer and
-// appends it to the question's Answers slice.
-// JSON-RPC: {"method":"tools/call","params":{"name":"get_content",
-//
-//	"arguments":{"query":"SO_A<id>"}}}
-func fetchAcceptedAnswer(ctx context.Context, client *mcp.Client, q *mcp.QuestionData) {
-	ansResult, err := client.CallTool(ctx, "get_content", map[string]any{
-		"query": fmt.Sprintf("SO_A%d", q.AcceptedAnswerID),
-	})
-	if err != nil {
+// fetchAllAnswers loads the answers the search did not embed, paging
+// through them where the backend allows, adding any not already in
+// q.Answers.  It does nothing when every answer is already present.
+func fetchAllAnswers(parent context.Context, p provider.Provider, q *mcp.QuestionData) {
+	if q.QuestionID == 0 || len(q.Answers) >= q.AnswerCount {
 		return
 	}
-	ansText := mcp.ExtractText(ansResult)
-	ansResp, err := mcp.ParseResponse(ansText)
-	if err != nil || ansResp == nil || len(ansResp.Items) == 0 {
+	ctx, cancel := context.WithTimeout(parent, cfg.Timeouts.FetchTimeout())
+	defer cancel()
+
+	status(spinnerSty, i18n.Tf("📖 Fetching all %d answers...", q.AnswerCount), "fetching all answers",
+		"question_id", q.QuestionID, "have", len(q.Answers), "total", q.AnswerCount)
+	answers, err := provider.AllAnswers(ctx, p, q.QuestionID)
+	if err != nil {
+		slog.Warn("fetch all answers failed", "question_id", q.QuestionID, "err", err)
+		if ctx.Err() == context.DeadlineExceeded {
+			fmt.Println(dimSty.Render(i18n.T("  Timed out fetching the remaining answers.")))
+		}
 		return
 	}
-	ans := mcp.AnswerFromItem(ansResp.Items[0])
-	q.Answers = append(q.Answers, ans)
+	have := make(map[int]bool, len(q.Answers))
+	for _, a := range q.Answers {
+		have[a.AnswerID] = true
+	}
+	for _, a := range answers {
+		if !have[a.AnswerID] {
+			q.Answers = append(q.Answers, a)
+			have[a.AnswerID] = true
+		}
+	}
+	if len(q.Answers) < q.AnswerCount && q.Link != "" {
+		fmt.Println(dimSty.Render(i18n.Tf("  %d answers could not be loaded; see %s", q.AnswerCount-len(q.Answers), q.Link)))
+	}
 }
 
-// ---------- interactive answer selection ----------
+// suggestAlternatives handles an empty result: it offers relaxed or
+// corrected versions of query in a picker and searches the chosen one.
+// Without a terminal the suggestions are just listed.
+func suggestAlternatives(parent context.Context, p provider.Provider, query string) error {
+	alts := mcp.Suggestions(query, tagOf)
+	if len(alts) == 0 {
+		printError(i18n.T("No results"), i18n.T("No results found for your query."))
+		return errNoResults
+	}
+	slog.Info("offering alternative queries", "query", query, "suggestions", alts)
 
-// answerSelectionLoop shows a promptui list of answers with arrow-key
-// navigation. The user selects an answer to view it, then can go back
-// to pick another or exit.
-func answerSelectionLoop(answers []mcp.AnswerData) error {
-	sorted := mcp.SortAnswers(answers)
-	if len(sorted) > maxAnswersToShow {
-		sorted = sorted[:maxAnswersToShow]
+	if !interactive() {
+		var b strings.Builder
+		b.WriteString(i18n.T("No results found. Did you mean:") + "\n")
+		for _, a := range alts {
+			fmt.Fprintf(&b, "\n  • %s", a)
+		}
+		printError(i18n.T("No results"), b.String())
+		return errNoResults
+	}
+
+	fmt.Println(dimSty.Render(i18n.T("  No results found.")))
+	sel := promptui.Select{
+		Label:     i18n.T("Did you mean") + " " + pickHint("search", "cancel"),
+		Items:     alts,
+		Size:      len(alts),
+		Templates: selectTemplates(),
+		HideHelp:  ui.Plain(),
+		Stdout:    bellSkipper{},
+		Stdin:     pickerStdin(),
+	}
+	idx, _, err := sel.Run()
+	if err != nil {
+		return errNoResults
 	}
+	return searchWith(parent, p, alts[idx], false)
+}
+
+// ---------- interactive answer selection ----------
+
+// answerSelectionLoop shows a promptui list of q's answers with arrow-key
+// navigation, a page of --answers at a time with entries to turn the
+// page. The user selects an answer to view it, then can go back to pick
+// another, list every answer at once, move through the session history,
+// or exit.  With open set, the best answer is shown first, before the
+// list.
+func answerSelectionLoop(ctx context.Context, p provider.Provider, e *navEntry, open bool) (navStep, error) {
+	q := e.question
+	// pageSize answers are listed at a time; 0 lists them all.
+	pageSize := max(askAnswers, 0)
+	page, cursor := 0, 0
+	// Without a terminal, print the best answer and stop.
+	open = open || !interactive()
+	hasRelated := len(relatedOf(ctx, p, e)) > 0
 
 	for {
-		// Build the selection items (one-line previews).
-		items := make([]string, len(sorted))
-		for i := range sorted {
-			items[i] = mcp.FormatAnswerPreview(&sorted[i], i)
+		sorted := mcp.SortAnswersForVersion(q.Answers, versionTarget)
+		total := max(q.AnswerCount, len(sorted))
+		start, end := 0, len(sorted)
+		if pageSize > 0 {
+			start = min(page*pageSize, len(sorted))
+			end = min(start+pageSize, len(sorted))
+		}
+		shown := sorted[start:end]
+
+		// Build the selection items (score badge, vote bar, one-line
+		// preview), plus entries to turn the page.  The vote bars are
+		// scaled to the top answer overall, so pages compare.
+		top := 0
+		for _, a := range sorted {
+			top = max(top, a.Score)
+		}
+		items := make([]string, len(shown), len(shown)+2)
+		for i := range shown {
+			a := &shown[i]
+			items[i] = fmt.Sprintf("%s %s #%d %s", ui.ScoreBadge(a.Score, a.IsAccepted), ui.VoteBar(a.Score, top), start+i+1, mcp.FormatAnswerSummary(a))
+			if versionTarget != nil {
+				if c, mention := versionTarget.Classify(a.BodyMarkdown); c == mcp.VersionMismatch {
+					items[i] += ui.PlainText(" ⚠ " + mention)
+				}
+			}
+		}
+		next, prev := -1, -1
+		if rest := total - end; rest > 0 {
+			next = len(items)
+			if pageSize > 0 {
+				items = append(items, i18n.Tf("   ⋯ Next page (%d more)", rest))
+			} else {
+				items = append(items, i18n.Tf("   ⋯ Show all answers (%d more)", rest))
+			}
+		}
+		if start > 0 {
+			prev = len(items)
+			items = append(items, i18n.T("   ⋯ Previous page"))
+		}
+		expand := -1
+		if e.expandQuestion != nil {
+			expand = len(items)
+			items = append(items, i18n.Tf("   ⋯ Expand question (%d more lines)", e.folded))
 		}
 
+		label := i18n.T("Select an answer") + " " + pickHint("view", "go back")
+		if start > 0 || next >= 0 {
+			label = i18n.Tf("Answers %d–%d of %d — select one", start+1, end, total) + " " + pickHint("view", "go back")
+		}
 		sel := promptui.Select{
-			Label: "Select an answer (↑↓ navigate, Enter to view, Ctrl+C to go back)",
-			Items: items,
-			Size:  len(items),
-			Templates: &promptui.SelectTemplates{
-				Label:    "{{ . }}",
-				Active:   "▸ {{ . | cyan }}",
-				Inactive: "  {{ . }}",
-				Selected: "▸ {{ . | green }}",
-			},
-		}
-
-		idx, _, err := sel.Run()
-		if err != nil {
-			// Ctrl+C or interrupt → exit answer loop
-			return nil
+			Label:     label,
+			Items:     items,
+			Size:      len(items),
+			Templates: selectTemplates(),
+			HideHelp:  ui.Plain(),
+			Stdout:    bellSkipper{},
+			Stdin:     pickerStdin(),
+		}
+
+		idx := 0
+		if open {
+			open = false
+		} else {
+			var err error
+			idx, _, err = sel.RunCursorAt(min(cursor, len(items)-1), 0)
+			if err != nil {
+				// Ctrl+C or interrupt → exit answer loop
+				return navDone, nil
+			}
 		}
+		switch idx {
+		case next:
+			// Answers past those loaded are fetched when paged to.
+			if pageSize == 0 || end+pageSize > len(q.Answers) {
+				fetchAllAnswers(ctx, p, q)
+			}
+			if pageSize > 0 && end < len(q.Answers) {
+				page++
+			}
+			// Leave the cursor on the entry, so Enter keeps paging.
+			cursor = idx
+			continue
+		case prev:
+			page--
+			cursor = 0
+			if page > 0 {
+				cursor = pageSize + 1
+			}
+			continue
+		case expand:
+			expandQuestion(e)
+			cursor = 0
+			continue
+		}
+		cursor = idx
+		idx += start
 
 		// Render the selected answer with glamour + lipgloss.
-		md := mcp.FormatSingleAnswer(&sorted[idx])
-		renderAndPrint(md)
+		printCautions(q, &sorted[idx])
+		md := mcp.FormatSingleAnswer(translateAnswer(ctx, withAuthor(ctx, p, q, &sorted[idx])))
+		rendered := printDoc(ui.Document{Markdown: ui.AnnotateCode(md, q.Tags), Data: &sorted[idx]})
+		code := newCodeNav(q, &sorted[idx])
+		recordViewed(answerDoc(q, &sorted[idx]))
+		recordUsage(answerEvent(q, &sorted[idx]))
+		if !interactive() {
+			return navDone, nil
+		}
 
 		// Post-answer navigation.
-		fmt.Println(dimSty.Render("  [Enter] back to answers  |  [n] new question  |  [q] quit"))
-		reader := bufio.NewReader(os.Stdin)
-		input, _ := reader.ReadString('\n')
-		input = strings.TrimSpace(strings.ToLower(input))
-
-		switch input {
-		case "n", "q":
-			return nil
-		default:
-			continue // back to answer list
+	nav:
+		for {
+			r, reload := pendingRefresh(q.QuestionID)
+			if reload {
+				fmt.Println(dimSty.Render(refreshNotice(r)))
+			}
+			fmt.Println(dimSty.Render(answerHints(e, hasRelated, reload)))
+			if h := code.hints(); h != "" {
+				fmt.Println(dimSty.Render(h))
+			}
+			reader := bufio.NewReader(os.Stdin)
+			input, _ := reader.ReadString('\n')
+			if term, ok := typedFind(input); ok {
+				findInText(rendered, term)
+				continue
+			}
+			if code.handle(ctx, input) {
+				continue
+			}
+
+			switch typedAction(input) {
+			case actSave:
+				saveBookmark(q, &sorted[idx])
+			case actGist:
+				publishGist(ctx, q, &sorted[idx])
+			case actQR:
+				showQR(q)
+			case actPlain:
+				printPlain(&sorted[idx])
+			case actExpand:
+				expandQuestion(e)
+			case actRelated:
+				if reloadQuestion(q) {
+					// New answers tend to have few votes; list them all.
+					pageSize, page, cursor = 0, 0, 0
+					fetchAllAnswers(ctx, p, q)
+					fmt.Println(dimSty.Render(i18n.T("  Reloaded.")))
+					break nav
+				}
+				if next := pickRelated(ctx, p, e); next != nil {
+					history.push(next)
+					return showEntry(ctx, p, next, false)
+				}
+			case actAll:
+				pageSize, page, cursor = 0, 0, idx
+				fetchAllAnswers(ctx, p, q)
+				break nav
+			case actBack:
+				return navBack, nil
+			case actForward:
+				return navForward, nil
+			case actNew, actQuit:
+				return navDone, nil
+			default:
+				break nav // back to answer list
+			}
 		}
 	}
 }
 
+// answerHints is the line of keys under an answer.  reload offers to
+// reload a question that changed, in place of the related questions.
+func answerHints(e *navEntry, hasRelated, reload bool) string {
+	hints := []hint{{actAnswers, "back to answers"}}
+	if e.expandQuestion != nil {
+		hints = append(hints, hint{actExpand, "expand question"})
+	}
+	hints = append(hints, hint{actAll, "all answers"}, hint{actBack, "back"}, hint{actForward, "forward"}, hint{actSave, "save"})
+	if !cfg.Anonymous {
+		hints = append(hints, hint{actGist, "gist"})
+	}
+	hints = append(hints, hint{actQR, "QR code"}, hint{actPlain, "plain text"})
+	switch {
+	case reload:
+		hints = append(hints, hint{actRelated, "reload"})
+	case hasRelated:
+		hints = append(hints, hint{actRelated, "related"})
+	}
+	return keyHints(append(hints, hint{actNew, "new question"}, hint{actQuit, "quit"})...) + "  |  " + findHint("find")
+}
+
 // ---------- helpers ----------
 
-// renderAndPrint renders markdown through glamour + lipgloss and prints.
-func renderAndPrint(md string) {
-	rendered, err := ui.RenderContent(md)
+// status prints a styled progress line for humans and records the same
+// event in the structured log, so automation can follow progress
+// without scraping the decorated terminal output.
+func status(sty lipgloss.Style, text, msg string, args ...any) {
+	fmt.Fprintln(chrome(), sty.Render(ui.PlainText(text)))
+	slog.Info(msg, args...)
+}
+
+// terminalOnly fails with exitUsage when --format writes for other
+// programs: the command what prints only for a person at a terminal, and
+// styled text where JSON was asked for would mislead whatever reads it.
+func terminalOnly(what string) error {
+	if ui.Current().Styled() {
+		return nil
+	}
+	return withExitCode(exitUsage, fmt.Errorf("%s prints for the terminal only; run it without --format", what))
+}
+
+// chrome is where flo prints what surrounds rendered output — the
+// banner, progress, related questions: stdout on a terminal, but stderr
+// when --format writes for other programs, so stdout holds only what
+// the renderer wrote.
+func chrome() io.Writer {
+	if ui.Current().Styled() {
+		return os.Stdout
+	}
+	return os.Stderr
+}
+
+// renderAndPrint renders markdown with the renderer --format selects
+// (glamour + lipgloss by default) and prints it.  On the terminal,
+// images are replaced by numbered placeholders and listed after the
+// text, with thumbnails where they can be drawn.  It returns the
+// rendered text.
+func renderAndPrint(md string) string {
+	return printDoc(ui.Document{Markdown: md})
+}
+
+// printDoc is renderAndPrint for a document carrying the data it shows,
+// for the JSON format.
+func printDoc(doc ui.Document) string {
+	rendered, _, _ := renderFolded(doc, 0)
+	return rendered
+}
+
+// minFolded is the fewest lines worth folding away.
+const minFolded = 10
+
+// renderFolded is renderAndPrint for text that may run long: when the
+// rendered text is more than keep lines, with at least minFolded past
+// them, it prints only the first keep and returns how many it held back
+// and a func printing them.  expand is nil when everything was printed;
+// keep <= 0 always prints everything, as do the formats for programs.
+func renderFolded(doc ui.Document, keep int) (rendered string, folded int, expand func()) {
+	if !ui.Current().Styled() {
+		rendered, err := ui.Render(doc)
+		if err != nil {
+			slog.Warn("render failed", "err", err)
+			rendered = doc.Markdown
+		}
+		fmt.Fprint(os.Stdout, rendered)
+		return rendered, 0, nil
+	}
+	md, imgs := ui.ExtractImages(doc.Markdown)
+	if len(imgs) > 0 {
+		md += ui.ImagesSection(imgs)
+	}
+	rendered, err := ui.Render(ui.Document{Markdown: md, Data: doc.Data})
 	if err != nil {
-		fmt.Fprint(os.Stdout, md)
-		return
+		rendered = md
+	}
+	thumbnails := func() {
+		if len(imgs) > 0 {
+			if protocol := imageProtocol(); protocol != "" {
+				showThumbnails(protocol, imgs)
+			}
+		}
+	}
+	if n := strings.Count(rendered, "\n"); keep > 0 && n-keep >= minFolded {
+		cut := 0
+		for range keep {
+			cut += strings.IndexByte(rendered[cut:], '\n') + 1
+		}
+		fmt.Fprint(os.Stdout, rendered[:cut])
+		tail := rendered[cut:]
+		return rendered, n - keep, func() {
+			fmt.Fprint(os.Stdout, tail)
+			thumbnails()
+		}
 	}
 	fmt.Fprint(os.Stdout, rendered)
+	thumbnails()
+	return rendered, 0, nil
+}
+
+// tagAliases maps query words to the Stack Overflow tag they imply.
+var tagAliases = map[string]string{
+	"go": "go", "golang": "go",
+	"python": "python", "py": "python",
+	"javascript": "javascript", "js": "javascript", "node": "node.js",
+	"typescript": "typescript", "ts": "typescript",
+	"java": "java",
+	"c++": "c++", "cpp": "c++",
+	"c#": "c#", "csharp": "c#",
+	"ruby": "ruby", "rust": "rust", "swift": "swift",
+	"kotlin": "kotlin", "php": "php",
+	"bash": "bash", "shell": "bash",
+	"sql": "sql", "mysql": "mysql", "postgres": "postgresql",
+	"react": "reactjs", "docker": "docker",
+	"kubernetes": "kubernetes", "k8s": "kubernetes",
+	"git": "git",
+}
+
+// siteTagAliases adds, for Stack Overflow in other languages, the words
+// its users write for a tag in their own language, inflected forms
+// included.
+var siteTagAliases = map[string]map[string]string{
+	"es.stackoverflow": {"pitón": "python"},
+	"pt.stackoverflow": {"píton": "python", "pitão": "python"},
+	"ru.stackoverflow": {
+		"питон": "python", "питоне": "python", "пайтон": "python", "пайтоне": "python",
+		"джава": "java", "джаве": "java", "джаваскрипт": "javascript",
+		"тайпскрипт": "typescript", "пхп": "php",
+		"шарп": "c#", "шарпе": "c#", "плюсы": "c++", "плюсах": "c++",
+		"голанг": "go", "голанге": "go", "руби": "ruby", "раст": "rust", "расте": "rust",
+		"котлин": "kotlin", "котлине": "kotlin", "свифт": "swift",
+		"баш": "bash", "гит": "git", "докер": "docker", "докере": "docker",
+		"реакт": "reactjs", "реакте": "reactjs", "постгрес": "postgresql",
+		"кубернетес": "kubernetes",
+	},
+	"ja.stackoverflow": {
+		"パイソン": "python", "ジャバスクリプト": "javascript", "ジャバ": "java",
+		"タイプスクリプト": "typescript", "ルビー": "ruby", "コトリン": "kotlin",
+		"スウィフト": "swift", "go言語": "go", "ゴー言語": "go", "c言語": "c",
+		"ギット": "git", "ドッカー": "docker", "リアクト": "reactjs",
+	},
+}
+
+// spaceless are the languages written without spaces between words:
+// on their sites a tag's word is looked for anywhere in the query.
+var spaceless = map[string]bool{"ja": true}
+
+// tagOf returns the tag implied by a lower-case query word, or "".
+func tagOf(word string) string {
+	return tagAliases[word]
 }
 
 // detectTagHints extracts likely programming-language tags from the
-// user's query to help rank search results.
+// user's query to help rank search results.  The words of the site's
+// language count too (see siteTagAliases).
 func detectTagHints(query string) []string {
-	langMap := map[string]string{
-		"go": "go", "golang": "go",
-		"python": "python", "py": "python",
-		"javascript": "javascript", "js": "javascript", "node": "node.js",
-		"typescript": "typescript", "ts": "typescript",
-		"java": "java",
-		"c++": "c++", "cpp": "c++",
-		"c#": "c#", "csharp": "c#",
-		"ruby": "ruby", "rust": "rust", "swift": "swift",
-		"kotlin": "kotlin", "php": "php",
-		"bash": "bash", "shell": "bash",
-		"sql": "sql", "mysql": "mysql", "postgres": "postgresql",
-		"react": "reactjs", "docker": "docker",
-		"kubernetes": "kubernetes", "k8s": "kubernetes",
-		"git": "git",
-	}
-
-	words := strings.Fields(strings.ToLower(query))
+	lower := strings.ToLower(query)
+	local := siteTagAliases[currentSite()]
 	seen := make(map[string]bool)
 	var hints []string
-	for _, w := range words {
-		if tag, ok := langMap[w]; ok && !seen[tag] {
+	add := func(tag string) {
+		if !seen[tag] {
 			hints = append(hints, tag)
 			seen[tag] = true
 		}
 	}
+	for _, w := range strings.Fields(lower) {
+		if tag, ok := tagAliases[w]; ok {
+			add(tag)
+		} else if tag, ok := local[w]; ok {
+			add(tag)
+		}
+	}
+	if s, ok := provider.LookupSite(currentSite()); ok && spaceless[s.Language] {
+		// Longest first, so ジャバスクリプト is not also read as ジャバ.
+		words := slices.SortedFunc(maps.Keys(local), func(a, b string) int { return len(b) - len(a) })
+		for _, w := range words {
+			if strings.Contains(lower, w) {
+				add(local[w])
+				lower = strings.ReplaceAll(lower, w, " ")
+			}
+		}
+	}
 	return hints
 }
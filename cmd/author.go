@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+	"github.com/ratnesh-maurya/flo/pkg/quota"
+)
+
+// authors holds the authors looked up this session, by user ID: nil for
+// one that could not be, so each is asked for once.
+var authors = map[int]*provider.Author{}
+
+// withAuthor returns a with its author's reputation and, when they hold
+// a gold badge in q's main tag, that tag, for the byline.  The author is
+// looked up the first time one of their answers is shown, as an extra
+// the API budget may skip.
+func withAuthor(ctx context.Context, p provider.Provider, q *mcp.QuestionData, a *mcp.AnswerData) *mcp.AnswerData {
+	id := a.Owner.ID()
+	if cfg.Display.NoAuthors || id == 0 {
+		return a
+	}
+	author, ok := authors[id]
+	if !ok {
+		fctx, cancel := context.WithTimeout(quota.Extra(ctx), cfg.Timeouts.FetchTimeout())
+		defer cancel()
+		var err error
+		author, err = provider.AuthorOf(fctx, p, id)
+		switch {
+		case err == nil:
+		case errors.Is(err, provider.ErrUnsupported), errors.Is(err, quota.ErrSkipped):
+			slog.Debug("author not looked up", "user_id", id, "err", err)
+		default:
+			slog.Warn("look up author failed", "user_id", id, "err", err)
+		}
+		authors[id] = author
+	}
+	if author == nil {
+		return a
+	}
+	out := *a
+	if author.Reputation > 0 {
+		out.Owner.Reputation = author.Reputation
+	}
+	if len(q.Tags) > 0 && slices.Contains(author.GoldTags, q.Tags[0]) {
+		out.Owner.GoldTags = []string{q.Tags[0]}
+	}
+	return &out
+}
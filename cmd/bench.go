@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/spf13/cobra"
+)
+
+var (
+	benchRuns  int
+	benchQuery string
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Measure MCP connection and tool-call latency",
+	Long: `Connect to the MCP server several times and report how long each stage
+takes (p50/p95), to tell "flo is slow" apart from "the network or OAuth
+is slow".
+
+Each run spawns a fresh bridge and measures:
+  spawn       starting the bridge subprocess (npx/mcp-remote)
+  handshake   the MCP initialize round trip (remote connect + auth)
+  ping        an MCP ping on the established connection
+  so_search   a search tool call
+  get_content a content fetch for the first search hit`,
+	Args: cobra.NoArgs,
+	RunE: runBench,
+}
+
+func init() {
+	benchCmd.Flags().IntVarP(&benchRuns, "runs", "n", 5, "number of runs")
+	benchCmd.Flags().StringVar(&benchQuery, "query", "how to reverse a string in go", "search query used for tool calls")
+	rootCmd.AddCommand(benchCmd)
+}
+
+// benchStages lists the measured stages in display order.
+var benchStages = []string{"spawn", "handshake", "ping", "so_search", "get_content"}
+
+// runBench implements `flo bench`.
+func runBench(cmd *cobra.Command, args []string) error {
+	if benchRuns < 1 {
+		return fmt.Errorf("--runs must be at least 1")
+	}
+	ctx := cmd.Context()
+	samples := make(map[string][]time.Duration)
+	failures := make(map[string]int)
+
+	for i := 1; i <= benchRuns; i++ {
+		status(spinnerSty, fmt.Sprintf("⏱  Run %d/%d...", i, benchRuns), "bench run", "run", i)
+		if err := benchOnce(ctx, samples, failures); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			printError(fmt.Sprintf("Run %d failed", i), err.Error())
+		}
+	}
+
+	fmt.Println()
+	fmt.Println(formatBenchTable(samples, failures))
+	return nil
+}
+
+// benchOnce performs one full connect → ping → search → fetch cycle and
+// appends each stage's latency to samples.
+func benchOnce(ctx context.Context, samples map[string][]time.Duration, failures map[string]int) error {
+	connectCtx, cancel := context.WithTimeout(ctx, cfg.Timeouts.ConnectTimeout())
+	defer cancel()
+
+	client, err := mcp.NewClient(connectCtx, mcpOptions())
+	if err != nil {
+		failures["handshake"]++
+		return err
+	}
+	defer client.Close()
+
+	t := client.Timings()
+	samples["spawn"] = append(samples["spawn"], t.Spawn)
+	samples["handshake"] = append(samples["handshake"], t.Handshake)
+
+	callCtx, cancelCall := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancelCall()
+
+	start := time.Now()
+	if err := client.Ping(callCtx); err != nil {
+		failures["ping"]++
+	} else {
+		samples["ping"] = append(samples["ping"], time.Since(start))
+	}
+
+	start = time.Now()
+	res, err := client.CallTool(callCtx, "so_search", map[string]any{"query": benchQuery})
+	if err != nil {
+		failures["so_search"]++
+		return err
+	}
+	samples["so_search"] = append(samples["so_search"], time.Since(start))
+
+	resp, err := mcp.ParseResponse(mcp.ExtractText(res))
+	if err != nil || len(resp.Items) == 0 || resp.Items[0].QuestionID == 0 {
+		failures["get_content"]++
+		return nil
+	}
+	start = time.Now()
+	_, err = client.CallTool(callCtx, "get_content", map[string]any{
+		"query": fmt.Sprintf("SO_Q%d", resp.Items[0].QuestionID),
+	})
+	if err != nil {
+		failures["get_content"]++
+		return err
+	}
+	samples["get_content"] = append(samples["get_content"], time.Since(start))
+	return nil
+}
+
+// formatBenchTable renders per-stage min/p50/p95/max as an aligned table.
+func formatBenchTable(samples map[string][]time.Duration, failures map[string]int) string {
+	var b strings.Builder
+	b.WriteString(promptSty.Render(fmt.Sprintf("%-12s %5s %9s %9s %9s %9s %6s",
+		"stage", "runs", "min", "p50", "p95", "max", "fails")))
+	b.WriteString("\n")
+	for _, stage := range benchStages {
+		d := samples[stage]
+		if len(d) == 0 {
+			b.WriteString(fmt.Sprintf("%-12s %5d %9s %9s %9s %9s %6d\n",
+				stage, 0, "-", "-", "-", "-", failures[stage]))
+			continue
+		}
+		sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+		b.WriteString(fmt.Sprintf("%-12s %5d %9s %9s %9s %9s %6d\n",
+			stage, len(d),
+			fmtLatency(d[0]), fmtLatency(percentile(d, 50)),
+			fmtLatency(percentile(d, 95)), fmtLatency(d[len(d)-1]),
+			failures[stage]))
+	}
+	return b.String()
+}
+
+// percentile returns the p-th percentile (nearest-rank) of sorted samples.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// fmtLatency formats a duration with millisecond precision (microsecond
+// for sub-millisecond values, which a local mock server produces).
+func fmtLatency(d time.Duration) string {
+	if d < time.Millisecond {
+		return d.Round(time.Microsecond).String()
+	}
+	return d.Round(time.Millisecond).String()
+}
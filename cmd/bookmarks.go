@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/bookmarks"
+	"github.com/ratnesh-maurya/flo/pkg/config"
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+var bookmarksCmd = &cobra.Command{
+	Use:     "bookmarks",
+	Aliases: []string{"bookmark"},
+	Short:   "List saved answers",
+	Long: `List the answers you have saved with [s] after viewing them.
+
+Bookmarks can carry your own note and local tags.  --note opens them in
+$EDITOR for the bookmark given by its number in the list, its question
+or answer ID, or its link:
+
+  flo bookmarks --note 2
+  flo bookmarks search goroutine leak
+
+Bookmarks can be exported with "flo export".`,
+	Args: cobra.NoArgs,
+	RunE: runBookmarks,
+}
+
+var bookmarksSearchCmd = &cobra.Command{
+	Use:   "search <terms>...",
+	Short: "Find bookmarks by title, note or tag",
+	Long: `List the bookmarks whose question title, note, Stack Overflow tags or
+local tags contain every term, ignoring case.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runBookmarksSearch,
+}
+
+// bookmarkNote is the --note flag of `flo bookmarks`.
+var bookmarkNote string
+
+func init() {
+	bookmarksCmd.Flags().StringVar(&bookmarkNote, "note", "", "edit the note and local tags of this bookmark (list number, question or answer ID, or link) in $EDITOR")
+	bookmarksCmd.AddCommand(bookmarksSearchCmd)
+	rootCmd.AddCommand(bookmarksCmd)
+}
+
+// runBookmarks implements `flo bookmarks`.
+func runBookmarks(cmd *cobra.Command, args []string) error {
+	store, err := openBookmarks()
+	if err != nil {
+		printError("Bookmarks unavailable", err.Error())
+		return err
+	}
+	items := store.All()
+	if len(items) == 0 {
+		fmt.Println(dimSty.Render("No bookmarks yet — press [" + keyName(actSave) + "] after viewing an answer to save it."))
+		return nil
+	}
+	if bookmarkNote != "" {
+		return annotateBookmark(store, items, bookmarkNote)
+	}
+	printDoc(ui.Document{Markdown: formatBookmarkList(fmt.Sprintf("Bookmarks (%d)", len(items)), items), Data: items})
+	return nil
+}
+
+// runBookmarksSearch implements `flo bookmarks search`.
+func runBookmarksSearch(cmd *cobra.Command, args []string) error {
+	store, err := openBookmarks()
+	if err != nil {
+		printError("Bookmarks unavailable", err.Error())
+		return err
+	}
+	var found []*bookmarks.Bookmark
+	for _, bm := range store.All() {
+		if bm.Matches(args) {
+			found = append(found, bm)
+		}
+	}
+	query := strings.Join(args, " ")
+	if len(found) == 0 {
+		fmt.Println(dimSty.Render(fmt.Sprintf("No bookmark matches %q.", query)))
+		return nil
+	}
+	printDoc(ui.Document{Markdown: formatBookmarkList(fmt.Sprintf("Bookmarks matching %q (%d)", query, len(found)), found), Data: found})
+	return nil
+}
+
+// annotateBookmark edits the note and local tags of the bookmark ref
+// names in $EDITOR.
+func annotateBookmark(store *bookmarks.Store, items []*bookmarks.Bookmark, ref string) error {
+	bm, err := findBookmark(items, ref)
+	if err != nil {
+		printError("No such bookmark", err.Error())
+		return err
+	}
+	doc, err := editText(bookmarks.FormatAnnotation(bm), "flo-note-*.md")
+	if err != nil {
+		printError("Could not edit the note", err.Error())
+		return err
+	}
+	note, tags, err := bookmarks.ParseAnnotation(doc)
+	if err != nil {
+		printError("Note not saved", err.Error())
+		return err
+	}
+	if !bm.Annotate(note, tags, time.Now()) {
+		fmt.Println(dimSty.Render("Note unchanged."))
+		return nil
+	}
+	if err := store.Save(); err != nil {
+		printError("Could not save the note", err.Error())
+		return err
+	}
+	fmt.Println(successSty.Render("📝 Note saved for " + bm.Title))
+	return nil
+}
+
+// findBookmark resolves ref — a number from the bookmark list, a
+// question or answer ID, or a link — among items, as listed by
+// Store.All.
+func findBookmark(items []*bookmarks.Bookmark, ref string) (*bookmarks.Bookmark, error) {
+	m := reQuestionRef.FindStringSubmatch(strings.TrimSpace(ref))
+	if m == nil {
+		return nil, fmt.Errorf("%q is not a list number, ID or link", ref)
+	}
+	id, _ := strconv.Atoi(m[2])
+	if m[1] == "" && id >= 1 && id <= len(items) {
+		return items[id-1], nil
+	}
+	for _, bm := range items {
+		if bm.QuestionID == id || bm.AnswerID == id {
+			return bm, nil
+		}
+	}
+	return nil, fmt.Errorf("no bookmark for %s (run flo bookmarks to list them)", ref)
+}
+
+// formatBookmarkList builds a Markdown list of bookmarks under heading.
+func formatBookmarkList(heading string, items []*bookmarks.Bookmark) string {
+	var b strings.Builder
+	b.WriteString("# " + heading + "\n\n")
+	for i, bm := range items {
+		accepted := ""
+		if bm.Accepted {
+			accepted = " ✅"
+		}
+		b.WriteString(fmt.Sprintf("%d. **%s**%s  \n   Answer by %s (score %d) | saved %s",
+			i+1, bm.Title, accepted, bm.AnswerAuthor, bm.AnswerScore, bm.SavedAt.Format("Jan 2, 2006")))
+		if len(bm.Tags) > 0 {
+			b.WriteString(" — `" + strings.Join(bm.Tags, "` `") + "`")
+		}
+		if len(bm.LocalTags) > 0 {
+			b.WriteString("  \n   #" + strings.Join(bm.LocalTags, " #"))
+		}
+		if bm.Note != "" {
+			b.WriteString("  \n   📝 " + noteExcerpt(bm.Note))
+		}
+		b.WriteString(fmt.Sprintf("  \n   %s\n\n", bm.Link))
+	}
+	return b.String()
+}
+
+// noteExcerptLen bounds the note shown in bookmark lists.
+const noteExcerptLen = 100
+
+// noteExcerpt is the first line of note, shortened to noteExcerptLen.
+func noteExcerpt(note string) string {
+	line, _, more := strings.Cut(strings.TrimSpace(note), "\n")
+	if r := []rune(line); len(r) > noteExcerptLen {
+		line, more = string(r[:noteExcerptLen]), true
+	}
+	if more {
+		line += "…"
+	}
+	return "*" + line + "*"
+}
+
+// openBookmarks opens the bookmark store in the user data directory.
+func openBookmarks() (*bookmarks.Store, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return nil, err
+	}
+	return bookmarks.Open(bookmarks.DefaultPath(dir))
+}
+
+// saveBookmark stores answer a to question q and reports the outcome.
+func saveBookmark(q *mcp.QuestionData, a *mcp.AnswerData) {
+	store, err := openBookmarks()
+	if err != nil {
+		printError(i18n.T("Could not save bookmark"), err.Error())
+		return
+	}
+	isNew := store.Add(bookmarkOf(q, a))
+	if err := store.Save(); err != nil {
+		printError(i18n.T("Could not save bookmark"), err.Error())
+		return
+	}
+	if isNew {
+		fmt.Println(successSty.Render(i18n.T("⭐ Saved to bookmarks")))
+	} else {
+		fmt.Println(successSty.Render(i18n.T("⭐ Bookmark updated")))
+	}
+}
+
+// bookmarkOf is the bookmark for answer a to question q.
+func bookmarkOf(q *mcp.QuestionData, a *mcp.AnswerData) bookmarks.Bookmark {
+	bm := bookmarks.Bookmark{
+		QuestionID:       q.QuestionID,
+		AnswerID:         a.AnswerID,
+		Title:            html.UnescapeString(q.Title),
+		Link:             q.Link,
+		Tags:             q.Tags,
+		QuestionBody:     html.UnescapeString(q.BodyMarkdown),
+		AnswerBody:       html.UnescapeString(a.BodyMarkdown),
+		AnswerAuthor:     html.UnescapeString(a.Owner.DisplayName),
+		AnswerAuthorLink: a.Owner.Link,
+		AnswerScore:      a.Score,
+		Accepted:         a.IsAccepted,
+		QuestionScore:    q.Score,
+	}
+	if q.CreationDate > 0 {
+		bm.CreationDate = time.Unix(q.CreationDate, 0)
+	}
+	if a.CreationDate > 0 {
+		bm.AnswerDate = time.Unix(a.CreationDate, 0)
+	}
+	return bm
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bountyTags  []string
+	bountyLimit int
+)
+
+// defaultBountyLimit is how many questions flo bounties lists without
+// --limit.
+const defaultBountyLimit = 20
+
+var bountiesCmd = &cobra.Command{
+	Use:   "bounties",
+	Short: "List the questions with an open bounty, largest first",
+	Long: `List the questions in your tags that carry an open bounty, with the
+reputation offered, when the bounty closes and how many answers the
+question has so far — the largest bounties first.
+
+  flo bounties                   your subscribed tags, or every tag
+  flo bounties --tag go --tag rust
+
+Bounties come from the Stack Exchange API; with backend: mcp they are
+fetched from the API through failover (see flo doctor).`,
+	Args: cobra.NoArgs,
+	RunE: runBounties,
+}
+
+func init() {
+	bountiesCmd.Flags().StringSliceVar(&bountyTags, "tag", nil, "tags to look in, repeated or comma-separated (default: your subscribed tags)")
+	bountiesCmd.Flags().IntVarP(&bountyLimit, "limit", "n", defaultBountyLimit, "most questions listed")
+	_ = bountiesCmd.RegisterFlagCompletionFunc("tag", completeTags)
+	rootCmd.AddCommand(bountiesCmd)
+}
+
+// runBounties implements `flo bounties`.
+func runBounties(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	tags, err := tagsOrSubscribed(bountyTags)
+	if err != nil {
+		printError("Bounties failed", err.Error())
+		return err
+	}
+	if len(tags) == 0 {
+		tags = []string{""} // every tag
+	}
+
+	p, release, err := connect(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	status(spinnerSty, "💰 Looking for bounties...", "finding bounties", "tags", tags)
+	all := &mcp.SOResponse{}
+	var lastErr error
+	for _, tag := range tags {
+		tctx, cancel := context.WithTimeout(ctx, cfg.Timeouts.SearchTimeout())
+		resp, err := provider.Bountied(tctx, p, tag, 0)
+		cancel()
+		switch {
+		case err == nil:
+			all = mcp.MergeResponses(all, resp)
+		case errors.Is(err, provider.ErrNotFound):
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case errors.Is(err, provider.ErrUnsupported):
+			printError("Bounties unavailable", "Bounties come from the Stack Exchange API, which this config does not use: "+
+				"run with --backend api, or keep api in failover.backends.")
+			return err
+		default:
+			slog.Warn("bounties tag failed", "tag", tag, "err", err)
+			lastErr = err
+		}
+	}
+	if len(all.Items) == 0 && lastErr != nil {
+		printError("Bounties failed", lastErr.Error())
+		return lastErr
+	}
+	if len(all.Items) == 0 {
+		fmt.Fprintln(chrome(), dimSty.Render("No open bounties in these tags."))
+		return errNoResults
+	}
+
+	found := all.Items
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].BountyAmount != found[j].BountyAmount {
+			return found[i].BountyAmount > found[j].BountyAmount
+		}
+		return found[i].BountyClosesDate < found[j].BountyClosesDate
+	})
+	if bountyLimit > 0 && len(found) > bountyLimit {
+		found = found[:bountyLimit]
+	}
+	now := time.Now()
+	if ui.Current().Styled() {
+		for i := range found {
+			printBounty(i+1, &found[i], now)
+		}
+	} else {
+		printDoc(ui.Document{Markdown: formatBounties(found, now), Data: found})
+	}
+	if lastErr != nil {
+		fmt.Fprintln(chrome(), spinnerSty.Render("⚠ Some tags could not be searched; see the log."))
+	}
+	return nil
+}
+
+// printBounty prints the nth bountied question, with how long its
+// bounty has left at now.
+func printBounty(n int, q *mcp.QuestionData, now time.Time) {
+	meta, link := bountyMeta(q, now)
+	fmt.Printf("%3d. %s %s\n", n, successSty.Render(fmt.Sprintf("+%d", q.BountyAmount)), html.UnescapeString(q.Title))
+	fmt.Println(dimSty.Render("     " + meta))
+	fmt.Println(dimSty.Render("     " + link))
+}
+
+// formatBounties renders the bountied questions found as Markdown, for
+// the formats other than ansi.
+func formatBounties(found []mcp.QuestionData, now time.Time) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# Open bounties (%d)\n\n", len(found)))
+	for i := range found {
+		q := &found[i]
+		meta, link := bountyMeta(q, now)
+		b.WriteString(fmt.Sprintf("%d. **+%d** %s  \n   %s  \n   %s\n\n",
+			i+1, q.BountyAmount, html.UnescapeString(q.Title), meta, link))
+	}
+	return b.String()
+}
+
+// bountyMeta returns the line describing q's bounty at now, and its link.
+func bountyMeta(q *mcp.QuestionData, now time.Time) (meta, link string) {
+	answers := fmt.Sprintf("%d answers", q.AnswerCount)
+	if q.AnswerCount == 1 {
+		answers = "1 answer"
+	}
+	closes := "closes in " + shortAge(time.Unix(q.BountyClosesDate, 0).Sub(now))
+	if q.BountyClosesDate == 0 {
+		closes = "closing date unknown"
+	}
+	meta = fmt.Sprintf("%s · %s · score %+d", closes, answers, q.Score)
+	if len(q.Tags) > 0 {
+		meta += " · [" + q.Tags[0] + "]"
+	}
+	link = q.Link
+	if link == "" {
+		link = postURL("q", q.QuestionID)
+	}
+	return meta, link
+}
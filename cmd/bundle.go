@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/bundle"
+	"github.com/ratnesh-maurya/flo/pkg/index"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+	"github.com/ratnesh-maurya/flo/pkg/subscriptions"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bundleTags     []string
+	bundleTop      int
+	bundleAnswers  int
+	bundleMinScore int
+)
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Carry the top questions of some tags to a machine offline",
+}
+
+var bundleCreateCmd = &cobra.Command{
+	Use:   "create --tag <tag> <file>",
+	Short: "Write the top questions of tags, with their best answers, to a bundle file",
+	Long: `On a machine with a connection, fetch the highest scored questions of
+each tag with their best answers and write them to one compressed file,
+to carry to an air-gapped machine and load there with flo bundle load.
+
+  flo bundle create --tag go --top 500 go.flo
+  flo bundle create --tag docker,kubernetes --answers 5 ops.flo
+
+The questions cost a request per hundred, and one more each whose
+answers the backend does not send along.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBundleCreate,
+}
+
+var bundleLoadCmd = &cobra.Command{
+	Use:   "load <file>",
+	Short: "Load a bundle into the local index for offline search",
+	Long: `Add the questions and answers of a bundle made with flo bundle create to
+the local index, with no network access.  Search them with flo local,
+or browse them as usual with --backend local (or backend: local in the
+config):
+
+  flo bundle load go.flo
+  flo --backend local ask "reverse a string"
+
+Posts already in the index, such as those you viewed, are kept.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBundleLoad,
+}
+
+func init() {
+	bundleCreateCmd.Flags().StringSliceVar(&bundleTags, "tag", nil, "tags to bundle, repeated or comma-separated (required)")
+	bundleCreateCmd.Flags().IntVar(&bundleTop, "top", 500, "highest scored questions bundled per tag")
+	bundleCreateCmd.Flags().IntVar(&bundleAnswers, "answers", 3, "best answers bundled per question, the accepted one first")
+	bundleCreateCmd.Flags().IntVar(&bundleMinScore, "min-score", 0, "leave out questions scoring below this")
+	_ = bundleCreateCmd.MarkFlagRequired("tag")
+	_ = bundleCreateCmd.RegisterFlagCompletionFunc("tag", completeTags)
+	bundleCmd.AddCommand(bundleCreateCmd, bundleLoadCmd)
+	rootCmd.AddCommand(bundleCmd)
+}
+
+// runBundleCreate implements `flo bundle create`.
+func runBundleCreate(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	var tags []string
+	for _, t := range bundleTags {
+		tag, err := subscriptions.NormalizeTag(t)
+		if err != nil {
+			return withExitCode(exitUsage, err)
+		}
+		tags = append(tags, tag)
+	}
+	if bundleTop <= 0 {
+		return withExitCode(exitUsage, errors.New("--top must be at least 1"))
+	}
+
+	p, release, err := connect(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	b := &bundle.Bundle{Version: bundle.Version, Created: time.Now().UTC(), Site: currentSite(), Tags: tags}
+	seen := make(map[int]bool)
+	// Each page of questions gets the time of a search.
+	pages := time.Duration((bundleTop + 99) / 100)
+	for _, tag := range tags {
+		status(spinnerSty, fmt.Sprintf("📦 Fetching the top questions of [%s]...", tag), "bundling tag",
+			"tag", tag, "top", bundleTop)
+		tctx, cancel := context.WithTimeout(ctx, pages*cfg.Timeouts.SearchTimeout())
+		resp, err := provider.NewQuestions(tctx, p, tag, time.Time{}, bundleMinScore, bundleTop)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			printError("Bundle failed", fmt.Sprintf("[%s]: %v", tag, err))
+			return err
+		}
+		for i := range resp.Items {
+			q := &resp.Items[i]
+			if seen[q.QuestionID] {
+				continue
+			}
+			seen[q.QuestionID] = true
+			if len(q.Answers) == 0 && q.AnswerCount > 0 && bundleAnswers > 0 {
+				fctx, cancel := context.WithTimeout(ctx, cfg.Timeouts.FetchTimeout())
+				q.Answers, err = provider.AllAnswers(fctx, p, q.QuestionID)
+				cancel()
+				if err != nil {
+					if ctx.Err() != nil {
+						return ctx.Err()
+					}
+					slog.Warn("fetch bundled answers failed", "question_id", q.QuestionID, "err", err)
+				}
+			}
+			b.Docs = append(b.Docs, bundleDocs(q)...)
+		}
+	}
+	questions, answers := b.Counts()
+	if questions == 0 {
+		fmt.Println(dimSty.Render("No questions found to bundle; try a lower --min-score."))
+		return errNoResults
+	}
+
+	if err := b.WriteFile(args[0]); err != nil {
+		printError("Bundle failed", err.Error())
+		return err
+	}
+	size := ""
+	if info, err := os.Stat(args[0]); err == nil {
+		size = " (" + humanBytes(info.Size()) + ")"
+	}
+	fmt.Println(successSty.Render(fmt.Sprintf("✅ Bundled %d questions and %d answers in %s%s", questions, answers, args[0], size)))
+	fmt.Println(dimSty.Render("Load it offline with: flo bundle load " + args[0]))
+	return nil
+}
+
+// bundleDocs converts q and its best answers into index documents.
+func bundleDocs(q *mcp.QuestionData) []index.Doc {
+	docs := []index.Doc{questionDoc(q)}
+	sorted := mcp.SortAnswers(q.Answers)
+	for i := 0; i < len(sorted) && i < bundleAnswers; i++ {
+		docs = append(docs, answerDoc(q, &sorted[i]))
+	}
+	for i := range docs {
+		docs[i].Source = bundle.Source
+	}
+	return docs
+}
+
+// runBundleLoad implements `flo bundle load`.
+func runBundleLoad(cmd *cobra.Command, args []string) error {
+	b, err := bundle.ReadFile(args[0])
+	if err != nil {
+		printError("Load failed", err.Error())
+		return err
+	}
+	if site := currentSite(); b.Site != "" && b.Site != site {
+		fmt.Println(spinnerSty.Render(fmt.Sprintf("⚠ The bundle is from %s, and flo is set to %s.", b.Site, site)))
+	}
+
+	idx, err := openIndex()
+	if err != nil {
+		printError("Load failed", err.Error())
+		return err
+	}
+	var fresh []index.Doc
+	for _, d := range b.Docs {
+		if idx.Get(d.ID) == nil {
+			fresh = append(fresh, d)
+		}
+	}
+	idx.Add(fresh...)
+	if err := idx.Save(); err != nil {
+		printError("Load failed", err.Error())
+		return err
+	}
+
+	questions, answers := b.Counts()
+	msg := fmt.Sprintf("✅ Loaded %d questions and %d answers on [%s], bundled %s (%d posts in index)",
+		questions, answers, strings.Join(b.Tags, "] ["), b.Created.Local().Format("Jan 2, 2006"), idx.Len())
+	fmt.Println(successSty.Render(msg))
+	if kept := len(b.Docs) - len(fresh); kept > 0 {
+		fmt.Println(dimSty.Render(fmt.Sprintf("%d posts were in the index already and were kept.", kept)))
+	}
+	fmt.Println(dimSty.Render(`Search them with flo local "<query>", or flo --backend local ask "<query>".`))
+	return nil
+}
@@ -0,0 +1,341 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/cache"
+	"github.com/ratnesh-maurya/flo/pkg/config"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+	"github.com/ratnesh-maurya/flo/pkg/taglist"
+	"github.com/spf13/cobra"
+)
+
+// respCache holds backend responses for the current command; nil when
+// caching is disabled.
+var respCache *cache.Cache
+
+// noCache is the --no-cache flag of commands that query the server.
+var noCache bool
+
+// openCache sets up respCache from the config.  Problems are logged and
+// leave caching off: the cache must never stop a query from running.
+func openCache() {
+	if noCache || cfg.Cache.Disabled {
+		return
+	}
+	dir, err := config.CacheDir()
+	if err != nil {
+		slog.Warn("response cache disabled", "err", err)
+		return
+	}
+	local := responseFiles(dir)
+
+	var shared cache.Backend
+	if cfg.Cache.Shared != "" {
+		hc, err := newHTTPClient(0)
+		if err == nil {
+			shared, err = cache.OpenShared(cfg.Cache.Shared, hc, cfg.Cache.Token)
+		}
+		if err != nil {
+			slog.Warn("shared cache disabled", "err", err)
+			shared = nil
+		}
+		if shared != nil && cfg.Anonymous {
+			shared = cache.ReadOnly(shared)
+		}
+	}
+	respCache = cache.New(local, shared, cfg.Cache.TTL)
+	respCache.PostTTL = cfg.Cache.PostTTL
+	if respCache.PostTTL <= 0 {
+		respCache.PostTTL = cache.DefaultPostTTL
+	}
+	if site := currentSite(); site != provider.DefaultSite {
+		respCache.Site = site
+	}
+	if !cfg.Cache.NoRefresh {
+		respCache.SoftTTL = cfg.Cache.SoftTTL
+		if respCache.SoftTTL <= 0 {
+			respCache.SoftTTL = cache.DefaultSoftTTL
+		}
+	}
+	respCache.Queries = cache.OpenQueryIndex(queryIndexPath(dir))
+}
+
+// responseFiles returns the local tier inside the cache directory dir.
+func responseFiles(dir string) *cache.FileBackend {
+	return &cache.FileBackend{Dir: filepath.Join(dir, "responses")}
+}
+
+// queryIndexPath returns the similar-search index inside dir.
+func queryIndexPath(dir string) string {
+	return filepath.Join(dir, "queries.json")
+}
+
+// cacheHits returns how many lookups the response cache has served.
+func cacheHits() int64 {
+	if respCache == nil {
+		return 0
+	}
+	return respCache.Hits()
+}
+
+// closeCache lets background writes to the shared cache finish.
+func closeCache() {
+	if respCache != nil {
+		respCache.Close()
+	}
+}
+
+// nsTags is the `flo cache` name of the cached tag lists, which are
+// files of their own rather than response entries.
+const nsTags = "tags"
+
+// cacheNamespaces are the groups `flo cache` reports and clears, in the
+// order it lists them.
+var cacheNamespaces = []string{cache.NSSearches, cache.NSPosts, cache.NSRelated, nsTags, cache.NSOther}
+
+// cacheOlderThan is the --older-than flag of `flo cache prune`.
+var cacheOlderThan string
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Show the size of the local cache, clear it or prune it",
+	Long: `Report and trim what flo keeps in its cache directory: search results,
+single questions and answers, related questions and the tag lists used
+for completion.
+
+  flo cache stats
+  flo cache clear posts
+  flo cache prune --older-than 30d
+
+clear and prune act on every namespace unless some are named
+(searches, posts, related, tags, other).  A shared cache is not touched.`,
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show the entries and disk space of each cache namespace",
+	Args:  cobra.NoArgs,
+	RunE:  runCacheStats,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:       "clear [namespace...]",
+	Short:     "Delete cached entries, all of them or those of the named namespaces",
+	Args:      cobra.OnlyValidArgs,
+	ValidArgs: cacheNamespaces,
+	RunE:      runCacheClear,
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune [namespace...]",
+	Short: "Delete expired cache entries, or those older than --older-than",
+	Long: `Delete the cache entries that have expired and can no longer be served,
+along with files left behind by interrupted writes.  With --older-than,
+delete every entry stored before then instead, fresh or not.`,
+	Args:      cobra.OnlyValidArgs,
+	ValidArgs: cacheNamespaces,
+	RunE:      runCachePrune,
+}
+
+func init() {
+	cachePruneCmd.Flags().StringVar(&cacheOlderThan, "older-than", "", "delete entries stored before this age or date (e.g. 30d, 12h, 2024-05-01)")
+	cacheCmd.AddCommand(cacheStatsCmd, cacheClearCmd, cachePruneCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+// cacheItem is a file of the local cache, for `flo cache`.
+type cacheItem struct {
+	ns       string
+	key      string // response entries only
+	path     string
+	size     int64
+	storedAt time.Time
+	expired  bool
+}
+
+// localCache lists the files of the local cache in dir: the response
+// entries and the tag lists.
+func localCache(dir string, now time.Time) ([]cacheItem, error) {
+	files := responseFiles(dir)
+	entries, err := files.Entries()
+	if err != nil {
+		return nil, err
+	}
+	ttl := cfg.Cache.TTL
+	if ttl <= 0 {
+		ttl = cache.DefaultTTL
+	}
+	var items []cacheItem
+	for _, e := range entries {
+		items = append(items, cacheItem{
+			ns:       e.Namespace,
+			key:      e.Key,
+			path:     filepath.Join(files.Dir, e.Key+".json"),
+			size:     e.Size,
+			storedAt: e.StoredAt,
+			expired:  e.Expired(now, ttl),
+		})
+	}
+	lists, err := filepath.Glob(taglist.DefaultPath(dir, "*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range lists {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		items = append(items, cacheItem{
+			ns:       nsTags,
+			path:     path,
+			size:     info.Size(),
+			storedAt: info.ModTime(),
+			expired:  now.Sub(info.ModTime()) > taglist.MaxAge,
+		})
+	}
+	return items, nil
+}
+
+// runCacheStats implements `flo cache stats`.
+func runCacheStats(cmd *cobra.Command, args []string) error {
+	dir, err := config.CacheDir()
+	if err != nil {
+		printError("Cache unavailable", err.Error())
+		return err
+	}
+	items, err := localCache(dir, time.Now())
+	if err != nil {
+		printError("Cache unreadable", err.Error())
+		return err
+	}
+	type total struct {
+		entries, expired int
+		size             int64
+	}
+	totals := map[string]*total{}
+	for _, ns := range cacheNamespaces {
+		totals[ns] = &total{}
+	}
+	var all total
+	for _, it := range items {
+		t, ok := totals[it.ns]
+		if !ok {
+			t = totals[cache.NSOther]
+		}
+		for _, t := range []*total{t, &all} {
+			t.entries++
+			t.size += it.size
+			if it.expired {
+				t.expired++
+			}
+		}
+	}
+	// The similar-search index belongs with the searches it points to.
+	if info, err := os.Stat(queryIndexPath(dir)); err == nil {
+		totals[cache.NSSearches].size += info.Size()
+		all.size += info.Size()
+	}
+
+	fmt.Println(promptSty.Render("Cache  ") + dimSty.Render(dir))
+	row := func(name string, t *total) {
+		line := fmt.Sprintf("  %-10s %6d  %10s", name, t.entries, humanBytes(t.size))
+		if t.expired > 0 {
+			line += dimSty.Render(fmt.Sprintf("  %d expired", t.expired))
+		}
+		fmt.Println(line)
+	}
+	for _, ns := range cacheNamespaces {
+		row(ns, totals[ns])
+	}
+	row("total", &all)
+	if all.expired > 0 {
+		fmt.Println()
+		fmt.Println(dimSty.Render("Run flo cache prune to delete expired entries."))
+	}
+	if cfg.Cache.Shared != "" {
+		fmt.Println(dimSty.Render("The shared cache is not included."))
+	}
+	return nil
+}
+
+// runCacheClear implements `flo cache clear`.
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	return removeCached(args, func(cacheItem) bool { return true })
+}
+
+// runCachePrune implements `flo cache prune`.
+func runCachePrune(cmd *cobra.Command, args []string) error {
+	now := time.Now()
+	stale := func(it cacheItem) bool { return it.expired }
+	if cacheOlderThan != "" {
+		cutoff, err := parseSince("--older-than", cacheOlderThan, now)
+		if err != nil {
+			return withExitCode(exitUsage, err)
+		}
+		stale = func(it cacheItem) bool { return it.storedAt.Before(cutoff) }
+	}
+	if dir, err := config.CacheDir(); err == nil {
+		// A write still in progress is at most a few seconds old.
+		if n, err := responseFiles(dir).RemoveTemp(now.Add(-time.Hour)); err == nil && n > 0 {
+			slog.Debug("removed interrupted cache writes", "count", n)
+		}
+	}
+	return removeCached(args, stale)
+}
+
+// removeCached deletes the local cache items of the namespaces in nss
+// (all when empty) that match, and reports what it freed.
+func removeCached(nss []string, match func(cacheItem) bool) error {
+	dir, err := config.CacheDir()
+	if err != nil {
+		printError("Cache unavailable", err.Error())
+		return err
+	}
+	if len(nss) == 0 {
+		nss = cacheNamespaces
+	}
+	items, err := localCache(dir, time.Now())
+	if err != nil {
+		printError("Cache unreadable", err.Error())
+		return err
+	}
+	var removed int
+	var freed int64
+	for _, it := range items {
+		if !slices.Contains(nss, it.ns) || !match(it) {
+			continue
+		}
+		if err := os.Remove(it.path); err != nil && !os.IsNotExist(err) {
+			printError("Could not delete cache entry", err.Error())
+			return err
+		}
+		removed++
+		freed += it.size
+	}
+	if slices.Contains(nss, cache.NSSearches) {
+		// Forget the similar searches whose entries are gone.
+		files := responseFiles(dir)
+		x := cache.OpenQueryIndex(queryIndexPath(dir))
+		if _, err := x.Retain(func(key string) bool {
+			_, err := files.Get(context.Background(), key)
+			return err == nil
+		}); err != nil {
+			slog.Warn("could not update the search index", "err", err)
+		}
+	}
+	what := "all namespaces"
+	if len(nss) < len(cacheNamespaces) {
+		what = strings.Join(nss, ", ")
+	}
+	status(successSty, fmt.Sprintf("Deleted %d cache files (%s) from %s", removed, humanBytes(freed), what),
+		"cache entries deleted", "count", removed, "bytes", freed, "namespaces", nss)
+	return nil
+}
@@ -0,0 +1,424 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/clipboard"
+	"github.com/ratnesh-maurya/flo/pkg/export"
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	codeBlock int
+	codeOut   string
+	codeForce bool
+)
+
+var codeCmd = &cobra.Command{
+	Use:   "code <query | question or answer URL | question ID>",
+	Short: "Print a code block of an answer, or write it to a file",
+	Long: `Take a code block from the accepted (or top) answer of a question, or
+from an answer linked to, and print it as it is, or write it to a file
+with --out — handy for grabbing a full example program:
+
+  flo code 1752414
+  flo code https://stackoverflow.com/a/10030772 --out reverse.go
+  flo code "table driven tests in go" --block 2 --out main_test.go
+
+Without --block it takes the longest block.  --out never overwrites a
+file unless --force is given.  Under an answer in the interactive
+session, w<n> <file> does the same for block n.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runCode,
+
+	ValidArgsFunction: completeRecentQuestions,
+}
+
+func init() {
+	codeCmd.Flags().IntVarP(&codeBlock, "block", "b", 0, "take block n of the answer, counted from 1 (default the longest)")
+	codeCmd.Flags().StringVarP(&codeOut, "out", "o", "", "write the block to this file instead of printing it")
+	codeCmd.Flags().BoolVarP(&codeForce, "force", "f", false, "let --out overwrite an existing file")
+	codeCmd.Flags().BoolVar(&noCache, "no-cache", false, "always query the server, bypassing the response cache")
+	rootCmd.AddCommand(codeCmd)
+}
+
+// runCode implements `flo code`.
+func runCode(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if codeBlock < 0 {
+		return withExitCode(exitUsage, errors.New("--block must be at least 1"))
+	}
+	// Only the code goes to stdout, so it can be redirected to a file.
+	out := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = out }()
+
+	p, release, err := connect(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	q, a, err := shareTarget(ctx, p, strings.TrimSpace(strings.Join(args, " ")))
+	if q == nil || a == nil {
+		return err
+	}
+	blocks := mcp.CodeBlocks(a.BodyMarkdown)
+	switch {
+	case len(blocks) == 0:
+		printError("No code", fmt.Sprintf("The answer has no code block.\n\n  %s", answerLink(q, a)))
+		return errNoResults
+	case codeBlock > len(blocks):
+		printError("No such block", fmt.Sprintf("The answer has %d code blocks.\n\n  %s", len(blocks), answerLink(q, a)))
+		return withExitCode(exitUsage, fmt.Errorf("--block %d: no such block", codeBlock))
+	}
+	i := codeBlock - 1
+	if i < 0 {
+		i = longestBlock(blocks)
+	}
+	code := blocks[i].Code
+
+	if codeOut == "" {
+		if !strings.HasSuffix(code, "\n") {
+			code += "\n"
+		}
+		fmt.Fprint(out, code)
+		return nil
+	}
+	bm := bookmarkOf(q, a)
+	credited, note := creditCode(export.CreditOf(&bm), code, blocks[i].Lang, q.Tags)
+	if err := writeCode(codeOut, credited, codeForce); err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			printError("File exists", fmt.Sprintf("%s is there already; add --force to overwrite it.", codeOut))
+			return withExitCode(exitUsage, err)
+		}
+		printError("Write failed", err.Error())
+		return err
+	}
+	lines := strings.Count(code, "\n") + 1
+	fmt.Println(successSty.Render(fmt.Sprintf("✅ Wrote code block %d of %d (%d %s) to %s", i+1, len(blocks), lines, plural(lines, "line", "lines"), codeOut)))
+	fmt.Println(dimSty.Render(answerLink(q, a)))
+	if note != "" {
+		fmt.Println(dimSty.Render("Credit it where you use it: " + note))
+	}
+	return nil
+}
+
+// longestBlock returns the index of the block with the most lines, the
+// first of those tied.
+func longestBlock(blocks []mcp.CodeBlock) int {
+	best := 0
+	for i, b := range blocks {
+		if strings.Count(b.Code, "\n") > strings.Count(blocks[best].Code, "\n") {
+			best = i
+		}
+	}
+	return best
+}
+
+// codeNav steps through the code blocks of the answer on screen, for
+// when only the code is wanted: next_code and prev_code show a block
+// again on its own, unwrapped, scroll_left and scroll_right move along
+// one wider than the terminal, yank copies one to the clipboard and
+// write saves one to a file.
+type codeNav struct {
+	blocks []mcp.CodeBlock
+	tags   []string
+	cur    int          // the block shown last; -1 before any
+	view   *ui.CodeView // of the block shown, when it rendered
+	// credit is the answer's attribution, added to the code copied or
+	// written when export.Attribute is set.
+	credit export.Credit
+}
+
+// The arrow keys, as a prompt reads them (lower-cased): they scroll a
+// code block whatever the keymap.
+const (
+	arrowLeft  = "\x1b[d"
+	arrowRight = "\x1b[c"
+)
+
+// newCodeNav returns the code navigation of a, an answer to q.
+func newCodeNav(q *mcp.QuestionData, a *mcp.AnswerData) *codeNav {
+	bm := bookmarkOf(q, a)
+	return &codeNav{blocks: mcp.CodeBlocks(a.BodyMarkdown), tags: q.Tags, cur: -1, credit: export.CreditOf(&bm)}
+}
+
+// creditCode returns code, of a block in lang or guessed from it and
+// tags, with credit added as a comment when export.Attribute is set.
+// note is the credit to add by hand when lang has no comments flo knows,
+// else "".
+func creditCode(credit export.Credit, code, lang string, tags []string) (out, note string) {
+	if !export.Attribute {
+		return code, ""
+	}
+	if lang == "" {
+		lang = ui.CodeLanguage(code, tags)
+	}
+	out, ok := credit.Code(code, lang)
+	if !ok {
+		return code, credit.Text()
+	}
+	return out, ""
+}
+
+// hints is the line of code keys under the answer, or "" when it has
+// no code.
+func (c *codeNav) hints() string {
+	if len(c.blocks) == 0 {
+		return ""
+	}
+	n := len(c.blocks)
+	return "  " + fmt.Sprintf(i18n.Plural(n, "%d code block", "%d code blocks"), n) + "  |  " +
+		i18n.Tf("%s / %s next / previous block  |  %s<n> copy block n", keyName(actNextCode), keyName(actPrevCode), keyName(actYank)) + "  |  " +
+		i18n.Tf("%s<n> <file> write block n to a file", keyName(actWrite))
+}
+
+// handle acts on input when it is one of the code keys, and reports
+// whether it was.
+func (c *codeNav) handle(ctx context.Context, input string) bool {
+	if len(c.blocks) == 0 {
+		return false
+	}
+	if n, path, force, ok := typedWrite(input); ok {
+		if path == "" {
+			fmt.Println(spinnerSty.Render(i18n.Tf("  ⚠ Write to which file? %s<n> <file>", keyName(actWrite))))
+		} else if n = c.pick(n, actWrite); n > 0 {
+			c.write(n-1, path, force)
+		}
+		return true
+	}
+	act := typedAction(input)
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case arrowLeft:
+		act = actScrollLeft
+	case arrowRight:
+		act = actScrollRight
+	}
+	switch act {
+	case actScrollLeft, actScrollRight:
+		c.scroll(act == actScrollRight)
+		return true
+	case actNextCode:
+		if c.cur == len(c.blocks)-1 {
+			fmt.Println(dimSty.Render(i18n.T("  Back to the first code block.")))
+		}
+		c.show((c.cur + 1) % len(c.blocks))
+		return true
+	case actPrevCode:
+		if c.cur <= 0 {
+			c.cur = len(c.blocks)
+			fmt.Println(dimSty.Render(i18n.T("  Back to the last code block.")))
+		}
+		c.show(c.cur - 1)
+		return true
+	}
+	n, ok := typedCount(input, actYank)
+	if !ok {
+		return false
+	}
+	if n = c.pick(n, actYank); n > 0 {
+		c.yank(ctx, n-1)
+	}
+	return true
+}
+
+// pick returns the block act's key typed with the number n (0 for
+// none) stands for, counted from 1: block n, else the only one or the
+// one shown last.  It explains and returns 0 when there is no such
+// block.
+func (c *codeNav) pick(n int, act string) int {
+	switch {
+	case n == 0 && len(c.blocks) == 1:
+		return 1
+	case n == 0 && c.cur >= 0:
+		return c.cur + 1
+	case n == 0:
+		fmt.Println(spinnerSty.Render(i18n.Tf("  ⚠ Which block? %s1 to %s%d.", keyName(act), keyName(act), len(c.blocks))))
+		return 0
+	case n > len(c.blocks):
+		fmt.Println(spinnerSty.Render("  ⚠ " + fmt.Sprintf(i18n.Plural(len(c.blocks), "The answer has %d code block.", "The answer has %d code blocks."), len(c.blocks))))
+		return 0
+	}
+	return n
+}
+
+// typedWrite parses a line typed at a prompt as the write key, an
+// optional block number and "!" to overwrite, then a file name, as in
+// "w2 main.go" or "w! main.go".  The name keeps its case.
+func typedWrite(input string) (n int, path string, force, ok bool) {
+	key, err := lineKey(keys[actWrite])
+	if err != nil || key == "" {
+		return 0, "", false, false
+	}
+	input = strings.TrimSpace(input)
+	if len(input) < len(key) || !strings.EqualFold(input[:len(key)], key) {
+		return 0, "", false, false
+	}
+	rest := input[len(key):]
+	digits := len(rest) - len(strings.TrimLeft(rest, "0123456789"))
+	if digits > 0 {
+		if n, err = strconv.Atoi(rest[:digits]); err != nil || n < 1 {
+			return 0, "", false, false
+		}
+		rest = rest[digits:]
+	}
+	rest, force = strings.CutPrefix(rest, "!")
+	if path = strings.TrimSpace(rest); path != "" && path == rest {
+		// A name must follow a space: "what" is no write.
+		return 0, "", false, false
+	}
+	return n, path, force, true
+}
+
+// write saves block i to path, unless a file is there and force is
+// not set.
+func (c *codeNav) write(i int, path string, force bool) {
+	code, note := creditCode(c.credit, c.blocks[i].Code, c.blocks[i].Lang, c.tags)
+	err := writeCode(path, code, force)
+	switch {
+	case errors.Is(err, fs.ErrExist):
+		fmt.Println(spinnerSty.Render(i18n.Tf("  ⚠ %s exists; %s%d! %s overwrites it.", path, keyName(actWrite), i+1, path)))
+	case err != nil:
+		printError(i18n.T("Could not write"), err.Error())
+	default:
+		lines := strings.Count(c.blocks[i].Code, "\n") + 1
+		what := fmt.Sprintf(i18n.Plural(lines, "code block %d (%d line)", "code block %d (%d lines)"), i+1, lines)
+		status(successSty, "  "+i18n.Tf("💾 Wrote %s to %s", what, path), "wrote code block", "block", i+1, "path", path)
+		c.creditNote(note)
+	}
+}
+
+// creditNote prints the credit to add by hand to code copied or
+// written in a language without comments; nothing when note is "".
+func (c *codeNav) creditNote(note string) {
+	if note != "" {
+		fmt.Println(dimSty.Render("  " + i18n.Tf("Credit it where you use it: %s", note)))
+	}
+}
+
+// writeCode writes code to path, ending in a newline.  Unless force is
+// set it fails with an error wrapping fs.ErrExist when path exists,
+// rather than overwrite it.
+func writeCode(path, code string, force bool) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return err
+	}
+	if !strings.HasSuffix(code, "\n") {
+		code += "\n"
+	}
+	if _, err := f.WriteString(code); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// show prints block i on its own.
+func (c *codeNav) show(i int) {
+	c.cur = i
+	b := c.blocks[i]
+	lang := b.Lang
+	if lang == "" {
+		lang = ui.CodeLanguage(b.Code, c.tags)
+	}
+	fmt.Println(promptSty.Render(i18n.Tf("  Code block %d of %d", i+1, len(c.blocks))))
+	view, err := ui.NewCodeView(b.Code, lang)
+	if err != nil {
+		c.view = nil
+		fmt.Println(b.Code)
+		return
+	}
+	c.view = view
+	c.print()
+}
+
+// scroll moves the block shown half a screen right, or left.
+func (c *codeNav) scroll(right bool) {
+	switch {
+	case c.view == nil:
+		fmt.Println(spinnerSty.Render(i18n.Tf("  ⚠ Show a code block first, with %s.", keyName(actNextCode))))
+		return
+	case !c.view.Wide():
+		fmt.Println(dimSty.Render(i18n.T("  The code block fits the screen.")))
+		return
+	}
+	step := c.view.Step()
+	if !right {
+		step = -step
+	}
+	if !c.view.Scroll(step) {
+		if right {
+			fmt.Println(dimSty.Render(i18n.T("  Already at the end of the lines.")))
+		} else {
+			fmt.Println(dimSty.Render(i18n.T("  Already at the start of the lines.")))
+		}
+		return
+	}
+	c.print()
+}
+
+// print prints the window of the block shown, and where it is in lines
+// wider than the terminal.
+func (c *codeNav) print() {
+	fmt.Print(c.view.View())
+	if !c.view.Wide() {
+		return
+	}
+	from, to, total := c.view.Columns()
+	fmt.Println(dimSty.Render(i18n.Tf("  Columns %d–%d of %d  |  %s / %s or ← / → scroll", from, to, total,
+		keyName(actScrollLeft), keyName(actScrollRight))))
+}
+
+// printPlain reprints the body of a as the Markdown it was written in,
+// unstyled and outside the frame: copied from the rendered answer, code
+// comes with the border, the wrapping and glamour's indent.
+func printPlain(a *mcp.AnswerData) {
+	fmt.Println(dimSty.Render(i18n.T("  The answer as Markdown:")))
+	fmt.Println()
+	fmt.Println(strings.TrimRight(html.UnescapeString(a.BodyMarkdown), "\n"))
+	fmt.Println()
+}
+
+// yank copies block i to the clipboard: over SSH, or where no clipboard
+// tool is installed, through the terminal (OSC 52).
+func (c *codeNav) yank(ctx context.Context, i int) {
+	lines := strings.Count(c.blocks[i].Code, "\n") + 1
+	code, note := creditCode(c.credit, c.blocks[i].Code, c.blocks[i].Lang, c.tags)
+	what := fmt.Sprintf(i18n.Plural(lines, "code block %d (%d line)", "code block %d (%d lines)"), i+1, lines)
+	ssh := os.Getenv("SSH_CONNECTION") != "" || os.Getenv("SSH_TTY") != ""
+	if !ssh {
+		cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		err := clipboard.Write(cctx, code)
+		cancel()
+		if err == nil {
+			status(successSty, "  "+i18n.Tf("📋 Copied %s", what), "copied code block", "block", i+1)
+			c.creditNote(note)
+			return
+		}
+		if !errors.Is(err, clipboard.ErrUnavailable) {
+			printError(i18n.T("Could not copy"), err.Error())
+			return
+		}
+	}
+	fmt.Print(clipboard.OSC52(code))
+	status(successSty, "  "+i18n.Tf("📋 Sent %s to the terminal's clipboard", what), "copied code block through the terminal", "block", i+1)
+	c.creditNote(note)
+	fmt.Println(dimSty.Render(i18n.T("  (If nothing was copied, the terminal does not support OSC 52; tmux needs set-clipboard on.)")))
+}
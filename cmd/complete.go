@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chzyer/readline"
+	"github.com/ratnesh-maurya/flo/pkg/config"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+	"github.com/ratnesh-maurya/flo/pkg/taglist"
+	"golang.org/x/term"
+)
+
+// slashCommands are the REPL commands offered when a line starts with
+// "/".
+var slashCommands = []string{"/reset"}
+
+// popularTagCount is how many tags the cached tag list holds.
+const popularTagCount = 1000
+
+// maxCompletions caps the candidates listed for one Tab press, so a
+// single letter does not fill the screen with tags.
+const maxCompletions = 40
+
+// tagSource holds the tags offered for completion: subscriptions first,
+// then the cached list of popular tags.  refreshTagList replaces the
+// list, in the background while the REPL runs.
+type tagSource struct {
+	mu      sync.Mutex
+	popular []string
+	subs    []string
+}
+
+// knownTags is the tag source of the REPL and of shell completion,
+// loaded by loadKnownTags.
+var knownTags tagSource
+
+// tags returns every known tag.
+func (s *tagSource) tags() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return taglist.Merge(s.subs, s.popular)
+}
+
+// tagListPath returns the cached tag list for the configured site.
+func tagListPath() (string, error) {
+	dir, err := config.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return taglist.DefaultPath(dir, tagSite()), nil
+}
+
+// tagSite is the site tags are completed for.
+func tagSite() string {
+	return currentSite()
+}
+
+// loadKnownTags fills knownTags from the subscriptions and the cached
+// tag list.  It returns the list's path, empty when there is none, and
+// whether the list should be refreshed.  Failures are logged:
+// completion is a convenience.
+func loadKnownTags() (path string, stale bool) {
+	if store, err := openSubscriptions(); err == nil {
+		var subs []string
+		for _, s := range store.All() {
+			subs = append(subs, s.Tag)
+		}
+		knownTags.mu.Lock()
+		knownTags.subs = subs
+		knownTags.mu.Unlock()
+	}
+
+	path, err := tagListPath()
+	if err != nil {
+		slog.Debug("tag list unavailable", "err", err)
+		return "", false
+	}
+	list, err := taglist.Load(path)
+	if err != nil {
+		slog.Warn("tag list unreadable", "err", err)
+	}
+	knownTags.mu.Lock()
+	knownTags.popular = list.Tags
+	knownTags.mu.Unlock()
+	return path, list.Stale(time.Now())
+}
+
+// refreshTagList fetches the site's popular tags into the cached list at
+// path and knownTags.  Tags are public, so the API needs no login even
+// with the MCP backend.
+func refreshTagList(ctx context.Context, path string) {
+	hc, err := newHTTPClient(0)
+	if err != nil {
+		slog.Debug("tag list refresh skipped", "err", err)
+		return
+	}
+	rest := provider.NewREST(hc, tagSite(), cfg.API.Key)
+	rest.BaseURL = cfg.API.URL
+
+	fctx, cancel := context.WithTimeout(ctx, cfg.Timeouts.FetchTimeout())
+	defer cancel()
+	tags, err := rest.PopularTags(fctx, popularTagCount)
+	if len(tags) == 0 {
+		slog.Info("tag list refresh failed", "err", err)
+		return
+	}
+	list := &taglist.List{Site: tagSite(), FetchedAt: time.Now(), Tags: tags}
+	if err := list.Save(path); err != nil {
+		slog.Warn("tag list not saved", "err", err)
+	}
+	knownTags.mu.Lock()
+	knownTags.popular = tags
+	knownTags.mu.Unlock()
+	slog.Debug("tag list refreshed", "site", list.Site, "tags", len(tags))
+}
+
+// replCompleter completes the word before the cursor in the REPL: slash
+// commands at the start of the line, tags inside "[...]", and alias
+// names and tags anywhere else.
+type replCompleter struct {
+	tags func() []string
+}
+
+// Do implements readline.AutoCompleter.
+func (c replCompleter) Do(line []rune, pos int) ([][]rune, int) {
+	start := pos
+	for start > 0 && line[start-1] != ' ' {
+		start--
+	}
+	word := string(line[start:pos])
+	if start == 0 {
+		// A refinement (">words") completes like a question.
+		word = strings.TrimPrefix(word, ">")
+	}
+
+	var (
+		candidates []string
+		suffix     = " "
+	)
+	switch {
+	case start == 0 && strings.HasPrefix(word, "/"):
+		candidates = slashCommands
+	case strings.HasPrefix(word, "["):
+		word = word[1:]
+		candidates = c.tags()
+		suffix = "] "
+	case word == "":
+		return nil, 0
+	default:
+		candidates = taglist.Merge(aliasNames(), c.tags())
+	}
+
+	var out [][]rune
+	for _, m := range taglist.Complete(candidates, word) {
+		out = append(out, []rune(m[len(word):]+suffix))
+		if len(out) == maxCompletions {
+			break
+		}
+	}
+	return out, len([]rune(word))
+}
+
+// aliasNames returns the words tagAliases knows, sorted.
+func aliasNames() []string {
+	names := make([]string, 0, len(tagAliases))
+	for w := range tagAliases {
+		names = append(names, w)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// replInput reads REPL lines: through readline, with completion and
+// history, on a terminal; plainly from anything else, so piped
+// questions still work.
+type replInput struct {
+	prompt string
+	rl     *readline.Instance
+	reader *bufio.Reader
+}
+
+// newReplInput returns an input showing prompt.
+func newReplInput(prompt string) *replInput {
+	in := &replInput{prompt: prompt}
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		rl, err := readline.NewEx(&readline.Config{
+			Prompt:          prompt,
+			AutoComplete:    replCompleter{tags: knownTags.tags},
+			Stdout:          bellSkipper{},
+			InterruptPrompt: "^C",
+		})
+		if err == nil {
+			in.rl = rl
+			return in
+		}
+		slog.Warn("line editing disabled", "err", err)
+	}
+	in.reader = bufio.NewReader(os.Stdin)
+	return in
+}
+
+// ReadLine returns the next line, trimmed.  Ctrl+C discards a partly
+// typed line and ends the REPL on an empty one, as Ctrl+D does.
+func (in *replInput) ReadLine() (string, error) {
+	if in.rl == nil {
+		os.Stdout.WriteString(in.prompt)
+		line, err := in.reader.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(line), nil
+	}
+	line, err := in.rl.Readline()
+	if errors.Is(err, readline.ErrInterrupt) {
+		if line == "" {
+			return "", io.EOF
+		}
+		return "", nil
+	}
+	return strings.TrimSpace(line), err
+}
+
+// Close restores the terminal.
+func (in *replInput) Close() {
+	if in.rl != nil {
+		in.rl.Close()
+	}
+}
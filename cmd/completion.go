@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/config"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+	"github.com/ratnesh-maurya/flo/pkg/taglist"
+	"github.com/ratnesh-maurya/flo/pkg/usage"
+	"github.com/spf13/cobra"
+)
+
+// Dynamic values for the shell completion scripts cobra generates
+// (`flo completion bash|zsh|fish|powershell`).  The functions run in
+// the hidden __complete command, which skips the root's pre-run hook, so
+// each loads the config itself.  They must stay quick and quiet: the
+// only network call fetches the tag list when none is cached yet.
+
+// completionFetchTimeout bounds that fetch; a shell waiting on Tab
+// should not hang.
+const completionFetchTimeout = 3 * time.Second
+
+// recentQuestionCompletions is how many recently viewed questions are
+// offered.
+const recentQuestionCompletions = 20
+
+// loadCompletionConfig loads the config file named by --config, so
+// completions see the configured site and API settings.
+func loadCompletionConfig(cmd *cobra.Command) {
+	if selectConfig() != nil {
+		return
+	}
+	if loaded, err := config.Load(configPath); err == nil {
+		cfg = loaded
+		applyFlagOverrides(cmd)
+	}
+}
+
+// completeProfiles completes --profile with the profiles created so far.
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	names, _ := config.Profiles()
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSites completes --site with the sites flo knows by name.
+func completeSites(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	var sites []cobra.Completion
+	for _, s := range provider.Sites {
+		name := s.Language
+		if s.Param == provider.DefaultSite {
+			name = s.Param
+		}
+		sites = append(sites, cobra.CompletionWithDesc(name, s.Name))
+	}
+	return sites, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTags completes tags: subscribed ones first, then the cached
+// popular ones.  It also serves comma-separated flags such as
+// `flo digest --tags go,ku<Tab>`, and skips tags already given.
+func completeTags(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	loadCompletionConfig(cmd)
+	if path, stale := loadKnownTags(); path != "" && stale && len(knownTags.tags()) == 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), completionFetchTimeout)
+		refreshTagList(ctx, path)
+		cancel()
+	}
+
+	done := make(map[string]bool)
+	for _, a := range args {
+		done[strings.ToLower(a)] = true
+	}
+	head := ""
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		head, toComplete = toComplete[:i+1], toComplete[i+1:]
+		for _, t := range strings.Split(head, ",") {
+			done[strings.ToLower(t)] = true
+		}
+	}
+
+	var out []cobra.Completion
+	for _, t := range taglist.Complete(knownTags.tags(), toComplete) {
+		if !done[t] {
+			out = append(out, head+t)
+		}
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSubscribedTags completes the tags the user follows.
+func completeSubscribedTags(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	store, err := openSubscriptions()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	done := make(map[string]bool)
+	for _, a := range args {
+		done[strings.ToLower(a)] = true
+	}
+	var out []cobra.Completion
+	for _, s := range store.All() {
+		if !done[s.Tag] && strings.HasPrefix(s.Tag, toComplete) {
+			out = append(out, s.Tag)
+		}
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRecentQuestions completes the IDs of the questions viewed
+// most recently, newest first, described by their titles.  It reads the
+// usage log behind `flo stats`, so it offers nothing when stats are
+// disabled.
+func completeRecentQuestions(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	path, err := usagePath()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	events, err := usage.Load(path)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// Answer events carry no title; the question's own event does.
+	titles := make(map[int]string)
+	for _, ev := range events {
+		if ev.Title != "" {
+			titles[ev.QuestionID] = ev.Title
+		}
+	}
+	seen := make(map[int]bool)
+	var out []cobra.Completion
+	for i := len(events) - 1; i >= 0 && len(out) < recentQuestionCompletions; i-- {
+		ev := events[i]
+		if ev.QuestionID == 0 || seen[ev.QuestionID] {
+			continue
+		}
+		seen[ev.QuestionID] = true
+		id := strconv.Itoa(ev.QuestionID)
+		if strings.HasPrefix(id, toComplete) {
+			out = append(out, cobra.CompletionWithDesc(id, titles[ev.QuestionID]))
+		}
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
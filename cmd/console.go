@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/manifoldco/promptui"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+)
+
+// selectTemplates are the promptui templates shared by every picker.
+// Plain consoles get them without colour or symbols.
+func selectTemplates() *promptui.SelectTemplates {
+	if ui.Plain() {
+		return &promptui.SelectTemplates{
+			Label:    "{{ . }}",
+			Active:   "> {{ . }}",
+			Inactive: "  {{ . }}",
+			Selected: "> {{ . }}",
+		}
+	}
+	return &promptui.SelectTemplates{
+		Label:    "{{ . }}",
+		Active:   "▸ {{ . | cyan }}",
+		Inactive: "  {{ . }}",
+		Selected: "▸ {{ . | green }}",
+	}
+}
+
+// bellSkipper is the output for promptui pickers.  readline rings the
+// terminal bell on every key that does not change the line, which Windows
+// consoles (and some macOS terminals) sound for each arrow key press.
+type bellSkipper struct{}
+
+func (bellSkipper) Write(b []byte) (int, error) {
+	if len(b) == 1 && b[0] == '\a' {
+		return 0, nil
+	}
+	return os.Stdout.Write(b)
+}
+
+func (bellSkipper) Close() error {
+	return nil
+}
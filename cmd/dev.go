@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/mockserver"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// mockFixtures is the --fixtures flag of `flo dev mock`.
+	mockFixtures string
+	// mockLatency is the --latency flag of `flo dev mock`.
+	mockLatency time.Duration
+	// mockExpireAfter is the --expire-after flag of `flo dev mock`.
+	mockExpireAfter int
+)
+
+var devCmd = &cobra.Command{
+	Use:   "dev",
+	Short: "Tools for working on flo itself",
+}
+
+var devMockCmd = &cobra.Command{
+	Use:   "mock [url]",
+	Short: "Run a mock MCP server with canned Stack Overflow fixtures",
+	Long: `Serve so_search and get_content over stdio from canned questions, so
+flo can be developed and tested offline, without an OAuth login.  It is
+meant to be started as the bridge command:
+
+  flo --mcp-cmd "flo dev mock"
+  flo --mcp-cmd "flo dev mock --fixtures my.json --latency 500ms"
+
+Queries containing "empty" return no results.  --expire-after N refuses
+every tool call after the first N with HTTP 401, as a server does once
+the login expires, until flo restarts the bridge.  The url argument, which
+flo appends to bridge commands, is ignored.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDevMock,
+}
+
+func init() {
+	devMockCmd.Flags().StringVar(&mockFixtures, "fixtures", "", "JSON file of questions to serve instead of the built-in ones")
+	devMockCmd.Flags().DurationVar(&mockLatency, "latency", 0, "delay every tool call by this long")
+	devMockCmd.Flags().IntVar(&mockExpireAfter, "expire-after", 0, "refuse tool calls after this many, as if the login expired")
+	devCmd.AddCommand(devMockCmd)
+	rootCmd.AddCommand(devCmd)
+}
+
+// runDevMock implements `flo dev mock`.  Stdout carries the protocol, so
+// nothing else may be printed there.
+func runDevMock(cmd *cobra.Command, args []string) error {
+	opts := mockserver.Options{Latency: mockLatency, ExpireAfter: mockExpireAfter}
+	if mockFixtures != "" {
+		data, err := os.ReadFile(mockFixtures)
+		if err != nil {
+			return err
+		}
+		if opts.Fixtures, err = mockserver.ParseFixtures(data); err != nil {
+			return err
+		}
+	}
+	srv, err := mockserver.New(opts)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "flo mock MCP server ready on stdio")
+	return srv.Serve(cmd.Context(), os.Stdin, os.Stdout)
+}
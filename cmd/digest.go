@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/config"
+	"github.com/ratnesh-maurya/flo/pkg/digest"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+	"github.com/ratnesh-maurya/flo/pkg/subscriptions"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	digestTags     []string
+	digestSince    string
+	digestMinScore int
+	digestLimit    int
+	digestOutput   string
+	digestKeep     bool
+)
+
+// Digest defaults, when neither a flag nor the config sets them.
+const (
+	defaultDigestMinScore = 10
+	defaultDigestLimit    = 10
+	defaultDigestPeriod   = 7 * 24 * time.Hour
+)
+
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Report the new high-scoring questions in your tags since the last digest",
+	Long: `Compile the questions asked in your tags since the last digest that
+already score well into one report — a Monday-morning catch-up.
+
+  flo digest                       your subscribed tags (see flo tags)
+  flo digest --tags go,rust        these tags instead
+  flo digest --since 30d -o digest.md
+
+The first digest covers the last 7 days; each one then starts where the
+previous ended (--keep leaves that point where it was).  --since takes a
+duration in days or hours ("14d", "36h") or a date ("2024-05-01").
+With --output the report is written as Markdown instead of shown.`,
+	Args: cobra.NoArgs,
+	RunE: runDigest,
+}
+
+func init() {
+	digestCmd.Flags().StringSliceVar(&digestTags, "tags", nil, "tags to cover, comma-separated (default: your subscribed tags)")
+	digestCmd.Flags().StringVar(&digestSince, "since", "", "start of the period: a duration (14d, 36h) or a date (default: the last digest)")
+	digestCmd.Flags().IntVar(&digestMinScore, "min-score", 0, "lowest question score listed (default digest.min_score, or 10)")
+	digestCmd.Flags().IntVarP(&digestLimit, "limit", "n", 0, "most questions listed per tag (default digest.limit, or 10)")
+	digestCmd.Flags().StringVarP(&digestOutput, "output", "o", "", "write the report as Markdown to this file")
+	digestCmd.Flags().BoolVar(&digestKeep, "keep", false, "do not record this digest as the last one")
+	_ = digestCmd.RegisterFlagCompletionFunc("tags", completeTags)
+	rootCmd.AddCommand(digestCmd)
+}
+
+// runDigest implements `flo digest`.
+func runDigest(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	subs, err := digestSubscriptions()
+	if err != nil {
+		printError("Digest failed", err.Error())
+		return err
+	}
+	if len(subs) == 0 {
+		return fmt.Errorf("no tags to cover: run flo tags subscribe go, or pass --tags go,rust")
+	}
+	minScore := firstPositive(digestMinScore, cfg.Digest.MinScore, defaultDigestMinScore)
+	limit := firstPositive(digestLimit, cfg.Digest.Limit, defaultDigestLimit)
+
+	statePath, err := digestStatePath()
+	if err != nil {
+		printError("Digest failed", err.Error())
+		return err
+	}
+	state, err := digest.LoadState(statePath)
+	if err != nil {
+		slog.Warn("digest state unreadable; starting over", "err", err)
+	}
+	now := time.Now()
+	since := state.LastRun
+	if digestSince != "" {
+		if since, err = parseSince("--since", digestSince, now); err != nil {
+			return err
+		}
+	} else if since.IsZero() {
+		since = now.Add(-defaultDigestPeriod)
+	}
+
+	p, release, err := connect(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	status(spinnerSty, fmt.Sprintf("📰 Collecting questions since %s...", since.Format("Jan 2 15:04")), "collecting digest",
+		"tags", len(subs), "since", since, "min_score", minScore)
+	d := &digest.Digest{Since: since, Until: now, MinScore: minScore}
+	failed := 0
+	for _, sub := range subs {
+		s := digest.Section{Tag: sub.Tag, Options: sub.Options()}
+		fetch := limit
+		if sub.UnansweredOnly {
+			fetch = 3 * limit // most high scorers are answered
+		}
+		tctx, cancel := context.WithTimeout(ctx, cfg.Timeouts.SearchTimeout())
+		resp, err := provider.NewQuestions(tctx, p, sub.Tag, since, firstPositive(sub.MinScore, minScore), fetch)
+		cancel()
+		switch {
+		case err == nil:
+			for i := range resp.Items {
+				if len(s.Questions) < limit && sub.Matches(&resp.Items[i], minScore) {
+					s.Questions = append(s.Questions, resp.Items[i])
+				}
+			}
+		case errors.Is(err, provider.ErrNotFound):
+		case ctx.Err() != nil:
+			return ctx.Err()
+		default:
+			slog.Warn("digest tag failed", "tag", s.Tag, "err", err)
+			s.Err = err
+			failed++
+		}
+		d.Sections = append(d.Sections, s)
+	}
+
+	if digestOutput != "" {
+		if err := os.WriteFile(expandHome(digestOutput), []byte(d.Markdown()), 0o644); err != nil {
+			printError("Digest failed", err.Error())
+			return err
+		}
+		fmt.Println(successSty.Render(fmt.Sprintf("✅ Wrote %d questions to %s", d.Len(), digestOutput)))
+	} else {
+		printDoc(ui.Document{Markdown: d.Markdown(), Data: d})
+	}
+
+	if digestKeep || failed == len(subs) {
+		return nil
+	}
+	if err := (digest.State{LastRun: now}).Save(statePath); err != nil {
+		slog.Warn("save digest state", "err", err)
+	}
+	return nil
+}
+
+// digestSubscriptions returns the tags a digest covers: those given
+// with --tags, else the subscribed tags, else digest.tags.
+func digestSubscriptions() ([]*subscriptions.Subscription, error) {
+	tags := digestTags
+	if len(tags) == 0 {
+		store, err := openSubscriptions()
+		if err != nil {
+			return nil, err
+		}
+		if subs := store.All(); len(subs) > 0 {
+			return subs, nil
+		}
+		tags = cfg.Digest.Tags
+	}
+	subs := make([]*subscriptions.Subscription, 0, len(tags))
+	for _, t := range tags {
+		tag, err := subscriptions.NormalizeTag(t)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, &subscriptions.Subscription{Tag: tag})
+	}
+	return subs, nil
+}
+
+// firstPositive returns the first of values above zero.
+func firstPositive(values ...int) int {
+	for _, v := range values {
+		if v > 0 {
+			return v
+		}
+	}
+	return 0
+}
+
+// parseSince reads a point in time given as an age — a number of days
+// ("14d") or a Go duration ("36h") — or a date ("2024-05-01", local
+// time); flag names the option in errors.
+func parseSince(flag, s string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid %s %q (want e.g. 14d, 36h or 2024-05-01)", flag, s)
+}
+
+// digestStatePath returns the location of the digest state.
+func digestStatePath() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return digest.DefaultStatePath(dir), nil
+}
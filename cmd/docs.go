@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Generate man pages and a CLI reference from flo's commands",
+	Long: `Write the documentation of every command, its flags and examples, as
+generated from the commands themselves, so it never drifts from --help.
+Packagers ship the man pages; releases include them.
+
+  flo docs man              man pages (flo.1, flo-ask.1, ...) into ./man
+  flo docs markdown docs/   one Markdown page per command into docs/
+
+Man pages are dated by SOURCE_DATE_EPOCH when it is set, and by the
+current month otherwise, so a release build is reproducible.`,
+}
+
+var docsManCmd = &cobra.Command{
+	Use:   "man [dir]",
+	Short: "Write a man page for each command (default dir ./man)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := docsDir(args, "man")
+		if err != nil {
+			return err
+		}
+		header := &doc.GenManHeader{
+			Title:   "FLO",
+			Section: "1",
+			Source:  "flo " + version,
+			Manual:  "flo manual",
+		}
+		if err := doc.GenManTree(docsRoot(), header, dir); err != nil {
+			return err
+		}
+		fmt.Println(successSty.Render("✓ Man pages written to " + dir))
+		return nil
+	},
+}
+
+var docsMarkdownCmd = &cobra.Command{
+	Use:   "markdown [dir]",
+	Short: "Write a Markdown page for each command (default dir ./docs/cli)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := docsDir(args, "docs/cli")
+		if err != nil {
+			return err
+		}
+		if err := doc.GenMarkdownTree(docsRoot(), dir); err != nil {
+			return err
+		}
+		fmt.Println(successSty.Render("✓ CLI reference written to " + dir))
+		return nil
+	},
+}
+
+func init() {
+	docsCmd.AddCommand(docsManCmd, docsMarkdownCmd)
+	rootCmd.AddCommand(docsCmd)
+}
+
+// docsDir returns the directory named by args, or def, creating it.
+func docsDir(args []string, def string) (string, error) {
+	dir := def
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// docsRoot returns the root command set up for generating docs: without
+// cobra's "Auto generated ... on <date>" footer, which would make every
+// build's pages differ.
+func docsRoot() *cobra.Command {
+	rootCmd.DisableAutoGenTag = true
+	return rootCmd
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/config"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+	"github.com/spf13/cobra"
+)
+
+// doctorReset is the --reset-circuits flag of `flo doctor`.
+var doctorReset bool
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check flo's setup and show the state of each backend",
+	Long: `Report the config in use, the backend and what it fails over to, whether
+the MCP bridge can run and is logged in, and each backend's circuit.
+
+A backend's circuit opens after failover.threshold consecutive failures:
+flo then goes straight to the next backend, trying the failing one again
+once failover.cooldown has passed.  --reset-circuits closes them all.`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorReset, "reset-circuits", false, "close every circuit, so failing backends are tried again at once")
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// runDoctor implements `flo doctor`.
+func runDoctor(cmd *cobra.Command, args []string) error {
+	row := func(name, value string) {
+		fmt.Printf("  %-12s %s\n", name, value)
+	}
+	primary, err := primaryBackend()
+	if err != nil {
+		printError("Invalid configuration", err.Error())
+		return err
+	}
+
+	fmt.Println(promptSty.Render("Setup"))
+	switch path := configPath; {
+	case path != "":
+		row("Config", path)
+	default:
+		if path, err := config.DefaultPath(); err == nil {
+			row("Config", path)
+		}
+	}
+	if config.Profile != "" {
+		row("Profile", config.Profile)
+	}
+	if cfg.Anonymous {
+		row("Mode", "anonymous: no login; gists, sync and shared cache writes are off")
+	}
+	if wd, err := os.Getwd(); err == nil {
+		if project := config.FindProject(wd); project != "" {
+			row("Project", project)
+		}
+	}
+	chain := append([]string{primary}, cfg.Failover.FailoverBackends(primary)...)
+	row("Backend", strings.Join(chain, " → "))
+	if site := currentSite(); site != provider.DefaultSite {
+		row("Site", site)
+	}
+	if primary == config.BackendMCP {
+		row("MCP bridge", bridgeStatus())
+		if cfg.MCP.Command == "" {
+			row("mcp-remote", remoteStatus())
+		}
+		if h, why := headless(); h {
+			row("Login", fmt.Sprintf("from another device, pasting back the address (%s)", why))
+		} else {
+			row("Login", "in a browser on this machine")
+		}
+	}
+	if primary == config.BackendLocal {
+		if idx, err := openIndex(); err != nil {
+			row("Local index", "unreadable: "+err.Error())
+		} else {
+			row("Local index", fmt.Sprintf("%d posts", idx.Len()))
+		}
+	}
+	if primary == config.BackendAPI || slices.Contains(chain, config.FallbackAPI) || !cfg.API.NoAnswerPaging {
+		row("API quota", quotaStatus())
+	}
+	if dir, err := config.CacheDir(); err == nil {
+		row("Cache", dir)
+	}
+
+	fmt.Println()
+	fmt.Println(promptSty.Render("Circuits"))
+	circuits := openCircuits()
+	if circuits == nil {
+		fmt.Println(dimSty.Render("  Failover is disabled (failover.disabled in the config)."))
+		return nil
+	}
+	if doctorReset {
+		if err := circuits.Reset(); err != nil {
+			printError("Could not reset the circuits", err.Error())
+			return err
+		}
+		status(successSty, "  All circuits closed.", "circuits reset")
+		return nil
+	}
+	now := time.Now()
+	for _, name := range chain {
+		if name == config.FallbackCache {
+			continue
+		}
+		st := circuits.State(name)
+		switch {
+		case st.Open():
+			line := fmt.Sprintf("open since %s after %d failures", st.OpenedAt.Local().Format(time.Kitchen), st.Failures)
+			if next := st.NextProbe(circuits.Cooldown); next.After(now) {
+				line += fmt.Sprintf("; next try in %s", next.Sub(now).Round(time.Second))
+			} else {
+				line += "; tried again on the next request"
+			}
+			row(name, spinnerSty.Render(line))
+		case st.Failures > 0:
+			row(name, fmt.Sprintf("closed, %d of %d failures", st.Failures, circuits.Threshold))
+		default:
+			row(name, successSty.Render("closed"))
+		}
+		if st.LastError != "" {
+			row("", dimSty.Render(fmt.Sprintf("last error %s ago: %s", now.Sub(st.FailedAt).Round(time.Second), st.LastError)))
+		}
+	}
+	return nil
+}
+
+// remoteStatus describes where the default bridge's mcp-remote comes
+// from.
+func remoteStatus() string {
+	if inst := managedRemote(); inst != nil {
+		return inst.Version + ", installed by flo setup"
+	}
+	version := remoteVersion()
+	switch {
+	case mcp.RemoteCached(version):
+		return version + " through npx"
+	default:
+		return spinnerSty.Render(version + " through npx, not downloaded yet (run flo setup)")
+	}
+}
+
+// bridgeStatus describes whether the MCP bridge command can be run and
+// has been logged in.
+func bridgeStatus() string {
+	opts := mcpOptions()
+	argv, err := mcp.BridgeCommand(opts)
+	if err == nil {
+		_, err = cfg.MCP.Remote.BridgeArgs()
+	}
+	if err != nil {
+		return spinnerSty.Render(err.Error())
+	}
+	if _, err := exec.LookPath(argv[0]); err != nil {
+		return spinnerSty.Render(fmt.Sprintf("%s not found (install Node.js, or set backend: api)", argv[0]))
+	}
+	if mcp.NeedsLogin(opts) {
+		return "not logged in yet (the first search starts the login)"
+	}
+	return successSty.Render("ready")
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+)
+
+// maxDuplicateHops bounds how far a chain of duplicates is followed.
+const maxDuplicateHops = 5
+
+// resolveDuplicate follows q's duplicate chain to the canonical question
+// and returns it with q's answers merged in, noting the redirect.  It
+// returns q itself when q is not a duplicate or the original cannot be
+// fetched.
+func resolveDuplicate(parent context.Context, p provider.Provider, q *mcp.QuestionData) *mcp.QuestionData {
+	cur := q
+	seen := map[int]bool{q.QuestionID: true}
+	for hop := 0; hop < maxDuplicateHops; hop++ {
+		ids := mcp.DuplicateOf(cur)
+		if len(ids) == 0 || seen[ids[0]] {
+			break
+		}
+		seen[ids[0]] = true
+		ctx, cancel := context.WithTimeout(parent, cfg.Timeouts.FetchTimeout())
+		orig, err := p.GetQuestion(ctx, ids[0])
+		cancel()
+		if err != nil {
+			slog.Warn("fetch duplicate original failed", "question_id", cur.QuestionID, "original", ids[0], "err", err)
+			break
+		}
+		slog.Info("followed duplicate", "from", cur.QuestionID, "to", orig.QuestionID)
+		mcp.MergeAnswers(orig, cur)
+		cur = orig
+	}
+	if cur == q {
+		return q
+	}
+	fmt.Println(dimSty.Render(i18n.Tf("  ↪ %q was closed as a duplicate; showing the original question, with its answers merged in.",
+		html.UnescapeString(q.Title))))
+	return cur
+}
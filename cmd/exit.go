@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+
+	"github.com/ratnesh-maurya/flo/pkg/config"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+)
+
+// Exit codes.  They are part of flo's interface for scripts (see the
+// README); never renumber them.
+const (
+	exitOK      = 0
+	exitFailure = 1 // anything not covered below
+	// exitNoResults: the search or lookup found nothing to show.
+	exitNoResults = 2
+	// exitConnection: the backend could not be reached, or timed out.
+	exitConnection = 3
+	// exitAuthRequired: the backend needs a login flo may not start, or
+	// refused the credentials.
+	exitAuthRequired = 4
+	// exitParse: a server reply or a config file could not be parsed.
+	exitParse = 5
+	// exitInputRequired: the command needs input flo may not ask for.
+	exitInputRequired = 6
+	// exitUsage: unknown command or flag, or bad arguments.
+	exitUsage = 7
+	// exitInterrupted: Ctrl+C or SIGTERM (128 + SIGINT, as shells use).
+	exitInterrupted = 130
+)
+
+// exitError carries the process exit code for err.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// withExitCode makes err exit the process with code.
+func withExitCode(code int, err error) error {
+	return &exitError{code, err}
+}
+
+// errNoResults is returned, after the user has been told, when there is
+// nothing to show.
+var errNoResults = withExitCode(exitNoResults, errors.New("no results"))
+
+// ExitCode returns the process exit code for an error returned by
+// Execute: the code attached with withExitCode, else one derived from
+// the kind of failure.
+func ExitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	var (
+		coded     *exitError
+		apiErr    *provider.APIError
+		netErr    net.Error
+		parseErr  *config.ParseError
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.As(err, &coded):
+		return coded.code
+	case errors.Is(err, context.Canceled):
+		return exitInterrupted
+	case errors.Is(err, provider.ErrNotFound):
+		return exitNoResults
+	case errors.As(err, &apiErr) && apiErr.Auth(), errors.Is(err, mcp.ErrUnauthorized):
+		return exitAuthRequired
+	case errors.Is(err, provider.ErrConnectionLost), errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr):
+		return exitConnection
+	case errors.As(err, &parseErr), errors.As(err, &syntaxErr), errors.As(err, &typeErr), errors.Is(err, mcp.ErrUnexpectedShape):
+		return exitParse
+	}
+	return exitFailure
+}
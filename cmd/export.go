@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ratnesh-maurya/flo/pkg/export"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportAnki  string
+	exportVault string
+	exportHTML  string
+	exportTitle string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export bookmarks to other tools",
+	Long: `Export saved answers (see "flo bookmarks") to other tools.
+
+  flo export --anki deck.txt   Anki flashcards: question front, answer back
+  flo export --vault ~/notes   one Markdown note per answer (Obsidian etc.)
+  flo export --html out/       a static site: an index by tag and one page
+                               per answer, to host as a team FAQ
+
+The Anki file uses Anki's text import format (File > Import); fields are
+HTML and SO tags become "so::<tag>" Anki tags.
+
+Vault notes carry YAML frontmatter (title, url, tags, date, score) and are
+named after the question, so re-exporting updates them in place. With
+export.vault_dir set in the config file, a plain "flo export" writes
+there.
+
+The HTML pages render the question, the answer and your note, with the
+attribution the license requires; they need no server-side code.
+
+"flo bookmarks export" is the same command.`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+// bookmarksExportCmd is exportCmd under `flo bookmarks`.
+var bookmarksExportCmd = &cobra.Command{
+	Use:   exportCmd.Use,
+	Short: exportCmd.Short,
+	Long:  exportCmd.Long,
+	Args:  cobra.NoArgs,
+	RunE:  runExport,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{exportCmd, bookmarksExportCmd} {
+		c.Flags().StringVar(&exportAnki, "anki", "", "write Anki flashcards (text import format) to this file")
+		c.Flags().StringVar(&exportVault, "vault", "", "write Markdown notes with frontmatter into this directory")
+		c.Flags().StringVar(&exportHTML, "html", "", "write a static HTML site into this directory")
+		c.Flags().StringVar(&exportTitle, "title", "Saved answers", "title of the HTML site's index page")
+	}
+	bookmarksCmd.AddCommand(bookmarksExportCmd)
+	rootCmd.AddCommand(exportCmd)
+}
+
+// runExport implements `flo export`.
+func runExport(cmd *cobra.Command, args []string) error {
+	if exportAnki == "" && exportVault == "" && exportHTML == "" {
+		exportVault = cfg.Export.VaultDir
+	}
+	if exportAnki == "" && exportVault == "" && exportHTML == "" {
+		return fmt.Errorf("choose an export format, e.g. --anki deck.txt, --vault ~/notes or --html out/")
+	}
+
+	store, err := openBookmarks()
+	if err != nil {
+		printError("Export failed", err.Error())
+		return err
+	}
+	items := store.All()
+	if len(items) == 0 {
+		printError("Nothing to export", "You have no bookmarks yet — press ["+keyName(actSave)+"] after viewing an answer to save it.")
+		return nil
+	}
+
+	if exportVault != "" {
+		written, err := export.WriteVault(expandHome(exportVault), items)
+		if err != nil {
+			printError("Export failed", err.Error())
+			return err
+		}
+		fmt.Println(successSty.Render(fmt.Sprintf("✅ Wrote %d notes to %s", len(written), exportVault)))
+	}
+	if exportHTML != "" {
+		if _, err := export.WriteSite(expandHome(exportHTML), exportTitle, items); err != nil {
+			printError("Export failed", err.Error())
+			return err
+		}
+		index := filepath.Join(exportHTML, "index.html")
+		fmt.Println(successSty.Render(fmt.Sprintf("✅ Wrote %d pages and %s", len(items), index)))
+	}
+	if exportAnki == "" {
+		return nil
+	}
+
+	if strings.EqualFold(filepath.Ext(exportAnki), ".apkg") {
+		err := fmt.Errorf("writing .apkg packages is not supported; use a .txt or .csv file and import it via File > Import in Anki")
+		printError("Export failed", err.Error())
+		return err
+	}
+
+	f, err := os.Create(exportAnki)
+	if err != nil {
+		printError("Export failed", err.Error())
+		return err
+	}
+	if err := export.WriteAnki(f, items); err != nil {
+		f.Close()
+		printError("Export failed", err.Error())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		printError("Export failed", err.Error())
+		return err
+	}
+
+	fmt.Println(successSty.Render(fmt.Sprintf("✅ Wrote %d flashcards to %s", len(items), exportAnki)))
+	return nil
+}
+
+// expandHome replaces a leading "~" with the user's home directory, for
+// paths that come from the config file rather than the shell.
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+)
+
+// findContext is the number of lines shown either side of a match.
+const findContext = 2
+
+// findInText searches rendered text (an answer as printed) for term and
+// steps through the lines matching it with the find_next and find_prev
+// keys (n and N), showing each with a few lines around it and the
+// matches highlighted.  The find key and a new term (/term) searches
+// again; anything else ends the search.  As in less and vim,
+// the search ignores case unless term has upper-case letters.
+func findInText(rendered, term string) {
+	lines := strings.Split(ui.StripEscapes(rendered), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(strings.Trim(l, "│ "), " ")
+	}
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		re := findPattern(term)
+		var hits []int
+		for i, l := range lines {
+			if re.MatchString(l) {
+				hits = append(hits, i)
+			}
+		}
+		if len(hits) == 0 {
+			fmt.Println(dimSty.Render(i18n.Tf("  No matches for %q.", term)))
+			return
+		}
+
+		cur := 0
+	step:
+		for {
+			showMatch(lines, hits[cur], re)
+			fmt.Println(dimSty.Render(i18n.Tf("  Match %d of %d", cur+1, len(hits)) + "  |" +
+				keyHints(hint{actFindNext, "next"}, hint{actFindPrev, "previous"}) +
+				"  |  " + findHint("find another") + "  |  [Enter] " + i18n.T("done")))
+			input, _ := reader.ReadString('\n')
+			input = strings.TrimSpace(input)
+			again, isFind := typedFind(input)
+			switch {
+			case input == findKey(actFindNext):
+				cur = (cur + 1) % len(hits)
+				if cur == 0 {
+					fmt.Println(dimSty.Render(i18n.T("  Back at the first match.")))
+				}
+			case input == findKey(actFindPrev):
+				cur = (cur + len(hits) - 1) % len(hits)
+				if cur == len(hits)-1 {
+					fmt.Println(dimSty.Render(i18n.T("  Back at the last match.")))
+				}
+			case isFind:
+				term = again
+				break step
+			default:
+				return
+			}
+		}
+	}
+}
+
+// typedFind returns the word searched for when input is the find key
+// followed by one, as in /word.
+func typedFind(input string) (string, bool) {
+	key := findKey(actFind)
+	if key == "" {
+		return "", false
+	}
+	term, ok := strings.CutPrefix(strings.TrimSpace(input), key)
+	term = strings.TrimSpace(term)
+	return term, ok && term != ""
+}
+
+// findPattern matches term literally, ignoring case unless term has
+// upper-case letters.
+func findPattern(term string) *regexp.Regexp {
+	expr := regexp.QuoteMeta(term)
+	if strings.ToLower(term) == term {
+		expr = "(?i)" + expr
+	}
+	return regexp.MustCompile(expr)
+}
+
+// showMatch prints line at with findContext lines either side, the
+// matches of re highlighted and the line itself marked.
+func showMatch(lines []string, at int, re *regexp.Regexp) {
+	fmt.Println()
+	for i := max(at-findContext, 0); i <= min(at+findContext, len(lines)-1); i++ {
+		if i != at {
+			fmt.Println(dimSty.Render(fmt.Sprintf("  %4d  %s", i+1, lines[i])))
+			continue
+		}
+		line := re.ReplaceAllStringFunc(lines[i], func(m string) string {
+			if ui.Plain() {
+				return "[" + m + "]"
+			}
+			return matchSty.Render(m)
+		})
+		fmt.Printf("%s  %s\n", promptSty.Render(ui.PlainText(fmt.Sprintf("▸ %4d", i+1))), line)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/ratnesh-maurya/flo/pkg/export"
+	"github.com/ratnesh-maurya/flo/pkg/gist"
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+)
+
+// gistTimeout bounds publishing a gist.
+const gistTimeout = 30 * time.Second
+
+// langExts maps code fence languages to file extensions, so gists are
+// highlighted by GitHub.
+var langExts = map[string]string{
+	"go": "go", "python": "py", "javascript": "js", "typescript": "ts",
+	"java": "java", "c": "c", "cpp": "cpp", "c++": "cpp", "csharp": "cs", "c#": "cs",
+	"ruby": "rb", "rust": "rs", "php": "php", "swift": "swift", "kotlin": "kt",
+	"bash": "sh", "sh": "sh", "shell": "sh", "powershell": "ps1",
+	"sql": "sql", "json": "json", "yaml": "yaml", "html": "html", "css": "css",
+	"xml": "xml", "dockerfile": "Dockerfile",
+}
+
+// reSlug matches runs of characters not allowed in a gist file name.
+var reSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// gistToken returns the configured GitHub token, falling back to the
+// environment variables the gh CLI reads.
+func gistToken() string {
+	if cfg.Gist.Token != "" {
+		return cfg.Gist.Token
+	}
+	if t := os.Getenv("GH_TOKEN"); t != "" {
+		return t
+	}
+	return os.Getenv("GITHUB_TOKEN")
+}
+
+// publishGist asks which of a's code blocks to share — or the whole
+// question and answer — publishes it as a gist and prints the URL.
+func publishGist(ctx context.Context, q *mcp.QuestionData, a *mcp.AnswerData) {
+	if err := requireAccount("Publishing gists"); err != nil {
+		printError(i18n.T("Gists unavailable"), err.Error())
+		return
+	}
+	token := gistToken()
+	if token == "" {
+		printError(i18n.T("No GitHub token"), i18n.T("Set gist.token in the config, or GH_TOKEN / GITHUB_TOKEN, to a token with the gist scope."))
+		return
+	}
+
+	blocks := mcp.CodeBlocks(a.BodyMarkdown)
+	choice := len(blocks) // the whole Q&A
+	if len(blocks) > 0 {
+		items := make([]string, 0, len(blocks)+1)
+		for i, b := range blocks {
+			first := []rune(strings.TrimSpace(strings.SplitN(strings.TrimSpace(b.Code), "\n", 2)[0]))
+			if len(first) > 60 {
+				first = append(first[:57], []rune("...")...)
+			}
+			items = append(items, fmt.Sprintf("Code block %d: %s", i+1, string(first)))
+		}
+		items = append(items, "Whole question and answer (Markdown)")
+		sel := promptui.Select{
+			Label:     "Publish as a gist",
+			Items:     items,
+			Size:      min(len(items), 10),
+			Templates: selectTemplates(),
+			HideHelp:  ui.Plain(),
+			Stdout:    bellSkipper{},
+			Stdin:     pickerStdin(),
+		}
+		var err error
+		if choice, _, err = sel.Run(); err != nil {
+			return
+		}
+	}
+
+	title := html.UnescapeString(q.Title)
+	slug := strings.Trim(reSlug.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if len(slug) > 50 {
+		slug = strings.TrimRight(slug[:50], "-")
+	}
+	if slug == "" {
+		slug = "answer"
+	}
+	bm := bookmarkOf(q, a)
+	credit := export.CreditOf(&bm)
+	g := gist.Gist{
+		Description: fmt.Sprintf("%s — %s", title, answerLink(q, a)),
+		Public:      cfg.Gist.Public,
+		Files:       make(map[string]string),
+	}
+	if export.Attribute {
+		g.Description = title + " — " + credit.Text()
+	}
+	if choice < len(blocks) {
+		b := blocks[choice]
+		lang := b.Lang
+		if lang == "" {
+			lang = ui.CodeLanguage(b.Code, q.Tags)
+		}
+		ext, ok := langExts[strings.ToLower(lang)]
+		if !ok {
+			ext = "txt"
+		}
+		g.Files[slug+"."+ext] = b.Code + "\n"
+	} else {
+		md := mcp.FormatQuestionHeader(q) + "\n" + mcp.FormatSingleAnswer(a)
+		if export.Attribute {
+			md += "\n---\n\n" + credit.Markdown() + "\n"
+		}
+		g.Files[slug+".md"] = md
+	}
+
+	hc, err := newHTTPClient(gistTimeout)
+	if err != nil {
+		printError(i18n.T("Could not publish gist"), err.Error())
+		return
+	}
+	client := gist.New(hc, token)
+	if cfg.Gist.APIURL != "" {
+		client.APIURL = cfg.Gist.APIURL
+	}
+	status(spinnerSty, i18n.T("📤 Publishing gist..."), "publishing gist", "question_id", q.QuestionID, "answer_id", a.AnswerID)
+	gctx, cancel := context.WithTimeout(ctx, gistTimeout)
+	defer cancel()
+	url, err := client.Create(gctx, g)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			printError(i18n.T("Gist timed out"), i18n.T("GitHub did not answer in time. Try again."))
+			return
+		}
+		printError(i18n.T("Could not publish gist"), err.Error())
+		return
+	}
+	fmt.Println(successSty.Render("🔗 " + url))
+}
+
+// answerLink is the URL of a, or of its question when the answer has
+// none.
+func answerLink(q *mcp.QuestionData, a *mcp.AnswerData) string {
+	if a.Link != "" {
+		return a.Link
+	}
+	if a.AnswerID != 0 {
+		return postURL("a", a.AnswerID)
+	}
+	return q.Link
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+	"golang.org/x/term"
+)
+
+// maxImageBytes caps the size of an image downloaded for a thumbnail.
+const maxImageBytes = 5 << 20
+
+// imageProtocol returns the inline image protocol to draw thumbnails
+// with, or "" to only list image links.  display.images selects it:
+// "auto" (the default) detects the terminal, "off" disables
+// thumbnails, and kitty, iterm or sixel force a protocol.
+func imageProtocol() string {
+	switch cfg.Display.Images {
+	case "", "auto":
+		if !term.IsTerminal(int(os.Stdout.Fd())) {
+			return ""
+		}
+		return ui.DetectImageProtocol()
+	case "off", "none":
+		return ""
+	default:
+		return cfg.Display.Images
+	}
+}
+
+// showThumbnails downloads imgs and draws them inline, each under its
+// number in the Images list.  Images that cannot be fetched or decoded
+// are skipped; their links are already listed.
+func showThumbnails(protocol string, imgs []ui.Image) {
+	client, err := newHTTPClient(15 * time.Second)
+	if err != nil {
+		slog.Warn("image download disabled", "err", err)
+		return
+	}
+	for i, img := range imgs {
+		data, err := fetchImage(client, img.URL)
+		if err == nil {
+			fmt.Println(dimSty.Render(i18n.Tf("  Image %d", i+1)))
+			err = ui.Thumbnail(os.Stdout, protocol, data)
+		}
+		if err != nil {
+			slog.Debug("thumbnail skipped", "url", img.URL, "err", err)
+		}
+	}
+}
+
+// fetchImage downloads url, refusing anything over maxImageBytes.
+func fetchImage(client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxImageBytes {
+		return nil, fmt.Errorf("image larger than %d bytes", maxImageBytes)
+	}
+	return data, nil
+}
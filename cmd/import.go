@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ratnesh-maurya/flo/pkg/index"
+	"github.com/ratnesh-maurya/flo/pkg/sedump"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importTags     []string
+	importMinScore int
+	importSite     string
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import content into the local index",
+}
+
+var importDumpCmd = &cobra.Command{
+	Use:   "dump <Posts.xml>",
+	Short: "Import a Stack Exchange data dump for offline search",
+	Long: `Load questions and answers from the Posts.xml file of an official
+Stack Exchange data dump (https://archive.org/details/stackexchange) into
+the local index, so "flo local" can search them without any network
+access — useful on air-gapped machines.
+
+Extract the site's archive first, then optionally narrow the import:
+
+  flo import dump Posts.xml --tags go,docker --min-score 5`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImportDump,
+}
+
+func init() {
+	importDumpCmd.Flags().StringSliceVar(&importTags, "tags", nil, "only import questions with any of these tags (and their answers)")
+	importDumpCmd.Flags().IntVar(&importMinScore, "min-score", 0, "skip posts scoring below this")
+	importDumpCmd.Flags().StringVar(&importSite, "site", "stackoverflow.com", "site the dump belongs to, used to build post links")
+	importCmd.AddCommand(importDumpCmd)
+	rootCmd.AddCommand(importCmd)
+}
+
+// runImportDump implements `flo import dump`.
+func runImportDump(cmd *cobra.Command, args []string) error {
+	f, err := os.Open(args[0])
+	if err != nil {
+		printError("Import failed", err.Error())
+		return err
+	}
+	defer f.Close()
+
+	status(spinnerSty, fmt.Sprintf("📥 Reading %s...", args[0]), "importing dump",
+		"file", args[0], "tags", importTags, "min_score", importMinScore)
+
+	ctx := cmd.Context()
+	var docs []index.Doc
+	questions, answers := 0, 0
+	err = sedump.Read(f, sedump.Filter{Tags: importTags, MinScore: importMinScore}, func(p *sedump.Post) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		docs = append(docs, dumpDoc(p))
+		if p.IsQuestion {
+			questions++
+		} else {
+			answers++
+		}
+		if n := questions + answers; n%1000 == 0 {
+			fmt.Printf("\r%s", dimSty.Render(fmt.Sprintf("  %d posts read...", n)))
+		}
+		return nil
+	})
+	fmt.Print("\r")
+	if err != nil {
+		printError("Import failed", err.Error())
+		return err
+	}
+
+	idx, err := openIndex()
+	if err != nil {
+		printError("Import failed", err.Error())
+		return err
+	}
+	idx.Add(docs...)
+	if err := idx.Save(); err != nil {
+		printError("Import failed", err.Error())
+		return err
+	}
+
+	fmt.Println(successSty.Render(fmt.Sprintf("✅ Imported %d questions and %d answers (%d posts in index)",
+		questions, answers, idx.Len())))
+	return nil
+}
+
+// dumpDoc converts a data-dump post into an index document.
+func dumpDoc(p *sedump.Post) index.Doc {
+	site := strings.TrimSuffix(importSite, "/")
+	if p.IsQuestion {
+		return index.Doc{
+			ID:         fmt.Sprintf("q%d", p.ID),
+			Kind:       index.KindQuestion,
+			QuestionID: p.ID,
+			Title:      p.Title,
+			Body:       p.BodyMarkdown,
+			Tags:       p.Tags,
+			Author:     p.Owner,
+			Score:      p.Score,
+			Link:       fmt.Sprintf("https://%s/q/%d", site, p.ID),
+			Source:     "dump",
+		}
+	}
+	return index.Doc{
+		ID:         fmt.Sprintf("a%d", p.ID),
+		Kind:       index.KindAnswer,
+		QuestionID: p.ParentID,
+		AnswerID:   p.ID,
+		Title:      p.ParentTitle,
+		Body:       p.BodyMarkdown,
+		Tags:       p.ParentTags,
+		Author:     p.Owner,
+		Score:      p.Score,
+		Accepted:   p.Accepted,
+		Link:       fmt.Sprintf("https://%s/a/%d", site, p.ID),
+		Source:     "dump",
+	}
+}
@@ -0,0 +1,404 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/ratnesh-maurya/flo/pkg/config"
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Set flo up step by step: site, backend, login, theme and defaults",
+	Long: `Walk through setting flo up: choose the site and the backend, install
+the MCP bridge (checking Node.js) or give an API key, pick a colour
+palette, language and keys, and save the answers to the config file.  A
+test search at the end checks everything works, logging in to Stack
+Overflow when the MCP server needs it.
+
+Run it again to change the answers; the settings it doesn't ask about,
+and the comments in the file, are kept.`,
+	Args: cobra.NoArgs,
+	RunE: runInit,
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+}
+
+// errInitStopped is returned by the wizard's prompts on Ctrl+C.
+var errInitStopped = errors.New("init stopped")
+
+// initChoice is one answer offered by a question of the wizard.
+type initChoice struct{ label, value string }
+
+// languageNames are the languages of flo's messages, by code, as they
+// call themselves.
+var languageNames = map[string]string{
+	"en": "English",
+	"es": "Español",
+	"pt": "Português",
+	"hi": "हिन्दी",
+	"ja": "日本語",
+}
+
+// paletteNotes describe the palettes for the wizard.
+var paletteNotes = map[string]string{
+	"default":       "orange, green and red",
+	"deuteranopia":  "for red-green colour blindness",
+	"high-contrast": "bold colours for low-contrast screens",
+}
+
+// runInit implements `flo init`.
+func runInit(cmd *cobra.Command, args []string) error {
+	if err := requireInteractive("flo init"); err != nil {
+		printError("flo init asks questions", "Write the config file yourself instead; see the Configuration section of the README.")
+		return err
+	}
+	path, err := userConfigPath()
+	if err != nil {
+		printError("No config directory", err.Error())
+		return err
+	}
+
+	fmt.Println(promptSty.Render("⚡ Setting up flo"))
+	fmt.Println(dimSty.Render("  Answers are saved to " + path + ".\n  Enter takes the highlighted choice; Ctrl+C stops without saving anything."))
+	fmt.Println()
+
+	settings, err := initQuestions(cmd)
+	if errors.Is(err, errInitStopped) {
+		fmt.Println(dimSty.Render("  Stopped; nothing was saved."))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := config.Update(path, settings); err != nil {
+		printError("Could not save the config", err.Error())
+		return err
+	}
+	fmt.Println()
+	fmt.Println(successSty.Render("✅ Saved " + path))
+	fmt.Println(dimSty.Render("  Run flo init again to change it, or edit the file."))
+	fmt.Println()
+
+	test, err := initPick("Run a test search now?", []initChoice{
+		{"Yes, search once to check everything works", "yes"},
+		{"No, I'm done", "no"},
+	}, 0)
+	if err != nil || test == "no" {
+		initDone()
+		return nil
+	}
+	if err := initTestSearch(cmd.Context()); err != nil {
+		fmt.Println(dimSty.Render("  The settings are saved; fix the above and run flo doctor, or flo init again."))
+		return err
+	}
+	initDone()
+	return nil
+}
+
+// initQuestions asks the wizard's questions and returns the settings to
+// save, applying each answer to cfg as it goes, so later steps (the
+// bridge install, the test search) use them.
+func initQuestions(cmd *cobra.Command) (map[string]any, error) {
+	settings := make(map[string]any)
+
+	// Site.
+	var sites []initChoice
+	def := 0
+	for i, s := range provider.Sites {
+		value := s.Language
+		if s.Param == provider.DefaultSite {
+			value = s.Param
+		}
+		if s.Param == currentSite() {
+			def = i
+		}
+		sites = append(sites, initChoice{fmt.Sprintf("%s (%s)", s.Name, s.Host), value})
+	}
+	site, err := initPick("Which Stack Overflow do you search?", sites, def)
+	if err != nil {
+		return nil, err
+	}
+	cfg.API.Site = site
+	settings["api.site"] = site
+	if site == provider.DefaultSite {
+		settings["api.site"] = nil
+	}
+
+	// Backend.
+	backend := config.BackendAPI
+	if provider.ServedByMCP(site) {
+		def := 0
+		if cfg.Backend == config.BackendAPI {
+			def = 1
+		}
+		backend, err = initPick("Where should flo read Stack Overflow from?", []initChoice{
+			{"Stack Overflow's MCP server: log in with your account (needs Node.js)", config.BackendMCP},
+			{"The Stack Exchange API: no login, a daily quota", config.BackendAPI},
+		}, def)
+		if err != nil {
+			return nil, err
+		}
+		settings["backend"] = backend
+	} else {
+		fmt.Println(dimSty.Render("  That site is read through the Stack Exchange API; no login is needed."))
+		settings["backend"] = nil
+	}
+	if backend == config.BackendMCP {
+		if backend, err = initBridge(cmd); err != nil {
+			return nil, err
+		}
+		if backend == config.BackendAPI {
+			settings["backend"] = backend
+		}
+	}
+	cfg.Backend = backend
+	if backend == config.BackendAPI {
+		fmt.Println(dimSty.Render("  Without a key the API allows 300 requests a day; a free key from\n" +
+			"  https://stackapps.com/apps/oauth/register raises that to 10,000."))
+		key, err := initAsk("API key (Enter to skip): ")
+		if err != nil {
+			return nil, err
+		}
+		if key != "" {
+			cfg.API.Key = key
+			settings["api.key"] = key
+		}
+	}
+
+	// Palette.
+	var palettes []initChoice
+	def = 0
+	for i, name := range ui.PaletteNames() {
+		label := name
+		if note := paletteNotes[name]; note != "" {
+			label += ": " + note
+		}
+		if name == cfg.Display.Palette {
+			def = i
+		}
+		palettes = append(palettes, initChoice{label, name})
+	}
+	palette, err := initPick("Colours?", palettes, def)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Display.Palette = palette
+	settings["display.palette"] = palette
+	if palette == "default" {
+		settings["display.palette"] = nil
+	}
+	if err := applyPalette(); err != nil {
+		return nil, err
+	}
+
+	// Language.
+	system, ok := languageNames[i18n.Language(i18n.FromEnv())]
+	if !ok {
+		system = languageNames[i18n.English]
+	}
+	langs := []initChoice{{fmt.Sprintf("Follow the system (%s now)", system), ""}}
+	def = 0
+	for _, code := range i18n.Languages() {
+		if code == cfg.Display.Language {
+			def = len(langs)
+		}
+		langs = append(langs, initChoice{fmt.Sprintf("%s (%s)", languageNames[code], code), code})
+	}
+	lang, err := initPick("Language of flo's messages?", langs, def)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Display.Language = lang
+	settings["display.language"] = lang
+	if lang == "" {
+		settings["display.language"] = nil
+	}
+
+	// Keys.
+	def = 0
+	for i, name := range []string{"default", "vim", "emacs"} {
+		if name == cfg.Keys.Preset {
+			def = i
+		}
+	}
+	preset, err := initPick("Keys?", []initChoice{
+		{"Letters: [b] back, [s] save, [q] quit", "default"},
+		{"vim: h/l back and forward, :w save, :q quit", "vim"},
+		{"emacs: C-b/C-f back and forward, C-g cancel", "emacs"},
+	}, def)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Keys.Preset = preset
+	settings["keys.preset"] = preset
+	if preset == "default" {
+		settings["keys.preset"] = nil
+	}
+
+	// Opening a question.
+	def = 0
+	if cfg.Display.NoQuestion {
+		def = 1
+	}
+	open, err := initPick("When you open a question?", []initChoice{
+		{"Show the question, then let me pick an answer", "question"},
+		{"Go straight to the best answer", "answer"},
+	}, def)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Display.NoQuestion = open == "answer"
+	settings["display.no_question"] = nil
+	if cfg.Display.NoQuestion {
+		settings["display.no_question"] = true
+	}
+	if err := applyKeymap(); err != nil {
+		return nil, err
+	}
+	return settings, applyLanguage()
+}
+
+// initBridge gets the MCP bridge ready: it checks Node.js and installs
+// mcp-remote, as flo setup does, and explains the login.  When that
+// fails, the user may switch to the API; the backend to use is returned.
+func initBridge(cmd *cobra.Command) (string, error) {
+	switch {
+	case cfg.MCP.Command != "":
+		fmt.Println(dimSty.Render("  mcp.command is set in the config; flo runs that bridge."))
+	case managedRemote() != nil:
+		fmt.Println(successSty.Render(fmt.Sprintf("  ✅ mcp-remote %s is installed.", managedRemote().Version)))
+	default:
+		install, err := initPick("Install the MCP bridge now? Otherwise the first search downloads it.", []initChoice{
+			{"Yes, install mcp-remote (checks Node.js first)", "yes"},
+			{"No, later", "no"},
+		}, 0)
+		if err != nil {
+			return "", err
+		}
+		if install == "no" {
+			break
+		}
+		if err := runSetup(cmd, nil); err != nil {
+			use, err := initPick("Use the Stack Exchange API instead?", []initChoice{
+				{"Yes, read through the API", config.BackendAPI},
+				{"No, keep the MCP server; I'll fix the above", config.BackendMCP},
+			}, 0)
+			if err != nil || use == config.BackendAPI {
+				return config.BackendAPI, err
+			}
+		}
+	}
+	if mcp.NeedsLogin(mcpOptions()) {
+		if h, why := headless(); h {
+			fmt.Println(dimSty.Render(fmt.Sprintf("  The first search logs you in. With no browser here (%s), flo shows an\n"+
+				"  address to open on any device; paste back where the browser ends up.", why)))
+		} else {
+			fmt.Println(dimSty.Render("  The first search logs you in: a browser opens at Stack Overflow."))
+		}
+	}
+	return config.BackendMCP, nil
+}
+
+// initTestSearch searches once with the new settings and shows the top
+// question, logging in first when the MCP server needs it.
+func initTestSearch(ctx context.Context) error {
+	query := "reverse a string in python"
+	if s, ok := provider.LookupSite(currentSite()); ok && s.Language != "en" {
+		query = "python"
+	}
+	p, release, err := connect(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer release()
+	q, err := bestMatch(ctx, p, query)
+	if q == nil {
+		return err
+	}
+	fmt.Println(successSty.Render(fmt.Sprintf("✅ flo works. The top question for %q:", query)))
+	fmt.Println("  " + html.UnescapeString(q.Title))
+	fmt.Println(dimSty.Render("  " + q.Link))
+	return nil
+}
+
+// userConfigPath returns the config file flo reads: --config, or the
+// default one.
+func userConfigPath() (string, error) {
+	if configPath != "" {
+		return configPath, nil
+	}
+	return config.DefaultPath()
+}
+
+// initDone says how to go on.
+func initDone() {
+	fmt.Println()
+	fmt.Println(promptSty.Render("You're set."))
+	fmt.Println(dimSty.Render("  flo                      ask away, one question after another\n" +
+		"  flo ask \"<question>\"     one search\n" +
+		"  flo doctor               check the setup"))
+}
+
+// initPick asks question and returns the value of the choice picked;
+// the one at def starts highlighted.
+func initPick(question string, choices []initChoice, def int) (string, error) {
+	items := make([]string, len(choices))
+	for i, c := range choices {
+		items[i] = c.label
+	}
+	sel := promptui.Select{
+		Label:     question,
+		Items:     items,
+		Size:      len(items),
+		CursorPos: def,
+		Templates: selectTemplates(),
+		HideHelp:  ui.Plain(),
+		Stdout:    bellSkipper{},
+		Stdin:     pickerStdin(),
+	}
+	i, _, err := sel.Run()
+	if err != nil {
+		return "", errInitStopped
+	}
+	return choices[i].value, nil
+}
+
+// initAsk asks for a line of text.
+func initAsk(label string) (string, error) {
+	fmt.Print(promptSty.Render(label))
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", errInitStopped
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// firstRunHint points new users at flo init: it is shown when the
+// interactive session starts without a config file.
+func firstRunHint() {
+	if configPath != "" || !interactive() {
+		return
+	}
+	path, err := config.DefaultPath()
+	if err != nil {
+		return
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		fmt.Println(dimSty.Render(i18n.T("  New to flo? flo init sets it up in a minute.")))
+		fmt.Println()
+	}
+}
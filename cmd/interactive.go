@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// nonInteractive is --non-interactive (or $FLO_NO_INTERACTIVE): flo
+// must never wait for the user — no pickers, prompts, editors or
+// browser login.
+var nonInteractive bool
+
+// errLoginRequired is returned in non-interactive mode for lookups the
+// cache cannot answer before the MCP server has been logged in to.
+var errLoginRequired = withExitCode(exitAuthRequired, errors.New(
+	"Stack Overflow login required: run flo once in a terminal to log in, or use --backend api"))
+
+// applyNonInteractive reads $FLO_NO_INTERACTIVE unless the flag is set.
+func applyNonInteractive(cmd *cobra.Command) error {
+	if cmd.Flags().Changed("non-interactive") {
+		return nil
+	}
+	v := os.Getenv("FLO_NO_INTERACTIVE")
+	if v == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fmt.Errorf("FLO_NO_INTERACTIVE=%q: want true or false", v)
+	}
+	nonInteractive = b
+	return nil
+}
+
+// interactive reports whether flo may prompt: stdin is a terminal,
+// --non-interactive is not set, and the output is for the terminal
+// rather than, with --format, for another program.
+func interactive() bool {
+	return !nonInteractive && ui.Current().Styled() && term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// requireInteractive fails with exitInputRequired in non-interactive
+// mode; what names the interaction that was needed.
+func requireInteractive(what string) error {
+	if !nonInteractive {
+		return nil
+	}
+	return withExitCode(exitInputRequired, fmt.Errorf("%s needs a terminal; not available with --non-interactive", what))
+}
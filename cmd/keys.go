@@ -0,0 +1,399 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chzyer/readline"
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+)
+
+// Keymap actions.  The picker ones are single keys pressed in promptui
+// lists; the rest are typed at the prompts under a question or answer,
+// then Enter.
+const (
+	actUp       = "up"
+	actDown     = "down"
+	actPageUp   = "page_up"
+	actPageDown = "page_down"
+	actOpen     = "open"
+	actCancel   = "cancel"
+
+	actAnswers = "answers"
+	actAll     = "all"
+	actBack    = "back"
+	actForward = "forward"
+	actSave    = "save"
+	actGist    = "gist"
+	actQR      = "qr"
+	actExpand  = "expand"
+	actRelated = "related" // also reloads a question that changed
+	actNew     = "new"
+	actQuit    = "quit"
+	actJump    = "jump"
+	// The code block keys: next_code and prev_code show the next and
+	// previous block of an answer, yank followed by n copies block n,
+	// and scroll_left and scroll_right move along the lines of a block
+	// wider than the terminal.
+	actNextCode    = "next_code"
+	actPrevCode    = "prev_code"
+	actYank        = "yank"
+	actScrollLeft  = "scroll_left"
+	actScrollRight = "scroll_right"
+	// actPlain reprints an answer as its Markdown, for copying.
+	actPlain = "plain"
+	// actWrite followed by n and a file name writes block n to the file.
+	actWrite = "write"
+	// The find keys: find followed by a word searches an answer for it,
+	// and find_next and find_prev step through the matches.  They are
+	// read at the prompt under a match, apart from the others, and there
+	// a key's case counts, as in less and vim: n and N differ.
+	actFind     = "find"
+	actFindNext = "find_next"
+	actFindPrev = "find_prev"
+)
+
+// findActions are the keys read at the prompt under a match.  find is
+// also read under an answer, as the start of what is typed.
+var findActions = map[string]bool{actFind: true, actFindNext: true, actFindPrev: true}
+
+// pickerCodes are the keys promptui lists know each picker action by;
+// a key bound to the action is read as its code.  The arrow keys,
+// Ctrl+N/P/F/B and h/j/k/l work in every keymap.
+var pickerCodes = map[string]byte{
+	actUp:       byte(readline.CharPrev),
+	actDown:     byte(readline.CharNext),
+	actPageUp:   byte(readline.CharBackward),
+	actPageDown: byte(readline.CharForward),
+	actOpen:     byte(readline.CharEnter),
+	actCancel:   byte(readline.CharInterrupt),
+}
+
+// defaultKeys is the default keymap; "" leaves an action to the keys
+// it always has.
+var defaultKeys = map[string]string{
+	actUp: "", actDown: "", actPageUp: "", actPageDown: "",
+	actOpen: "Enter", actCancel: "C-c",
+
+	actAnswers: "Enter", actAll: "a", actBack: "b", actForward: "f",
+	actSave: "s", actGist: "g", actQR: "qr", actExpand: "x",
+	actRelated: "r", actNew: "n", actQuit: "q", actJump: "j",
+	actNextCode: "]", actPrevCode: "[", actYank: "y",
+	actScrollLeft: "h", actScrollRight: "l", actPlain: "p", actWrite: "w",
+	actFind: "/", actFindNext: "n", actFindPrev: "N",
+}
+
+// keyPresets change some of the default keys.
+var keyPresets = map[string]map[string]string{
+	"default": nil,
+	"vim": {
+		actCancel: "q",
+		actBack:   "h", actForward: "l", actSave: ":w", actExpand: "zo",
+		actNew: "o", actQuit: ":q", actJump: "n",
+		actScrollLeft: "zh", actScrollRight: "zl",
+	},
+	"emacs": {
+		actCancel: "C-g",
+		actAll:    "C-a", actBack: "C-b", actForward: "C-f", actExpand: "C-e",
+		actNew: "C-n", actQuit: "C-g", actJump: "M-n", actYank: "M-w",
+		actFindNext: "C-n", actFindPrev: "C-p",
+	},
+}
+
+var (
+	// keys is the keymap in use: action to key, as written in the config.
+	keys = defaultKeys
+	// lineKeys maps what is typed at a prompt to its action.
+	lineKeys = defaultLineKeys
+	// pickerKeys maps the bytes of rebound picker keys to promptui's.
+	pickerKeys map[byte]byte
+)
+
+// ttyKeys are the control keys a terminal keeps to itself while a line
+// is typed (interrupt, end of input, suspend, flow control, line
+// editing), so they never reach a prompt.
+const ttyKeys = "cdzsquwrvoyjmhi\\"
+
+// applyKeymap sets up the keymap chosen by keys.preset and keys.bind.
+func applyKeymap() error {
+	name := cfg.Keys.Preset
+	if name == "" {
+		name = "default"
+	}
+	preset, ok := keyPresets[name]
+	if !ok {
+		return fmt.Errorf("keys.preset: unknown preset %q (default, vim or emacs)", name)
+	}
+	km := make(map[string]string, len(defaultKeys))
+	for a, k := range defaultKeys {
+		km[a] = k
+	}
+	for a, k := range preset {
+		km[a] = k
+	}
+	for a, k := range cfg.Keys.Bind {
+		if _, ok := defaultKeys[a]; !ok {
+			return fmt.Errorf("keys.bind: unknown action %q (%s)", a, strings.Join(keyActions(), ", "))
+		}
+		km[a] = k
+	}
+
+	picker := make(map[byte]byte)
+	for a, code := range pickerCodes {
+		if km[a] == "" {
+			continue
+		}
+		in, err := pickerKey(km[a])
+		if err != nil {
+			return fmt.Errorf("keys.bind.%s: %w", a, err)
+		}
+		if in != code {
+			picker[in] = code
+		}
+	}
+	for a, k := range km {
+		if _, isPicker := pickerCodes[a]; isPicker {
+			continue
+		}
+		if _, err := lineKey(k); err != nil {
+			return fmt.Errorf("keys.bind.%s: %w", a, err)
+		}
+	}
+	for _, group := range []string{groupPicker, groupPrompt, groupFind} {
+		if err := keyClash(km, group); err != nil {
+			return err
+		}
+	}
+	keys, lineKeys, pickerKeys = km, lineActions(km), picker
+	return nil
+}
+
+// keyActions returns the action names, sorted.
+func keyActions() []string {
+	names := make([]string, 0, len(defaultKeys))
+	for a := range defaultKeys {
+		names = append(names, a)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// The groups of actions read together, whose keys must differ.
+const (
+	groupPicker = "picker"
+	groupPrompt = "prompt"
+	groupFind   = "find"
+)
+
+// keyGroup returns the group action is read in.
+func keyGroup(action string) string {
+	if _, isPicker := pickerCodes[action]; isPicker {
+		return groupPicker
+	}
+	if findActions[action] {
+		return groupFind
+	}
+	return groupPrompt
+}
+
+// keyClash fails when two actions of group share a key.
+func keyClash(km map[string]string, group string) error {
+	seen := make(map[string]string)
+	for _, a := range keyActions() {
+		if keyGroup(a) != group || km[a] == "" {
+			continue
+		}
+		k := km[a]
+		if group != groupFind {
+			k = strings.ToLower(k)
+		}
+		if other, ok := seen[k]; ok {
+			return fmt.Errorf("keys: %s and %s are both bound to %s", other, a, km[a])
+		}
+		seen[k] = a
+	}
+	return nil
+}
+
+// lineActions maps what is typed for each prompt action to the action.
+func lineActions(km map[string]string) map[string]string {
+	m := make(map[string]string)
+	for a, k := range km {
+		if keyGroup(a) != groupPrompt {
+			continue
+		}
+		if in, err := lineKey(k); err == nil {
+			m[in] = a
+		}
+	}
+	return m
+}
+
+// lineKey returns what a prompt reads (lower-cased, without the Enter)
+// when key is typed.
+func lineKey(key string) (string, error) {
+	switch {
+	case strings.EqualFold(key, "enter"):
+		return "", nil
+	case isCombo(key, "C-"):
+		c := strings.ToLower(key[2:])
+		if strings.Contains(ttyKeys, c) {
+			return "", fmt.Errorf("%s never reaches flo: the terminal keeps it", key)
+		}
+		return string(c[0] & 0x1f), nil
+	case isCombo(key, "M-"):
+		return "\x1b" + strings.ToLower(key[2:]), nil
+	case key == "" || strings.ContainsAny(key, " \t"):
+		return "", fmt.Errorf("%q is not a key", key)
+	}
+	return strings.ToLower(key), nil
+}
+
+// pickerKey returns the byte a picker reads when key is pressed; only
+// single keys can be bound there.
+func pickerKey(key string) (byte, error) {
+	switch {
+	case strings.EqualFold(key, "enter"):
+		return byte(readline.CharEnter), nil
+	case isCombo(key, "C-"):
+		return strings.ToLower(key[2:])[0] & 0x1f, nil
+	case len(key) == 1 && key[0] > ' ' && key[0] < 0x7f:
+		return key[0], nil
+	}
+	return 0, fmt.Errorf("%q is not a single key", key)
+}
+
+// isCombo reports whether key is prefix (C- or M-) and one character.
+func isCombo(key, prefix string) bool {
+	return len(key) == len(prefix)+1 && strings.EqualFold(key[:len(prefix)], prefix) &&
+		key[len(prefix)] > ' ' && key[len(prefix)] < 0x7f
+}
+
+// keyName returns how action's key is written in hints: "s", "Enter",
+// "Ctrl+B", "Alt+N".
+func keyName(action string) string {
+	k := keys[action]
+	switch {
+	case strings.EqualFold(k, "enter"):
+		return "Enter"
+	case isCombo(k, "C-"):
+		return "Ctrl+" + strings.ToUpper(k[2:])
+	case isCombo(k, "M-"):
+		return "Alt+" + strings.ToUpper(k[2:])
+	}
+	return k
+}
+
+// typedAction returns the action bound to the line typed at a prompt,
+// or "".
+func typedAction(input string) string {
+	return lineKeys[strings.ToLower(strings.TrimSpace(input))]
+}
+
+// typedCount parses a line typed at a prompt as action's key followed by
+// a number, as in y2; n is 0 when no number follows.
+func typedCount(input, action string) (n int, ok bool) {
+	key, err := lineKey(keys[action])
+	if err != nil || key == "" {
+		return 0, false
+	}
+	rest, found := strings.CutPrefix(strings.ToLower(strings.TrimSpace(input)), key)
+	if !found {
+		return 0, false
+	}
+	if rest == "" {
+		return 0, true
+	}
+	if n, err = strconv.Atoi(rest); err != nil || n < 1 {
+		return 0, false
+	}
+	return n, true
+}
+
+// findKey returns what is typed for the find action, its case kept:
+// "n" and "N" are different keys under a match.
+func findKey(action string) string {
+	k := keys[action]
+	if strings.EqualFold(k, "enter") || isCombo(k, "C-") || isCombo(k, "M-") {
+		in, _ := lineKey(k)
+		return in
+	}
+	return k
+}
+
+// findHint renders the hint for searching, as "[/word] find".
+func findHint(label string) string {
+	sep := ""
+	if len(keys[actFind]) > 1 {
+		sep = " "
+	}
+	return "[" + keyName(actFind) + sep + i18n.T("word") + "] " + i18n.T(label)
+}
+
+// defaultLineKeys maps what is typed for each prompt action in the
+// default keymap.
+var defaultLineKeys = lineActions(defaultKeys)
+
+// replKey returns the action a line typed at the Ask prompt stands for
+// when it is back, forward or quit, or "".  The default keys work there
+// too, as control keys edit the line instead of reaching flo.
+func replKey(query string) string {
+	for _, a := range []string{typedAction(query), defaultLineKeys[strings.ToLower(strings.TrimSpace(query))]} {
+		switch a {
+		case actBack, actForward, actQuit:
+			return a
+		}
+	}
+	return ""
+}
+
+// hint is one entry of a line of key hints.
+type hint struct{ action, label string }
+
+// keyHints renders hints as "  [a] all answers  |  [b] back".
+func keyHints(hints ...hint) string {
+	parts := make([]string, len(hints))
+	for i, h := range hints {
+		parts[i] = "[" + keyName(h.action) + "] " + i18n.T(h.label)
+	}
+	return "  " + strings.Join(parts, "  |  ")
+}
+
+// pickHint returns the key help for a picker label, e.g. "(↑↓ navigate,
+// Enter to view, Ctrl+C to go back)".
+func pickHint(open, cancel string) string {
+	return i18n.Tf("(↑↓ navigate, %s to %s, %s to %s)", keyName(actOpen), i18n.T(open), keyName(actCancel), i18n.T(cancel))
+}
+
+// pickerStdin returns the input for promptui lists: stdin, with the keys
+// the keymap binds read as the ones promptui knows.  It is nil (stdin as
+// is) when no picker key is rebound.
+func pickerStdin() io.ReadCloser {
+	if len(pickerKeys) == 0 {
+		return nil
+	}
+	return keyReader{os.Stdin}
+}
+
+// keyReader translates the rebound picker keys read from r.
+type keyReader struct{ r io.Reader }
+
+func (k keyReader) Read(b []byte) (int, error) {
+	n, err := k.r.Read(b)
+	for i := 0; i < n; i++ {
+		if b[i] == '\x1b' {
+			break // an escape sequence (arrow keys) is left alone
+		}
+		if c, ok := pickerKeys[b[i]]; ok {
+			b[i] = c
+		}
+	}
+	return n, err
+}
+
+// Close leaves stdin open for the prompts after the list.
+func (keyReader) Close() error { return nil }
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+)
+
+// applyLanguage switches flo's messages to display.language (FLO_LANG),
+// or else to the language of the environment's locale.  A language flo
+// has no messages in is an error in the config, but not in LANG, which
+// every program shares: flo just stays in English.
+func applyLanguage() error {
+	if l := cfg.Display.Language; l != "" {
+		if err := i18n.Set(l); err != nil {
+			return fmt.Errorf("display.language: %w", err)
+		}
+		return nil
+	}
+	if err := i18n.Set(i18n.FromEnv()); err != nil {
+		slog.Debug("no messages in the locale's language; using English", "locale", i18n.FromEnv())
+	}
+	return nil
+}
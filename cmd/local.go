@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/ratnesh-maurya/flo/pkg/config"
+	"github.com/ratnesh-maurya/flo/pkg/embed"
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+	"github.com/ratnesh-maurya/flo/pkg/index"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	localLimit    int
+	localSemantic bool
+)
+
+var localCmd = &cobra.Command{
+	Use:   "local <query>",
+	Short: "Search questions and answers you have viewed, offline",
+	Long: `Search your personal index of every question and answer flo has shown
+you. No network connection is needed.
+
+  flo local "reverse string"
+  flo local --semantic "goroutine leak on ticker"
+
+--semantic ranks by meaning rather than keywords, using a local embedding
+model (Ollama or any OpenAI-compatible server; see "embeddings" in the
+config file). New documents are embedded on demand.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runLocal,
+}
+
+func init() {
+	localCmd.Flags().IntVarP(&localLimit, "limit", "n", 10, "maximum number of results")
+	localCmd.Flags().BoolVar(&localSemantic, "semantic", false, "rank by semantic similarity using a local embedding model")
+	rootCmd.AddCommand(localCmd)
+}
+
+// runLocal implements `flo local`.
+func runLocal(cmd *cobra.Command, args []string) error {
+	idx, err := openIndex()
+	if err != nil {
+		printError("Local index unavailable", err.Error())
+		return err
+	}
+	query := strings.Join(args, " ")
+
+	var hits []index.Hit
+	if localSemantic {
+		hits, err = semanticSearch(cmd.Context(), idx, query)
+		if err != nil {
+			if cmd.Context().Err() != nil {
+				return cmd.Context().Err()
+			}
+			printError("Semantic search failed", err.Error()+
+				"\n\nIs a local embedding server running? e.g.\n  ollama pull "+embed.DefaultModel)
+			return err
+		}
+	} else {
+		hits = idx.Search(query, localLimit)
+	}
+	if len(hits) == 0 {
+		printError(i18n.T("No results"), i18n.Tf("Nothing in your local index (%d posts) matches %q.", idx.Len(), query))
+		return errNoResults
+	}
+
+	printDoc(ui.Document{Markdown: formatLocalHits(query, hits), Data: hits})
+
+	if !interactive() {
+		return nil
+	}
+	return localSelectionLoop(hits)
+}
+
+// semanticSearch embeds any not-yet-embedded documents, then ranks the
+// index by cosine similarity to the embedded query.
+func semanticSearch(ctx context.Context, idx *index.Index, query string) ([]index.Hit, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return nil, err
+	}
+	vecs, err := index.OpenVectors(index.VectorsPath(dir))
+	if err != nil {
+		return nil, err
+	}
+	hc, err := newHTTPClient(time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	e := embed.NewHTTPEmbedder(cfg.Embeddings.URL, cfg.Embeddings.Model, hc)
+
+	n, syncErr := vecs.Sync(ctx, idx, e, func(done, total int) {
+		fmt.Printf("\r%s", dimSty.Render(fmt.Sprintf("  embedding %d/%d documents...", done, total)))
+	})
+	if n > 0 {
+		fmt.Println()
+		if err := vecs.Save(); err != nil {
+			slog.Warn("save vectors", "err", err)
+		}
+	}
+	if syncErr != nil {
+		return nil, syncErr
+	}
+
+	qv, err := e.Embed(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return vecs.Search(idx, qv, localLimit), nil
+}
+
+// localSelectionLoop lets the user open hits from the local index.
+func localSelectionLoop(hits []index.Hit) error {
+	items := make([]string, len(hits))
+	for i, h := range hits {
+		items[i] = fmt.Sprintf("#%d %s %s", i+1, kindBadge(h.Doc.Kind), h.Doc.Title)
+	}
+	for {
+		sel := promptui.Select{
+			Label:     i18n.T("Open a result") + " " + pickHint("view", "exit"),
+			Items:     items,
+			Size:      len(items),
+			Templates: selectTemplates(),
+			HideHelp:  ui.Plain(),
+			Stdout:    bellSkipper{},
+			Stdin:     pickerStdin(),
+		}
+		i, _, err := sel.Run()
+		if err != nil {
+			return nil
+		}
+		renderAndPrint(ui.AnnotateCode(formatLocalDoc(hits[i].Doc), hits[i].Doc.Tags))
+	}
+}
+
+// formatLocalHits builds a Markdown list of local search hits.
+func formatLocalHits(query string, hits []index.Hit) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# Local results for %q\n\n", query))
+	for i, h := range hits {
+		d := h.Doc
+		// Imported posts were never viewed; say where they came from.
+		when := "viewed " + d.ViewedAt.Format("Jan 2, 2006")
+		if d.Source != "" {
+			when = "from a " + d.Source + ", " + d.ViewedAt.Format("Jan 2, 2006")
+		}
+		b.WriteString(fmt.Sprintf("%d. %s **%s**  \n   Score: %d | %s",
+			i+1, kindBadge(d.Kind), d.Title, d.Score, when))
+		if len(d.Tags) > 0 {
+			b.WriteString(" — `" + strings.Join(d.Tags, "` `") + "`")
+		}
+		b.WriteString("  \n")
+		if snippet := snippetOf(d.Body, 100); snippet != "" {
+			b.WriteString("   " + snippet + "\n")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// formatLocalDoc renders a stored document as Markdown.
+func formatLocalDoc(d *index.Doc) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# %s\n\n", d.Title))
+	meta := fmt.Sprintf("%s  |  Score: **%d**", kindBadge(d.Kind), d.Score)
+	if d.Accepted {
+		meta += "  |  ✅ Accepted"
+	}
+	if d.Author != "" {
+		meta += fmt.Sprintf("  |  By **%s**", d.Author)
+	}
+	b.WriteString(meta + "\n\n---\n\n")
+	b.WriteString(d.Body + "\n")
+	if d.Link != "" {
+		b.WriteString(fmt.Sprintf("\n🔗 %s\n", d.Link))
+	}
+	return b.String()
+}
+
+// kindBadge labels a document as question or answer.
+func kindBadge(k index.Kind) string {
+	if k == index.KindAnswer {
+		return "💬 A"
+	}
+	return "❓ Q"
+}
+
+// snippetOf returns the first non-empty, non-code line of body, truncated.
+func snippetOf(body string, max int) string {
+	inFence := false
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || line == "" {
+			continue
+		}
+		if r := []rune(line); len(r) > max {
+			line = string(r[:max-3]) + "..."
+		}
+		return line
+	}
+	return ""
+}
+
+// ---------- recording viewed posts ----------
+
+// openIndex opens the local index in the user data directory.
+func openIndex() (*index.Index, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return nil, err
+	}
+	return index.Open(index.DefaultPath(dir))
+}
+
+// recordViewed adds docs to the local index, appending them without
+// reading it.  Failures are logged but never interrupt the interactive
+// flow.
+func recordViewed(docs ...index.Doc) {
+	dir, err := config.DataDir()
+	if err != nil {
+		slog.Warn("open local index", "err", err)
+		return
+	}
+	if err := index.Append(index.DefaultPath(dir), docs...); err != nil {
+		slog.Warn("save local index", "err", err)
+	}
+}
+
+// questionDoc converts a question into an index document.
+func questionDoc(q *mcp.QuestionData) index.Doc {
+	return index.Doc{
+		ID:         fmt.Sprintf("q%d", q.QuestionID),
+		Kind:       index.KindQuestion,
+		QuestionID: q.QuestionID,
+		Title:      html.UnescapeString(q.Title),
+		Body:       html.UnescapeString(q.BodyMarkdown),
+		Tags:       q.Tags,
+		Author:     html.UnescapeString(q.Owner.DisplayName),
+		Score:      q.Score,
+		Link:       q.Link,
+	}
+}
+
+// answerDoc converts an answer to q into an index document.  The
+// question's title and tags are copied so answers are findable by topic.
+func answerDoc(q *mcp.QuestionData, a *mcp.AnswerData) index.Doc {
+	link := a.Link
+	if link == "" {
+		link = q.Link
+	}
+	return index.Doc{
+		ID:         fmt.Sprintf("a%d", a.AnswerID),
+		Kind:       index.KindAnswer,
+		QuestionID: q.QuestionID,
+		AnswerID:   a.AnswerID,
+		Title:      html.UnescapeString(q.Title),
+		Body:       html.UnescapeString(a.BodyMarkdown),
+		Tags:       q.Tags,
+		Author:     html.UnescapeString(a.Owner.DisplayName),
+		Score:      a.Score,
+		Accepted:   a.IsAccepted,
+		Link:       link,
+	}
+}
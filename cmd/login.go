@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+)
+
+// headless reports whether the browser login cannot work here — no
+// browser would open where the user is — and why.  mcp.remote.headless
+// overrides the detection.
+func headless() (bool, string) {
+	if h := cfg.MCP.Remote.Headless; h != nil {
+		return *h, "mcp.remote.headless in the config"
+	}
+	if os.Getenv("SSH_CONNECTION") != "" || os.Getenv("SSH_TTY") != "" {
+		// With X forwarding a browser opens on the user's own screen.
+		if runtime.GOOS == "darwin" || runtime.GOOS == "windows" || os.Getenv("DISPLAY") == "" {
+			return true, "SSH session"
+		}
+		return false, "SSH session with X forwarding"
+	}
+	switch runtime.GOOS {
+	case "darwin", "windows":
+		return false, ""
+	}
+	if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+		return true, "no display"
+	}
+	return false, ""
+}
+
+// loginURLsShown keeps a login address from being shown twice.
+var loginURLsShown sync.Map
+
+// showLoginURL shows the address the MCP bridge asks the user to log in
+// at.  Where no browser opens it is the only way in: the user logs in
+// on another machine and pastes back the address the browser ends up
+// at, which CompleteLogin hands to the bridge.
+func showLoginURL(url string) {
+	if _, shown := loginURLsShown.LoadOrStore(url, true); shown {
+		return
+	}
+	if h, _ := headless(); !h {
+		fmt.Println(dimSty.Render(i18n.T("  If no browser opened, log in at:") + "\n  " + url))
+		return
+	}
+	fmt.Println()
+	fmt.Println(promptSty.Render(i18n.T("🔑 Log in to Stack Overflow from any device with a browser:")))
+	fmt.Println("  " + url)
+	if !interactive() {
+		fmt.Println(dimSty.Render(i18n.T("  Run flo in a terminal to finish the login; it needs the address the browser ends up at.")))
+		return
+	}
+	fmt.Println(dimSty.Render(i18n.T("  After you log in, the browser goes to a localhost address that does not load.\n" +
+		"  Copy that address from its address bar and paste it here, or press Enter if\n" +
+		"  you logged in on this machine.")))
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Print(promptSty.Render(i18n.T("  Address: ")))
+		input, err := reader.ReadString('\n')
+		input = strings.TrimSpace(input)
+		if input == "" {
+			if err != nil {
+				return // stdin closed
+			}
+			status(dimSty, i18n.T("  Waiting for the login to finish..."), "waiting for browser login")
+			return
+		}
+		if err := mcp.CompleteLogin(context.Background(), url, input); err != nil {
+			fmt.Println(spinnerSty.Render("  ⚠ " + err.Error()))
+			continue
+		}
+		status(successSty, i18n.T("  ✅ Logged in; finishing the connection..."), "login code handed to the bridge")
+		return
+	}
+}
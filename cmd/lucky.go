@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+	"github.com/ratnesh-maurya/flo/pkg/usage"
+	"github.com/spf13/cobra"
+)
+
+var luckyCmd = &cobra.Command{
+	Use:   "lucky <query>",
+	Short: "Print the best answer for a query and exit",
+	Long: `Search, take the top question's accepted answer (or its highest-voted
+one), print it and exit — no pickers, no prompts.
+
+  flo lucky "reverse a string in go"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runLucky,
+}
+
+func init() {
+	luckyCmd.Flags().BoolVar(&noCache, "no-cache", false, "always query the server, bypassing the response cache")
+	rootCmd.AddCommand(luckyCmd)
+}
+
+// runLucky implements `flo lucky`.
+func runLucky(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	query := mcp.NormalizeQuery(strings.Join(args, " "))
+
+	p, release, err := connect(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	q, err := bestMatch(ctx, p, query)
+	if q == nil {
+		return err
+	}
+	if len(q.Answers) == 0 {
+		printError(i18n.T("No answer"), i18n.Tf("%q has no answer yet.", html.UnescapeString(q.Title))+"\n\n  "+q.Link)
+		return errNoResults
+	}
+
+	// SortAnswers puts the accepted answer first, then by score.
+	best := mcp.SortAnswers(q.Answers)[0]
+	md := fmt.Sprintf("# %s\n\n%s", html.UnescapeString(q.Title), mcp.FormatSingleAnswer(&best))
+	if q.Link != "" {
+		md += fmt.Sprintf("\n🔗 %s\n", q.Link)
+	}
+	printCautions(q, &best)
+	printDoc(ui.Document{Markdown: ui.AnnotateCode(md, q.Tags), Data: q})
+	recordViewed(questionDoc(q), answerDoc(q, &best))
+	recordUsage(questionEvent(q))
+	recordUsage(answerEvent(q, &best))
+	return nil
+}
+
+// bestMatch searches for query and returns the top question, with its
+// accepted answer fetched when the search did not embed any.  Failures
+// are reported to the user; nothing found returns errNoResults.
+func bestMatch(ctx context.Context, p provider.Provider, query string) (*mcp.QuestionData, error) {
+	searchCtx, cancelSearch := context.WithTimeout(ctx, cfg.Timeouts.SearchTimeout())
+	defer cancelSearch()
+
+	slog.Info("best match search", "query", query)
+	hits := cacheHits()
+	resp, err := p.Search(searchCtx, query)
+	if err == nil || errors.Is(err, provider.ErrNotFound) {
+		recordUsage(usage.Event{Kind: usage.KindSearch, Query: query, Cached: cacheHits() > hits})
+	}
+	if errors.Is(err, provider.ErrNotFound) {
+		printError(i18n.T("No results"), i18n.Tf("Nothing found for %q.", query))
+		return nil, errNoResults
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		reportSearchError(searchCtx, err)
+		return nil, err
+	}
+
+	hints := detectTagHints(query)
+	q := mcp.BestQuestionWithAnswers(resp, hints)
+	if q == nil {
+		q = mcp.BestQuestion(resp, hints)
+		if q == nil {
+			q = &resp.Items[0]
+		}
+		if q.AcceptedAnswerID > 0 {
+			fetchAcceptedAnswer(ctx, p, q)
+		}
+	}
+	return resolveDuplicate(ctx, p, q), nil
+}
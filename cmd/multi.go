@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/manifoldco/promptui"
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+)
+
+// askQueries holds the repeatable -q/--query flag.
+var askQueries []string
+
+// multiHit is a question found by one or more of the queries in a
+// multi-query search.
+type multiHit struct {
+	question *mcp.QuestionData
+	labels   []int // 1-based numbers of the queries that found it
+}
+
+// multiSearch runs every query concurrently over the shared connection
+// and lets the user pick from the merged results, each labeled with the
+// queries that found it.
+func multiSearch(ctx context.Context, p provider.Provider, queries []string) error {
+	for i, q := range queries {
+		if !askVerbatim {
+			queries[i] = mcp.NormalizeQuery(q)
+		}
+		fmt.Println(dimSty.Render(fmt.Sprintf("  [%d] %s", i+1, queries[i])))
+	}
+	status(spinnerSty, "\n"+i18n.Tf("🔍 Searching %d queries...", len(queries))+"\n", "multi-query search",
+		"queries", queries)
+
+	results := make([]*mcp.SOResponse, len(queries))
+	errs := make([]error, len(queries))
+	var wg sync.WaitGroup
+	for i, q := range queries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sctx, cancel := context.WithTimeout(ctx, cfg.Timeouts.SearchTimeout())
+			defer cancel()
+			results[i], errs[i] = p.Search(sctx, q)
+		}()
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	for i, err := range errs {
+		switch {
+		case err == nil:
+		case errors.Is(err, provider.ErrNotFound):
+			fmt.Println(dimSty.Render(i18n.Tf("  [%d] no results", i+1)))
+		default:
+			slog.Warn("search failed", "query", queries[i], "err", err)
+			fmt.Println(dimSty.Render(i18n.Tf("  [%d] failed: %v", i+1, err)))
+		}
+	}
+
+	hits := mergeHits(results)
+	if len(hits) == 0 {
+		printError(i18n.T("No results"), i18n.T("None of the queries found anything."))
+		// Report a failure rather than "no results" when searches failed.
+		for _, err := range errs {
+			if err != nil && !errors.Is(err, provider.ErrNotFound) {
+				return err
+			}
+		}
+		return errNoResults
+	}
+	if !interactive() {
+		printDoc(ui.Document{Markdown: formatMultiHits(hits), Data: hits})
+		return nil
+	}
+	return multiSelectionLoop(ctx, p, hits)
+}
+
+// mergeHits interleaves the result lists — first result of each query,
+// then the second of each, and so on — so every phrasing is represented
+// near the top.  Questions found by several queries appear once, with
+// all their labels.
+func mergeHits(results []*mcp.SOResponse) []*multiHit {
+	var hits []*multiHit
+	byID := make(map[int]*multiHit)
+	for rank := 0; ; rank++ {
+		more := false
+		for qi, resp := range results {
+			if resp == nil || rank >= len(resp.Items) {
+				continue
+			}
+			more = true
+			q := &resp.Items[rank]
+			if h, ok := byID[q.QuestionID]; ok && q.QuestionID != 0 {
+				h.labels = append(h.labels, qi+1)
+				continue
+			}
+			h := &multiHit{question: q, labels: []int{qi + 1}}
+			byID[q.QuestionID] = h
+			hits = append(hits, h)
+		}
+		if !more {
+			return hits
+		}
+	}
+}
+
+// label is a hit's query labels, e.g. "[1,3]".
+func (h *multiHit) label() string {
+	sort.Ints(h.labels)
+	nums := make([]string, len(h.labels))
+	for i, n := range h.labels {
+		nums[i] = fmt.Sprint(n)
+	}
+	return "[" + strings.Join(nums, ",") + "]"
+}
+
+// multiSelectionLoop lets the user open merged hits until they cancel.
+func multiSelectionLoop(ctx context.Context, p provider.Provider, hits []*multiHit) error {
+	items := make([]string, len(hits))
+	for i, h := range hits {
+		q := h.question
+		items[i] = fmt.Sprintf("%-7s %s %s", h.label(), ui.ScoreBadge(q.Score, q.AcceptedAnswerID > 0), html.UnescapeString(q.Title))
+		if !q.IsAnswered {
+			items[i] += " " + ui.UnansweredBadge()
+		}
+	}
+	for {
+		sel := promptui.Select{
+			Label:     i18n.T("Open a result") + " " + pickHint("view", "exit"),
+			Items:     items,
+			Size:      min(len(items), 15),
+			Templates: selectTemplates(),
+			HideHelp:  ui.Plain(),
+			Stdout:    bellSkipper{},
+			Stdin:     pickerStdin(),
+		}
+		i, _, err := sel.Run()
+		if err != nil {
+			return nil
+		}
+		q := hits[i].question
+		if len(q.Answers) == 0 && q.AcceptedAnswerID > 0 {
+			status(spinnerSty, i18n.T("📖 Fetching accepted answer..."), "fetching accepted answer",
+				"question_id", q.QuestionID, "answer_id", q.AcceptedAnswerID)
+			fetchAcceptedAnswer(ctx, p, q)
+		}
+		e := &navEntry{question: q}
+		history.push(e)
+		if err := browse(ctx, p, e, false); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// formatMultiHits lists merged hits as Markdown, for non-interactive
+// output.
+func formatMultiHits(hits []*multiHit) string {
+	var b strings.Builder
+	b.WriteString("# " + i18n.T("Stack Overflow Search Results") + "\n\n")
+	for i, h := range hits {
+		q := h.question
+		fmt.Fprintf(&b, "%d. %s **%s**  \n   %s  \n   %s\n\n",
+			i+1, h.label(), html.UnescapeString(q.Title), i18n.Tf("Score: %d | Answers: %d", q.Score, q.AnswerCount), q.Link)
+	}
+	return b.String()
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"html"
+	"os"
+
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+)
+
+// navStep is what the user asked for after viewing something.
+type navStep int
+
+const (
+	navDone    navStep = iota // return to the prompt
+	navBack                   // previous view in the session
+	navForward                // next view, after going back
+)
+
+// navEntry is one thing shown in a session: a question with the answers
+// already fetched for it, or — when no question stood out — a result list.
+// A question picked from a search keeps the results and tag hints, to
+// offer the next-best answered question when it has no good answer, and
+// the related questions listed under it once found.
+type navEntry struct {
+	question *mcp.QuestionData
+	results  *mcp.SOResponse
+	from     *mcp.SOResponse
+	tagHints []string
+	related  []mcp.QuestionData
+	// expandQuestion prints the folded part of a long question and
+	// folded is its length in lines; nil while the question is shown
+	// whole.
+	expandQuestion func()
+	folded         int
+}
+
+// navHistory is a browser-style history of the views in a REPL session.
+// Going back and then viewing something new drops the forward entries.
+type navHistory struct {
+	entries []*navEntry
+	pos     int // index of the current entry; -1 when empty
+}
+
+// history is the session's navigation stack.
+var history = navHistory{pos: -1}
+
+// push records e as the current view.
+func (h *navHistory) push(e *navEntry) {
+	h.entries = append(h.entries[:h.pos+1], e)
+	h.pos = len(h.entries) - 1
+}
+
+// back moves to the previous view, or returns nil at the start.
+func (h *navHistory) back() *navEntry {
+	if h.pos <= 0 {
+		return nil
+	}
+	h.pos--
+	return h.entries[h.pos]
+}
+
+// forward moves to the next view, or returns nil at the end.
+func (h *navHistory) forward() *navEntry {
+	if h.pos+1 >= len(h.entries) {
+		return nil
+	}
+	h.pos++
+	return h.entries[h.pos]
+}
+
+// browse shows e and then follows back/forward requests through the
+// history until the user returns to the prompt.  Revisited views are
+// redrawn from memory, without querying the server again.
+func browse(ctx context.Context, p provider.Provider, e *navEntry, revisit bool) error {
+	for e != nil {
+		step, err := showEntry(ctx, p, e, revisit)
+		if err != nil || step == navDone {
+			return err
+		}
+		e = navigate(step)
+		revisit = true
+	}
+	return nil
+}
+
+// navigate moves through the history, explaining when there is nowhere
+// to go.
+func navigate(step navStep) *navEntry {
+	var e *navEntry
+	if step == navBack {
+		if e = history.back(); e == nil {
+			fmt.Println(dimSty.Render(i18n.T("  Nothing further back.")))
+		}
+	} else {
+		if e = history.forward(); e == nil {
+			fmt.Println(dimSty.Render(i18n.T("  Nothing further forward.")))
+		}
+	}
+	return e
+}
+
+// showEntry renders a view.  Questions are followed by a few related
+// ones; those with answers go to the answer picker, whose navigation
+// line offers back, forward and the related questions.  With
+// display.no_question only the title is shown and the best answer opens
+// directly.
+func showEntry(ctx context.Context, p provider.Provider, e *navEntry, revisit bool) (navStep, error) {
+	if e.question == nil {
+		printDoc(ui.Document{Markdown: mcp.FormatSearchResults(e.results, 10), Data: e.results})
+		return navDone, nil
+	}
+	q := e.question
+	answersOnly := cfg.Display.NoQuestion && len(q.Answers) > 0
+	if answersOnly {
+		fmt.Println(promptSty.Render("  " + html.UnescapeString(q.Title)))
+	} else {
+		// A long question is folded when there are answers to get to;
+		// the asker's full dump matters less than they do.
+		keep := 0
+		if len(q.Answers) > 0 && interactive() {
+			keep = cfg.Display.QuestionLineLimit()
+		}
+		_, e.folded, e.expandQuestion = renderFolded(ui.Document{Markdown: ui.AnnotateCode(mcp.FormatQuestionHeader(q), q.Tags), Data: q}, keep)
+		if e.expandQuestion != nil {
+			fmt.Println(dimSty.Render(i18n.Tf("  ⋯ %d more lines of the question are folded; expand them from the answer list.", e.folded)))
+		}
+		printRelated(relatedOf(ctx, p, e))
+	}
+	if !revisit {
+		recordViewed(questionDoc(q))
+		recordUsage(questionEvent(q))
+	}
+	if !q.IsAnswered {
+		if next := offerAnswered(ctx, p, e); next != nil {
+			history.push(next)
+			return showEntry(ctx, p, next, false)
+		}
+	}
+	if len(q.Answers) > 0 {
+		return answerSelectionLoop(ctx, p, e, answersOnly)
+	}
+	if q.Link != "" {
+		fmt.Println(dimSty.Render(i18n.Tf("  View on Stack Overflow: %s", q.Link) + "\n"))
+	}
+	if len(e.related) > 0 && interactive() {
+		fmt.Println(dimSty.Render(keyHints(hint{actRelated, "open a related question"}, hint{actAnswers, "back to the prompt"})))
+		input, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if typedAction(input) == actRelated {
+			if next := pickRelated(ctx, p, e); next != nil {
+				history.push(next)
+				return showEntry(ctx, p, next, false)
+			}
+		}
+	}
+	return navDone, nil
+}
+
+// expandQuestion prints the part of e's question folded away when it
+// was shown, if any.
+func expandQuestion(e *navEntry) {
+	if e.expandQuestion == nil {
+		return
+	}
+	e.expandQuestion()
+	e.expandQuestion = nil
+}
+
+// offerAnswered notes that e's question is unanswered and, when the
+// search it came from has an answered question, offers to jump to the
+// best of them with a single key.  It returns that question's entry,
+// with its accepted answer fetched, or nil to stay.
+func offerAnswered(ctx context.Context, p provider.Provider, e *navEntry) *navEntry {
+	q := mcp.NextAnswered(e.from, e.question, e.tagHints)
+	if q == nil || !interactive() {
+		fmt.Println("  " + ui.UnansweredBadge() + dimSty.Render(i18n.T(" — no accepted or upvoted answer yet.")))
+		return nil
+	}
+	fmt.Println("  " + ui.UnansweredBadge() + dimSty.Render(i18n.T(" — no accepted or upvoted answer yet. Next-best answered question:")))
+	fmt.Println(promptSty.Render("  " + html.UnescapeString(q.Title)))
+	fmt.Println(dimSty.Render(keyHints(hint{actJump, "jump to it"}, hint{actAnswers, "stay here"})))
+	input, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if typedAction(input) != actJump {
+		return nil
+	}
+	return openQuestion(ctx, p, q, e)
+}
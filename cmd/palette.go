@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+)
+
+func init() { buildStyles() }
+
+// applyPalette switches to the palette chosen by display.palette or
+// --palette; empty keeps the default.
+func applyPalette() error {
+	if cfg.Display.Palette == "" {
+		return nil
+	}
+	if err := ui.SetPalette(cfg.Display.Palette); err != nil {
+		return err
+	}
+	buildStyles()
+	return nil
+}
+
+// buildStyles derives the command styles from the current palette.
+func buildStyles() {
+	p := ui.CurrentPalette()
+	brandStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(p.Brand).
+		MarginBottom(1)
+	errorStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(p.Error).
+		Border(ui.Border()).
+		BorderForeground(p.ErrorBox).
+		Padding(1, 2).
+		MarginTop(1).
+		MarginBottom(1)
+	cautionStyle = lipgloss.NewStyle().
+		Foreground(p.Progress).
+		Border(ui.Border()).
+		BorderForeground(p.Progress).
+		Padding(0, 1).
+		MarginTop(1)
+	infoStyle = lipgloss.NewStyle().
+		Foreground(p.Dim).
+		Italic(true)
+
+	spinnerSty = lipgloss.NewStyle().Foreground(p.Progress).Bold(true)
+	successSty = lipgloss.NewStyle().Foreground(p.Success).Bold(true)
+	promptSty = lipgloss.NewStyle().Foreground(p.Brand).Bold(true)
+	dimSty = lipgloss.NewStyle().Foreground(p.Dim)
+	matchSty = lipgloss.NewStyle().Foreground(p.Brand).Reverse(true).Bold(true)
+}
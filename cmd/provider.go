@@ -0,0 +1,371 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/breaker"
+	"github.com/ratnesh-maurya/flo/pkg/config"
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+)
+
+// keepaliver is implemented by providers holding a connection that
+// idle-time pings keep fresh (the MCP bridge).
+type keepaliver interface {
+	StartKeepalive(interval time.Duration)
+}
+
+// connect opens the configured backend and returns it behind the
+// response cache, with a function that releases both.  verbose reports
+// connection progress, as the interactive commands do.
+//
+// Unless failover is disabled, the backend is followed by its fallbacks
+// (see config.FailoverConfig) and its circuit is kept across runs: a
+// backend that keeps failing is skipped, without even starting the MCP
+// bridge, until it is due to be probed again.
+func connect(ctx context.Context, verbose bool) (provider.Provider, func(), error) {
+	primary, err := primaryBackend()
+	if err != nil {
+		printError(i18n.T("Invalid configuration"), err.Error())
+		return nil, nil, err
+	}
+	if primary == config.BackendMCP {
+		if _, err := cfg.MCP.Remote.BridgeArgs(); err != nil {
+			printError(i18n.T("Invalid configuration"), err.Error())
+			return nil, nil, err
+		}
+	}
+	fallbacks := cfg.Failover.FailoverBackends(primary)
+	for _, name := range fallbacks {
+		if name != config.FallbackAPI && name != config.FallbackCache {
+			err := fmt.Errorf("unknown failover backend %q (want %s or %s)", name, config.FallbackAPI, config.FallbackCache)
+			printError(i18n.T("Invalid configuration"), err.Error())
+			return nil, nil, err
+		}
+	}
+	circuits := openCircuits()
+	// The primary is skipped only for a live fallback, never for the
+	// cache alone.
+	apiFallback := slices.Contains(fallbacks, config.FallbackAPI)
+
+	var (
+		backends []provider.Backend
+		release  = func() {}
+		// down explains why the primary is missing from backends.
+		down error
+	)
+	open := apiFallback && circuits != nil && !circuits.Ready(primary, time.Now())
+	switch {
+	case open:
+		down = fmt.Errorf("%s keeps failing", backendName(primary))
+	case primary == config.BackendAPI:
+		rest, err := newREST()
+		if err != nil {
+			printError(i18n.T("Connection failed"), err.Error())
+			return nil, nil, err
+		}
+		backends = append(backends, provider.Backend{Name: primary, Provider: rest})
+		ui.Footer = i18n.T("Powered by the Stack Exchange API")
+		if s, ok := provider.LookupSite(currentSite()); ok && s.Param != provider.DefaultSite {
+			ui.Footer += " · " + s.Name
+		}
+		if cfg.Anonymous {
+			ui.Footer += " · " + i18n.T("anonymous, read-only")
+		}
+	case primary == config.BackendLocal:
+		idx, err := openIndex()
+		if err != nil {
+			printError(i18n.T("Local index unavailable"), err.Error())
+			return nil, nil, err
+		}
+		backends = append(backends, provider.Backend{Name: primary, Provider: provider.NewLocal(idx)})
+		ui.Footer = i18n.Tf("From the local index · %d posts", idx.Len())
+		// The index is on disk already.
+		noCache = true
+	case nonInteractive && mcp.NeedsLogin(mcpOptions()):
+		// The bridge would open a browser and wait.
+		slog.Warn("MCP login required; skipping the MCP server")
+		down = errLoginRequired
+	default:
+		client, err := connectMCP(ctx, verbose, len(fallbacks) == 0)
+		if err != nil {
+			if ctx.Err() != nil || len(fallbacks) == 0 {
+				if ctx.Err() == nil {
+					err = withExitCode(exitConnection, err)
+				}
+				return nil, nil, err
+			}
+			if circuits != nil {
+				circuits.Failure(primary, err, time.Now())
+			}
+			down = withExitCode(exitConnection, err)
+			break
+		}
+		unregister := onShutdown(func() { client.Close() })
+		release = func() {
+			unregister()
+			client.Close()
+		}
+		m := provider.NewMCP(client)
+		m.Answers = answerLister()
+		backends = append(backends, provider.Backend{Name: primary, Provider: m})
+	}
+	if apiFallback {
+		if rest, err := newREST(); err == nil {
+			backends = append(backends, provider.Backend{Name: config.FallbackAPI, Provider: rest})
+		} else {
+			slog.Warn("API fallback disabled", "err", err)
+		}
+	}
+	if down != nil && len(backends) > 0 {
+		if open {
+			failoverNotice(primary, nil)
+		} else {
+			failoverNotice(primary, down)
+		}
+	}
+
+	var p provider.Provider
+	switch {
+	case len(backends) == 0:
+		// Serve what the cache holds.
+		p = provider.Unavailable(down)
+	case circuits == nil:
+		p = backends[0].Provider
+	default:
+		f := provider.NewFailover(circuits, backends...)
+		f.OnFailover = failoverNotice
+		p = f
+	}
+
+	openCache()
+	if respCache != nil {
+		respCache.StaleIfError = slices.Contains(fallbacks, config.FallbackCache)
+	}
+	p = provider.Cached(p, respCache)
+	watchRefreshes(p)
+	if s, ok := p.(provider.StaleReporter); ok {
+		s.OnStale(staleNotice)
+	}
+	if s, ok := p.(provider.Saver); ok && primary == config.BackendAPI {
+		s.SaveRequests(quotaLow)
+	}
+	return p, func() {
+		closeCache()
+		release()
+	}, nil
+}
+
+// currentSite returns the API parameter of the configured site.
+func currentSite() string {
+	return provider.SiteParam(cfg.API.Site)
+}
+
+// postURL returns the address of a post on the configured site; kind
+// is "q" or "a".  Sites flo does not know by name get Stack Overflow
+// addresses, as before sites could be chosen.
+func postURL(kind string, id int) string {
+	host := "stackoverflow.com"
+	if s, ok := provider.LookupSite(currentSite()); ok {
+		host = s.Host
+	}
+	return fmt.Sprintf("https://%s/%s/%d", host, kind, id)
+}
+
+// primaryBackend returns the backend content comes from first: the
+// configured one, by default the MCP server.  The MCP server serves
+// Stack Overflow only, so any other site defaults to the API, and
+// asking the MCP server for it is an error.
+func primaryBackend() (string, error) {
+	site := currentSite()
+	switch cfg.Backend {
+	case "":
+		if !provider.ServedByMCP(site) {
+			slog.Info("site not served by the MCP server; using the Stack Exchange API", "site", site)
+			return config.BackendAPI, nil
+		}
+		return config.BackendMCP, nil
+	case config.BackendMCP:
+		if !provider.ServedByMCP(site) {
+			return "", fmt.Errorf("the MCP server serves Stack Overflow only, not %s; use --backend api", site)
+		}
+		return config.BackendMCP, nil
+	case config.BackendAPI, config.BackendLocal:
+		return cfg.Backend, nil
+	}
+	return "", fmt.Errorf("unknown backend %q (want %s, %s or %s)", cfg.Backend, config.BackendMCP, config.BackendAPI, config.BackendLocal)
+}
+
+// newREST returns the configured Stack Exchange API client.
+func newREST() (*provider.REST, error) {
+	// Requests are bounded per operation (see cfg.Timeouts).
+	hc, err := newHTTPClient(0)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("using Stack Exchange API", "site", currentSite(), "key", cfg.API.Key != "")
+	rest := provider.NewREST(hc, currentSite(), cfg.API.Key)
+	rest.BaseURL = cfg.API.URL
+	rest.OnRequest = recordAPICall
+	rest.Budget = apiBudget()
+	return rest, nil
+}
+
+// openCircuits loads the backends' circuits, or returns nil when
+// failover is disabled.
+func openCircuits() *breaker.Breakers {
+	if cfg.Failover.Disabled {
+		return nil
+	}
+	dir, err := config.CacheDir()
+	if err != nil {
+		slog.Warn("circuit breaker disabled", "err", err)
+		return nil
+	}
+	return breaker.Load(breaker.DefaultPath(dir), cfg.Failover.Threshold, cfg.Failover.Cooldown)
+}
+
+// backendName names a backend or fallback for humans.
+func backendName(name string) string {
+	switch name {
+	case config.BackendMCP:
+		return i18n.T("the MCP server")
+	case config.BackendAPI:
+		return i18n.T("the Stack Exchange API")
+	case config.BackendLocal:
+		return i18n.T("the local index")
+	}
+	return name
+}
+
+// failedOver records the backends failover has been announced for, so
+// each is reported once per run.
+var failedOver sync.Map
+
+// failoverNotice tells the user that name is being skipped, after err
+// (nil: its circuit is open).
+func failoverNotice(name string, err error) {
+	if _, told := failedOver.LoadOrStore(name, true); told {
+		return
+	}
+	msg := i18n.Tf("  ⚠ Skipping %s, which keeps failing (see flo doctor).", backendName(name))
+	switch {
+	case errors.Is(err, mcp.ErrUnauthorized):
+		msg = i18n.T("  ⚠ Your Stack Overflow login expired and could not be renewed; trying the next backend.")
+	case err != nil:
+		msg = i18n.Tf("  ⚠ Could not use %s (%v); trying the next backend.", backendName(name), err)
+	}
+	fmt.Fprintln(os.Stderr, dimSty.Render(msg))
+}
+
+// staleNoticeOnce reports the first expired copy shown in a run only.
+var staleNoticeOnce sync.Once
+
+// staleNotice tells the user that an expired cached copy is shown.
+func staleNotice(age time.Duration, err error) {
+	staleNoticeOnce.Do(func() {
+		now := time.Now()
+		fmt.Fprintln(os.Stderr, dimSty.Render(i18n.Tf(
+			"  ⚠ No backend answered (%v); showing a cached copy from %s.", err, mcp.RelativeTime(now.Add(-age), now))))
+	})
+}
+
+// answerLister returns the Stack Exchange API client the MCP backend
+// pages through answers with, or nil when api.no_answer_paging is set.
+// Listing answers needs no login, so it works alongside the MCP login.
+func answerLister() provider.AnswerLister {
+	if cfg.API.NoAnswerPaging {
+		return nil
+	}
+	hc, err := newHTTPClient(0)
+	if err != nil {
+		slog.Warn("answer paging disabled", "err", err)
+		return nil
+	}
+	rest := provider.NewREST(hc, provider.DefaultSite, cfg.API.Key)
+	rest.BaseURL = cfg.API.URL
+	rest.Budget = apiBudget()
+	return rest
+}
+
+// reportLoginExpired explains a login the MCP server no longer accepts
+// and that could not be renewed.
+func reportLoginExpired() {
+	var b strings.Builder
+	b.WriteString(i18n.T("Stack Overflow no longer accepts your login, and it could not be renewed.") + "\n\n")
+	if nonInteractive {
+		b.WriteString(i18n.T("Run flo once in a terminal to log in again, or use --backend api."))
+	} else {
+		b.WriteString(i18n.T("Run flo again to log in through the browser."))
+	}
+	if dir := mcp.TokenDir(mcpOptions()); dir != "" {
+		b.WriteString("\n" + i18n.Tf("If that keeps failing, delete the saved login in %s first.", dir))
+	}
+	printError(i18n.T("Login expired"), b.String())
+}
+
+// connectMCP starts the MCP bridge.  The mcp-remote bridge communicates
+// over stdin/stdout JSON-RPC; the first run opens a browser for OAuth
+// and later runs reuse the token.  Failures are explained to the user
+// when report is set; otherwise the caller has somewhere else to go.
+func connectMCP(ctx context.Context, verbose, report bool) (*mcp.Client, error) {
+	if verbose {
+		status(spinnerSty, i18n.T("⏳ Connecting to Stack Overflow MCP server..."), "connecting to MCP server",
+			"url", cfg.MCP.URL, "command", bridgeCommand())
+		if h, _ := headless(); h {
+			fmt.Println(dimSty.Render(i18n.T("  (first run prints an address to log in at from another device)")))
+		} else {
+			fmt.Println(dimSty.Render(i18n.T("  (first run may open a browser for Stack Overflow login)")))
+		}
+		if remoteDownload() {
+			fmt.Println(dimSty.Render(i18n.T("  (npx is downloading mcp-remote first, which can take minutes; `flo setup` does it ahead of time)")))
+		}
+	}
+
+	connectCtx, cancel := context.WithTimeout(ctx, cfg.Timeouts.ConnectTimeout())
+	defer cancel()
+
+	start := time.Now()
+	client, err := mcp.NewClient(connectCtx, mcpOptions())
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err() // interrupted; nothing to report
+		}
+		slog.Error("MCP connect failed", "err", err)
+		if !report {
+			return nil, err
+		}
+		if connectCtx.Err() != nil {
+			printError(i18n.T("Connection timed out"), i18n.Tf(
+				"The MCP server did not answer within %s. Try again, or raise timeouts.connect in the config.",
+				cfg.Timeouts.ConnectTimeout()))
+			return nil, err
+		}
+		if errors.Is(err, mcp.ErrUnauthorized) {
+			reportLoginExpired()
+			return nil, err
+		}
+		if strings.Contains(err.Error(), "not found") {
+			printError(i18n.T("Node.js not found"), nodeHelp)
+			return nil, fmt.Errorf("npx not found")
+		}
+		printError(i18n.T("Connection failed"), err.Error())
+		return nil, err
+	}
+
+	if verbose {
+		status(successSty, i18n.T("✅ Connected!"), "connected to MCP server", "elapsed", time.Since(start))
+		fmt.Println()
+	}
+	return client, nil
+}
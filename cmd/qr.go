@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+)
+
+// showQR prints the question's link as a QR code, to open the thread on
+// a phone when there is no local browser, e.g. over SSH.
+func showQR(q *mcp.QuestionData) {
+	link := q.Link
+	if link == "" && q.QuestionID != 0 {
+		link = postURL("q", q.QuestionID)
+	}
+	if link == "" {
+		printError(i18n.T("No link"), i18n.T("This post has no URL to encode."))
+		return
+	}
+	code, err := ui.QRCode(link)
+	if err != nil {
+		printError(i18n.T("Could not draw QR code"), err.Error())
+		return
+	}
+	fmt.Println()
+	fmt.Print(code)
+	fmt.Println(dimSty.Render("  " + link))
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/quality"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+)
+
+var (
+	checkerOnce sync.Once
+	// checker is nil when cautions are disabled or the rules are invalid.
+	checker *quality.Checker
+)
+
+// qualityChecker builds the checker from the config on first use.  An
+// invalid user rule is reported once and disables the cautions.
+func qualityChecker() *quality.Checker {
+	checkerOnce.Do(func() {
+		if cfg.Quality.Disabled {
+			return
+		}
+		var rules []quality.Rule
+		if !cfg.Quality.NoBuiltin {
+			rules = append(rules, quality.Builtin...)
+		}
+		for _, r := range cfg.Quality.Rules {
+			rules = append(rules, quality.Rule{Tags: r.Tags, Pattern: r.Pattern, Message: r.Message})
+		}
+		c, err := quality.NewChecker(rules)
+		if err != nil {
+			printError("Invalid configuration", err.Error()+"\n\nAnswer quality cautions are off until it is fixed.")
+			return
+		}
+		checker = c
+	})
+	return checker
+}
+
+// printCautions shows a banner above answer a when it looks outdated.
+func printCautions(q *mcp.QuestionData, a *mcp.AnswerData) {
+	c := qualityChecker()
+	if c == nil {
+		return
+	}
+	warnings := c.Check(q, a)
+	if len(warnings) == 0 {
+		return
+	}
+	slog.Info("answer flagged as possibly outdated", "answer_id", a.AnswerID, "warnings", warnings)
+	var b strings.Builder
+	b.WriteString(i18n.T("⚠ Caution: this answer may be outdated"))
+	for _, w := range warnings {
+		fmt.Fprintf(&b, "\n  • %s", w)
+	}
+	fmt.Println(cautionStyle.Render(ui.PlainText(b.String())))
+}
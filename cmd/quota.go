@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/config"
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+	"github.com/ratnesh-maurya/flo/pkg/quota"
+)
+
+var (
+	budgetOnce sync.Once
+	// budget is nil when its count cannot be kept.
+	budget *quota.Budget
+)
+
+// apiBudget loads the day's budget of API requests on first use; every
+// API client of the run shares it.
+func apiBudget() *quota.Budget {
+	budgetOnce.Do(func() {
+		dir, err := config.CacheDir()
+		if err != nil {
+			slog.Warn("API budget disabled", "err", err)
+			return
+		}
+		budget = quota.Load(quota.DefaultPath(dir), cfg.API.DailyBudget)
+	})
+	return budget
+}
+
+// quotaNoticeOnce reports a low budget once per run.
+var quotaNoticeOnce sync.Once
+
+// quotaLow reports whether the API budget is low, telling the user once
+// that flo is saving requests.
+func quotaLow() bool {
+	b := apiBudget()
+	if b == nil {
+		return false
+	}
+	now := time.Now()
+	s := b.Status(now)
+	if !s.Low() {
+		return false
+	}
+	quotaNoticeOnce.Do(func() {
+		renews := quota.Wait(quota.Renews(now).Sub(now))
+		msg := i18n.Tf("  ⚠ %d of today's %d API requests left; using the cache where possible and skipping extras until the quota renews in %s.", s.Left, s.Limit, renews)
+		if s.Spent() {
+			msg = i18n.Tf("  ⚠ Today's %d API requests are used up; showing cached copies until the quota renews in %s.", s.Limit, renews)
+		}
+		fmt.Fprintln(os.Stderr, spinnerSty.Render(msg))
+	})
+	return true
+}
+
+// quotaStatus describes the day's API budget, for flo doctor.
+func quotaStatus() string {
+	b := apiBudget()
+	if b == nil {
+		return "not counted"
+	}
+	now := time.Now()
+	s := b.Status(now)
+	line := fmt.Sprintf("%d of %d requests used today", s.Used, s.Limit)
+	if s.QuotaRemaining >= 0 {
+		line += fmt.Sprintf("; the API reports %d left", s.QuotaRemaining)
+	}
+	line += "; renews in " + quota.Wait(quota.Renews(now).Sub(now))
+	if s.Low() {
+		return spinnerSty.Render(line)
+	}
+	return line
+}
@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/config"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+	"github.com/ratnesh-maurya/flo/pkg/rawlog"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rawList     bool
+	rawEnvelope bool
+	rawCheck    bool
+)
+
+var rawCmd = &cobra.Command{
+	Use:   "raw [id]",
+	Short: "Show the MCP server's reply to a recent call, unparsed",
+	Long: `Show what the MCP server sent back for a recent call, exactly as it came,
+pretty-printed and colored like jq.  When flo shows nothing, or the wrong
+thing, for a search that works on the website, the reply shows whether
+the server changed its format.
+
+flo keeps the last 50 calls in <cache dir>/raw.  Without an id the last
+call is shown; --list lists them with their ids.  The text of the reply
+(the JSON flo parses) is printed on its own; --envelope prints the whole
+tool result around it.
+
+--check reads the reply as a search does and reports where it departs
+from the shape flo expects: the envelope the posts came in, fields
+renamed or of another type, and each expected field missing, with how
+many posts lack it.  flo reads such replies as far as it can and says
+so; this shows exactly what it could not read.
+
+  flo raw              the last call
+  flo raw --list       the calls kept
+  flo raw 42 | jq .    call 42, through jq
+  flo raw --check      what in the last reply flo could not read
+  flo ask --raw "reverse a string in go"   search, then show the reply`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRaw,
+}
+
+func init() {
+	rawCmd.Flags().BoolVarP(&rawList, "list", "l", false, "list the calls kept, newest last")
+	rawCmd.Flags().BoolVar(&rawEnvelope, "envelope", false, "print the whole tool result, not just its text")
+	rawCmd.Flags().BoolVar(&rawCheck, "check", false, "report where the reply departs from the shape flo expects")
+	rootCmd.AddCommand(rawCmd)
+}
+
+// rawDir returns where the MCP replies are kept.
+func rawDir() (string, error) {
+	dir, err := config.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return rawlog.DefaultDir(dir), nil
+}
+
+var (
+	rawMu sync.Mutex
+	// rawCalls are the ids of the calls recorded by this run.
+	rawCalls []int
+)
+
+// recordRaw keeps the reply to c for `flo raw`.  Failures are logged but
+// never fail the call.
+func recordRaw(c mcp.Call) {
+	e := rawlog.Entry{Tool: c.Tool, Args: c.Args, Result: c.Result, Text: c.Text}
+	if c.Err != nil {
+		e.Error = c.Err.Error()
+	}
+	rawMu.Lock()
+	defer rawMu.Unlock()
+	dir, err := rawDir()
+	if err != nil {
+		slog.Warn("record MCP reply", "err", err)
+		return
+	}
+	id, err := rawlog.Record(dir, e)
+	if err != nil {
+		slog.Warn("record MCP reply", "err", err)
+		return
+	}
+	rawCalls = append(rawCalls, id)
+}
+
+// runRaw implements `flo raw`.
+func runRaw(cmd *cobra.Command, args []string) error {
+	dir, err := rawDir()
+	if err != nil {
+		printError("Replies unavailable", err.Error())
+		return err
+	}
+	if rawList {
+		return listRaw(dir)
+	}
+
+	var id int
+	if len(args) == 1 {
+		if id, err = strconv.Atoi(strings.TrimPrefix(args[0], "#")); err != nil || id < 1 {
+			err := fmt.Errorf("invalid call id %q", args[0])
+			printError("Invalid id", "Give the number flo raw --list shows for the call.")
+			return withExitCode(exitUsage, err)
+		}
+	} else {
+		ids, err := rawlog.IDs(dir)
+		if err != nil {
+			printError("Replies unreadable", err.Error())
+			return err
+		}
+		if len(ids) == 0 {
+			fmt.Fprintln(os.Stderr, dimSty.Render("No MCP calls recorded yet — the replies are kept as flo makes them."))
+			return nil
+		}
+		id = ids[len(ids)-1]
+	}
+	e, err := rawlog.Load(dir, id)
+	if errors.Is(err, fs.ErrNotExist) {
+		printError("No such call", fmt.Sprintf("Call %d is not kept; flo raw --list shows the ones that are.", id))
+		return withExitCode(exitUsage, fmt.Errorf("call %d is not kept", id))
+	}
+	if err != nil {
+		printError("Reply unreadable", err.Error())
+		return err
+	}
+	if rawCheck {
+		return checkRaw(e)
+	}
+	printRaw(e)
+	return nil
+}
+
+// checkRaw reports how the reply kept in e departs from the shape flo
+// expects.
+func checkRaw(e *rawlog.Entry) error {
+	fmt.Println(dimSty.Render(fmt.Sprintf("Call %d · %s %s · %s", e.ID, e.Tool, rawArgs(e.Args), e.Time.Format(time.DateTime))))
+	if e.Error != "" {
+		fmt.Println(dimSty.Render("  The call failed, so there is no reply to check: " + e.Error))
+		return nil
+	}
+	if e.Text == "" {
+		fmt.Println(dimSty.Render("  The reply has no text; flo reads it as nothing found."))
+		return nil
+	}
+	resp, err := mcp.ParseResponse(e.Text)
+	if err != nil {
+		printError("Could not read the reply", err.Error())
+		return err
+	}
+	if resp.Drift == nil {
+		fmt.Println(successSty.Render(fmt.Sprintf("  ✔ The reply is in the shape flo expects (%d %s).",
+			len(resp.Items), plural(len(resp.Items), "post", "posts"))))
+		return nil
+	}
+	fmt.Println(spinnerSty.Render(fmt.Sprintf("  ⚠ The reply has changed shape: %s. flo read %d %s from it.",
+		resp.Drift.Summary(), resp.Drift.Posts, plural(resp.Drift.Posts, "post", "posts"))))
+	for _, l := range resp.Drift.Details() {
+		fmt.Println("    " + l)
+	}
+	return nil
+}
+
+// listRaw prints the calls kept in dir.
+func listRaw(dir string) error {
+	entries, err := rawlog.List(dir)
+	if err != nil {
+		printError("Replies unreadable", err.Error())
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Println(dimSty.Render("No MCP calls recorded yet — the replies are kept as flo makes them."))
+		return nil
+	}
+	now := time.Now()
+	for _, e := range entries {
+		outcome := fmt.Sprintf("%d bytes", len(e.Text))
+		if e.Error != "" {
+			outcome = "failed"
+		}
+		fmt.Printf("  %s  %-16s %-12s %s  %s\n", promptSty.Render(fmt.Sprintf("%4d", e.ID)),
+			mcp.RelativeTime(e.Time, now), e.Tool, rawArgs(e.Args), dimSty.Render(outcome))
+	}
+	return nil
+}
+
+// rawArgs renders the arguments of a call on one line.
+func rawArgs(args map[string]any) string {
+	data, err := json.Marshal(args)
+	if err != nil || len(args) == 0 {
+		return "{}"
+	}
+	return string(data)
+}
+
+// printRaw prints e: a line about the call on stderr, so stdout can be
+// piped to jq, then the reply on stdout.
+func printRaw(e *rawlog.Entry) {
+	fmt.Fprintln(os.Stderr, dimSty.Render(fmt.Sprintf("Call %d · %s %s · %s", e.ID, e.Tool, rawArgs(e.Args), e.Time.Format(time.DateTime))))
+	if e.Error != "" {
+		printError("The call failed", e.Error)
+		return
+	}
+	data := []byte(e.Text)
+	if rawEnvelope || !json.Valid(data) {
+		if !rawEnvelope {
+			fmt.Fprintln(os.Stderr, dimSty.Render("The text of the reply is not JSON; showing the whole tool result."))
+		}
+		data = e.Result
+	}
+	fmt.Println(colorJSON(data))
+}
+
+// askRawSearch implements `flo ask --raw`: it searches for each query
+// without the response cache and prints the replies the searches got
+// instead of the results.
+func askRawSearch(ctx context.Context, queries []string) error {
+	noCache = true
+	p, release, err := connect(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	for _, query := range queries {
+		if !askVerbatim {
+			query = mcp.NormalizeQuery(query)
+		}
+		sctx, cancel := context.WithTimeout(ctx, cfg.Timeouts.SearchTimeout())
+		_, err := p.Search(sctx, query)
+		cancel()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// A reply that does not parse is what --raw is for.
+		if err != nil && !errors.Is(err, provider.ErrNotFound) {
+			slog.Warn("search failed", "query", query, "err", err)
+		}
+	}
+
+	dir, err := rawDir()
+	if err != nil {
+		printError("Replies unavailable", err.Error())
+		return err
+	}
+	rawMu.Lock()
+	ids := rawCalls
+	rawMu.Unlock()
+	if len(ids) == 0 {
+		err := errors.New("no MCP calls were made")
+		printError("Nothing to show", "The search made no MCP call: --raw shows the replies of the MCP server, and the search went to another backend.")
+		return err
+	}
+	for _, id := range ids {
+		e, err := rawlog.Load(dir, id)
+		if err != nil {
+			printError("Reply unreadable", err.Error())
+			return err
+		}
+		printRaw(e)
+	}
+	return nil
+}
+
+// colorJSON indents data and colors it the way jq does: keys, strings,
+// numbers and true, false and null apart.  Text that is not JSON is
+// returned as is.
+func colorJSON(data []byte) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return string(data)
+	}
+	src := buf.String()
+	if ui.Plain() {
+		return src
+	}
+	var out strings.Builder
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '"':
+			j := i + 1
+			for ; j < len(src) && src[j] != '"'; j++ {
+				if src[j] == '\\' {
+					j++
+				}
+			}
+			j++
+			sty := successSty
+			if strings.HasPrefix(strings.TrimLeft(src[j:], " "), ":") {
+				sty = promptSty
+			}
+			out.WriteString(sty.Render(src[i:j]))
+			i = j
+		case c == '-' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z':
+			j := i
+			for j < len(src) && strings.IndexByte("+-.0123456789Eabcdefghijklmnopqrstuvwxyz", src[j]) >= 0 {
+				j++
+			}
+			sty := spinnerSty
+			if c >= 'a' && c <= 'z' {
+				sty = dimSty
+			}
+			out.WriteString(sty.Render(src[i:j]))
+			i = j
+		default:
+			out.WriteByte(c)
+			i++
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+)
+
+// searchContext is the most recent search in a REPL session, kept so a
+// follow-up ("> only with generics") can refine it.
+type searchContext struct {
+	query string
+	resp  *mcp.SOResponse
+}
+
+// lastSearch is the current refinement context; nil after /reset or
+// before the first search.
+var lastSearch *searchContext
+
+// refineLast handles a "> <refinement>" REPL line: it re-queries with
+// the previous terms plus the refinement, merges the new results with
+// the previous ones, and shows the best match among those that fit the
+// refinement.  The combined query becomes the new context, so
+// refinements stack until /reset.
+func refineLast(parent context.Context, p provider.Provider, text string) error {
+	prev := lastSearch
+	if prev == nil {
+		fmt.Println(dimSty.Render(i18n.T("  Nothing to refine yet — ask a question first.")))
+		return nil
+	}
+	include, exclude := mcp.RefinementTerms(text)
+	if len(include) == 0 && len(exclude) == 0 {
+		fmt.Println(dimSty.Render(i18n.T("  Add terms to refine by, e.g. > only with generics")))
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(parent, cfg.Timeouts.SearchTimeout())
+	defer cancel()
+
+	combined := strings.TrimSpace(prev.query + " " + strings.Join(include, " "))
+	status(spinnerSty, "\n"+i18n.Tf("🔎 Refining: %q", combined)+"\n", "refining",
+		"previous", prev.query, "include", include, "exclude", exclude)
+
+	// A failed or empty re-query is not fatal: the previous results can
+	// still be re-ranked on their own.
+	var fresh *mcp.SOResponse
+	if len(include) > 0 {
+		var err error
+		if fresh, err = p.Search(ctx, combined); err != nil {
+			if parent.Err() != nil {
+				return parent.Err()
+			}
+			slog.Warn("refinement re-query failed", "err", err)
+		}
+	}
+
+	merged := mcp.MergeResponses(fresh, prev.resp)
+	refined := mcp.Refine(merged, include, exclude)
+	if refined == nil {
+		printError(i18n.T("No match"), i18n.T("None of the results match that refinement. Try other terms, or /reset to start over."))
+		return nil
+	}
+	slog.Debug("refined results", "candidates", len(merged.Items), "kept", len(refined.Items))
+
+	lastSearch = &searchContext{query: combined, resp: merged}
+	return showResults(parent, p, refined, detectTagHints(combined))
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+)
+
+// refreshes holds what background cache refreshes found changed, by
+// question ID, until the question is reloaded with [r].
+var refreshes = struct {
+	sync.Mutex
+	pending map[int]provider.Refresh
+}{pending: map[int]provider.Refresh{}}
+
+// watchRefreshes collects the changes p's background refreshes find.
+func watchRefreshes(p provider.Provider) {
+	if r, ok := p.(provider.Refresher); ok {
+		r.OnRefresh(noteRefresh)
+	}
+}
+
+// noteRefresh records r, merging it with an earlier refresh of the same
+// question — its search result and its answer list are refreshed apart.
+func noteRefresh(r provider.Refresh) {
+	refreshes.Lock()
+	defer refreshes.Unlock()
+	if prev, ok := refreshes.pending[r.QuestionID]; ok {
+		if r.Question == nil {
+			r.Question = prev.Question
+		}
+		r.Answers = mergeAnswers(prev.Answers, r.Answers)
+		r.NewAnswers = max(r.NewAnswers, prev.NewAnswers)
+		r.Edited = max(r.Edited, prev.Edited)
+	}
+	refreshes.pending[r.QuestionID] = r
+}
+
+// pendingRefresh returns the refresh waiting for question id, if any.
+func pendingRefresh(id int) (provider.Refresh, bool) {
+	refreshes.Lock()
+	defer refreshes.Unlock()
+	r, ok := refreshes.pending[id]
+	return r, ok
+}
+
+// reloadQuestion updates q with its pending refresh, returning false
+// when there is none.
+func reloadQuestion(q *mcp.QuestionData) bool {
+	refreshes.Lock()
+	r, ok := refreshes.pending[q.QuestionID]
+	delete(refreshes.pending, q.QuestionID)
+	refreshes.Unlock()
+	if !ok {
+		return false
+	}
+	answers := mergeAnswers(q.Answers, r.Answers)
+	if r.Question != nil {
+		*q = *r.Question
+	}
+	q.Answers = answers
+	q.AnswerCount = max(q.AnswerCount, len(answers))
+	return true
+}
+
+// mergeAnswers returns have with the answers of fresh replacing those
+// with the same ID and the others appended.
+func mergeAnswers(have, fresh []mcp.AnswerData) []mcp.AnswerData {
+	out := append([]mcp.AnswerData(nil), have...)
+	at := make(map[int]int, len(out))
+	for i, a := range out {
+		at[a.AnswerID] = i
+	}
+	for _, a := range fresh {
+		if i, ok := at[a.AnswerID]; ok {
+			out[i] = a
+			continue
+		}
+		at[a.AnswerID] = len(out)
+		out = append(out, a)
+	}
+	return out
+}
+
+// refreshNotice describes a pending refresh in one line.
+func refreshNotice(r provider.Refresh) string {
+	var parts []string
+	if r.NewAnswers > 0 {
+		parts = append(parts, fmt.Sprintf(i18n.Plural(r.NewAnswers, "%d new answer available", "%d new answers available"), r.NewAnswers))
+	}
+	if r.Edited > 0 {
+		parts = append(parts, fmt.Sprintf(i18n.Plural(r.Edited, "%d answer edited", "%d answers edited"), r.Edited))
+	}
+	return "  " + i18n.Tf("↻ Updated — %s — press %s", strings.Join(parts, ", "), keyName(actRelated))
+}
+
+// plural returns one when n is 1, many otherwise.
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
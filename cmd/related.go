@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+
+	"github.com/manifoldco/promptui"
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+	"github.com/ratnesh-maurya/flo/pkg/quota"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+)
+
+// Related questions listed under a question: at most relatedMax, and
+// the backend is asked for more when the search gave fewer than
+// relatedMin.
+const (
+	relatedMax = 5
+	relatedMin = 3
+)
+
+// relatedOf returns the questions related to e's question, ranked by
+// shared tags and title words, from the search it came from and, when
+// that has too few, from the backend.  The list is kept in e, so
+// revisits draw it without fetching.
+func relatedOf(ctx context.Context, p provider.Provider, e *navEntry) []mcp.QuestionData {
+	if e.related != nil || cfg.Display.NoRelated {
+		return e.related
+	}
+	q := e.question
+	related := mcp.Related(q, e.from, relatedMax)
+	if len(related) < relatedMin && q.QuestionID != 0 {
+		// Listing them is an extra, skipped when the API budget is low.
+		fctx, cancel := context.WithTimeout(quota.Extra(ctx), cfg.Timeouts.FetchTimeout())
+		defer cancel()
+		more, err := provider.RelatedCandidates(fctx, p, q)
+		if err != nil {
+			slog.Warn("fetch related questions failed", "question_id", q.QuestionID, "err", err)
+		} else {
+			related = mcp.Related(q, mcp.MergeResponses(&mcp.SOResponse{Items: related}, more), relatedMax)
+		}
+	}
+	e.related = append([]mcp.QuestionData{}, related...)
+	return e.related
+}
+
+// printRelated lists related questions below a question.
+func printRelated(related []mcp.QuestionData) {
+	if len(related) == 0 {
+		return
+	}
+	out := chrome()
+	fmt.Fprintln(out, dimSty.Render(i18n.T("  Related questions:")))
+	for i := range related {
+		q := &related[i]
+		line := fmt.Sprintf("    %d. %s (%d)", i+1, html.UnescapeString(q.Title), q.Score)
+		if !q.IsAnswered {
+			line += " " + ui.UnansweredBadge()
+		}
+		fmt.Fprintln(out, dimSty.Render(line))
+	}
+	fmt.Fprintln(out)
+}
+
+// pickRelated lets the user choose one of e's related questions and
+// returns its entry, or nil when they cancel.
+func pickRelated(ctx context.Context, p provider.Provider, e *navEntry) *navEntry {
+	if len(e.related) == 0 {
+		fmt.Println(dimSty.Render(i18n.T("  No related questions.")))
+		return nil
+	}
+	items := make([]string, len(e.related))
+	for i := range e.related {
+		q := &e.related[i]
+		items[i] = fmt.Sprintf("%s %s", ui.ScoreBadge(q.Score, q.AcceptedAnswerID > 0), html.UnescapeString(q.Title))
+		if !q.IsAnswered {
+			items[i] += " " + ui.UnansweredBadge()
+		}
+	}
+	sel := promptui.Select{
+		Label:     i18n.T("Open a related question") + " " + pickHint("view", "cancel"),
+		Items:     items,
+		Size:      len(items),
+		Templates: selectTemplates(),
+		HideHelp:  ui.Plain(),
+		Stdout:    bellSkipper{},
+		Stdin:     pickerStdin(),
+	}
+	i, _, err := sel.Run()
+	if err != nil {
+		return nil
+	}
+	q := e.related[i]
+	return openQuestion(ctx, p, &q, e)
+}
+
+// openQuestion prepares q, found while viewing e, to be shown: it
+// fetches the accepted answer when q has no answers and follows a
+// duplicate to its original.
+func openQuestion(ctx context.Context, p provider.Provider, q *mcp.QuestionData, e *navEntry) *navEntry {
+	if len(q.Answers) == 0 && q.AcceptedAnswerID > 0 {
+		status(spinnerSty, i18n.T("📖 Fetching accepted answer..."), "fetching accepted answer",
+			"question_id", q.QuestionID, "answer_id", q.AcceptedAnswerID)
+		fetchAcceptedAnswer(ctx, p, q)
+	}
+	q = resolveDuplicate(ctx, p, q)
+	return &navEntry{question: q, from: e.from, tagHints: e.tagHints}
+}
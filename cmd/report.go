@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/config"
+	"github.com/ratnesh-maurya/flo/pkg/crash"
+	"github.com/ratnesh-maurya/flo/pkg/logging"
+	"github.com/ratnesh-maurya/flo/pkg/update"
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Write a diagnostic bundle and prepare a GitHub issue to attach it to",
+	Long: `When flo crashes it writes a diagnostic bundle, a text file with the
+stack trace, the versions involved, the config and the last log records,
+and prints its path.  flo report helps you file it: it picks the latest
+crash bundle of the past week, or writes a new one, and prints a link
+to a new GitHub issue, prefilled with the versions.
+
+Keys, tokens and passwords are redacted from bundles and your home
+directory is shown as ~, but queries and paths are kept: read the file
+through before attaching it.  Bundles are kept in flo/crash in your
+user cache directory (~/.cache/flo/crash on Linux).`,
+	Args: cobra.NoArgs,
+	RunE: runReport,
+}
+
+var reportFresh bool
+
+func init() {
+	reportCmd.Flags().BoolVar(&reportFresh, "new", false, "write a new bundle even when there is a recent crash bundle")
+	rootCmd.AddCommand(reportCmd)
+}
+
+// reportMaxAge is how recent a crash bundle flo report picks must be.
+const reportMaxAge = 7 * 24 * time.Hour
+
+// runReport implements `flo report`.
+func runReport(cmd *cobra.Command, args []string) error {
+	dir, err := crash.Dir()
+	if err != nil {
+		printError("Could not write the diagnostic bundle", err.Error())
+		return err
+	}
+	path, when, err := crash.Latest(dir)
+	if err != nil || reportFresh || time.Since(when) > reportMaxAge {
+		path = ""
+	}
+	title := "Bug: "
+	if path != "" {
+		fmt.Println(promptSty.Render("📎 Diagnostic bundle of the crash on " + when.Format("Jan 2 15:04") + ":"))
+		title += crashSummary(path)
+	} else {
+		path, err = crash.Save(dir, newBundle("", ""))
+		if err != nil {
+			printError("Could not write the diagnostic bundle", err.Error())
+			return err
+		}
+		fmt.Println(promptSty.Render("📎 Diagnostic bundle:"))
+	}
+	fmt.Println("  " + path)
+	fmt.Println(dimSty.Render("  Read it through, then attach it to the issue: drag it into the description."))
+	fmt.Println()
+	fmt.Println(promptSty.Render("Open a new issue:"))
+	fmt.Println("  " + issueURL(title, path))
+	return nil
+}
+
+// crashSummary returns the first line of the Crash section of the
+// bundle at path, for an issue title.
+func crashSummary(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	_, after, found := strings.Cut(string(data), "\n== Crash ==\n")
+	if !found {
+		return ""
+	}
+	line, _, _ := strings.Cut(after, "\n")
+	const max = 80
+	if len(line) > max {
+		line = line[:max] + "…"
+	}
+	return line
+}
+
+// issueURL returns the link to a new flo issue with title and a body
+// listing the versions and asking for the bundle at path.
+func issueURL(title, path string) string {
+	var body strings.Builder
+	body.WriteString("### What happened\n\n<!-- What you ran, what you expected, what you got. -->\n\n")
+	body.WriteString("### Versions\n\n")
+	for _, v := range bundleVersions() {
+		fmt.Fprintf(&body, "- %s: %s\n", v[0], crash.RedactString(v[1]))
+	}
+	fmt.Fprintf(&body, "\n### Diagnostic bundle\n\n<!-- Attach %s here. -->\n", filepath.Base(path))
+	q := url.Values{"title": {title}, "body": {body.String()}}
+	return "https://github.com/" + update.Repo + "/issues/new?" + q.Encode()
+}
+
+// newBundle gathers a diagnostic bundle of this run; reason and stack
+// are empty for one made on request.
+func newBundle(reason, stack string) *crash.Bundle {
+	return &crash.Bundle{
+		Time:     time.Now(),
+		Reason:   reason,
+		Stack:    stack,
+		Args:     os.Args[1:],
+		Versions: bundleVersions(),
+		Config:   cfg,
+		Log:      logging.Recent(),
+	}
+}
+
+// bundleVersions lists what a bug report needs to know of the setup.
+func bundleVersions() [][2]string {
+	flo := version
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" {
+				flo += " (" + s.Value + ")"
+			}
+		}
+	}
+	remote := remoteVersion() + " via npx"
+	if cfg.MCP.Command != "" {
+		remote = "mcp.command is set"
+	} else if inst := managedRemote(); inst != nil {
+		remote = inst.Version + " (flo setup)"
+	}
+	backend, err := primaryBackend()
+	if err != nil {
+		backend = cfg.Backend
+	}
+	versions := [][2]string{
+		{"flo", flo},
+		{"go", runtime.Version()},
+		{"os", runtime.GOOS + "/" + runtime.GOARCH},
+		{"backend", backend + " (" + currentSite() + ")"},
+		{"mcp-remote", remote},
+		{"terminal", os.Getenv("TERM")},
+		{"locale", firstEnv("LC_ALL", "LC_MESSAGES", "LANG")},
+	}
+	if config.Profile != "" {
+		versions = append(versions, [2]string{"profile", config.Profile})
+	}
+	return versions
+}
+
+// firstEnv returns the first of the environment variables set.
+func firstEnv(names ...string) string {
+	for _, n := range names {
+		if v := os.Getenv(n); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// crashTrace is where the runtime writes a fatal error of this process.
+var crashTrace *os.File
+
+// startCrashTrace has the runtime write fatal errors, which no recover
+// catches, to this process's trace file, for the next run to bundle.
+func startCrashTrace() {
+	dir, err := crash.Dir()
+	if err != nil {
+		return
+	}
+	f, err := crash.CreateTrace(dir, os.Getpid())
+	if err != nil {
+		slog.Debug("create crash trace", "err", err)
+		return
+	}
+	if err := debug.SetCrashOutput(f, debug.CrashOptions{}); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return
+	}
+	crashTrace = f
+}
+
+// stopCrashTrace removes the trace file of a run that did not crash.
+func stopCrashTrace() {
+	if crashTrace == nil {
+		return
+	}
+	debug.SetCrashOutput(nil, debug.CrashOptions{})
+	crashTrace.Close()
+	os.Remove(crashTrace.Name())
+	crashTrace = nil
+}
+
+// crashed bundles the panic r, recovered with its stack, tells the user
+// where the bundle is and returns the error to exit with.
+func crashed(r any, stack []byte) error {
+	reason := fmt.Sprint("panic: ", r)
+	body := "This is a bug in flo."
+	var path string
+	dir, err := crash.Dir()
+	if err == nil {
+		path, err = crash.Save(dir, newBundle(reason, string(stack)))
+	}
+	if err != nil {
+		body += "\n\n" + reason + "\n\n" + string(stack)
+	} else {
+		body += " A diagnostic bundle is in\n" + path + "\n\nflo report helps you file it."
+	}
+	printError("flo crashed", body)
+	return withExitCode(exitFailure, fmt.Errorf("%s", reason))
+}
+
+// bundleEarlierCrashes bundles the fatal errors earlier runs left in
+// their trace files, and says where the bundles are.
+func bundleEarlierCrashes() {
+	dir, err := crash.Dir()
+	if err != nil {
+		return
+	}
+	traces, err := crash.Sweep(dir, os.Getpid())
+	for _, t := range traces {
+		// The config and versions are this run's; the command line and
+		// log of the crashed run are lost with it.
+		b := newBundle(t.Reason, t.Stack)
+		b.Time, b.Args, b.Log = t.Time, nil, nil
+		path, err := crash.Save(dir, b)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintln(os.Stderr, spinnerSty.Render("⚠ flo crashed on "+t.Time.Format("Jan 2 15:04")+"; diagnostic bundle: "+path))
+		fmt.Fprintln(os.Stderr, dimSty.Render("  flo report helps you file it."))
+	}
+	if err != nil {
+		slog.Debug("sweep crash traces", "err", err)
+	}
+}
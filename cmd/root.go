@@ -2,33 +2,36 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/ratnesh-maurya/flo/pkg/config"
+	"github.com/ratnesh-maurya/flo/pkg/export"
+	"github.com/ratnesh-maurya/flo/pkg/httpx"
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+	"github.com/ratnesh-maurya/flo/pkg/logging"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
 	"github.com/spf13/cobra"
 )
 
 // version is set at build time via ldflags.
 var version = "dev"
 
+// Styles are built from the active palette by buildStyles.
 var (
-	brandStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#FF6600")).
-			MarginBottom(1)
-
-	errorStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#FF0000")).
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#FF4444")).
-			Padding(1, 2).
-			MarginTop(1).
-			MarginBottom(1)
-
-	infoStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")).
-			Italic(true)
+	brandStyle lipgloss.Style
+	errorStyle lipgloss.Style
+	infoStyle  lipgloss.Style
+	// cautionStyle frames warnings about possibly outdated answers.
+	cautionStyle lipgloss.Style
 )
 
 var rootCmd = &cobra.Command{
@@ -39,15 +42,266 @@ var rootCmd = &cobra.Command{
 	// runAsk is defined in ask.go (same package); with zero args it
 	// starts a REPL, with args it does a one-shot search.
 	RunE: runAsk,
+	// Runtime failures (and Ctrl+C) are not usage mistakes; don't dump
+	// the flag help after them.
+	SilenceUsage: true,
+
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		commandStarted = true
+		closer, err := logging.Setup(logOpts)
+		if err != nil {
+			return err
+		}
+		logCloser = closer
+
+		if err := selectConfig(); err != nil {
+			return err
+		}
+		if err := applyNonInteractive(cmd); err != nil {
+			return err
+		}
+		loaded, err := config.Load(configPath)
+		if err != nil {
+			return err
+		}
+		cfg = loaded
+		applyFlagOverrides(cmd)
+		if err := applyAnonymous(cmd); err != nil {
+			return err
+		}
+		if cfg.Display.WideTables != "" {
+			ui.WideTables = cfg.Display.WideTables
+		}
+		export.Attribute = !cfg.Export.NoAttribution
+		if err := applyPalette(); err != nil {
+			return err
+		}
+		if err := ui.SetFormat(cfg.Display.Format); err != nil {
+			return withExitCode(exitUsage, err)
+		}
+		if err := applyKeymap(); err != nil {
+			return err
+		}
+		if err := applyLanguage(); err != nil {
+			return err
+		}
+
+		if !strings.HasPrefix(cmd.Name(), "__") {
+			bundleEarlierCrashes()
+		}
+		startUpdateNotice(cmd)
+		return nil
+	},
+}
+
+var (
+	// logOpts is populated from the persistent --log-* flags.
+	logOpts logging.Options
+	// logCloser flushes the --log-file destination on exit.
+	logCloser io.Closer
+	// netOpts is populated from the persistent TLS flags and applies to
+	// every HTTP client and to the mcp-remote subprocess.
+	netOpts httpx.Options
+
+	// commandStarted is set once the command line has been parsed and
+	// the command begins to run, to tell usage errors from failures.
+	commandStarted bool
+
+	// configPath is the --config flag; empty means the default location.
+	configPath string
+	// cfg is the loaded configuration with flag overrides applied.
+	cfg = config.Default()
+
+	// Flag values that override the config file when set.
+	flagBackend    string
+	flagMCPURL     string
+	flagMCPCmd     string
+	flagNoQuestion bool
+	flagFormat     string
+	flagPalette    string
+	flagProfile    string
+	flagSite       string
+)
+
+func init() {
+	pf := rootCmd.PersistentFlags()
+	pf.StringVar(&configPath, "config", "", "path to config file (default: <user config dir>/flo/config.yaml)")
+	pf.StringVar(&flagProfile, "profile", "", "use a named profile, with its own config, login, bookmarks, history and cache")
+	pf.StringVar(&flagBackend, "backend", "", "content backend: mcp (the official MCP server, default), api (the Stack Exchange API) or local (the local index, offline)")
+	pf.StringVar(&flagSite, "site", "", "Stack Exchange site: stackoverflow (default), es, pt, ru or ja for Stack Overflow in that language, or any other site's API name")
+	pf.StringVar(&flagMCPURL, "mcp-url", "", "MCP server URL (default "+mcp.DefaultURL+")")
+	pf.StringVar(&flagMCPCmd, "mcp-cmd", "", "MCP bridge command; {url} is replaced by the server URL (default: the mcp-remote flo setup installed, else \""+mcp.DefaultCommand+"\")")
+	pf.StringVar(&logOpts.Level, "log-level", "", "structured log level: debug, info, warn, error (disabled by default)")
+	pf.StringVar(&logOpts.Format, "log-format", "text", "structured log format: text or json")
+	pf.StringVar(&logOpts.File, "log-file", "", "append structured logs to this file instead of stderr")
+	pf.StringVar(&netOpts.CAFile, "ca-file", "", "extra PEM CA bundle to trust (e.g. for corporate TLS interception)")
+	pf.StringVar(&flagPalette, "palette", "", "color palette: "+strings.Join(ui.PaletteNames(), ", "))
+	pf.StringVar(&flagFormat, "format", "", "output format: ansi (the terminal, default), markdown, html or json (one object per line)")
+	pf.BoolVar(&nonInteractive, "non-interactive", false, "never prompt (for CI): print the best answer, and fail instead of opening a picker, editor or browser login")
+	pf.BoolVar(&netOpts.InsecureSkipVerify, "insecure-skip-verify", false, "disable TLS certificate verification (insecure)")
+	_ = rootCmd.RegisterFlagCompletionFunc("backend", cobra.FixedCompletions([]cobra.Completion{
+		cobra.CompletionWithDesc(config.BackendMCP, "the official Stack Overflow MCP server"),
+		cobra.CompletionWithDesc(config.BackendAPI, "the Stack Exchange API"),
+		cobra.CompletionWithDesc(config.BackendLocal, "the local index, offline"),
+	}, cobra.ShellCompDirectiveNoFileComp))
+	rootCmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return withExitCode(exitUsage, err)
+	})
+	_ = rootCmd.RegisterFlagCompletionFunc("profile", completeProfiles)
+	_ = rootCmd.RegisterFlagCompletionFunc("site", completeSites)
+	_ = rootCmd.RegisterFlagCompletionFunc("palette", cobra.FixedCompletions(ui.PaletteNames(), cobra.ShellCompDirectiveNoFileComp))
+	_ = rootCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(ui.Formats(), cobra.ShellCompDirectiveNoFileComp))
+}
+
+// selectConfig picks the profile and config file: --profile and
+// --config, or else $FLO_PROFILE and $FLO_CONFIG.  The profile's
+// directories are used by every later path lookup (see config.Profile).
+func selectConfig() error {
+	if flagProfile == "" {
+		flagProfile = os.Getenv("FLO_PROFILE")
+	}
+	if configPath == "" {
+		configPath = os.Getenv("FLO_CONFIG")
+	}
+	if flagProfile == "" {
+		return nil
+	}
+	if err := config.ValidateProfile(flagProfile); err != nil {
+		return err
+	}
+	config.Profile = flagProfile
+	return nil
+}
+
+// applyFlagOverrides copies explicitly set flags over the loaded config,
+// giving flags precedence over the config files and environment.
+func applyFlagOverrides(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	if flags.Changed("backend") {
+		cfg.Backend = flagBackend
+	}
+	if flags.Changed("site") {
+		cfg.API.Site = flagSite
+	}
+	if flags.Changed("mcp-url") {
+		cfg.MCP.URL = flagMCPURL
+	}
+	if flags.Changed("mcp-cmd") {
+		cfg.MCP.Command = flagMCPCmd
+	}
+	if flags.Changed("palette") {
+		cfg.Display.Palette = flagPalette
+	}
+	if flags.Changed("format") {
+		cfg.Display.Format = flagFormat
+	}
+	if flags.Changed("no-question") {
+		cfg.Display.NoQuestion = flagNoQuestion
+	}
+}
+
+// mcpOptions returns the options used to launch the MCP bridge.
+func mcpOptions() mcp.Options {
+	// An invalid mcp.remote is reported by connect before any bridge
+	// starts.
+	remoteArgs, _ := cfg.MCP.Remote.BridgeArgs()
+	return mcp.Options{
+		URL:        cfg.MCP.URL,
+		Command:    bridgeCommand(),
+		RemoteArgs: remoteArgs,
+		Env:        bridgeEnv(),
+		OnReconnect: func() {
+			status(spinnerSty, i18n.T("🔄 Connection went stale — reconnecting..."), "reconnecting to MCP server")
+		},
+		OnReauth: func() bool {
+			if nonInteractive {
+				return false
+			}
+			status(spinnerSty, i18n.T("🔑 Your Stack Overflow login expired — renewing it (a browser may open)..."), "renewing MCP login")
+			return true
+		},
+		OnLoginURL: showLoginURL,
+		OnResult: func(c mcp.Call) {
+			recordRaw(c)
+			recordMCPCall(c)
+		},
+		LoginTimeout: cfg.Timeouts.ConnectTimeout(),
+	}
+}
+
+// bridgeEnv returns the environment the bridge subprocess gets on top
+// of flo's own: the proxy settings, the token store and mcp.env.  Later
+// entries win.
+func bridgeEnv() []string {
+	env := append(httpx.SubprocessEnv(netOpts), profileEnv()...)
+	if dir := cfg.MCP.Remote.ConfigDir; dir != "" {
+		env = append(env, "MCP_REMOTE_CONFIG_DIR="+expandHome(dir))
+	}
+	names := make([]string, 0, len(cfg.MCP.Env))
+	for name := range cfg.MCP.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		env = append(env, name+"="+cfg.MCP.Env[name])
+	}
+	return env
+}
+
+// profileEnv points mcp-remote's token store (~/.mcp-auth by default)
+// into a named profile's config directory, so each profile logs in with
+// its own account.
+func profileEnv() []string {
+	if config.Profile == "" {
+		return nil
+	}
+	dir, err := config.Dir()
+	if err != nil {
+		return nil
+	}
+	return []string{"MCP_REMOTE_CONFIG_DIR=" + filepath.Join(dir, "mcp-auth")}
+}
+
+// newHTTPClient returns an HTTP client honoring the proxy environment and
+// the --ca-file / --insecure-skip-verify flags.
+func newHTTPClient(timeout time.Duration) (*http.Client, error) {
+	return httpx.NewClient(netOpts, timeout)
 }
 
-// Execute runs the root command.
-func Execute() error {
-	return rootCmd.Execute()
+// Execute runs the root command.  Its context is cancelled on Ctrl+C
+// or SIGTERM so in-flight MCP calls unwind cleanly (see signal.go).
+func Execute() (err error) {
+	if ui.Setup() {
+		buildStyles()
+	}
+	startCrashTrace()
+	defer func() {
+		if r := recover(); r != nil {
+			err = crashed(r, debug.Stack())
+		}
+		stopCrashTrace()
+		if logCloser != nil {
+			logCloser.Close()
+		}
+	}()
+	ctx, stop := signalContext()
+	defer stop()
+	start := time.Now()
+	c, err := rootCmd.ExecuteContextC(ctx)
+	if err != nil && !commandStarted && ExitCode(err) == exitFailure {
+		// Cobra rejected the command line before running anything.
+		err = withExitCode(exitUsage, err)
+	}
+	if commandStarted {
+		recordCommand(c, time.Since(start), err)
+		flushTelemetry()
+	}
+	printUpdateNotice()
+	return err
 }
 
 // printError prints a styled error message to stderr.
 func printError(title, body string) {
-	msg := fmt.Sprintf("\u2716 %s\n\n%s", title, body)
+	msg := ui.PlainText(fmt.Sprintf("\u2716 %s\n\n%s", title, body))
 	fmt.Fprintln(os.Stderr, errorStyle.Render(msg))
 }
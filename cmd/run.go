@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/config"
+	"github.com/ratnesh-maurya/flo/pkg/index"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/sandbox"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	runLang       string
+	runTimeout    time.Duration
+	runYes        bool
+	runUnconfined bool
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run [block]",
+	Short: "Run a code block of the answer viewed last in a scratch directory",
+	Long: `Try the code of the answer you viewed last: flo shows code block n (by
+default the longest it can run), says how it will run it, and once you
+agree writes it to a new temporary directory and runs it there, showing
+its output as it comes.
+
+  flo lucky "read a file line by line in go"
+  flo run                  the longest runnable block
+  flo run 2 --timeout 30s
+
+Go (a whole program, run with go run), Python, Node.js and Bash run,
+each with the tool installed; --lang says which when flo cannot tell.
+The program gets no input, --timeout to finish, and an environment of
+PATH and a locale only, with HOME and TMPDIR in its directory, which is
+removed afterwards.  It runs sandboxed, with no network and your files
+read-only: on Linux with bwrap (bubblewrap) or, failing that, unshare,
+and on macOS with sandbox-exec.  Where none of these works flo refuses
+to run it unless you pass --unconfined, which runs it as you, with
+your files and network.  Read the code before you agree either way.
+--yes runs it without asking.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRun,
+}
+
+func init() {
+	runCmd.Flags().StringVar(&runLang, "lang", "", "run the block as this language ("+strings.Join(sandbox.Languages(), ", ")+")")
+	runCmd.Flags().DurationVar(&runTimeout, "timeout", 10*time.Second, "stop the program after this long, building included")
+	runCmd.Flags().BoolVarP(&runYes, "yes", "y", false, "run without asking first")
+	runCmd.Flags().BoolVar(&runUnconfined, "unconfined", false, "run with your files and network when no sandbox is available")
+	_ = runCmd.RegisterFlagCompletionFunc("lang", func(*cobra.Command, []string, string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		return sandbox.Languages(), cobra.ShellCompDirectiveNoFileComp
+	})
+	rootCmd.AddCommand(runCmd)
+}
+
+// runRun implements `flo run`.
+func runRun(cmd *cobra.Command, args []string) error {
+	if err := terminalOnly("flo run"); err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+	n := 0
+	if len(args) == 1 {
+		var err error
+		if n, err = strconv.Atoi(args[0]); err != nil || n < 1 {
+			return withExitCode(exitUsage, fmt.Errorf("%q is not a block number", args[0]))
+		}
+	}
+	if runTimeout <= 0 {
+		return withExitCode(exitUsage, errors.New("--timeout must be positive"))
+	}
+	lang := ""
+	if runLang != "" {
+		if lang = sandbox.Normalize(runLang); lang == "" {
+			return withExitCode(exitUsage, fmt.Errorf("--lang: cannot run %q (want %s)", runLang, strings.Join(sandbox.Languages(), ", ")))
+		}
+	}
+
+	idx, err := openIndex()
+	if err != nil {
+		printError("Run failed", err.Error())
+		return err
+	}
+	d := lastViewedAnswer(idx)
+	if d == nil {
+		printError("Nothing to run", "View an answer first, in flo or with flo lucky; flo run takes its code.")
+		return errNoResults
+	}
+	// The index keeps text unescaped, and CodeBlocks expects it as posts
+	// carry it.
+	blocks := mcp.CodeBlocks(html.EscapeString(d.Body))
+	switch {
+	case len(blocks) == 0:
+		printError("Nothing to run", fmt.Sprintf("The answer you viewed last has no code.\n\n  %s", d.Link))
+		return errNoResults
+	case n > len(blocks):
+		printError("No such block", fmt.Sprintf("The answer you viewed last has %d code %s.\n\n  %s", len(blocks), plural(len(blocks), "block", "blocks"), d.Link))
+		return withExitCode(exitUsage, fmt.Errorf("block %d: no such block", n))
+	}
+	langs := make([]string, len(blocks))
+	for i, b := range blocks {
+		langs[i] = lang
+		if langs[i] == "" {
+			langs[i] = sandbox.Normalize(b.Lang)
+		}
+		if langs[i] == "" {
+			langs[i] = sandbox.Normalize(ui.CodeLanguage(b.Code, d.Tags))
+		}
+	}
+	i := n - 1
+	if i < 0 {
+		i = runnableBlock(blocks, langs)
+	}
+	b := blocks[i]
+	if langs[i] == "" {
+		printError("Cannot run", fmt.Sprintf("flo cannot tell what language code block %d is in; name it with --lang (%s).",
+			i+1, strings.Join(sandbox.Languages(), ", ")))
+		return withExitCode(exitUsage, errors.New("unknown language"))
+	}
+	if err := sandbox.Check(langs[i], b.Code); err != nil {
+		printError("Cannot run", fmt.Sprintf("Code block %d: %v.", i+1, err))
+		return err
+	}
+	command, _ := sandbox.Command(langs[i])
+	iso := sandbox.Available()
+	if iso == sandbox.NoIsolation && !runUnconfined {
+		printError("Cannot run", "flo found no sandbox to run code in here: on Linux install bubblewrap (bwrap), "+
+			"or allow unprivileged user namespaces.  --unconfined runs it as you, with your files and network.")
+		return sandbox.ErrNoIsolation
+	}
+
+	fmt.Println(promptSty.Render(d.Title))
+	fmt.Println(dimSty.Render(d.Link))
+	fmt.Println(promptSty.Render(fmt.Sprintf("Code block %d of %d · %s", i+1, len(blocks), langs[i])))
+	if rendered, err := ui.RenderCode(b.Code, langs[i]); err == nil {
+		fmt.Print(rendered)
+	} else {
+		fmt.Println(b.Code)
+	}
+	fmt.Println(dimSty.Render(fmt.Sprintf("flo will run it with `%s` in a new temporary directory, for at most %s,", command, runTimeout)))
+	fmt.Println(dimSty.Render("with no input and little of your environment, " + iso.Describe() + "."))
+	if !runYes {
+		if !term.IsTerminal(int(os.Stdin.Fd())) || nonInteractive {
+			return withExitCode(exitInputRequired, errors.New("flo run asks before running code; pass --yes to run it without asking"))
+		}
+		fmt.Print(promptSty.Render("Run it? [y/N] "))
+		line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if answer := strings.ToLower(strings.TrimSpace(line)); answer != "y" && answer != "yes" {
+			fmt.Println(dimSty.Render("Not run."))
+			return nil
+		}
+	}
+
+	status(dimSty, "── output ──", "running code block", "block", i+1, "lang", langs[i], "link", d.Link)
+	opts := sandbox.Options{Timeout: runTimeout, Output: os.Stdout, Unconfined: runUnconfined}
+	if dir, err := config.CacheDir(); err == nil {
+		opts.GoCache = filepath.Join(dir, "go-build")
+	}
+	res, err := sandbox.Run(ctx, langs[i], b.Code, opts)
+	if err != nil {
+		printError("Could not run", err.Error())
+		return err
+	}
+	fmt.Println(dimSty.Render("────────────"))
+	if res.Truncated {
+		fmt.Println(spinnerSty.Render(fmt.Sprintf("⚠ Output cut at %s.", humanBytes(sandbox.MaxOutput))))
+	}
+	took := res.Elapsed.Round(10 * time.Millisecond)
+	switch {
+	case res.TimedOut:
+		fmt.Println(spinnerSty.Render(fmt.Sprintf("⏱ Stopped after %s; raise --timeout to give it longer.", runTimeout)))
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case res.ExitCode == 0:
+		fmt.Println(successSty.Render(fmt.Sprintf("✅ Exited with status 0 in %s", took)))
+	default:
+		fmt.Println(spinnerSty.Render(fmt.Sprintf("⚠ Exited with status %d in %s", res.ExitCode, took)))
+	}
+	return nil
+}
+
+// lastViewedAnswer returns the answer viewed most recently, or nil;
+// imported and bundled posts were never viewed.
+func lastViewedAnswer(idx *index.Index) *index.Doc {
+	var last *index.Doc
+	for _, d := range idx.Docs() {
+		if d.Kind != index.KindAnswer || d.Source != "" {
+			continue
+		}
+		if last == nil || d.ViewedAt.After(last.ViewedAt) {
+			last = d
+		}
+	}
+	return last
+}
+
+// runnableBlock returns the index of the longest block in a language
+// flo can run, or of the longest block when none is.
+func runnableBlock(blocks []mcp.CodeBlock, langs []string) int {
+	best := -1
+	for i, b := range blocks {
+		if langs[i] != "" && (best < 0 || strings.Count(b.Code, "\n") > strings.Count(blocks[best].Code, "\n")) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return longestBlock(blocks)
+	}
+	return best
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/editor"
+	"github.com/ratnesh-maurya/flo/pkg/metrics"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// serveEditor is the --editor flag of `flo serve`.
+	serveEditor bool
+	// serveSocket is the --socket flag of `flo serve`.
+	serveSocket string
+	// serveTicker is the --ticker flag of `flo serve`.
+	serveTicker bool
+	// serveMetricsAddr is the --metrics flag of `flo serve`.
+	serveMetricsAddr string
+
+	// serveMetrics counts what flo serve does, for /metrics; nil unless
+	// --metrics is set.
+	serveMetrics *metrics.Set
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve --editor | --ticker",
+	Short: "Serve searches to editor plugins over a JSON protocol, and keep the ticker's feed",
+	Long: `Answer requests from an editor plugin: search, fetch an answer, or get
+an answer's code blocks as plain text.  Requests and responses are JSON
+objects, one per line, matched by id; requests run concurrently and can
+be cancelled.
+
+  → {"id":1,"method":"search","params":{"query":"reverse a string in go"}}
+  ← {"id":1,"result":{"questions":[...]}}
+  → {"id":2,"method":"code","params":{"question_id":1752414}}
+  ← {"id":2,"result":{"blocks":[{"lang":"go","code":"..."}]}}
+  → {"method":"cancel","params":{"id":1}}
+
+The protocol runs on stdin/stdout, or with --socket on a Unix socket
+that accepts any number of sessions.  Identical requests in flight at
+the same time, from one session or several, share a single call to the
+server.  See pkg/editor for every method.
+
+With --ticker, or ticker.enabled set, flo serve also keeps the feed of
+hot questions in your subscribed tags that flo ticker prints; alone,
+--ticker runs just that, as a daemon.
+
+With --metrics, flo serve also answers Prometheus scrapes at
+http://<address>/metrics: requests by method and status, backend call
+latency and errors, and cache hits and misses.  Bind it to localhost
+unless the network in between is trusted; it has no authentication.`,
+	Args: cobra.NoArgs,
+	RunE: runServe,
+}
+
+func init() {
+	serveCmd.Flags().BoolVar(&serveEditor, "editor", false, "speak the editor plugin protocol")
+	serveCmd.Flags().StringVar(&serveSocket, "socket", "", "listen on this Unix socket instead of stdio")
+	serveCmd.Flags().BoolVar(&serveTicker, "ticker", false, "keep the hot-question feed of flo ticker (also ticker.enabled)")
+	serveCmd.Flags().StringVar(&serveMetricsAddr, "metrics", "", "serve Prometheus metrics at http://`address`/metrics, e.g. localhost:9464")
+	rootCmd.AddCommand(serveCmd)
+}
+
+// runServe implements `flo serve`.  Stdout carries the protocol, so
+// anything else that would print there goes to stderr instead.
+func runServe(cmd *cobra.Command, args []string) error {
+	ticking := serveTicker || cfg.Ticker.Enabled
+	if !serveEditor && !ticking {
+		return errors.New("flo serve needs --editor, --ticker or both")
+	}
+	ctx := cmd.Context()
+	out := os.Stdout
+	os.Stdout = os.Stderr
+	if serveMetricsAddr != "" {
+		if err := startMetrics(ctx, serveMetricsAddr); err != nil {
+			return err
+		}
+	}
+
+	p, release, err := connect(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer release()
+	if !serveEditor {
+		fmt.Fprintln(os.Stderr, "flo ticker feed running; flo ticker prints it")
+		return runTickerFeed(ctx, p)
+	}
+	if ticking {
+		go func() {
+			if err := runTickerFeed(ctx, p); err != nil {
+				slog.Warn("ticker feed stopped", "err", err)
+			}
+		}()
+	}
+	// Sessions asking for the same thing at once share one backend call.
+	srv := editor.New(provider.Coalesced(p))
+	if serveMetrics != nil {
+		srv.OnRequest = serveMetrics.Request
+	}
+
+	if serveSocket == "" {
+		fmt.Fprintln(os.Stderr, "flo editor server ready on stdio")
+		return srv.Serve(ctx, os.Stdin, out)
+	}
+
+	if fi, err := os.Stat(serveSocket); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		os.Remove(serveSocket) // a stale socket from an earlier run
+	}
+	ln, err := net.Listen("unix", serveSocket)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(serveSocket)
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+	fmt.Fprintf(os.Stderr, "flo editor server listening on %s\n", serveSocket)
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		slog.Info("editor session opened")
+		go func() {
+			defer conn.Close()
+			if err := srv.Serve(ctx, conn, conn); err != nil {
+				slog.Warn("editor session ended", "err", err)
+			}
+		}()
+	}
+}
+
+// startMetrics starts counting into serveMetrics and serves them on
+// addr until ctx is done.
+func startMetrics(ctx context.Context, addr string) error {
+	serveMetrics = metrics.New()
+	serveMetrics.Cache = func() (int64, int64) {
+		if respCache == nil {
+			return 0, 0
+		}
+		return respCache.Hits(), respCache.Misses()
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("--metrics: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", serveMetrics)
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Warn("metrics server stopped", "err", err)
+		}
+	}()
+	fmt.Fprintf(os.Stderr, "flo metrics on http://%s/metrics\n", ln.Addr())
+	return nil
+}
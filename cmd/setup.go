@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/config"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/spf13/cobra"
+)
+
+// setupForce is the --force flag of `flo setup`.
+var setupForce bool
+
+var setupCmd = &cobra.Command{
+	Use:   "setup",
+	Short: "Install the MCP bridge ahead of the first search",
+	Long: `Install mcp-remote, the bridge flo runs to reach the Stack Overflow MCP
+server, and check that Node.js can run it.
+
+Without it, the first search has npx download mcp-remote, which can take
+minutes with nothing to show for it.  flo setup installs the version flo
+is tested with (or mcp.remote.version from the config) into flo's cache
+directory, showing npm's progress, and flo runs that copy from then on.
+Run it again after changing mcp.remote.version; --force reinstalls.
+
+It is not used when mcp.command (or --mcp-cmd) sets a bridge of your own.`,
+	Args: cobra.NoArgs,
+	RunE: runSetup,
+}
+
+func init() {
+	setupCmd.Flags().BoolVar(&setupForce, "force", false, "reinstall even if the version is already installed")
+	rootCmd.AddCommand(setupCmd)
+}
+
+// nodeHelp explains how to get Node.js, which the MCP bridge runs on.
+const nodeHelp = "flo requires Node.js (npx).\n\n" +
+	"  macOS:   brew install node\n" +
+	"  Ubuntu:  sudo apt install nodejs npm\n" +
+	"  Windows: choco install nodejs\n\n" +
+	"Or set backend: api to use the Stack Exchange API instead."
+
+// runSetup implements `flo setup`.
+func runSetup(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Minute)
+	defer cancel()
+
+	status(spinnerSty, "🔍 Checking Node.js...", "checking node")
+	node, err := mcp.NodeVersion(ctx)
+	if err != nil {
+		if node == "" {
+			printError("Node.js not found", nodeHelp)
+		} else {
+			printError("Node.js is too old", err.Error())
+		}
+		return err
+	}
+	fmt.Println(dimSty.Render("  Node.js " + node))
+
+	dir, err := config.BridgeDir()
+	if err != nil {
+		printError("Setup failed", err.Error())
+		return err
+	}
+	version := remoteVersion()
+	if !setupForce {
+		if inst := managedRemote(); inst != nil {
+			fmt.Println(successSty.Render(fmt.Sprintf("✅ mcp-remote %s is already installed.", inst.Version)))
+			fmt.Println(dimSty.Render("  " + dir))
+			setupNotes()
+			return nil
+		}
+	}
+
+	status(spinnerSty, fmt.Sprintf("📦 Installing mcp-remote@%s with npm...", version), "installing mcp-remote",
+		"version", version, "dir", dir)
+	inst, err := mcp.InstallRemote(ctx, dir, version, bridgeEnv(), func(line string) {
+		fmt.Println(dimSty.Render("  " + line))
+	})
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			printError("Setup timed out", "npm did not finish within 10 minutes. Check your network or proxy settings and try again.")
+			return err
+		}
+		printError("Setup failed", err.Error())
+		return err
+	}
+	fmt.Println(successSty.Render(fmt.Sprintf("✅ Installed mcp-remote %s", inst.Version)))
+	fmt.Println(dimSty.Render("  " + dir))
+	setupNotes()
+	return nil
+}
+
+// setupNotes says what is still in the way of a first search.
+func setupNotes() {
+	switch {
+	case cfg.MCP.Command != "":
+		fmt.Println(spinnerSty.Render("  mcp.command is set, so flo runs that instead; unset it to use this install."))
+	case mcp.NeedsLogin(mcpOptions()):
+		fmt.Println(dimSty.Render("  The first search opens a browser to log in to Stack Overflow."))
+	}
+}
+
+// remoteVersion is the mcp-remote version to run and install.
+func remoteVersion() string {
+	if v := cfg.MCP.Remote.Version; v != "" {
+		return v
+	}
+	return mcp.DefaultRemoteVersion
+}
+
+// managedRemote returns the mcp-remote `flo setup` installed, or nil
+// when there is none of the configured version.
+func managedRemote() *mcp.Installed {
+	dir, err := config.BridgeDir()
+	if err != nil {
+		return nil
+	}
+	inst, err := mcp.InstalledRemote(dir)
+	if err != nil {
+		slog.Warn("installed mcp-remote unusable; run flo setup --force", "err", err)
+		return nil
+	}
+	if inst == nil {
+		return nil
+	}
+	if v := remoteVersion(); v != "latest" && inst.Version != v {
+		slog.Debug("installed mcp-remote is not the configured version", "installed", inst.Version, "want", v)
+		return nil
+	}
+	return inst
+}
+
+// remoteDownload reports whether starting the default bridge has npx
+// download mcp-remote first.
+func remoteDownload() bool {
+	if cfg.MCP.Command != "" || managedRemote() != nil {
+		return false
+	}
+	return !mcp.RemoteCached(remoteVersion())
+}
+
+// bridgeCommand returns the bridge to run: mcp.command when set, else
+// the mcp-remote `flo setup` installed, else mcp-remote through npx.
+func bridgeCommand() string {
+	if cfg.MCP.Command != "" {
+		return cfg.MCP.Command
+	}
+	if inst := managedRemote(); inst != nil {
+		return mcp.InstalledCommand(inst.Script)
+	}
+	return mcp.RemoteCommand(remoteVersion())
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ratnesh-maurya/flo/pkg/export"
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// shareSlack is the --slack flag of `flo share`.
+	shareSlack bool
+	// shareMarkdown is the --markdown flag of `flo share`.
+	shareMarkdown bool
+)
+
+var shareCmd = &cobra.Command{
+	Use:   "share <query | question URL | question ID>",
+	Short: "Print a short, attributed summary of an answer to paste elsewhere",
+	Long: `Print a compact block for Slack, chat or a pull request: the question's
+title and link, an excerpt of its accepted (or top) answer, the answer's
+author and the CC BY-SA notice Stack Overflow content requires.
+
+  flo share "reverse a string in go"
+  flo share --slack https://stackoverflow.com/questions/1752414
+  flo share --markdown 1752414 | pbcopy
+
+A query picks the best match, as flo lucky does.  The default output is
+plain text; --slack uses Slack's mrkdwn and --markdown GitHub Markdown.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runShare,
+
+	ValidArgsFunction: completeRecentQuestions,
+}
+
+func init() {
+	shareCmd.Flags().BoolVar(&shareSlack, "slack", false, "format for Slack (mrkdwn)")
+	shareCmd.Flags().BoolVar(&shareMarkdown, "markdown", false, "format as GitHub Markdown")
+	shareCmd.MarkFlagsMutuallyExclusive("slack", "markdown")
+	shareCmd.Flags().BoolVar(&noCache, "no-cache", false, "always query the server, bypassing the response cache")
+	rootCmd.AddCommand(shareCmd)
+}
+
+// reQuestionRef matches a question ID, or a link to a question or an
+// answer.
+var reQuestionRef = regexp.MustCompile(`^(?:https?://(?:www\.)?stackoverflow\.com/(q|questions|a|answers)/)?(\d+)(?:[/?#].*)?$`)
+
+// runShare implements `flo share`.
+func runShare(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	// Only the share block goes to stdout, so it can be piped to a
+	// clipboard tool; progress notes go to stderr.
+	out := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = out }()
+
+	p, release, err := connect(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	q, a, err := shareTarget(ctx, p, strings.TrimSpace(strings.Join(args, " ")))
+	if q == nil || a == nil {
+		return err
+	}
+
+	style := export.SharePlain
+	switch {
+	case shareSlack:
+		style = export.ShareSlack
+	case shareMarkdown:
+		style = export.ShareMarkdown
+	}
+	bm := bookmarkOf(q, a)
+	fmt.Fprint(out, export.Share(&bm, style))
+	return nil
+}
+
+// shareTarget resolves the argument of `flo share` to a question and the
+// answer to quote.  Problems, including finding nothing to quote, are
+// reported to the user and returned.
+func shareTarget(ctx context.Context, p provider.Provider, arg string) (*mcp.QuestionData, *mcp.AnswerData, error) {
+	m := reQuestionRef.FindStringSubmatch(arg)
+	if m == nil {
+		q, err := bestMatch(ctx, p, mcp.NormalizeQuery(arg))
+		if q == nil {
+			return nil, nil, err
+		}
+		if len(q.Answers) == 0 {
+			printError(i18n.T("No answer"), i18n.Tf("%q has no answer yet.", html.UnescapeString(q.Title))+"\n\n  "+q.Link)
+			return nil, nil, errNoResults
+		}
+		return q, &mcp.SortAnswers(q.Answers)[0], nil
+	}
+
+	id, _ := strconv.Atoi(m[2])
+	fctx, cancel := context.WithTimeout(ctx, cfg.Timeouts.FetchTimeout())
+	defer cancel()
+	if m[1] == "a" || m[1] == "answers" {
+		a, err := p.GetAnswer(fctx, id)
+		if err != nil {
+			return nil, nil, reportFetchError(fctx, err, i18n.Tf("answer %d", id))
+		}
+		// An answer alone carries its question's title but not its link.
+		q := &mcp.QuestionData{Title: a.Title, Link: answerLink(&mcp.QuestionData{}, a)}
+		return q, a, nil
+	}
+	q, err := p.GetQuestion(fctx, id)
+	if err != nil {
+		return nil, nil, reportFetchError(fctx, err, i18n.Tf("question %d", id))
+	}
+	if len(q.Answers) == 0 && q.AcceptedAnswerID > 0 {
+		fetchAcceptedAnswer(ctx, p, q)
+	}
+	q = resolveDuplicate(ctx, p, q)
+	if len(q.Answers) == 0 {
+		printError(i18n.T("No answer"), i18n.Tf("%q has no answer yet.", html.UnescapeString(q.Title))+"\n\n  "+q.Link)
+		return nil, nil, errNoResults
+	}
+	return q, &mcp.SortAnswers(q.Answers)[0], nil
+}
+
+// reportFetchError explains why fetching what failed, and returns err.
+func reportFetchError(ctx context.Context, err error, what string) error {
+	switch {
+	case errors.Is(err, provider.ErrNotFound):
+		printError(i18n.T("Not found"), i18n.Tf("Could not find %s.", what))
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		printError(i18n.T("Fetch timed out"), i18n.Tf("Fetching %s took longer than %s. Try again, or raise timeouts.fetch in the config.",
+			what, cfg.Timeouts.FetchTimeout()))
+	default:
+		printError(i18n.T("Fetch failed"), err.Error())
+	}
+	return err
+}
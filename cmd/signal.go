@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// shutdownGrace is how long a command gets to unwind on its own after
+// Ctrl+C/SIGTERM (e.g. a search returning context.Canceled) before flo
+// runs the cleanup hooks and exits forcibly.  The forced path covers
+// code blocked on terminal input, which cannot observe the context.
+const shutdownGrace = 3 * time.Second
+
+var (
+	cleanupMu sync.Mutex
+	cleanups  []func()
+)
+
+// onShutdown registers fn to run if flo is interrupted.  It returns a
+// function that unregisters fn, for use once the resource has been
+// released normally.
+func onShutdown(fn func()) (unregister func()) {
+	cleanupMu.Lock()
+	defer cleanupMu.Unlock()
+	cleanups = append(cleanups, fn)
+	idx := len(cleanups) - 1
+	return func() {
+		cleanupMu.Lock()
+		defer cleanupMu.Unlock()
+		cleanups[idx] = nil
+	}
+}
+
+// runCleanups runs registered hooks in reverse registration order.
+func runCleanups() {
+	cleanupMu.Lock()
+	fns := cleanups
+	cleanups = nil
+	cleanupMu.Unlock()
+	for i := len(fns) - 1; i >= 0; i-- {
+		if fns[i] != nil {
+			fns[i]()
+		}
+	}
+}
+
+// signalContext returns a context cancelled on SIGINT/SIGTERM.  After a
+// signal, the command has shutdownGrace to return; otherwise cleanup
+// hooks run (killing the MCP bridge process tree), the terminal state
+// captured at startup is restored and flo exits with status 130.
+func signalContext() (context.Context, func()) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	restore := saveTerminal()
+
+	finished := make(chan struct{})
+	go func() {
+		select {
+		case <-finished:
+			return
+		case <-ctx.Done():
+		}
+		select {
+		case <-finished:
+		case <-time.After(shutdownGrace):
+			runCleanups()
+			restore()
+			fmt.Fprintln(os.Stderr, dimSty.Render("\n👋 Interrupted."))
+			os.Exit(exitInterrupted)
+		}
+	}()
+
+	return ctx, func() {
+		close(finished)
+		stop()
+		restore()
+	}
+}
+
+// saveTerminal snapshots the stdin terminal mode so it can be restored if
+// flo is interrupted while an interactive prompt has it in raw mode.
+func saveTerminal() (restore func()) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return func() {}
+	}
+	state, err := term.GetState(fd)
+	if err != nil {
+		return func() {}
+	}
+	return func() {
+		_ = term.Restore(fd, state)
+		// Make sure the cursor is visible again.
+		fmt.Fprint(os.Stdout, "\033[?25h")
+	}
+}
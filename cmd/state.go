@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ratnesh-maurya/flo/pkg/bookmarks"
+	"github.com/ratnesh-maurya/flo/pkg/breaker"
+	"github.com/ratnesh-maurya/flo/pkg/config"
+	"github.com/ratnesh-maurya/flo/pkg/state"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// stateNoCache is the --no-cache flag of `flo state export|import`.
+	stateNoCache bool
+	// stateDryRun is the --dry-run flag of `flo state import`.
+	stateDryRun bool
+)
+
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Back up or move flo's configuration, data and cache",
+	Long: `Pack everything flo keeps — the config file, bookmarks, the local index
+of viewed posts, the usage history, tag subscriptions and the response
+cache — into one .tar.gz, and restore it elsewhere.
+
+  flo state export flo-state.tar.gz
+  flo state import flo-state.tar.gz
+
+Importing replaces the files it restores, except bookmarks, which are
+merged with the ones already there.  Pass "-" to write to stdout or read
+from stdin.  The bundle holds the config file as is, including any API
+key or token in it: keep it private.`,
+}
+
+var stateExportCmd = &cobra.Command{
+	Use:   "export <bundle.tar.gz>",
+	Short: "Write flo's files to a bundle",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runStateExport,
+}
+
+var stateImportCmd = &cobra.Command{
+	Use:   "import <bundle.tar.gz>",
+	Short: "Restore flo's files from a bundle",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runStateImport,
+}
+
+func init() {
+	stateExportCmd.Flags().BoolVar(&stateNoCache, "no-cache", false, "leave the response cache out")
+	stateImportCmd.Flags().BoolVar(&stateNoCache, "no-cache", false, "do not restore the response cache")
+	stateImportCmd.Flags().BoolVar(&stateDryRun, "dry-run", false, "list the files that would be restored without writing them")
+	stateCmd.AddCommand(stateExportCmd, stateImportCmd)
+	rootCmd.AddCommand(stateCmd)
+}
+
+// stateRoots returns the directories a bundle covers.  The cache root is
+// left out with --no-cache.
+func stateRoots() ([]state.Root, error) {
+	configDir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	if configPath != "" {
+		configDir = filepath.Dir(configPath)
+	}
+	dataDir, err := config.DataDir()
+	if err != nil {
+		return nil, err
+	}
+	roots := []state.Root{{Name: "config", Dir: configDir}, {Name: "data", Dir: dataDir}}
+	if !stateNoCache {
+		cacheDir, err := config.CacheDir()
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, state.Root{Name: "cache", Dir: cacheDir})
+	}
+	return roots, nil
+}
+
+// skipState leaves out what another machine must not reuse: the clone
+// `flo sync` keeps (it is fetched again from the remote), and with
+// --config, any file beside it but the config itself.  Other profiles
+// (see --profile) are bundled separately.
+func skipState(root, rel string) bool {
+	if rel == "profiles" || strings.HasPrefix(rel, "profiles/") {
+		return true
+	}
+	switch root {
+	case "data":
+		return rel == "sync" || strings.HasPrefix(rel, "sync/")
+	case "config":
+		return configPath != "" && rel != filepath.Base(configPath)
+	case "cache":
+		// Backend health is this machine's own, `flo setup` installs
+		// the bridge for this machine's Node.js, the raw replies are for
+		// debugging here, and `flo run` builds Go for this machine.
+		return rel == filepath.Base(breaker.DefaultPath("")) ||
+			rel == "bridge" || strings.HasPrefix(rel, "bridge/") ||
+			rel == "raw" || strings.HasPrefix(rel, "raw/") ||
+			rel == "go-build" || strings.HasPrefix(rel, "go-build/")
+	}
+	return false
+}
+
+// mergeState merges bookmarks rather than replacing them.
+func mergeState(root, rel string, existing, incoming []byte) ([]byte, error) {
+	if root != "data" || rel != filepath.Base(bookmarks.DefaultPath("")) {
+		return incoming, nil
+	}
+	mine, err := bookmarks.Decode(existing)
+	if err != nil {
+		return incoming, nil // unreadable: take the bundle's
+	}
+	theirs, err := bookmarks.Decode(incoming)
+	if err != nil {
+		return nil, err
+	}
+	merged, _ := bookmarks.Merge(mine, theirs)
+	return bookmarks.Encode(merged)
+}
+
+// runStateExport implements `flo state export`.
+func runStateExport(cmd *cobra.Command, args []string) error {
+	roots, err := stateRoots()
+	if err != nil {
+		printError("Export failed", err.Error())
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	var f *os.File
+	if args[0] != "-" {
+		f, err = os.OpenFile(args[0], os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			printError("Export failed", err.Error())
+			return err
+		}
+		w = f
+	}
+	m, err := state.Export(w, roots, skipState)
+	if f != nil {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		printError("Export failed", err.Error())
+		return err
+	}
+	if f != nil {
+		fmt.Println(successSty.Render(fmt.Sprintf("✅ Wrote %d files (%s; %s) to %s",
+			m.Files, humanBytes(m.Bytes), strings.Join(m.Roots, ", "), args[0])))
+	}
+	return nil
+}
+
+// runStateImport implements `flo state import`.
+func runStateImport(cmd *cobra.Command, args []string) error {
+	roots, err := stateRoots()
+	if err != nil {
+		printError("Import failed", err.Error())
+		return err
+	}
+
+	var r io.Reader = os.Stdin
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			printError("Import failed", err.Error())
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	res, err := state.Import(r, roots, skipState, mergeState, stateDryRun)
+	if err != nil {
+		printError("Import failed", err.Error())
+		return err
+	}
+
+	from := fmt.Sprintf("bundle from %s, %s", res.Manifest.Host, res.Manifest.Created.Local().Format("Jan 2, 2006 15:04"))
+	if stateDryRun {
+		fmt.Println(dimSty.Render(fmt.Sprintf("Would restore %d files (%s):", len(res.Written), from)))
+		for _, name := range res.Written {
+			fmt.Println("  " + name)
+		}
+		return nil
+	}
+	msg := fmt.Sprintf("✅ Restored %d files (%s)", len(res.Written), from)
+	if res.Skipped > 0 {
+		msg += fmt.Sprintf("; skipped %d", res.Skipped)
+	}
+	fmt.Println(successSty.Render(msg))
+	return nil
+}
+
+// humanBytes formats n like "1.2 MB".
+func humanBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/config"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+	"github.com/ratnesh-maurya/flo/pkg/usage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsWeeks int
+	statsTop   int
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize your searches: top tags, queries per week, cache hits",
+	Long: `Summarize your local usage log: the tags of the questions you open most,
+searches per week, how many searches the response cache served, how many
+answers you read per search, and the queries and questions you keep coming
+back to on different days — likely the things worth writing down.
+
+The log is kept in <data dir>/usage.jsonl and never leaves your machine.
+Set stats.disabled: true in the config to stop recording.`,
+	Args: cobra.NoArgs,
+	RunE: runStats,
+}
+
+func init() {
+	statsCmd.Flags().IntVar(&statsWeeks, "weeks", 12, "number of weeks in the searches-per-week chart")
+	statsCmd.Flags().IntVarP(&statsTop, "top", "n", 10, "number of tags and repeated lookups listed")
+	rootCmd.AddCommand(statsCmd)
+}
+
+// statsBarWidth is the width of the longest bar in the charts.
+const statsBarWidth = 30
+
+// runStats implements `flo stats`.
+func runStats(cmd *cobra.Command, args []string) error {
+	path, err := usagePath()
+	if err != nil {
+		printError("Usage log unavailable", err.Error())
+		return err
+	}
+	events, err := usage.Load(path)
+	if err != nil {
+		printError("Usage log unavailable", err.Error())
+		return err
+	}
+	if len(events) == 0 {
+		msg := "No searches recorded yet — they are logged as you use flo."
+		if cfg.Stats.Disabled {
+			msg = "Recording is off (stats.disabled in the config)."
+		}
+		fmt.Fprintln(chrome(), dimSty.Render(msg))
+		return nil
+	}
+	s := usage.Summarize(events, max(statsWeeks, 1), max(statsTop, 1), time.Now())
+	if !ui.Current().Styled() {
+		printDoc(ui.Document{Markdown: formatStats(&s), Data: s})
+		return nil
+	}
+
+	fmt.Println(promptSty.Render(fmt.Sprintf("Since %s", s.Since.Format("Jan 2, 2006"))))
+	fmt.Printf("  Searches           %d\n", s.Searches)
+	fmt.Printf("  Questions opened   %d\n", s.Questions)
+	fmt.Printf("  Answers read       %d (%.1f per search)\n", s.Answers, s.AnswersPerSearch())
+	fmt.Printf("  Cache hit rate     %.0f%% (%d of %d searches)\n", 100*s.CacheHitRate(), s.CacheHits, s.Searches)
+
+	if len(s.TopTags) > 0 {
+		fmt.Println()
+		fmt.Println(promptSty.Render("Top tags"))
+		printBars(s.TopTags)
+	}
+
+	fmt.Println()
+	fmt.Println(promptSty.Render("Searches per week"))
+	weeks := make([]usage.Count, len(s.Weeks))
+	for i, w := range s.Weeks {
+		weeks[i] = usage.Count{Label: w.Start.Format("Jan 02"), N: w.Searches}
+	}
+	printBars(weeks)
+
+	if len(s.Repeated) > 0 {
+		fmt.Println()
+		fmt.Println(promptSty.Render("Looked up on more than one day"))
+		for _, r := range s.Repeated {
+			fmt.Printf("  %3d days  %s\n", r.N, r.Label)
+		}
+	}
+	return nil
+}
+
+// formatStats renders s as Markdown, for the formats other than ansi.
+func formatStats(s *usage.Summary) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# Since %s\n\n", s.Since.Format("Jan 2, 2006")))
+	b.WriteString(fmt.Sprintf("- Searches: %d\n", s.Searches))
+	b.WriteString(fmt.Sprintf("- Questions opened: %d\n", s.Questions))
+	b.WriteString(fmt.Sprintf("- Answers read: %d (%.1f per search)\n", s.Answers, s.AnswersPerSearch()))
+	b.WriteString(fmt.Sprintf("- Cache hit rate: %.0f%% (%d of %d searches)\n", 100*s.CacheHitRate(), s.CacheHits, s.Searches))
+	if len(s.TopTags) > 0 {
+		b.WriteString("\n## Top tags\n\n")
+		for _, c := range s.TopTags {
+			b.WriteString(fmt.Sprintf("- `%s` %d\n", c.Label, c.N))
+		}
+	}
+	b.WriteString("\n## Searches per week\n\n")
+	for _, w := range s.Weeks {
+		b.WriteString(fmt.Sprintf("- %s: %d\n", w.Start.Format("Jan 02"), w.Searches))
+	}
+	if len(s.Repeated) > 0 {
+		b.WriteString("\n## Looked up on more than one day\n\n")
+		for _, r := range s.Repeated {
+			b.WriteString(fmt.Sprintf("- %d days: %s\n", r.N, r.Label))
+		}
+	}
+	return b.String()
+}
+
+// printBars draws counts as a horizontal bar chart scaled to the largest.
+func printBars(counts []usage.Count) {
+	top, width := 0, 0
+	for _, c := range counts {
+		top = max(top, c.N)
+		width = max(width, len(c.Label))
+	}
+	for _, c := range counts {
+		n := 0
+		if top > 0 {
+			n = (c.N*statsBarWidth + top - 1) / top
+		}
+		fmt.Printf("  %-*s %s %d\n", width, c.Label, successSty.Render(ui.PlainText(strings.Repeat("█", n))), c.N)
+	}
+}
+
+// usagePath returns the location of the usage log.
+func usagePath() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return usage.DefaultPath(dir), nil
+}
+
+// recordUsage appends ev to the usage log unless stats.disabled is set.
+// Failures are logged but never interrupt the interactive flow.
+func recordUsage(ev usage.Event) {
+	if cfg.Stats.Disabled {
+		return
+	}
+	path, err := usagePath()
+	if err == nil {
+		err = usage.Append(path, ev)
+	}
+	if err != nil {
+		slog.Warn("record usage", "err", err)
+	}
+}
+
+// questionEvent is the usage event for opening q.
+func questionEvent(q *mcp.QuestionData) usage.Event {
+	return usage.Event{Kind: usage.KindQuestion, QuestionID: q.QuestionID, Title: html.UnescapeString(q.Title), Tags: q.Tags}
+}
+
+// answerEvent is the usage event for reading answer a to q.
+func answerEvent(q *mcp.QuestionData, a *mcp.AnswerData) usage.Event {
+	return usage.Event{Kind: usage.KindAnswer, QuestionID: q.QuestionID, AnswerID: a.AnswerID}
+}
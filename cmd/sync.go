@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ratnesh-maurya/flo/pkg/bookmarks"
+	"github.com/ratnesh-maurya/flo/pkg/config"
+	"github.com/ratnesh-maurya/flo/pkg/gitsync"
+	"github.com/spf13/cobra"
+)
+
+var syncRemote string
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync bookmarks through a git remote or gist",
+	Long: `Synchronize saved answers with a git remote so they follow you across
+machines.  Any URL git can push to works — a private repository, or a
+secret GitHub gist's clone URL (https://gist.github.com/<id>.git).
+
+Set the remote once in the config file:
+
+  sync:
+    remote: git@github.com:me/flo-bookmarks.git
+
+then run "flo sync" on each machine.  Bookmarks are merged entry by
+entry: answers saved anywhere are kept, and when the same answer was
+saved on two machines the most recent copy wins.  Authentication uses
+your normal git setup (SSH keys or a credential helper).`,
+	Args: cobra.NoArgs,
+	RunE: runSync,
+}
+
+func init() {
+	syncCmd.Flags().StringVar(&syncRemote, "remote", "", "git URL to sync with (overrides sync.remote)")
+	rootCmd.AddCommand(syncCmd)
+}
+
+// runSync implements `flo sync`.
+func runSync(cmd *cobra.Command, args []string) error {
+	if err := requireAccount("flo sync"); err != nil {
+		printError("Sync unavailable", err.Error())
+		return err
+	}
+	remote := syncRemote
+	if remote == "" {
+		remote = cfg.Sync.Remote
+	}
+	if remote == "" {
+		err := fmt.Errorf("no sync remote configured")
+		printError("Sync failed", "Set sync.remote in the config file or pass --remote <git url>.")
+		return err
+	}
+
+	store, err := openBookmarks()
+	if err != nil {
+		printError("Sync failed", err.Error())
+		return err
+	}
+	dataDir, err := config.DataDir()
+	if err != nil {
+		printError("Sync failed", err.Error())
+		return err
+	}
+	repo := &gitsync.Repo{Dir: filepath.Join(dataDir, "sync"), Remote: remote}
+
+	status(spinnerSty, fmt.Sprintf("🔄 Syncing bookmarks with %s...", remote), "syncing", "remote", remote)
+
+	const file = "bookmarks.json"
+	local := store.All()
+	var fromRemote []*bookmarks.Bookmark
+	var pulled, pushed int
+	host, _ := os.Hostname()
+	res, err := repo.Sync(cmd.Context(), []string{file}, "flo sync from "+host,
+		func(files map[string][]byte) (map[string][]byte, error) {
+			fromRemote = nil
+			if data := files[file]; len(data) > 0 {
+				items, err := bookmarks.Decode(data)
+				if err != nil {
+					return nil, fmt.Errorf("remote %s: %w", file, err)
+				}
+				fromRemote = items
+			}
+			merged, n := bookmarks.Merge(fromRemote, local)
+			_, pulled = bookmarks.Merge(local, fromRemote)
+			pushed = n
+			data, err := bookmarks.Encode(merged)
+			if err != nil {
+				return nil, err
+			}
+			return map[string][]byte{file: data}, nil
+		})
+	if err != nil {
+		printError("Sync failed", err.Error())
+		return err
+	}
+
+	store.Merge(fromRemote)
+	if err := store.Save(); err != nil {
+		printError("Sync failed", err.Error())
+		return err
+	}
+	if !res.Pushed {
+		pushed = 0
+	}
+	fmt.Println(successSty.Render(fmt.Sprintf("✅ Synced: %d pulled, %d pushed (%d bookmarks)",
+		pulled, pushed, len(store.All()))))
+	return nil
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ratnesh-maurya/flo/pkg/config"
+	"github.com/ratnesh-maurya/flo/pkg/subscriptions"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tagsMinScore   int
+	tagsUnanswered bool
+)
+
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "Manage the tags you follow",
+	Long: `Manage tag subscriptions.  Subscribed tags decide what flo digest
+reports; each can set its own minimum score and report only unanswered
+questions.
+
+  flo tags subscribe go kubernetes
+  flo tags subscribe rust --min-score 5 --unanswered
+  flo tags unsubscribe kubernetes
+  flo tags list`,
+}
+
+var tagsSubscribeCmd = &cobra.Command{
+	Use:     "subscribe <tag>...",
+	Aliases: []string{"sub", "add"},
+	Short:   "Follow tags, or change the options of followed ones",
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    runTagsSubscribe,
+
+	ValidArgsFunction: completeTags,
+}
+
+var tagsUnsubscribeCmd = &cobra.Command{
+	Use:     "unsubscribe <tag>...",
+	Aliases: []string{"unsub", "rm"},
+	Short:   "Stop following tags",
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    runTagsUnsubscribe,
+
+	ValidArgsFunction: completeSubscribedTags,
+}
+
+var tagsListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List followed tags",
+	Args:    cobra.NoArgs,
+	RunE:    runTagsList,
+}
+
+func init() {
+	tagsSubscribeCmd.Flags().IntVar(&tagsMinScore, "min-score", 0, "report only questions scoring at least this (default: the feature's own)")
+	tagsSubscribeCmd.Flags().BoolVar(&tagsUnanswered, "unanswered", false, "report only questions without an accepted or upvoted answer")
+	tagsCmd.AddCommand(tagsSubscribeCmd, tagsUnsubscribeCmd, tagsListCmd)
+	rootCmd.AddCommand(tagsCmd)
+}
+
+// runTagsSubscribe implements `flo tags subscribe`.
+func runTagsSubscribe(cmd *cobra.Command, args []string) error {
+	store, err := openSubscriptions()
+	if err != nil {
+		printError("Subscriptions unavailable", err.Error())
+		return err
+	}
+	var added, updated []string
+	for _, arg := range args {
+		tag, err := subscriptions.NormalizeTag(arg)
+		if err != nil {
+			return err
+		}
+		if store.Set(subscriptions.Subscription{Tag: tag, MinScore: tagsMinScore, UnansweredOnly: tagsUnanswered}) {
+			added = append(added, tag)
+		} else {
+			updated = append(updated, tag)
+		}
+	}
+	if err := store.Save(); err != nil {
+		printError("Subscribe failed", err.Error())
+		return err
+	}
+	if len(added) > 0 {
+		fmt.Println(successSty.Render("✅ Subscribed to " + strings.Join(added, ", ")))
+	}
+	if len(updated) > 0 {
+		fmt.Println(successSty.Render("✅ Updated " + strings.Join(updated, ", ")))
+	}
+	return nil
+}
+
+// runTagsUnsubscribe implements `flo tags unsubscribe`.
+func runTagsUnsubscribe(cmd *cobra.Command, args []string) error {
+	store, err := openSubscriptions()
+	if err != nil {
+		printError("Subscriptions unavailable", err.Error())
+		return err
+	}
+	var removed, unknown []string
+	for _, arg := range args {
+		tag, err := subscriptions.NormalizeTag(arg)
+		if err != nil {
+			return err
+		}
+		if store.Remove(tag) {
+			removed = append(removed, tag)
+		} else {
+			unknown = append(unknown, tag)
+		}
+	}
+	if len(unknown) > 0 {
+		fmt.Println(dimSty.Render("  Not subscribed to " + strings.Join(unknown, ", ")))
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+	if err := store.Save(); err != nil {
+		printError("Unsubscribe failed", err.Error())
+		return err
+	}
+	fmt.Println(successSty.Render("✅ Unsubscribed from " + strings.Join(removed, ", ")))
+	return nil
+}
+
+// runTagsList implements `flo tags list`.
+func runTagsList(cmd *cobra.Command, args []string) error {
+	store, err := openSubscriptions()
+	if err != nil {
+		printError("Subscriptions unavailable", err.Error())
+		return err
+	}
+	subs := store.All()
+	if len(subs) == 0 {
+		fmt.Println(dimSty.Render("No subscriptions yet — flo tags subscribe <tag> to follow one."))
+		return nil
+	}
+	width := 0
+	for _, s := range subs {
+		width = max(width, len(s.Tag))
+	}
+	for _, s := range subs {
+		line := fmt.Sprintf("  %-*s  since %s", width, s.Tag, s.SubscribedAt.Format("Jan 2, 2006"))
+		if opts := s.Options(); opts != "" {
+			line += "  (" + opts + ")"
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// openSubscriptions opens the subscription store in the data directory.
+func openSubscriptions() (*subscriptions.Store, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return nil, err
+	}
+	return subscriptions.Open(subscriptions.DefaultPath(dir))
+}
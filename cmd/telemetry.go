@@ -0,0 +1,341 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/config"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+	"github.com/ratnesh-maurya/flo/pkg/quota"
+	"github.com/ratnesh-maurya/flo/pkg/telemetry"
+	"github.com/spf13/cobra"
+)
+
+var telemetryCmd = &cobra.Command{
+	Use:   "telemetry",
+	Short: "Show, turn on or off, and export anonymous usage counts",
+	Long: `flo can count, when you opt in, how it is used and how it fails:
+
+  - the commands run, e.g. "ask" or "cache prune", never their arguments
+  - the backend calls they make, e.g. "mcp so_search" or
+    "api questions/{id}/answers", with IDs and tags masked
+  - how long each took, in buckets (<100ms, <300ms, <1s, ... >=30s)
+  - the class of error each ended with: connection, auth, parse, ...
+  - flo's version, OS and architecture
+
+Queries, titles, tags, IDs, addresses and file names are never recorded.
+The counts are added up on this machine, in <data dir>/telemetry.json,
+and nothing is sent anywhere: flo telemetry export writes them out as
+JSON, to attach to a bug report.
+
+It is off until flo telemetry enable.  DO_NOT_TRACK=1 keeps it off.`,
+	Args: cobra.NoArgs,
+	RunE: runTelemetryStatus,
+}
+
+var telemetryStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Say whether counting is on and summarize the counts",
+	Args:  cobra.NoArgs,
+	RunE:  runTelemetryStatus,
+}
+
+var telemetryEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Start counting (sets telemetry.enabled in the config)",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := setTelemetry(true)
+		if err != nil {
+			return err
+		}
+		fmt.Println(successSty.Render("✅ Telemetry is on."))
+		fmt.Println(dimSty.Render("  Saved to " + path + ". Counts stay on this machine; see flo telemetry --help."))
+		telemetryOverridden()
+		return nil
+	},
+}
+
+var telemetryDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Stop counting and delete the counts kept",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := setTelemetry(false); err != nil {
+			return err
+		}
+		path, err := telemetryPath()
+		if err == nil {
+			err = os.Remove(path)
+		}
+		switch {
+		case err == nil:
+			fmt.Println(successSty.Render("✅ Telemetry is off, and the counts are deleted."))
+		case errors.Is(err, fs.ErrNotExist):
+			fmt.Println(successSty.Render("✅ Telemetry is off."))
+		default:
+			printError("Could not delete the counts", err.Error())
+			return err
+		}
+		telemetryOverridden()
+		return nil
+	},
+}
+
+var telemetryExportCmd = &cobra.Command{
+	Use:   "export [file]",
+	Short: "Write the counts as JSON to file, or stdout",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runTelemetryExport,
+}
+
+func init() {
+	telemetryCmd.AddCommand(telemetryStatusCmd, telemetryEnableCmd, telemetryDisableCmd, telemetryExportCmd)
+	rootCmd.AddCommand(telemetryCmd)
+}
+
+// telemetryRun holds the counts of this run until Execute flushes them.
+var telemetryRun = telemetry.NewRecorder()
+
+// telemetryOn reports whether counts are recorded: telemetry.enabled is
+// set and DO_NOT_TRACK is not.
+func telemetryOn() bool {
+	return cfg.Telemetry.Enabled && !doNotTrack()
+}
+
+// doNotTrack reports whether DO_NOT_TRACK asks for no telemetry.
+func doNotTrack() bool {
+	v := os.Getenv("DO_NOT_TRACK")
+	b, err := strconv.ParseBool(v)
+	return v != "" && (err != nil || b)
+}
+
+// telemetryPath returns the location of the counts.
+func telemetryPath() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return telemetry.DefaultPath(dir), nil
+}
+
+// setTelemetry saves telemetry.enabled in the user config.
+func setTelemetry(on bool) (string, error) {
+	path, err := userConfigPath()
+	if err == nil {
+		var v any // unset is off
+		if on {
+			v = true
+		}
+		err = config.Update(path, map[string]any{"telemetry.enabled": v})
+	}
+	if err != nil {
+		printError("Could not save the config", err.Error())
+		return "", err
+	}
+	cfg.Telemetry.Enabled = on
+	return path, nil
+}
+
+// telemetryOverridden warns when the environment decides instead of the
+// config.
+func telemetryOverridden() {
+	switch {
+	case os.Getenv("FLO_TELEMETRY") != "":
+		fmt.Println(spinnerSty.Render("  FLO_TELEMETRY is set, and decides over the config."))
+	case doNotTrack():
+		fmt.Println(spinnerSty.Render("  DO_NOT_TRACK is set, so nothing is counted."))
+	}
+}
+
+// runTelemetryStatus implements `flo telemetry status`.
+func runTelemetryStatus(cmd *cobra.Command, args []string) error {
+	path, err := telemetryPath()
+	if err != nil {
+		printError("Telemetry unavailable", err.Error())
+		return err
+	}
+	switch {
+	case telemetryOn():
+		fmt.Println(successSty.Render("Telemetry is on.") + dimSty.Render(" flo telemetry disable turns it off."))
+	case doNotTrack():
+		fmt.Println(dimSty.Render("Telemetry is off: DO_NOT_TRACK is set."))
+	default:
+		fmt.Println(dimSty.Render("Telemetry is off. flo telemetry enable turns it on; see flo telemetry --help for what it counts."))
+	}
+	rep, err := telemetry.Load(path)
+	if err != nil {
+		printError("Telemetry unavailable", err.Error())
+		return err
+	}
+	if len(rep.Commands) == 0 && len(rep.Calls) == 0 {
+		return nil
+	}
+	fmt.Println(dimSty.Render(fmt.Sprintf("  %s, since %s", path, rep.Since.Local().Format("Jan 2, 2006"))))
+	for _, section := range []struct {
+		title string
+		stats map[string]*telemetry.Stat
+	}{{"Commands", rep.Commands}, {"Backend calls", rep.Calls}} {
+		if len(section.stats) == 0 {
+			continue
+		}
+		fmt.Println()
+		fmt.Println(promptSty.Render(section.title))
+		for _, name := range telemetry.Names(section.stats) {
+			s := section.stats[name]
+			line := fmt.Sprintf("  %-28s %5d", name, s.Count)
+			if n := s.Failures(); n > 0 {
+				line += spinnerSty.Render(fmt.Sprintf("  %d failed: %s", n, errorClasses(s.Errors)))
+			}
+			fmt.Println(line)
+		}
+	}
+	return nil
+}
+
+// errorClasses lists error counts as "connection 3, auth 1".
+func errorClasses(errs map[string]int) string {
+	names := make([]string, 0, len(errs))
+	for class := range errs {
+		names = append(names, class)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if errs[names[i]] != errs[names[j]] {
+			return errs[names[i]] > errs[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	parts := make([]string, len(names))
+	for i, class := range names {
+		parts[i] = fmt.Sprintf("%s %d", class, errs[class])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// runTelemetryExport implements `flo telemetry export`.
+func runTelemetryExport(cmd *cobra.Command, args []string) error {
+	path, err := telemetryPath()
+	if err != nil {
+		printError("Telemetry unavailable", err.Error())
+		return err
+	}
+	rep, err := telemetry.Load(path)
+	if err != nil {
+		printError("Telemetry unavailable", err.Error())
+		return err
+	}
+	data, err := rep.Encode()
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(expandHome(args[0]), data, 0o644); err != nil {
+		printError("Export failed", err.Error())
+		return err
+	}
+	fmt.Fprintln(os.Stderr, successSty.Render("✅ Wrote "+args[0]))
+	return nil
+}
+
+// recordCommand counts the run of c, which took d and returned err.
+// The hidden commands shells run for completion are not counted.
+func recordCommand(c *cobra.Command, d time.Duration, err error) {
+	if c == nil || !telemetryOn() || strings.HasPrefix(c.Name(), "__") {
+		return
+	}
+	name := strings.TrimPrefix(c.CommandPath(), rootCmd.Name()+" ")
+	if c == rootCmd {
+		name = "(repl)"
+	}
+	telemetryRun.Command(name, d, errorClass(err))
+}
+
+// recordMCPCall counts an MCP tool call.
+func recordMCPCall(c mcp.Call) {
+	if telemetryOn() {
+		telemetryRun.Call("mcp "+c.Tool, c.Duration, errorClass(c.Err))
+	}
+	if serveMetrics != nil {
+		serveMetrics.Call("mcp "+c.Tool, c.Duration, errorClass(c.Err))
+	}
+}
+
+// recordAPICall counts a Stack Exchange API request.
+func recordAPICall(path string, d time.Duration, err error) {
+	if telemetryOn() {
+		telemetryRun.Call("api "+apiOperation(path), d, errorClass(err))
+	}
+	if serveMetrics != nil {
+		serveMetrics.Call("api "+apiOperation(path), d, errorClass(err))
+	}
+}
+
+// apiOperation masks the IDs and tags in an API path:
+// "/questions/1;2/answers" gives "questions/{id}/answers".
+func apiOperation(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	for i, p := range parts {
+		if i > 0 && (parts[i-1] == "tags" || strings.Trim(p, "abcdefghijklmnopqrstuvwxyz-_") != "") {
+			parts[i] = "{id}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// flushTelemetry saves the counts of this run.
+func flushTelemetry() {
+	path, err := telemetryPath()
+	if err == nil {
+		err = telemetryRun.Flush(path, version, runtime.GOOS, runtime.GOARCH)
+	}
+	if err != nil {
+		slog.Warn("save telemetry", "err", err)
+	}
+}
+
+// errorClass names the kind of failure err is, from the exit code it
+// maps to; "" for none.  The Stack Exchange API's own error names are
+// kept, as "api_throttle_violation", and requests refused by the API
+// budget are "quota".
+func errorClass(err error) string {
+	if err == nil {
+		return ""
+	}
+	var apiErr *provider.APIError
+	if errors.As(err, &apiErr) && apiErr.Name != "" {
+		return "api_" + apiErr.Name
+	}
+	var spent *quota.SpentError
+	if errors.As(err, &spent) || errors.Is(err, quota.ErrSkipped) {
+		return "quota"
+	}
+	switch ExitCode(err) {
+	case exitNoResults:
+		return "no_results"
+	case exitConnection:
+		return "connection"
+	case exitAuthRequired:
+		return "auth"
+	case exitParse:
+		return "parse"
+	case exitInputRequired:
+		return "input_required"
+	case exitUsage:
+		return "usage"
+	case exitInterrupted:
+		return "interrupted"
+	}
+	return "other"
+}
@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/config"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+	"github.com/ratnesh-maurya/flo/pkg/quota"
+	"github.com/ratnesh-maurya/flo/pkg/ticker"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tickerLines  int
+	tickerFollow bool
+)
+
+// Ticker defaults and bounds.
+const (
+	defaultTickerLimit = 10
+	// tickerKeep is how long a question stays in the feed, and
+	// tickerMaxItems how many it holds at most.
+	tickerKeep     = 7 * 24 * time.Hour
+	tickerMaxItems = 500
+	// tickerFollowPoll is how often flo ticker -f looks for new entries.
+	tickerFollowPoll = 2 * time.Second
+)
+
+var tickerCmd = &cobra.Command{
+	Use:   "ticker",
+	Short: "Print the hot questions in your tags, one per line, as the daemon finds them",
+	Long: `Print the feed of hot questions in your subscribed tags (see flo tags)
+that flo serve keeps when started with --ticker, or with ticker.enabled
+set: every ticker.interval (15m) it looks up the best scoring questions
+of the last ticker.window (24h) in each tag, and feeds those it has not
+seen before.
+
+  flo serve --ticker &    keep the feed, alone or alongside --editor
+  flo ticker              the last 20 questions fed
+  flo ticker -f           then each new one as it is fed
+
+Each line gives the time the question was fed, its tag, score, answers
+and title, then its link.  A subscription's --min-score and --unanswered
+apply; ticker.min_score sets the minimum for the others.`,
+	Args: cobra.NoArgs,
+	RunE: runTicker,
+}
+
+func init() {
+	tickerCmd.Flags().IntVarP(&tickerLines, "lines", "n", 20, "how many of the latest questions to print first")
+	tickerCmd.Flags().BoolVarP(&tickerFollow, "follow", "f", false, "keep printing questions as they are fed, until Ctrl+C")
+	rootCmd.AddCommand(tickerCmd)
+}
+
+// runTicker implements `flo ticker`.
+func runTicker(cmd *cobra.Command, args []string) error {
+	if err := terminalOnly("flo ticker"); err != nil {
+		return err
+	}
+	path, err := tickerPath()
+	if err != nil {
+		printError("Ticker unavailable", err.Error())
+		return err
+	}
+	feed, err := ticker.Load(path)
+	if err != nil {
+		printError("Ticker unavailable", err.Error())
+		return err
+	}
+	tickerStale(feed)
+	items := feed.Items
+	if tickerLines >= 0 && len(items) > tickerLines {
+		items = items[len(items)-tickerLines:]
+	}
+	for i := range items {
+		printTickerItem(&items[i])
+	}
+	if !tickerFollow {
+		return nil
+	}
+
+	ctx := cmd.Context()
+	last := feed.Updated
+	if n := len(feed.Items); n > 0 {
+		last = feed.Items[n-1].Seen
+	}
+	t := time.NewTicker(tickerFollowPoll)
+	defer t.Stop()
+	var mod time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+		info, err := os.Stat(path)
+		if err != nil || !info.ModTime().After(mod) {
+			continue
+		}
+		mod = info.ModTime()
+		feed, err := ticker.Load(path)
+		if err != nil {
+			slog.Warn("read ticker feed", "err", err)
+			continue
+		}
+		for _, it := range feed.After(last) {
+			printTickerItem(&it)
+			last = it.Seen
+		}
+	}
+}
+
+// printTickerItem prints one line of the ticker.
+func printTickerItem(it *ticker.Item) {
+	link := it.Link
+	if link == "" {
+		link = postURL("q", it.QuestionID)
+	}
+	fmt.Println(it.Line() + "  " + dimSty.Render(link))
+}
+
+// tickerStale warns when no daemon seems to be keeping the feed.
+func tickerStale(feed *ticker.Feed) {
+	interval := cfg.Ticker.PollInterval()
+	switch {
+	case feed.Updated.IsZero():
+		fmt.Fprintln(os.Stderr, dimSty.Render("The feed is empty: flo serve --ticker keeps it (see flo ticker --help)."))
+	case time.Since(feed.Updated) > 2*interval+time.Minute:
+		fmt.Fprintln(os.Stderr, spinnerSty.Render(fmt.Sprintf("⚠ The feed was last updated %s ago; is flo serve --ticker running?",
+			time.Since(feed.Updated).Round(time.Minute))))
+	}
+}
+
+// tickerPath returns the location of the feed.
+func tickerPath() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return ticker.DefaultPath(dir), nil
+}
+
+// runTickerFeed keeps the feed until ctx is done, polling the tags at
+// once and then every ticker.interval.
+func runTickerFeed(ctx context.Context, p provider.Provider) error {
+	path, err := tickerPath()
+	if err != nil {
+		return err
+	}
+	t := time.NewTicker(cfg.Ticker.PollInterval())
+	defer t.Stop()
+	for {
+		pollTicker(ctx, p, path)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// pollTicker looks up the hot questions of each tag the digest covers
+// and feeds the new ones.  The requests are extras, which a low API
+// budget skips until the quota renews.
+func pollTicker(ctx context.Context, p provider.Provider, path string) {
+	// Subscriptions made while the daemon runs count from the next poll.
+	subs, err := digestSubscriptions()
+	if err != nil {
+		slog.Warn("ticker: subscriptions unavailable", "err", err)
+		return
+	}
+	if len(subs) == 0 {
+		slog.Warn("ticker: no tags to poll; run flo tags subscribe")
+		return
+	}
+	now := time.Now()
+	since := now.Add(-cfg.Ticker.NewWindow())
+	limit := firstPositive(cfg.Ticker.Limit, defaultTickerLimit)
+
+	feed, err := ticker.Load(path)
+	if err != nil {
+		slog.Warn("ticker feed unreadable; starting over", "err", err)
+		feed = &ticker.Feed{}
+	}
+	added := 0
+	for _, sub := range subs {
+		tctx, cancel := context.WithTimeout(quota.Extra(ctx), cfg.Timeouts.SearchTimeout())
+		resp, err := provider.NewQuestions(tctx, p, sub.Tag, since, firstPositive(sub.MinScore, cfg.Ticker.MinScore), limit)
+		cancel()
+		switch {
+		case errors.Is(err, provider.ErrNotFound):
+			continue
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			slog.Warn("ticker: poll failed", "tag", sub.Tag, "err", err)
+			continue
+		}
+		var hot []mcp.QuestionData
+		for i := range resp.Items {
+			if sub.Matches(&resp.Items[i], cfg.Ticker.MinScore) {
+				hot = append(hot, resp.Items[i])
+			}
+		}
+		added += len(feed.Add(sub.Tag, hot, now))
+	}
+	feed.Updated = now
+	feed.Prune(now, tickerKeep, tickerMaxItems)
+	if err := feed.Save(path); err != nil {
+		slog.Warn("ticker: save feed", "err", err)
+		return
+	}
+	slog.Info("ticker polled", "tags", len(subs), "new", added)
+}
@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+	"github.com/ratnesh-maurya/flo/pkg/subscriptions"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	topTag     string
+	topAllTime bool
+	topUsers   int
+	topAnswers int
+)
+
+// How far back an answerer's answers count as recent.
+const topAnswersPeriod = 365 * 24 * time.Hour
+
+var topCmd = &cobra.Command{
+	Use:   "top --tag <tag>",
+	Short: "Show a tag's top answerers and their recent best answers",
+	Long: `Show who answers a tag best: its top answerers of the last 30 days
+(--all-time for ever), with the score their answers earned, and under
+each their highest voted answers of the past year.  In a terminal, pick
+any of those answers to open it.
+
+  flo top --tag kubernetes
+  flo top --tag go --all-time -n 5
+
+The leaderboard comes from the Stack Exchange API and costs one request,
+plus one per answerer listed.`,
+	Args: cobra.NoArgs,
+	RunE: runTop,
+}
+
+func init() {
+	topCmd.Flags().StringVar(&topTag, "tag", "", "the tag to rank answerers in (required)")
+	topCmd.Flags().BoolVar(&topAllTime, "all-time", false, "rank by all-time score instead of the last 30 days")
+	topCmd.Flags().IntVarP(&topUsers, "limit", "n", 10, "answerers listed")
+	topCmd.Flags().IntVar(&topAnswers, "answers", 3, "answers listed under each answerer")
+	_ = topCmd.MarkFlagRequired("tag")
+	_ = topCmd.RegisterFlagCompletionFunc("tag", completeTags)
+	rootCmd.AddCommand(topCmd)
+}
+
+// topEntry is an answerer on the leaderboard with their answers.
+type topEntry struct {
+	provider.Answerer
+	TopAnswers []mcp.AnswerData `json:"top_answers,omitempty"`
+}
+
+// runTop implements `flo top`.
+func runTop(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	tag, err := subscriptions.NormalizeTag(topTag)
+	if err != nil {
+		return withExitCode(exitUsage, err)
+	}
+
+	p, release, err := connect(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	status(spinnerSty, fmt.Sprintf("🏆 Ranking the answerers of [%s]...", tag), "fetching top answerers",
+		"tag", tag, "all_time", topAllTime)
+	tctx, cancel := context.WithTimeout(ctx, cfg.Timeouts.SearchTimeout())
+	answerers, err := provider.TagAnswerers(tctx, p, tag, topAllTime, topUsers)
+	cancel()
+	switch {
+	case errors.Is(err, provider.ErrNotFound):
+		fmt.Fprintln(chrome(), dimSty.Render(fmt.Sprintf("Nobody has answered in [%s] lately; try --all-time.", tag)))
+		return errNoResults
+	case errors.Is(err, provider.ErrUnsupported):
+		printError("Leaderboard unavailable", "Top answerers come from the Stack Exchange API, which this config does not use: "+
+			"run with --backend api, or keep api in failover.backends.")
+		return err
+	case err != nil:
+		printError("Leaderboard failed", err.Error())
+		return err
+	}
+
+	since := time.Now().Add(-topAnswersPeriod)
+	entries := make([]topEntry, len(answerers))
+	for i, a := range answerers {
+		entries[i].Answerer = a
+		id := a.User.ID()
+		if id == 0 || topAnswers <= 0 {
+			continue
+		}
+		fctx, cancel := context.WithTimeout(ctx, cfg.Timeouts.FetchTimeout())
+		entries[i].TopAnswers, err = provider.UserTopAnswers(fctx, p, id, tag, since, topAnswers)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			slog.Warn("fetch top answers failed", "user_id", id, "tag", tag, "err", err)
+		}
+	}
+
+	period := "last 30 days"
+	if topAllTime {
+		period = "all time"
+	}
+	heading := fmt.Sprintf("Top answerers in [%s], %s", tag, period)
+	if !ui.Current().Styled() {
+		printDoc(ui.Document{Markdown: formatTopEntries(heading, entries), Data: entries})
+		return nil
+	}
+	fmt.Println(promptSty.Render(heading))
+	for i := range entries {
+		printTopEntry(i+1, &entries[i])
+	}
+	if interactive() {
+		return topSelectionLoop(ctx, p, entries)
+	}
+	return nil
+}
+
+// printTopEntry prints the nth answerer and their answers.
+func printTopEntry(n int, e *topEntry) {
+	line := fmt.Sprintf("%3d. %s", n, html.UnescapeString(e.User.DisplayName))
+	fmt.Println()
+	fmt.Println(line + dimSty.Render("  "+topMeta(e)))
+	for i := range e.TopAnswers {
+		a := &e.TopAnswers[i]
+		fmt.Printf("       %s %s\n", ui.ScoreBadge(a.Score, a.IsAccepted), html.UnescapeString(a.Title))
+		if a.Link != "" {
+			fmt.Println(dimSty.Render("            " + a.Link))
+		}
+	}
+}
+
+// formatTopEntries renders the leaderboard under heading as Markdown,
+// for the formats other than ansi.
+func formatTopEntries(heading string, entries []topEntry) string {
+	var b strings.Builder
+	b.WriteString("# " + heading + "\n\n")
+	for i := range entries {
+		e := &entries[i]
+		b.WriteString(fmt.Sprintf("%d. **%s** — %s\n", i+1, html.UnescapeString(e.User.DisplayName), topMeta(e)))
+		for j := range e.TopAnswers {
+			a := &e.TopAnswers[j]
+			b.WriteString(fmt.Sprintf("   - %+d %s", a.Score, html.UnescapeString(a.Title)))
+			if a.IsAccepted {
+				b.WriteString(" ✅")
+			}
+			if a.Link != "" {
+				b.WriteString("  \n     " + a.Link)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// topMeta describes e's standing: reputation, score and answers.
+func topMeta(e *topEntry) string {
+	meta := fmt.Sprintf("score %d from %d answers", e.Score, e.Answers)
+	if e.Answers == 1 {
+		meta = fmt.Sprintf("score %d from 1 answer", e.Score)
+	}
+	if e.User.Reputation > 0 {
+		meta = mcp.CompactNumber(e.User.Reputation) + " rep · " + meta
+	}
+	return meta
+}
+
+// topSelectionLoop lets the user open the answers listed until they
+// cancel.
+func topSelectionLoop(ctx context.Context, p provider.Provider, entries []topEntry) error {
+	var (
+		items   []string
+		answers []*mcp.AnswerData
+	)
+	for i := range entries {
+		e := &entries[i]
+		for j := range e.TopAnswers {
+			a := &e.TopAnswers[j]
+			items = append(items, fmt.Sprintf("%s %s — %s", ui.ScoreBadge(a.Score, a.IsAccepted),
+				html.UnescapeString(a.Title), html.UnescapeString(e.User.DisplayName)))
+			answers = append(answers, a)
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	fmt.Println()
+	for {
+		sel := promptui.Select{
+			Label:     i18n.T("Open an answer") + " " + pickHint("view", "exit"),
+			Items:     items,
+			Size:      min(len(items), 15),
+			Templates: selectTemplates(),
+			HideHelp:  ui.Plain(),
+			Stdout:    bellSkipper{},
+			Stdin:     pickerStdin(),
+		}
+		i, _, err := sel.Run()
+		if err != nil {
+			return nil
+		}
+		e := openAnswer(ctx, p, answers[i])
+		if e == nil {
+			continue
+		}
+		history.push(e)
+		if err := browse(ctx, p, e, false); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// openAnswer fetches the question a answers and returns its entry with
+// a as the one answer loaded, so it heads the answer list; the others
+// load when listed.  It returns nil, having said why, when the question
+// cannot be fetched.
+func openAnswer(ctx context.Context, p provider.Provider, a *mcp.AnswerData) *navEntry {
+	if a.QuestionID == 0 {
+		fmt.Println(dimSty.Render(i18n.Tf("  View on Stack Overflow: %s", a.Link)))
+		return nil
+	}
+	status(spinnerSty, i18n.T("📖 Fetching the question..."), "fetching question", "question_id", a.QuestionID)
+	fctx, cancel := context.WithTimeout(ctx, cfg.Timeouts.FetchTimeout())
+	q, err := p.GetQuestion(fctx, a.QuestionID)
+	cancel()
+	if err != nil {
+		slog.Warn("fetch question failed", "question_id", a.QuestionID, "err", err)
+		printError(i18n.T("Fetch failed"), err.Error())
+		return nil
+	}
+	opened := *q
+	opened.Answers = []mcp.AnswerData{*a}
+	for _, full := range q.Answers {
+		if full.AnswerID == a.AnswerID {
+			opened.Answers[0] = full
+		}
+	}
+	opened.AnswerCount = max(opened.AnswerCount, len(q.Answers))
+	return &navEntry{question: &opened}
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+	"github.com/ratnesh-maurya/flo/pkg/translate"
+)
+
+var (
+	translatorOnce sync.Once
+	translator     translate.Translator
+
+	// queryLang is the language of the last query translated; answers
+	// are translated back into it.
+	queryLang string
+
+	// answerTranslations are the answer bodies translated so far, by
+	// answer id and language.
+	answerTranslations = map[string]string{}
+)
+
+// queryTranslator returns the translation service set in the config, or
+// nil when translation is off.  An invalid translate section is reported
+// once and leaves it off.
+func queryTranslator() translate.Translator {
+	translatorOnce.Do(func() {
+		if cfg.Translate.Provider == "" {
+			return
+		}
+		hc, err := newHTTPClient(0)
+		if err == nil {
+			translator, err = translate.New(cfg.Translate.Provider, cfg.Translate.URL, cfg.Translate.Key, hc)
+		}
+		if err != nil {
+			printError(i18n.T("Invalid configuration"), err.Error()+"\n\n"+i18n.T("Queries are searched as written until it is fixed."))
+			translator = nil
+		}
+	})
+	return translator
+}
+
+// translateQuery returns query in English for searching, noting its
+// language for translateAnswer.  A query in English, one for a site in
+// another language, or one the service fails on is returned as is.
+func translateQuery(ctx context.Context, query string) string {
+	t := queryTranslator()
+	if t == nil {
+		return query
+	}
+	if s, ok := provider.LookupSite(currentSite()); ok && s.Language != translate.English {
+		return query
+	}
+	out, lang, err := t.Translate(ctx, []string{query}, "", translate.English)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Warn("query translation failed", "err", err)
+			fmt.Println(dimSty.Render(i18n.Tf("  ⚠ Could not translate the query (%v); searching it as written.", err)))
+		}
+		return query
+	}
+	queryLang = lang
+	english := strings.TrimSpace(out[0])
+	if lang == translate.English || english == "" || strings.EqualFold(english, query) {
+		return query
+	}
+	status(dimSty, "  "+i18n.Tf("🌐 Translated from %s: %q", lang, english), "query translated", "from", lang, "query", english)
+	return english
+}
+
+// translateAnswer returns a with its body translated, when
+// translate.answers is on, into translate.language or else the language
+// of the query.  Otherwise, and when the service fails, a is returned.
+func translateAnswer(ctx context.Context, a *mcp.AnswerData) *mcp.AnswerData {
+	if !cfg.Translate.Answers {
+		return a
+	}
+	t := queryTranslator()
+	if t == nil {
+		return a
+	}
+	lang := cfg.Translate.Language
+	if lang == "" {
+		lang = queryLang
+	}
+	if lang = i18n.Language(lang); lang == translate.English {
+		return a
+	}
+	key := fmt.Sprintf("%d/%s", a.AnswerID, lang)
+	body, ok := answerTranslations[key]
+	if !ok {
+		status(spinnerSty, i18n.T("🌐 Translating the answer..."), "translating answer", "answer_id", a.AnswerID, "to", lang)
+		tctx, cancel := context.WithTimeout(ctx, cfg.Timeouts.FetchTimeout())
+		defer cancel()
+		var err error
+		body, err = translate.Markdown(tctx, t, html.UnescapeString(a.BodyMarkdown), translate.English, lang)
+		if err != nil {
+			slog.Warn("answer translation failed", "answer_id", a.AnswerID, "err", err)
+			fmt.Println(dimSty.Render(i18n.Tf("  ⚠ Could not translate the answer (%v); showing it as written.", err)))
+			return a
+		}
+		answerTranslations[key] = body
+	}
+	fmt.Println(dimSty.Render(i18n.Tf("  🌐 Machine-translated by %s; code is as written.", t.Name())))
+	translated := *a
+	translated.BodyMarkdown = body
+	return &translated
+}
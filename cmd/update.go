@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/update"
+	"github.com/spf13/cobra"
+)
+
+var updateCheckOnly bool
+
+var updateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update flo to the latest release",
+	Long: `Check GitHub for the latest flo release and, if it is newer than the
+running version, download it, verify its SHA-256 checksum and replace
+the current binary in place.
+
+The checksum comes from the same GitHub release as the archive, and
+neither is signed: it catches a corrupted or truncated download, not a
+tampered release.  Install from a package manager, or check the release
+yourself, if you need more than that.
+
+  flo update           install the latest release
+  flo update --check   only report whether an update is available`,
+	Args: cobra.NoArgs,
+	RunE: runUpdate,
+}
+
+func init() {
+	updateCmd.Flags().BoolVar(&updateCheckOnly, "check", false, "only check for a newer version")
+	rootCmd.AddCommand(updateCmd)
+}
+
+// runUpdate implements `flo update`.
+func runUpdate(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Minute)
+	defer cancel()
+
+	hc, err := newHTTPClient(2 * time.Minute)
+	if err != nil {
+		printError("Update failed", err.Error())
+		return err
+	}
+
+	status(spinnerSty, "⏳ Checking for updates...", "checking for updates", "current", version)
+	rel, err := update.Latest(ctx, hc)
+	if err != nil {
+		printError("Update check failed", err.Error())
+		return err
+	}
+
+	if !update.Newer(rel.Version(), version) {
+		fmt.Println(successSty.Render(fmt.Sprintf("✅ flo %s is up to date.", version)))
+		return nil
+	}
+
+	fmt.Println(promptSty.Render(fmt.Sprintf("⬆ flo %s is available (you have %s)", rel.Version(), version)))
+	if rel.HTMLURL != "" {
+		fmt.Println(dimSty.Render("  " + rel.HTMLURL))
+	}
+	if updateCheckOnly {
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		printError("Update failed", err.Error())
+		return err
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+
+	status(spinnerSty, "📦 Downloading and verifying...", "applying update", "version", rel.Version(), "path", exe)
+	if err := update.Apply(ctx, hc, rel, exe); err != nil {
+		printError("Update failed", err.Error())
+		return err
+	}
+
+	fmt.Println(successSty.Render(fmt.Sprintf("✅ Updated to flo %s", rel.Version())))
+	return nil
+}
+
+// ---------- passive update notice ----------
+
+// updateNotice receives the result of the background version check
+// started by startUpdateNotice.
+var updateNotice chan string
+
+// startUpdateNotice kicks off the once-a-day "new version available"
+// check in the background so it never delays the command itself.  It
+// is skipped for scripts (--non-interactive), for flo serve, whose
+// output is for another program, and for hidden commands such as shell
+// completion.
+func startUpdateNotice(cmd *cobra.Command) {
+	if version == "dev" || cmd == updateCmd || cmd == serveCmd || nonInteractive ||
+		strings.HasPrefix(cmd.Name(), "__") || os.Getenv("FLO_NO_UPDATE_CHECK") != "" {
+		return
+	}
+	updateNotice = make(chan string, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		hc, err := newHTTPClient(0)
+		if err != nil {
+			updateNotice <- ""
+			return
+		}
+		updateNotice <- update.CheckForNotice(ctx, hc, version)
+	}()
+}
+
+// printUpdateNotice prints the notice, if any, to stderr once the
+// command is done, however it ended.
+func printUpdateNotice() {
+	if updateNotice == nil {
+		return
+	}
+	if latest := <-updateNotice; latest != "" {
+		fmt.Fprintln(os.Stderr, dimSty.Render(
+			fmt.Sprintf("\n⬆ flo %s is available (you have %s) — run `flo update`", latest, version)))
+	}
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/config"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+	"github.com/ratnesh-maurya/flo/pkg/watch"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchInterval time.Duration
+	watchOnce     bool
+)
+
+// watchMinInterval keeps flo watch from spending the API quota on one
+// question.
+const watchMinInterval = time.Minute
+
+var watchCmd = &cobra.Command{
+	Use:   "watch <question ID or URL>",
+	Short: "Follow a question and show exactly what changed between polls",
+	Long: `Follow one question: every --interval flo fetches it again and shows
+what changed since the last look — a new answer in full, an edit to the
+question or to an answer (the accepted one flagged) as a line diff, the
+accepted answer changing, answers removed and new comments.
+
+  flo watch 11227809                          until Ctrl+C
+  flo watch https://stackoverflow.com/q/11227809 --interval 15m
+  flo watch 11227809 --once                   what changed since last time
+
+What the last poll saw is kept under the data dir, so watching a
+question again starts with what changed in between.  Comments come from
+the Stack Exchange API; backends without it compare only the posts.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWatch,
+}
+
+func init() {
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", 5*time.Minute, "how often to look for changes")
+	watchCmd.Flags().BoolVar(&watchOnce, "once", false, "show what changed since the last look, then exit")
+	rootCmd.AddCommand(watchCmd)
+}
+
+// runWatch implements `flo watch`.
+func runWatch(cmd *cobra.Command, args []string) error {
+	if err := terminalOnly("flo watch"); err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+	m := reQuestionRef.FindStringSubmatch(strings.TrimSpace(args[0]))
+	if m == nil {
+		return withExitCode(exitUsage, fmt.Errorf("%q is not a question ID or link", args[0]))
+	}
+	if watchInterval < watchMinInterval {
+		return withExitCode(exitUsage, fmt.Errorf("--interval must be at least %s", watchMinInterval))
+	}
+	dir, err := config.DataDir()
+	if err != nil {
+		printError("Watch failed", err.Error())
+		return err
+	}
+
+	// Every poll must see the site as it is now.
+	noCache = true
+	p, release, err := connect(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	id, _ := strconv.Atoi(m[2])
+	if m[1] == "a" || m[1] == "answers" {
+		fctx, cancel := context.WithTimeout(ctx, cfg.Timeouts.FetchTimeout())
+		a, err := p.GetAnswer(fctx, id)
+		cancel()
+		if err != nil {
+			return reportFetchError(fctx, err, fmt.Sprintf("answer %d", id))
+		}
+		if a.QuestionID == 0 {
+			printError("Watch failed", fmt.Sprintf("Could not tell which question answer %d belongs to; watch the question instead.", id))
+			return errNoResults
+		}
+		id = a.QuestionID
+	}
+
+	path := watch.DefaultPath(dir, id)
+	last, err := watch.Load(path)
+	if err != nil {
+		slog.Warn("load watch snapshot", "err", err)
+	}
+	status(spinnerSty, "👀 Fetching the question...", "fetching watched question", "question_id", id)
+	snap, err := watchSnapshot(ctx, p, id, last)
+	if err != nil {
+		return reportFetchError(ctx, err, fmt.Sprintf("question %d", id))
+	}
+	q := &snap.Question
+	fmt.Println(promptSty.Render(html.UnescapeString(q.Title)))
+	fmt.Println(dimSty.Render(q.Link))
+	if last != nil {
+		changes := watch.Compare(last, snap)
+		if len(changes) == 0 {
+			fmt.Println(dimSty.Render(fmt.Sprintf("Nothing changed since you last looked, %s ago.", shortAge(time.Since(last.Taken)))))
+		} else {
+			fmt.Println(successSty.Render(fmt.Sprintf("Since you last looked, %s ago:", shortAge(time.Since(last.Taken)))))
+			renderAndPrint(watch.Markdown(q, changes))
+		}
+	}
+	saveWatch(snap, path)
+	if watchOnce {
+		return nil
+	}
+
+	fmt.Println(dimSty.Render(fmt.Sprintf("Watching every %s; Ctrl+C to stop.", watchInterval)))
+	t := time.NewTicker(watchInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+		fresh, err := watchSnapshot(ctx, p, id, snap)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			slog.Warn("watch poll failed", "question_id", id, "err", err)
+			fmt.Println(spinnerSty.Render(fmt.Sprintf("⚠ %s: could not fetch the question; trying again in %s.",
+				time.Now().Format("15:04"), watchInterval)))
+			continue
+		}
+		if changes := watch.Compare(snap, fresh); len(changes) > 0 {
+			fmt.Println(successSty.Render("── " + fresh.Taken.Format("15:04") + " ──"))
+			renderAndPrint(watch.Markdown(&fresh.Question, changes))
+		}
+		snap = fresh
+		saveWatch(snap, path)
+	}
+}
+
+// watchSnapshot fetches question id with all its answers and their
+// comments.  When the comments cannot be had, those of prev (which may
+// be nil) are kept, so that none are reported new when they come back.
+func watchSnapshot(ctx context.Context, p provider.Provider, id int, prev *watch.Snapshot) (*watch.Snapshot, error) {
+	fctx, cancel := context.WithTimeout(ctx, cfg.Timeouts.FetchTimeout())
+	defer cancel()
+	q, err := p.GetQuestion(fctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if q.AnswerCount > len(q.Answers) {
+		all, err := provider.AllAnswers(fctx, p, id)
+		if err != nil {
+			slog.Warn("fetch watched answers failed", "question_id", id, "err", err)
+		} else if len(all) > len(q.Answers) {
+			q.Answers = all
+		}
+	}
+	s := &watch.Snapshot{Taken: time.Now(), Question: *q}
+
+	ids := []int{id}
+	for _, a := range q.Answers {
+		ids = append(ids, a.AnswerID)
+	}
+	s.Comments, err = provider.Comments(fctx, p, ids)
+	if err != nil {
+		if !errors.Is(err, provider.ErrUnsupported) {
+			slog.Warn("fetch watched comments failed", "question_id", id, "err", err)
+		}
+		s.Comments, s.NoComments = nil, true
+		if prev != nil {
+			s.Comments, s.NoComments = prev.Comments, prev.NoComments
+		}
+	}
+	return s, nil
+}
+
+// saveWatch keeps snap at path for the next look; failing only costs
+// that look its starting point.
+func saveWatch(snap *watch.Snapshot, path string) {
+	if err := snap.Save(path); err != nil {
+		slog.Warn("save watch snapshot", "err", err)
+	}
+}
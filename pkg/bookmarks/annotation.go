@@ -0,0 +1,74 @@
+package bookmarks
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// annotationSeparator ends the header of an annotation document.
+const annotationSeparator = "---"
+
+// FormatAnnotation renders b's annotations as the document edited in
+// $EDITOR: commented instructions and a tags line, then the note below
+// a "---" line.
+//
+//	# Note and local tags for:
+//	#   How to reverse a string in Go?
+//	tags: strings, unicode
+//	---
+//	Works on runes, so combining characters are split.
+func FormatAnnotation(b *Bookmark) string {
+	var s strings.Builder
+	s.WriteString("# Note and local tags for:\n")
+	s.WriteString(fmt.Sprintf("#   %s\n", b.Title))
+	s.WriteString("# Separate tags with commas or spaces.  Write the note in Markdown\n")
+	s.WriteString("# below the --- line; lines starting with # above it are ignored.\n")
+	s.WriteString("tags: " + strings.Join(b.LocalTags, ", ") + "\n")
+	s.WriteString(annotationSeparator + "\n")
+	if b.Note != "" {
+		s.WriteString(b.Note + "\n")
+	}
+	return s.String()
+}
+
+// ParseAnnotation reads a document in the FormatAnnotation layout,
+// returning the note and the local tags, lower-cased and deduplicated.
+func ParseAnnotation(doc string) (note string, tags []string, err error) {
+	header, body, ok := strings.Cut(doc, "\n"+annotationSeparator+"\n")
+	if !ok {
+		header, ok = strings.CutSuffix(strings.TrimRight(doc, "\n"), "\n"+annotationSeparator)
+		if !ok {
+			return "", nil, errors.New(`missing the "---" line between the tags and the note`)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(header, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		value, ok := strings.CutPrefix(line, "tags:")
+		if !ok {
+			return "", nil, fmt.Errorf("unexpected line %q above the note (want \"tags: ...\")", line)
+		}
+		for _, t := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+			t = strings.ToLower(t)
+			if !seen[t] {
+				seen[t] = true
+				tags = append(tags, t)
+			}
+		}
+	}
+	return strings.TrimSpace(body), tags, nil
+}
+
+// Annotate sets b's note and local tags, reporting whether they changed.
+func (b *Bookmark) Annotate(note string, tags []string, now time.Time) bool {
+	if note == b.Note && strings.Join(tags, ",") == strings.Join(b.LocalTags, ",") {
+		return false
+	}
+	b.Note, b.LocalTags, b.EditedAt = note, tags, now
+	return true
+}
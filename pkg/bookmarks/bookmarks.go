@@ -0,0 +1,197 @@
+// Package bookmarks persists the answers a user saves from flo, so they
+// can be listed and exported later.  The store is a JSON file in the
+// user data directory.
+package bookmarks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/atomicfile"
+)
+
+// Bookmark is a saved question/answer pair.
+type Bookmark struct {
+	QuestionID    int       `json:"question_id"`
+	AnswerID      int       `json:"answer_id,omitempty"`
+	Title         string    `json:"title"`
+	Link          string    `json:"link"`
+	Tags          []string  `json:"tags,omitempty"`
+	QuestionBody  string    `json:"question_body,omitempty"`
+	AnswerBody    string    `json:"answer_body,omitempty"`
+	AnswerAuthor  string    `json:"answer_author,omitempty"`
+	AnswerScore   int       `json:"answer_score"`
+	Accepted      bool      `json:"accepted,omitempty"`
+	QuestionScore int       `json:"question_score"`
+	CreationDate  time.Time `json:"creation_date,omitempty"`
+	SavedAt       time.Time `json:"saved_at"`
+
+	// Attribution details (see export.Credit), absent from bookmarks
+	// saved by older versions: the answer author's profile URL and when
+	// the answer was posted, which decides its license version.
+	AnswerAuthorLink string    `json:"answer_author_link,omitempty"`
+	AnswerDate       time.Time `json:"answer_date,omitempty"`
+
+	// The user's own annotations: a free-form Markdown note, tags of
+	// their choosing (kept apart from the question's Stack Overflow
+	// tags) and when either was last edited.
+	Note      string    `json:"note,omitempty"`
+	LocalTags []string  `json:"local_tags,omitempty"`
+	EditedAt  time.Time `json:"edited_at,omitempty"`
+}
+
+// Modified is when b last changed: when it was saved or, if later, when
+// its annotations were edited.
+func (b *Bookmark) Modified() time.Time {
+	if b.EditedAt.After(b.SavedAt) {
+		return b.EditedAt
+	}
+	return b.SavedAt
+}
+
+// Matches reports whether every term occurs, ignoring case, in b's
+// title, note, Stack Overflow tags or local tags.
+func (b *Bookmark) Matches(terms []string) bool {
+	text := strings.ToLower(b.Title + "\n" + b.Note + "\n" +
+		strings.Join(b.Tags, " ") + "\n" + strings.Join(b.LocalTags, " "))
+	for _, t := range terms {
+		if !strings.Contains(text, strings.ToLower(t)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Key uniquely identifies a bookmark.
+func (b *Bookmark) Key() string {
+	return fmt.Sprintf("%d/%d", b.QuestionID, b.AnswerID)
+}
+
+// Store is the bookmark collection backed by a JSON file.
+type Store struct {
+	path  string
+	items []*Bookmark
+}
+
+// DefaultPath returns bookmarks.json inside dataDir.
+func DefaultPath(dataDir string) string {
+	return filepath.Join(dataDir, "bookmarks.json")
+}
+
+// Open loads the store at path; a missing file yields an empty store.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("read bookmarks: %w", err)
+	}
+	items, err := Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse bookmarks %s: %w", path, err)
+	}
+	s.items = items
+	return s, nil
+}
+
+// Decode parses a bookmarks.json document.
+func Decode(data []byte) ([]*Bookmark, error) {
+	var items []*Bookmark
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Encode serializes items in a stable order (newest first, then by
+// key), so the same set always produces the same bytes — keeping diffs
+// small when the file is stored in git.
+func Encode(items []*Bookmark) ([]byte, error) {
+	sorted := make([]*Bookmark, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].SavedAt.Equal(sorted[j].SavedAt) {
+			return sorted[i].SavedAt.After(sorted[j].SavedAt)
+		}
+		return sorted[i].Key() < sorted[j].Key()
+	})
+	return json.MarshalIndent(sorted, "", "  ")
+}
+
+// Merge combines two bookmark sets keyed by Key.  When both contain the
+// same bookmark the more recently modified copy wins (ties keep dst's).  It
+// returns the merged set and how many entries were taken from src.
+func Merge(dst, src []*Bookmark) ([]*Bookmark, int) {
+	merged := make([]*Bookmark, 0, len(dst)+len(src))
+	pos := make(map[string]int, len(dst))
+	for _, b := range dst {
+		pos[b.Key()] = len(merged)
+		merged = append(merged, b)
+	}
+	taken := 0
+	for _, b := range src {
+		i, ok := pos[b.Key()]
+		switch {
+		case !ok:
+			pos[b.Key()] = len(merged)
+			merged = append(merged, b)
+		case b.Modified().After(merged[i].Modified()):
+			merged[i] = b
+		default:
+			continue
+		}
+		taken++
+	}
+	return merged, taken
+}
+
+// Merge folds other into the store (see the Merge function) and reports
+// how many bookmarks were added or updated.
+func (s *Store) Merge(other []*Bookmark) int {
+	var n int
+	s.items, n = Merge(s.items, other)
+	return n
+}
+
+// All returns bookmarks, most recently saved first.
+func (s *Store) All() []*Bookmark {
+	out := make([]*Bookmark, len(s.items))
+	copy(out, s.items)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].SavedAt.After(out[j].SavedAt) })
+	return out
+}
+
+// Add inserts b, replacing any bookmark with the same key but keeping
+// its annotations.  It reports whether b was new.
+func (s *Store) Add(b Bookmark) bool {
+	if b.SavedAt.IsZero() {
+		b.SavedAt = time.Now()
+	}
+	for i, existing := range s.items {
+		if existing.Key() == b.Key() {
+			b.Note, b.LocalTags, b.EditedAt = existing.Note, existing.LocalTags, existing.EditedAt
+			s.items[i] = &b
+			return false
+		}
+	}
+	s.items = append(s.items, &b)
+	return true
+}
+
+// Save writes the store back to disk atomically.
+func (s *Store) Save() error {
+	data, err := Encode(s.items)
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(s.path, data, 0o644)
+}
@@ -0,0 +1,223 @@
+// Package breaker keeps a circuit breaker per backend.  After Threshold
+// consecutive failures a backend's circuit opens and callers skip it —
+// failing over to the next backend — until Cooldown has passed, when
+// one call is let through to probe it.  A success closes the circuit.
+//
+// The state is saved to a file, since each flo command is a process of
+// its own: a server that failed the last few commands is skipped by the
+// next one without waiting for it to time out again.
+package breaker
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/atomicfile"
+)
+
+// Defaults for a zero Threshold or Cooldown.
+const (
+	DefaultThreshold = 3
+	DefaultCooldown  = time.Minute
+)
+
+// State is one backend's circuit.
+type State struct {
+	// Failures counts the consecutive failures.
+	Failures int `json:"failures"`
+	// OpenedAt is when the circuit opened; zero while it is closed.
+	OpenedAt time.Time `json:"opened_at"`
+	// ProbedAt is when the backend was last tried while open.
+	ProbedAt  time.Time `json:"probed_at"`
+	LastError string    `json:"last_error,omitempty"`
+	FailedAt  time.Time `json:"failed_at"`
+}
+
+// Open reports whether the circuit is open.
+func (s State) Open() bool {
+	return !s.OpenedAt.IsZero()
+}
+
+// NextProbe returns when an open circuit next lets a call through.
+func (s State) NextProbe(cooldown time.Duration) time.Time {
+	last := s.OpenedAt
+	if s.ProbedAt.After(last) {
+		last = s.ProbedAt
+	}
+	return last.Add(cooldown)
+}
+
+// Breakers are the circuits of every backend, saved at path.
+type Breakers struct {
+	path      string
+	Threshold int
+	Cooldown  time.Duration
+
+	mu     sync.Mutex
+	states map[string]State
+}
+
+// Load reads the circuits saved at path; a missing or unreadable file
+// means every circuit is closed.
+func Load(path string, threshold int, cooldown time.Duration) *Breakers {
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultCooldown
+	}
+	b := &Breakers{path: path, Threshold: threshold, Cooldown: cooldown, states: map[string]State{}}
+	b.states, _ = read(path)
+	return b
+}
+
+// read returns the circuits saved at path; a missing or unreadable file
+// has none, the latter also logged and reported as an error.
+func read(path string) (map[string]State, error) {
+	states := map[string]State{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return states, nil
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &states)
+	}
+	if err != nil {
+		slog.Warn("circuit state unreadable", "err", err)
+		return map[string]State{}, err
+	}
+	return states, nil
+}
+
+// DefaultPath returns the state file inside cacheDir.
+func DefaultPath(cacheDir string) string {
+	return filepath.Join(cacheDir, "circuits.json")
+}
+
+// Ready reports whether name may be called at now: its circuit is
+// closed, or has been open long enough to be probed.
+func (b *Breakers) Ready(name string, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := b.states[name]
+	return !s.Open() || !now.Before(s.NextProbe(b.Cooldown))
+}
+
+// Allow is Ready, also recording a probe of an open circuit so that
+// concurrent callers do not all probe at once.
+func (b *Breakers) Allow(name string, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := b.states[name]
+	if !s.Open() {
+		return true
+	}
+	if now.Before(s.NextProbe(b.Cooldown)) {
+		return false
+	}
+	s.ProbedAt = now
+	b.set(name, s)
+	slog.Info("probing backend with an open circuit", "backend", name)
+	return true
+}
+
+// Success records a successful call to name, closing its circuit.
+func (b *Breakers) Success(name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s, ok := b.states[name]
+	if !ok {
+		return
+	}
+	if s.Open() {
+		slog.Info("circuit closed", "backend", name)
+	}
+	delete(b.states, name)
+	b.save(name)
+}
+
+// Failure records a failed call to name at now, opening its circuit on
+// the Threshold'th consecutive failure.  It reports whether the circuit
+// is open.
+func (b *Breakers) Failure(name string, err error, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := b.states[name]
+	s.Failures++
+	s.LastError = err.Error()
+	s.FailedAt = now
+	if !s.Open() && s.Failures >= b.Threshold {
+		s.OpenedAt = now
+		slog.Warn("circuit opened", "backend", name, "failures", s.Failures, "err", err)
+	}
+	b.set(name, s)
+	return s.Open()
+}
+
+// State returns name's circuit.
+func (b *Breakers) State(name string) State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.states[name]
+}
+
+// Names returns the backends with a recorded failure, sorted.
+func (b *Breakers) Names() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	names := make([]string, 0, len(b.states))
+	for name := range b.states {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Reset closes every circuit.
+func (b *Breakers) Reset() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.states = map[string]State{}
+	err := os.Remove(b.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// set stores s and saves.  The caller holds b.mu.
+func (b *Breakers) set(name string, s State) {
+	b.states[name] = s
+	b.save(name)
+}
+
+// save writes name's circuit atomically, over the file as it is now so
+// that the other circuits keep what concurrent flo processes saved for
+// them; those are taken up in b too.  Failures are only logged, since a
+// lost update costs at most a retry.  The caller holds b.mu.
+func (b *Breakers) save(name string) {
+	states, err := read(b.path)
+	if err != nil {
+		states = map[string]State{}
+	}
+	if s, ok := b.states[name]; ok {
+		states[name] = s
+	} else {
+		delete(states, name)
+	}
+	b.states = states
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err == nil {
+		err = atomicfile.WriteFile(b.path, data, 0o644)
+	}
+	if err != nil {
+		slog.Warn("circuit state not saved", "err", err)
+	}
+}
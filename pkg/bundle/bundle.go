@@ -0,0 +1,103 @@
+// Package bundle reads and writes offline bundles: the top questions of
+// some tags with their best answers, taken from the site on a connected
+// machine (flo bundle create) to load into the local index of one that
+// is not (flo bundle load).
+//
+// A bundle is gzip-compressed JSON holding a header and index documents,
+// so loading needs nothing but the file.
+package bundle
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/atomicfile"
+	"github.com/ratnesh-maurya/flo/pkg/index"
+)
+
+// Version is the bundle format this flo writes, and the newest it
+// reads.
+const Version = 1
+
+// Source marks the index documents that came from a bundle.
+const Source = "bundle"
+
+// Bundle is the content of a bundle file.
+type Bundle struct {
+	// Version is the format, see Version.
+	Version int       `json:"flo_bundle"`
+	Created time.Time `json:"created"`
+	// Site is the API parameter of the site the posts are from.
+	Site string      `json:"site"`
+	Tags []string    `json:"tags"`
+	Docs []index.Doc `json:"docs"`
+}
+
+// ErrNotBundle is returned by Read for a file that is not a bundle.
+var ErrNotBundle = errors.New("not a flo bundle")
+
+// Counts returns how many questions and answers b holds.
+func (b *Bundle) Counts() (questions, answers int) {
+	for _, d := range b.Docs {
+		if d.Kind == index.KindQuestion {
+			questions++
+		} else {
+			answers++
+		}
+	}
+	return questions, answers
+}
+
+// Write writes b to w.
+func (b *Bundle) Write(w io.Writer) error {
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(b); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// WriteFile writes b to path atomically.
+func (b *Bundle) WriteFile(path string) error {
+	var buf bytes.Buffer
+	if err := b.Write(&buf); err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// Read reads a bundle from r.
+func Read(r io.Reader) (*Bundle, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, ErrNotBundle
+	}
+	defer zr.Close()
+	var b Bundle
+	if err := json.NewDecoder(zr).Decode(&b); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotBundle, err)
+	}
+	switch {
+	case b.Version == 0:
+		return nil, ErrNotBundle
+	case b.Version > Version:
+		return nil, fmt.Errorf("bundle format %d is newer than this flo reads (%d); update flo", b.Version, Version)
+	}
+	return &b, nil
+}
+
+// ReadFile reads the bundle at path.
+func ReadFile(path string) (*Bundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
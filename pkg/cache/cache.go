@@ -0,0 +1,266 @@
+// Package cache stores MCP tool responses so repeated lookups are served
+// without another round trip (and without spending rate limit).
+//
+// A Cache always has a local on-disk tier and may add a shared tier that
+// a whole team points at — an HTTP endpoint, an S3 bucket or a Redis
+// server.  Reads go local first, then shared (read-through: shared hits
+// are copied locally); writes land locally at once and are pushed to the
+// shared tier in the background (write-back), so a slow team cache never
+// delays the answer on screen.
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTTL is how long a cached response stays fresh when the config
+// does not say otherwise.
+const DefaultTTL = 24 * time.Hour
+
+// DefaultPostTTL is how long a single question or answer stays fresh
+// when the config does not say otherwise.  Posts change far less often
+// than search rankings, and going back to one should not refetch it.
+const DefaultPostTTL = 7 * 24 * time.Hour
+
+// DefaultSoftTTL is the age past which a cached value is refreshed in
+// the background when the config does not say otherwise.
+const DefaultSoftTTL = time.Hour
+
+// sharedTimeout bounds each request to the shared tier.  It is a
+// best-effort optimization, so a slow server is treated as a miss.
+const sharedTimeout = 3 * time.Second
+
+// ErrMiss is returned by Backend.Get when the key is not stored.
+var ErrMiss = errors.New("cache miss")
+
+// Backend is a key/value store for cache entries.  Keys are opaque hex
+// strings safe to use as file names, URL path segments or Redis keys.
+type Backend interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Put(ctx context.Context, key string, data []byte, ttl time.Duration) error
+}
+
+// entry is the stored envelope.  Freshness is checked on read, so
+// backends without native expiry (files, plain HTTP) still honor the TTL.
+type entry struct {
+	StoredAt time.Time `json:"stored_at"`
+	// TTL overrides the cache's for this entry; zero means the cache's.
+	TTL time.Duration `json:"ttl,omitempty"`
+	// Namespace groups entries for `flo cache`; see the NS constants.
+	Namespace string `json:"ns,omitempty"`
+	Data      []byte `json:"data"`
+}
+
+// Namespaces entries are stored in, so they can be counted and cleared
+// by kind.
+const (
+	NSSearches = "searches"
+	NSPosts    = "posts"
+	NSRelated  = "related"
+	// NSOther holds entries stored without a namespace, by older
+	// versions of flo.
+	NSOther = "other"
+)
+
+// Cache combines the local and optional shared tiers.
+type Cache struct {
+	local  Backend
+	shared Backend
+	ttl    time.Duration
+
+	// PostTTL is how long single questions and answers stay fresh;
+	// zero means the cache's TTL.
+	PostTTL time.Duration
+	// StaleIfError lets an expired local value be served when the
+	// backend fails (see GetExpired and provider.Cached).
+	StaleIfError bool
+	// SoftTTL, when positive, is the age past which a value is still
+	// served but refetched in the background (see provider.Cached).
+	SoftTTL time.Duration
+	// Queries, when set, lets searches be served from the entry of a
+	// similar earlier search (see provider.Cached).
+	Queries *QueryIndex
+	// Site is the Stack Exchange site the responses come from, when it
+	// is not Stack Overflow; it is part of every key, so sites never
+	// share entries.
+	Site string
+
+	pending sync.WaitGroup
+	hits    atomic.Int64 // Get calls answered, for Hits
+	misses  atomic.Int64 // and not, for Misses
+}
+
+// New returns a cache over local and, if non-nil, shared.  A ttl of zero
+// means DefaultTTL.
+func New(local, shared Backend, ttl time.Duration) *Cache {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Cache{local: local, shared: shared, ttl: ttl}
+}
+
+// Key derives a cache key from a tool name and its arguments.
+func Key(tool string, args map[string]any) string {
+	// json.Marshal sorts map keys, so equal arguments hash equally.
+	raw, _ := json.Marshal(args)
+	sum := sha256.Sum256(append([]byte("v1\x00"+tool+"\x00"), raw...))
+	return hex.EncodeToString(sum[:])
+}
+
+// TTL returns how long entries stay fresh.
+func (c *Cache) TTL() time.Duration {
+	return c.ttl
+}
+
+// Hits returns how many Get calls have been answered so far.
+func (c *Cache) Hits() int64 {
+	return c.hits.Load()
+}
+
+// Misses returns how many Get calls have found nothing so far.
+func (c *Cache) Misses() int64 {
+	return c.misses.Load()
+}
+
+// Get returns the fresh cached value for key, if any.  Backend errors
+// are logged and reported as misses.
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, bool) {
+	data, _, ok := c.GetAge(ctx, key)
+	return data, ok
+}
+
+// GetAge is Get, also returning how long ago the value was stored, so
+// the caller can refresh values older than SoftTTL.
+func (c *Cache) GetAge(ctx context.Context, key string) ([]byte, time.Duration, bool) {
+	data, age, ok := c.lookup(ctx, key)
+	if ok {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	return data, age, ok
+}
+
+// lookup is GetAge, uncounted.
+func (c *Cache) lookup(ctx context.Context, key string) ([]byte, time.Duration, bool) {
+	if data, age, ok := c.get(ctx, c.local, key, "local"); ok {
+		return data, age, true
+	}
+	if c.shared == nil {
+		return nil, 0, false
+	}
+	sctx, cancel := context.WithTimeout(ctx, sharedTimeout)
+	defer cancel()
+	raw, err := c.shared.Get(sctx, key)
+	if err != nil {
+		if !errors.Is(err, ErrMiss) {
+			slog.Warn("shared cache read failed", "err", err)
+		}
+		return nil, 0, false
+	}
+	data, age, ok := c.decode(raw)
+	if !ok {
+		return nil, 0, false
+	}
+	slog.Debug("shared cache hit", "key", key)
+	if err := c.local.Put(ctx, key, raw, c.ttl); err != nil {
+		slog.Warn("cache write failed", "err", err)
+	}
+	return data, age, true
+}
+
+// GetExpired returns the local value for key even if it has expired,
+// with its age, for a caller that has nothing better.
+func (c *Cache) GetExpired(ctx context.Context, key string) ([]byte, time.Duration, bool) {
+	raw, err := c.local.Get(ctx, key)
+	if err != nil {
+		return nil, 0, false
+	}
+	var e entry
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return nil, 0, false
+	}
+	return e.Data, time.Since(e.StoredAt), true
+}
+
+func (c *Cache) get(ctx context.Context, b Backend, key, tier string) ([]byte, time.Duration, bool) {
+	raw, err := b.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, ErrMiss) {
+			slog.Warn("cache read failed", "tier", tier, "err", err)
+		}
+		return nil, 0, false
+	}
+	data, age, ok := c.decode(raw)
+	if ok {
+		slog.Debug("cache hit", "tier", tier, "key", key)
+	}
+	return data, age, ok
+}
+
+// decode unwraps an envelope, rejecting stale or corrupt entries, and
+// returns its age.
+func (c *Cache) decode(raw []byte) ([]byte, time.Duration, bool) {
+	var e entry
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return nil, 0, false
+	}
+	ttl := c.ttl
+	if e.TTL > 0 {
+		ttl = e.TTL
+	}
+	age := time.Since(e.StoredAt)
+	if age > ttl {
+		return nil, 0, false
+	}
+	return e.Data, age, true
+}
+
+// Put stores data under key locally and, in the background, in the
+// shared tier.  Call Close before exiting to let pending writes finish.
+func (c *Cache) Put(ctx context.Context, key string, data []byte) {
+	c.PutIn(ctx, "", key, data, 0)
+}
+
+// PutIn is Put into namespace ns, with an entry-specific ttl; zero means
+// the cache's.
+func (c *Cache) PutIn(ctx context.Context, ns, key string, data []byte, ttl time.Duration) {
+	e := entry{StoredAt: time.Now(), Namespace: ns, Data: data}
+	if ttl > 0 && ttl != c.ttl {
+		e.TTL = ttl
+	} else {
+		ttl = c.ttl
+	}
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	if err := c.local.Put(ctx, key, raw, ttl); err != nil {
+		slog.Warn("cache write failed", "err", err)
+	}
+	if c.shared == nil {
+		return
+	}
+	c.pending.Add(1)
+	go func() {
+		defer c.pending.Done()
+		sctx, cancel := context.WithTimeout(context.Background(), sharedTimeout)
+		defer cancel()
+		if err := c.shared.Put(sctx, key, raw, ttl); err != nil {
+			slog.Warn("shared cache write failed", "err", err)
+		}
+	}()
+}
+
+// Close waits for background shared-tier writes to finish.
+func (c *Cache) Close() {
+	c.pending.Wait()
+}
@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileBackend stores each entry as a file in Dir.  Expiry is left to the
+// envelope check in Cache.
+type FileBackend struct {
+	Dir string
+}
+
+// Get reads the entry for key.
+func (f *FileBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(f.Dir, key+".json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrMiss
+	}
+	return data, err
+}
+
+// Put writes the entry for key atomically.
+func (f *FileBackend) Put(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	if err := os.MkdirAll(f.Dir, 0o755); err != nil {
+		return err
+	}
+	path := filepath.Join(f.Dir, key+".json")
+	tmp, err := os.CreateTemp(f.Dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// EntryInfo describes an entry of a FileBackend, for cache management.
+type EntryInfo struct {
+	Key       string
+	Namespace string
+	StoredAt  time.Time
+	// TTL is the entry's own; zero means the cache's.
+	TTL  time.Duration
+	Size int64
+}
+
+// Expired reports whether the entry is stale at now, given the cache's
+// ttl.  Unreadable entries (zero StoredAt) are always expired.
+func (e EntryInfo) Expired(now time.Time, ttl time.Duration) bool {
+	if e.TTL > 0 {
+		ttl = e.TTL
+	}
+	return now.Sub(e.StoredAt) > ttl
+}
+
+// Entries lists the entries stored in f.Dir; a missing directory has
+// none.  Entries that cannot be read are listed in NSOther, with a zero
+// StoredAt.
+func (f *FileBackend) Entries() ([]EntryInfo, error) {
+	files, err := os.ReadDir(f.Dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []EntryInfo
+	for _, file := range files {
+		key, ok := strings.CutSuffix(file.Name(), ".json")
+		if !ok || !file.Type().IsRegular() {
+			continue
+		}
+		info := EntryInfo{Key: key, Namespace: NSOther}
+		if fi, err := file.Info(); err == nil {
+			info.Size = fi.Size()
+		}
+		if data, err := os.ReadFile(filepath.Join(f.Dir, file.Name())); err == nil {
+			var e entry
+			if json.Unmarshal(data, &e) == nil {
+				info.StoredAt, info.TTL = e.StoredAt, e.TTL
+				if e.Namespace != "" {
+					info.Namespace = e.Namespace
+				}
+			}
+		}
+		entries = append(entries, info)
+	}
+	return entries, nil
+}
+
+// Delete removes the entry for key; a missing entry is not an error.
+func (f *FileBackend) Delete(key string) error {
+	err := os.Remove(filepath.Join(f.Dir, key+".json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// RemoveTemp deletes the temporary files of writes interrupted before
+// cutoff, returning how many it removed.
+func (f *FileBackend) RemoveTemp(cutoff time.Time) (int, error) {
+	files, err := os.ReadDir(f.Dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".tmp") {
+			continue
+		}
+		if fi, err := file.Info(); err != nil || fi.ModTime().After(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(f.Dir, file.Name())); err == nil {
+			n++
+		}
+	}
+	return n, nil
+}
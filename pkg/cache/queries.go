@@ -0,0 +1,122 @@
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/atomicfile"
+)
+
+// maxQueries bounds a QueryIndex; the oldest entries are dropped first.
+const maxQueries = 2000
+
+// QueryIndex remembers the terms of cached searches, so a search worded
+// differently but asking the same thing can be served from their entry.
+// It lives beside the local tier and is not shared.
+type QueryIndex struct {
+	path string
+
+	mu      sync.Mutex
+	entries []QueryEntry
+}
+
+// QueryEntry is one indexed search.
+type QueryEntry struct {
+	Terms    []string  `json:"terms"`
+	Key      string    `json:"key"`
+	StoredAt time.Time `json:"stored_at"`
+}
+
+// OpenQueryIndex loads the index at path; a missing or unreadable file
+// yields an empty index, since it only ever saves a round trip.
+func OpenQueryIndex(path string) *QueryIndex {
+	x := &QueryIndex{path: path}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &x.entries)
+	}
+	return x
+}
+
+// Add records that the search with terms is cached under key, replacing
+// an entry with the same terms, and saves the index.
+func (x *QueryIndex) Add(terms []string, key string, now time.Time) error {
+	if len(terms) == 0 {
+		return nil
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.entries = slices.DeleteFunc(x.entries, func(e QueryEntry) bool { return slices.Equal(e.Terms, terms) })
+	x.entries = append(x.entries, QueryEntry{Terms: terms, Key: key, StoredAt: now})
+	if over := len(x.entries) - maxQueries; over > 0 {
+		x.entries = x.entries[over:]
+	}
+	return x.save()
+}
+
+// Nearest returns the key of the entry stored after since whose terms
+// are most similar to terms, if that similarity — the share of terms
+// the two have in common — is at least min.
+func (x *QueryIndex) Nearest(terms []string, min float64, since time.Time) (key string, similarity float64, ok bool) {
+	if len(terms) == 0 {
+		return "", 0, false
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for _, e := range x.entries {
+		if e.StoredAt.Before(since) {
+			continue
+		}
+		if s := jaccard(terms, e.Terms); s >= min && s > similarity {
+			key, similarity, ok = e.Key, s, true
+		}
+	}
+	return key, similarity, ok
+}
+
+// Retain drops the entries whose key keep rejects, such as searches that
+// have been cleared from the cache, and saves the index if it changed.
+// It returns how many entries it dropped.
+func (x *QueryIndex) Retain(keep func(key string) bool) (int, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	n := len(x.entries)
+	x.entries = slices.DeleteFunc(x.entries, func(e QueryEntry) bool { return !keep(e.Key) })
+	if dropped := n - len(x.entries); dropped > 0 {
+		return dropped, x.save()
+	}
+	return 0, nil
+}
+
+// save writes the index atomically.  The caller holds x.mu.
+func (x *QueryIndex) save() error {
+	data, err := json.Marshal(x.entries)
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(x.path, data, 0o644)
+}
+
+// jaccard is |a ∩ b| / |a ∪ b| for sorted, duplicate-free a and b.
+func jaccard(a, b []string) float64 {
+	common := 0
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			common++
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	union := len(a) + len(b) - common
+	if union == 0 {
+		return 0
+	}
+	return float64(common) / float64(union)
+}
@@ -0,0 +1,176 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// redisKeyPrefix namespaces flo's keys in a shared Redis.
+const redisKeyPrefix = "flo:cache:"
+
+// maxRedisValue bounds the values read from Redis; no cache entry comes
+// near it, so a longer reply is a broken or hostile server.
+const maxRedisValue = 16 << 20
+
+// RedisBackend stores entries in Redis with native expiry.  It speaks
+// just enough RESP for AUTH, SELECT, GET and SET, opening a connection
+// per operation — cache traffic is a handful of calls per query.
+type RedisBackend struct {
+	addr     string
+	useTLS   bool
+	tlsConf  *tls.Config
+	username string
+	password string
+	db       int
+}
+
+// newRedisBackend parses redis://[user:password@]host[:port][/db].
+// rediss connections verify the server as hc does, so --ca-file and
+// --insecure-skip-verify apply to them too.
+func newRedisBackend(u *url.URL, hc *http.Client) (*RedisBackend, error) {
+	r := &RedisBackend{addr: u.Host, useTLS: u.Scheme == "rediss", tlsConf: &tls.Config{}}
+	if tr, ok := hc.Transport.(*http.Transport); ok && tr.TLSClientConfig != nil {
+		r.tlsConf = tr.TLSClientConfig
+	}
+	if u.Port() == "" {
+		r.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		r.username = u.User.Username()
+		r.password, _ = u.User.Password()
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		n, err := strconv.Atoi(db)
+		if err != nil {
+			return nil, fmt.Errorf("shared cache url: bad redis database %q", db)
+		}
+		r.db = n
+	}
+	return r, nil
+}
+
+// Get returns the value stored for key.
+func (r *RedisBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	var out []byte
+	err := r.with(ctx, func(c *redisConn) error {
+		v, err := c.do("GET", redisKeyPrefix+key)
+		if err != nil {
+			return err
+		}
+		if v == nil {
+			return ErrMiss
+		}
+		out = v
+		return nil
+	})
+	return out, err
+}
+
+// Put stores data for key, expiring after ttl.
+func (r *RedisBackend) Put(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	return r.with(ctx, func(c *redisConn) error {
+		args := []string{"SET", redisKeyPrefix + key, string(data)}
+		if secs := int(ttl.Seconds()); secs > 0 {
+			args = append(args, "EX", strconv.Itoa(secs))
+		}
+		_, err := c.do(args...)
+		return err
+	})
+}
+
+// with dials, authenticates and selects the database, then runs fn.
+func (r *RedisBackend) with(ctx context.Context, fn func(*redisConn) error) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", r.addr)
+	if err != nil {
+		return err
+	}
+	if r.useTLS {
+		host, _, _ := net.SplitHostPort(r.addr)
+		conf := r.tlsConf.Clone()
+		conf.ServerName = host
+		conn = tls.Client(conn, conf)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	c := &redisConn{conn: conn, r: bufio.NewReader(conn)}
+	if r.password != "" {
+		args := []string{"AUTH", r.password}
+		if r.username != "" {
+			args = []string{"AUTH", r.username, r.password}
+		}
+		if _, err := c.do(args...); err != nil {
+			return err
+		}
+	}
+	if r.db != 0 {
+		if _, err := c.do("SELECT", strconv.Itoa(r.db)); err != nil {
+			return err
+		}
+	}
+	return fn(c)
+}
+
+// redisConn is a single RESP connection.
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// do sends a command and returns its reply: the bulk string for GET
+// (nil for a missing key) or nil for status replies.
+func (c *redisConn) do(args ...string) ([]byte, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(a), a)
+	}
+	if _, err := io.WriteString(c.conn, b.String()); err != nil {
+		return nil, err
+	}
+
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("redis: empty reply")
+	}
+	switch line[0] {
+	case '+', ':':
+		return nil, nil
+	case '-':
+		return nil, fmt.Errorf("redis: %s", line[1:])
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: bad reply %q", line)
+		}
+		if n == -1 {
+			return nil, nil
+		}
+		if n < 0 || n > maxRedisValue {
+			return nil, fmt.Errorf("redis: bad reply length %d", n)
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply %q", line)
+	}
+}
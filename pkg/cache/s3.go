@@ -0,0 +1,202 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Backend stores entries as objects in an S3 bucket (or any
+// S3-compatible store such as MinIO or Cloudflare R2), signing requests
+// with AWS Signature Version 4.  Credentials come from the standard
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN
+// variables.  Expiry is best left to a bucket lifecycle rule; stale
+// objects are ignored on read either way.
+type S3Backend struct {
+	client *http.Client
+	base   *url.URL // bucket URL; objects are base/prefix/key
+	prefix string
+	region string
+	keyID  string
+	secret string
+	token  string
+	now    func() time.Time
+}
+
+// newS3Backend parses s3://bucket/prefix?region=...&endpoint=...  Without
+// an endpoint the AWS virtual-hosted URL is used; with one, path-style
+// addressing (which S3-compatible stores expect).
+func newS3Backend(u *url.URL, hc *http.Client) (*S3Backend, error) {
+	bucket := u.Host
+	if bucket == "" {
+		return nil, fmt.Errorf("shared cache url: s3:// needs a bucket name")
+	}
+	q := u.Query()
+	region := q.Get("region")
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	s := &S3Backend{
+		client: hc,
+		prefix: strings.Trim(u.Path, "/"),
+		region: region,
+		keyID:  os.Getenv("AWS_ACCESS_KEY_ID"),
+		secret: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		token:  os.Getenv("AWS_SESSION_TOKEN"),
+		now:    time.Now,
+	}
+	if s.keyID == "" || s.secret == "" {
+		return nil, fmt.Errorf("shared cache: set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY for s3://")
+	}
+	var err error
+	if ep := q.Get("endpoint"); ep != "" {
+		s.base, err = url.Parse(strings.TrimSuffix(ep, "/") + "/" + bucket)
+	} else {
+		s.base, err = url.Parse(fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucket, region))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("shared cache url: %w", err)
+	}
+	return s, nil
+}
+
+// Get downloads the object for key.
+func (s *S3Backend) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrMiss
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("s3 GET: %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Put uploads the object for key.
+func (s *S3Backend) Put(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	resp, err := s.do(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("s3 PUT: %s", resp.Status)
+	}
+	return nil
+}
+
+// do sends a signed request for the object named key.
+func (s *S3Backend) do(ctx context.Context, method, key string, body []byte) (*http.Response, error) {
+	object := key + ".json"
+	if s.prefix != "" {
+		object = s.prefix + "/" + object
+	}
+	u := *s.base
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + object
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	s.sign(req, body)
+	return s.client.Do(req)
+}
+
+// sign adds SigV4 headers to req.
+func (s *S3Backend) sign(req *http.Request, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.token != "" {
+		req.Header.Set("X-Amz-Security-Token", s.token)
+	}
+
+	var names []string
+	for name := range req.Header {
+		names = append(names, strings.ToLower(name))
+	}
+	sort.Strings(names)
+	var canonHeaders strings.Builder
+	for _, name := range names {
+		canonHeaders.WriteString(name + ":" + strings.TrimSpace(req.Header.Get(name)) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonRequest := strings.Join([]string{
+		req.Method,
+		uriEncodePath(req.URL.Path),
+		"", // no query string
+		canonHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + s.region + "/s3/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonRequest))
+
+	k := hmacSHA256([]byte("AWS4"+s.secret), day)
+	k = hmacSHA256(k, s.region)
+	k = hmacSHA256(k, "s3")
+	k = hmacSHA256(k, "aws4_request")
+	sig := hex.EncodeToString(hmacSHA256(k, toSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.keyID, scope, signedHeaders, sig))
+}
+
+// uriEncodePath encodes each path segment as SigV4 requires: everything
+// but unreserved characters is percent-encoded, "/" is kept.
+func uriEncodePath(p string) string {
+	segs := strings.Split(p, "/")
+	for i, seg := range segs {
+		var b strings.Builder
+		for _, c := range []byte(seg) {
+			if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+				c == '-' || c == '_' || c == '.' || c == '~' {
+				b.WriteByte(c)
+			} else {
+				fmt.Fprintf(&b, "%%%02X", c)
+			}
+		}
+		segs[i] = b.String()
+	}
+	return strings.Join(segs, "/")
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, msg string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(msg))
+	return h.Sum(nil)
+}
@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// OpenShared returns the shared backend described by rawURL:
+//
+//	https://cache.example.com/flo    HTTP GET/PUT of <url>/<key>
+//	s3://bucket/prefix?region=...    S3 or an S3-compatible store
+//	redis://:password@host:6379/0    Redis (rediss:// for TLS)
+//
+// token, if set, is sent as a bearer token to HTTP endpoints.  Redis
+// over TLS takes its settings from hc's transport.
+func OpenShared(rawURL string, hc *http.Client, token string) (Backend, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("shared cache url: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https":
+		return &HTTPBackend{BaseURL: strings.TrimSuffix(rawURL, "/"), Client: hc, Token: token}, nil
+	case "s3":
+		return newS3Backend(u, hc)
+	case "redis", "rediss":
+		return newRedisBackend(u, hc)
+	default:
+		return nil, fmt.Errorf("shared cache url: unsupported scheme %q (want http, https, s3 or redis)", u.Scheme)
+	}
+}
+
+// HTTPBackend talks to any server that answers GET and PUT on
+// <BaseURL>/<key> — a WebDAV share, an nginx/caddy upload location, or a
+// small team service.  404 is a miss.
+type HTTPBackend struct {
+	BaseURL string
+	Client  *http.Client
+	Token   string
+}
+
+// Get fetches the entry for key.
+func (h *HTTPBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.BaseURL+"/"+key, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrMiss
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("shared cache GET: %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Put stores the entry for key.
+func (h *HTTPBackend) Put(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, h.BaseURL+"/"+key, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := h.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("shared cache PUT: %s", resp.Status)
+	}
+	return nil
+}
+
+func (h *HTTPBackend) do(req *http.Request) (*http.Response, error) {
+	if h.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+h.Token)
+	}
+	return h.Client.Do(req)
+}
+
+// ReadOnly returns b with writes dropped, for reading a shared cache
+// without adding to it.
+func ReadOnly(b Backend) Backend {
+	return readOnly{b}
+}
+
+type readOnly struct{ Backend }
+
+func (readOnly) Put(context.Context, string, []byte, time.Duration) error { return nil }
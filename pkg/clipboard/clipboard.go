@@ -0,0 +1,118 @@
+// Package clipboard reads and writes the system clipboard through the
+// platform's command-line tools, so flo needs no cgo or window-system
+// bindings: pbpaste and pbcopy on macOS, PowerShell and clip on Windows
+// (and WSL), and wl-clipboard, xclip or xsel on Linux and the BSDs.
+// Where none of them can reach the user's clipboard, as over SSH, OSC52
+// asks the terminal to set it.
+package clipboard
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrUnavailable is returned when no clipboard tool is installed.
+var ErrUnavailable = errors.New("no clipboard tool found (install wl-clipboard, xclip or xsel)")
+
+// readers lists the commands tried, in order, for the current platform.
+func readers() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbpaste"}}
+	case "windows":
+		return [][]string{{"powershell", "-NoProfile", "-Command", "Get-Clipboard -Raw"}}
+	}
+	var cmds [][]string
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		cmds = append(cmds, []string{"wl-paste", "--no-newline"})
+	}
+	cmds = append(cmds,
+		[]string{"xclip", "-selection", "clipboard", "-o"},
+		[]string{"xsel", "--clipboard", "--output"},
+	)
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		cmds = append(cmds, []string{"powershell.exe", "-NoProfile", "-Command", "Get-Clipboard -Raw"})
+	}
+	return cmds
+}
+
+// Read returns the clipboard's text, using the first tool that is
+// installed.
+func Read(ctx context.Context) (string, error) {
+	for _, argv := range readers() {
+		path, err := exec.LookPath(argv[0])
+		if err != nil {
+			continue
+		}
+		var stdout, stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, path, argv[1:]...)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return "", fmt.Errorf("%s: %s", argv[0], msg)
+			}
+			return "", fmt.Errorf("%s: %w", argv[0], err)
+		}
+		// PowerShell ends lines with \r\n.
+		return strings.ReplaceAll(stdout.String(), "\r\n", "\n"), nil
+	}
+	return "", ErrUnavailable
+}
+
+// writers lists the commands tried, in order, to set the clipboard from
+// their standard input.
+func writers() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip"}}
+	}
+	var cmds [][]string
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		cmds = append(cmds, []string{"wl-copy"})
+	}
+	cmds = append(cmds,
+		[]string{"xclip", "-selection", "clipboard", "-i"},
+		[]string{"xsel", "--clipboard", "--input"},
+	)
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		cmds = append(cmds, []string{"clip.exe"})
+	}
+	return cmds
+}
+
+// Write puts text on the clipboard, using the first tool that is
+// installed.
+func Write(ctx context.Context, text string) error {
+	for _, argv := range writers() {
+		path, err := exec.LookPath(argv[0])
+		if err != nil {
+			continue
+		}
+		// No output pipes: xclip and wl-copy stay behind to serve the
+		// selection, and would hold them open.
+		cmd := exec.CommandContext(ctx, path, argv[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s: %w", argv[0], err)
+		}
+		return nil
+	}
+	return ErrUnavailable
+}
+
+// OSC52 returns the escape sequence asking the terminal to put text on
+// the clipboard of the machine it runs on.  Not every terminal honours
+// it, and tmux only passes it on with set-clipboard enabled.
+func OSC52(text string) string {
+	return "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\x07"
+}
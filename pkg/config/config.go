@@ -0,0 +1,707 @@
+// Package config loads flo's user configuration file.
+//
+// The file lives at <user config dir>/flo/config.yaml (for example
+// ~/.config/flo/config.yaml on Linux) and is optional; every setting
+// has a built-in default.  A project file (.flo.yaml) and FLO_*
+// environment variables override it, and command-line flags override
+// those (see Load).
+//
+//	backend: mcp
+//	mcp:
+//	  url: https://mcp.stackoverflow.com
+//	  command: npx -y mcp-remote {url}
+//	  keepalive: 30s
+//	  remote:
+//	    port: 3334
+//	    headers:
+//	      X-Proxy-Token: ${PROXY_TOKEN}
+//	  env:
+//	    NODE_EXTRA_CA_CERTS: /etc/ssl/corp.pem
+//	timeouts:
+//	  connect: 3m
+//	  search: 1m
+//	  fetch: 30s
+//	api:
+//	  site: stackoverflow
+//	  key: <stack apps key>
+//	embeddings:
+//	  url: http://localhost:11434/api/embeddings
+//	  model: nomic-embed-text
+//	export:
+//	  vault_dir: ~/Documents/Vault/Stack Overflow
+//	  no_attribution: false
+//	sync:
+//	  remote: git@github.com:me/flo-bookmarks.git
+//	gist:
+//	  token: <github token with the gist scope>
+//	  public: false
+//	cache:
+//	  ttl: 24h
+//	  post_ttl: 168h
+//	  shared: https://cache.example.com/flo
+//	display:
+//	  no_question: true
+//	  images: auto
+//	  wide_tables: rotate
+//	  palette: deuteranopia
+//	quality:
+//	  rules:
+//	    - tags: [python]
+//	      pattern: '\bdistutils\b'
+//	      message: uses distutils, removed in Python 3.12
+//	stats:
+//	  disabled: false
+//	digest:
+//	  tags: [go, kubernetes]
+//	  min_score: 10
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the on-disk configuration.
+type Config struct {
+	// Anonymous reads public content through the Stack Exchange API,
+	// on its key-based quota, and never logs in: no MCP login, and the
+	// features acting under the user's accounts — gists, sync, writing
+	// to the shared cache — are off.
+	Anonymous bool `yaml:"anonymous"`
+	// Backend selects where content comes from: BackendMCP (the
+	// default), BackendAPI or BackendLocal.
+	Backend    string           `yaml:"backend"`
+	MCP        MCPConfig        `yaml:"mcp"`
+	API        APIConfig        `yaml:"api"`
+	Timeouts   TimeoutsConfig   `yaml:"timeouts"`
+	Embeddings EmbeddingsConfig `yaml:"embeddings"`
+	Translate  TranslateConfig  `yaml:"translate"`
+	Export     ExportConfig     `yaml:"export"`
+	Sync       SyncConfig       `yaml:"sync"`
+	Gist       GistConfig       `yaml:"gist"`
+	Cache      CacheConfig      `yaml:"cache"`
+	Display    DisplayConfig    `yaml:"display"`
+	Keys       KeysConfig       `yaml:"keys"`
+	Quality    QualityConfig    `yaml:"quality"`
+	Stats      StatsConfig      `yaml:"stats"`
+	Telemetry  TelemetryConfig  `yaml:"telemetry"`
+	Digest     DigestConfig     `yaml:"digest"`
+	Ticker     TickerConfig     `yaml:"ticker"`
+	Failover   FailoverConfig   `yaml:"failover"`
+}
+
+// KeysConfig sets the keys of the interactive views: the pickers and
+// the prompts under a question or answer.
+type KeysConfig struct {
+	// Preset is the keymap to start from: "default", "vim" or "emacs".
+	Preset string `yaml:"preset"`
+	// Bind maps actions ("back", "save", "quit", ...) to keys, over the
+	// preset's: a letter or word typed before Enter, "C-x" for a control
+	// key, "M-x" for an Alt key, or "Enter".
+	Bind map[string]string `yaml:"bind"`
+}
+
+// DisplayConfig controls how results are shown.
+type DisplayConfig struct {
+	// NoQuestion skips the question body and opens the best answer
+	// straight away.
+	NoQuestion bool `yaml:"no_question"`
+	// Images selects how pictures in posts are shown: "auto" (the
+	// default) draws thumbnails when the terminal supports kitty, iTerm2
+	// or sixel graphics, "off" only lists their links, and "kitty",
+	// "iterm" or "sixel" force a protocol.
+	Images string `yaml:"images"`
+	// WideTables selects how tables too wide for the terminal are shown:
+	// "auto" (the default), "truncate" or "rotate" (see package ui).
+	WideTables string `yaml:"wide_tables"`
+	// Palette names the colour palette: "default", "deuteranopia" or
+	// "high-contrast" (see package ui).
+	Palette string `yaml:"palette"`
+	// NoRelated hides the related questions listed under a question,
+	// and the search fetching them when the results hold too few.
+	NoRelated bool `yaml:"no_related"`
+	// NoAuthors leaves the author's reputation and gold tag badge out
+	// of an answer's byline, and the request looking them up.
+	NoAuthors bool `yaml:"no_authors"`
+	// Language is the language of flo's messages, as a code such as
+	// "ja" or a locale such as "pt_BR.UTF-8"; empty follows LC_ALL,
+	// LC_MESSAGES and LANG (see package i18n).
+	Language string `yaml:"language"`
+	// Format selects how rendered output is written: "ansi" (the
+	// default) for the terminal, or "markdown", "html" or "json" for
+	// files and other programs (see ui.Renderer).
+	Format string `yaml:"format"`
+	// QuestionLines is how many lines of a long question are shown
+	// before the rest is folded behind an expand key: 0 means
+	// DefaultQuestionLines, and a negative number never folds.
+	QuestionLines int `yaml:"question_lines"`
+}
+
+// DefaultQuestionLines is how many lines of a long question are shown
+// when display.question_lines is unset.
+const DefaultQuestionLines = 40
+
+// QuestionLineLimit returns the effective display.question_lines, or 0
+// when questions are never folded.
+func (d DisplayConfig) QuestionLineLimit() int {
+	switch {
+	case d.QuestionLines < 0:
+		return 0
+	case d.QuestionLines == 0:
+		return DefaultQuestionLines
+	}
+	return d.QuestionLines
+}
+
+// QualityConfig controls the cautions shown above likely outdated
+// answers (see package quality).
+type QualityConfig struct {
+	// Disabled turns the cautions off.
+	Disabled bool `yaml:"disabled"`
+	// NoBuiltin drops the built-in deprecation rules, keeping Rules.
+	NoBuiltin bool `yaml:"no_builtin"`
+	// Rules are extra deprecation rules.
+	Rules []QualityRule `yaml:"rules"`
+}
+
+// QualityRule flags answers whose body matches Pattern, a regular
+// expression, on questions with any of Tags (all questions when empty).
+type QualityRule struct {
+	Tags    []string `yaml:"tags"`
+	Pattern string   `yaml:"pattern"`
+	Message string   `yaml:"message"`
+}
+
+// StatsConfig controls the local usage log behind `flo stats`.
+type StatsConfig struct {
+	// Disabled stops logging searches and views.
+	Disabled bool `yaml:"disabled"`
+}
+
+// TelemetryConfig controls the anonymous usage counts behind `flo
+// telemetry`, which are off unless the user turns them on.
+type TelemetryConfig struct {
+	// Enabled records the counts; DO_NOT_TRACK=1 overrides it.
+	Enabled bool `yaml:"enabled"`
+}
+
+// DigestConfig holds defaults for `flo digest`.
+type DigestConfig struct {
+	// Tags are the tags the digest covers.
+	Tags []string `yaml:"tags"`
+	// MinScore is the lowest question score listed; zero means 10.
+	MinScore int `yaml:"min_score"`
+	// Limit is the most questions listed per tag; zero means 10.
+	Limit int `yaml:"limit"`
+}
+
+// TickerConfig configures the feed of hot questions in the subscribed
+// tags that flo serve keeps for `flo ticker`.
+type TickerConfig struct {
+	// Enabled has flo serve keep the feed, as --ticker does.
+	Enabled bool `yaml:"enabled"`
+	// Interval is how often the tags are polled; zero means
+	// DefaultTickerInterval.
+	Interval time.Duration `yaml:"interval"`
+	// Window is how recent a question must be to be fed; zero means
+	// DefaultTickerWindow.
+	Window time.Duration `yaml:"window"`
+	// MinScore is the lowest score fed, unless a tag's subscription sets
+	// its own; zero feeds any question.
+	MinScore int `yaml:"min_score"`
+	// Limit is the most questions fed per tag and poll, the best
+	// scoring; zero means 10.
+	Limit int `yaml:"limit"`
+}
+
+// Ticker defaults.
+const (
+	DefaultTickerInterval = 15 * time.Minute
+	DefaultTickerWindow   = 24 * time.Hour
+)
+
+// PollInterval returns the effective poll interval.
+func (t TickerConfig) PollInterval() time.Duration {
+	return orDefault(t.Interval, DefaultTickerInterval)
+}
+
+// NewWindow returns the effective window.
+func (t TickerConfig) NewWindow() time.Duration {
+	return orDefault(t.Window, DefaultTickerWindow)
+}
+
+// CacheConfig controls the response cache.
+type CacheConfig struct {
+	// Disabled turns caching off entirely.
+	Disabled bool `yaml:"disabled"`
+	// TTL is how long responses stay fresh; zero means the default.
+	TTL time.Duration `yaml:"ttl"`
+	// PostTTL is how long single questions and answers stay fresh;
+	// zero means the default (a week).
+	PostTTL time.Duration `yaml:"post_ttl"`
+	// SoftTTL is the age past which a cached response is shown at once
+	// and refetched in the background; zero means the default (an hour).
+	SoftTTL time.Duration `yaml:"soft_ttl"`
+	// NoRefresh turns background refreshes off: responses are used
+	// as cached until they expire.
+	NoRefresh bool `yaml:"no_refresh"`
+	// Shared is an optional team cache: an http(s)://, s3:// or
+	// redis:// URL (see package cache).
+	Shared string `yaml:"shared"`
+	// Token is sent as a bearer token to an http(s) shared cache.
+	Token string `yaml:"token"`
+}
+
+// FailoverConfig controls what flo does when its backend keeps failing
+// (see package breaker).
+type FailoverConfig struct {
+	// Backends are tried, in order, after the configured backend fails
+	// or while its circuit is open: "api" (the Stack Exchange API) and
+	// "cache" (expired cached responses).  Unset means api then cache
+	// for the MCP backend, cache for the API, and none for the local
+	// index, which is meant for machines offline.
+	Backends []string `yaml:"backends"`
+	// Threshold is the number of consecutive failures that opens a
+	// backend's circuit; zero means 3.
+	Threshold int `yaml:"threshold"`
+	// Cooldown is how long an open circuit waits before probing the
+	// backend again; zero means a minute.
+	Cooldown time.Duration `yaml:"cooldown"`
+	// Disabled turns circuits and failover off.
+	Disabled bool `yaml:"disabled"`
+}
+
+// Fallbacks to use in FailoverConfig.Backends.
+const (
+	FallbackAPI   = BackendAPI
+	FallbackCache = "cache"
+)
+
+// FailoverBackends returns the fallbacks for primary, the configured
+// backend, leaving primary itself out.
+func (f FailoverConfig) FailoverBackends(primary string) []string {
+	if f.Disabled {
+		return nil
+	}
+	backends := f.Backends
+	if backends == nil && primary == BackendLocal {
+		return nil
+	}
+	if backends == nil {
+		backends = []string{FallbackAPI, FallbackCache}
+	}
+	var out []string
+	for _, b := range backends {
+		if b != primary {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// SyncConfig configures `flo sync`.
+type SyncConfig struct {
+	// Remote is the git URL (repository or gist) bookmarks sync with.
+	Remote string `yaml:"remote"`
+}
+
+// GistConfig configures publishing answers as GitHub gists.
+type GistConfig struct {
+	// Token is a GitHub token with the gist scope; when empty, GH_TOKEN
+	// or GITHUB_TOKEN is used.
+	Token string `yaml:"token"`
+	// Public publishes public gists instead of secret ones.
+	Public bool `yaml:"public"`
+	// APIURL overrides the GitHub API root, for GitHub Enterprise.
+	APIURL string `yaml:"api_url"`
+}
+
+// ExportConfig holds defaults for `flo export`.
+type ExportConfig struct {
+	// VaultDir is the note directory used by a bare --vault.
+	VaultDir string `yaml:"vault_dir"`
+	// NoAttribution leaves the CC BY-SA attribution (author, link and
+	// license) out of exports, gists and share blocks.
+	NoAttribution bool `yaml:"no_attribution"`
+}
+
+// EmbeddingsConfig points semantic search at a local embedding server.
+// Empty fields fall back to the defaults in package embed (Ollama).
+type EmbeddingsConfig struct {
+	URL   string `yaml:"url"`
+	Model string `yaml:"model"`
+}
+
+// TranslateConfig sets up translating queries into English before they
+// are searched, and answers back into the language of the query.  It is
+// off unless Provider is set.
+type TranslateConfig struct {
+	// Provider is the translation service: "libretranslate" or
+	// "deepl" (see package translate).
+	Provider string `yaml:"provider"`
+	// URL is the service's address; empty means the provider's.
+	URL string `yaml:"url"`
+	// Key is the service's API key, which DeepL requires.
+	Key string `yaml:"key"`
+	// Answers translates the answer shown back, into Language.
+	Answers bool `yaml:"answers"`
+	// Language is the language answers are translated into; empty
+	// means that of the query.
+	Language string `yaml:"language"`
+}
+
+// TimeoutsConfig bounds each network operation separately, so one slow
+// fetch cannot hold up the rest of a session.  Zero fields mean the
+// defaults below.
+type TimeoutsConfig struct {
+	// Connect bounds starting the MCP bridge, including a first-run
+	// OAuth login in the browser.
+	Connect time.Duration `yaml:"connect"`
+	// Search bounds each search.
+	Search time.Duration `yaml:"search"`
+	// Fetch bounds each question or answer lookup.
+	Fetch time.Duration `yaml:"fetch"`
+}
+
+// Default timeouts.
+const (
+	DefaultConnectTimeout = 3 * time.Minute
+	DefaultSearchTimeout  = time.Minute
+	DefaultFetchTimeout   = 30 * time.Second
+)
+
+// ConnectTimeout returns the effective connect timeout.
+func (t TimeoutsConfig) ConnectTimeout() time.Duration {
+	return orDefault(t.Connect, DefaultConnectTimeout)
+}
+
+// SearchTimeout returns the effective search timeout.
+func (t TimeoutsConfig) SearchTimeout() time.Duration {
+	return orDefault(t.Search, DefaultSearchTimeout)
+}
+
+// FetchTimeout returns the effective fetch timeout.
+func (t TimeoutsConfig) FetchTimeout() time.Duration {
+	return orDefault(t.Fetch, DefaultFetchTimeout)
+}
+
+func orDefault(d, def time.Duration) time.Duration {
+	if d <= 0 {
+		return def
+	}
+	return d
+}
+
+// Backends selectable with the backend setting.  BackendLocal serves
+// the local index alone (flo local, flo import, flo bundle).
+const (
+	BackendMCP   = "mcp"
+	BackendAPI   = "api"
+	BackendLocal = "local"
+)
+
+// APIConfig configures the Stack Exchange REST API backend.  Empty
+// fields fall back to the defaults in package provider.
+type APIConfig struct {
+	// URL is the API root, e.g. for a proxy or mirror.
+	URL string `yaml:"url"`
+	// Site is the Stack Exchange site, e.g. "stackoverflow", or "es",
+	// "pt", "ru" or "ja" for Stack Overflow in that language (see
+	// provider.Sites).  The MCP server serves Stack Overflow only, so
+	// any other site is read through the API unless Backend says
+	// otherwise.
+	Site string `yaml:"site"`
+	// Key is an optional Stack Apps key, which raises the daily quota.
+	Key string `yaml:"key"`
+	// DailyBudget is how many requests flo makes a day; zero means the
+	// API's own quota (see package quota).
+	DailyBudget int `yaml:"daily_budget"`
+	// NoAnswerPaging stops the MCP backend from listing the answers
+	// get_content leaves out through the API.
+	NoAnswerPaging bool `yaml:"no_answer_paging"`
+}
+
+// MCPConfig selects the MCP server and the bridge used to reach it.
+// Empty fields fall back to the defaults in package mcp.
+type MCPConfig struct {
+	// URL is the remote MCP endpoint.
+	URL string `yaml:"url"`
+	// Command is the bridge command line.  The placeholder {url} is
+	// replaced by URL; if absent, URL is appended as the last argument.
+	Command string `yaml:"command"`
+	// Keepalive is the idle ping interval in the REPL.  Zero means the
+	// default (30s); a negative value disables keepalive pings.
+	Keepalive time.Duration `yaml:"keepalive"`
+	// Remote passes options to the default mcp-remote bridge.
+	Remote RemoteConfig `yaml:"remote"`
+	// Env sets environment variables for the bridge subprocess, e.g.
+	// NODE_EXTRA_CA_CERTS or a proxy for Node.
+	Env map[string]string `yaml:"env"`
+}
+
+// RemoteConfig holds mcp-remote options.  They are passed only when the
+// bridge command runs mcp-remote.
+type RemoteConfig struct {
+	// Version is the mcp-remote release the default bridge runs and
+	// `flo setup` installs: empty means the one flo is tested with,
+	// latest whatever npm has.
+	Version string `yaml:"version"`
+	// ConfigDir is where mcp-remote keeps its OAuth tokens
+	// ($MCP_REMOTE_CONFIG_DIR); empty means ~/.mcp-auth, or the
+	// profile's own directory.
+	ConfigDir string `yaml:"config_dir"`
+	// Port is the local port of the OAuth callback; zero lets
+	// mcp-remote choose.
+	Port int `yaml:"port"`
+	// Host is the OAuth callback host, e.g. 127.0.0.1 where localhost
+	// does not resolve to it.
+	Host string `yaml:"host"`
+	// Headers are sent with every request to the MCP server, e.g. for
+	// an SSO proxy.  Values may refer to environment variables as
+	// ${NAME}; mcp-remote expands them.
+	Headers map[string]string `yaml:"headers"`
+	// Transport is mcp-remote's transport strategy: http-first (its
+	// default), sse-first, http-only or sse-only.
+	Transport string `yaml:"transport"`
+	// OAuthClientInfo is the JSON of a pre-registered OAuth client, or
+	// @path to a file holding it, for servers that do not allow dynamic
+	// client registration.
+	OAuthClientInfo string `yaml:"oauth_client_info"`
+	// Headless, when set, forces the login for machines without a
+	// browser (true: log in on another device and paste back where the
+	// browser ends up) or the browser login (false).  Unset, flo detects
+	// an SSH session or a missing display.
+	Headless *bool `yaml:"headless"`
+	// AuthTimeout is how long mcp-remote waits for the browser login.
+	AuthTimeout time.Duration `yaml:"auth_timeout"`
+	// Args are further mcp-remote arguments, e.g. --allow-http.
+	Args []string `yaml:"args"`
+}
+
+// Transports mcp-remote accepts.
+var remoteTransports = []string{"http-first", "sse-first", "http-only", "sse-only"}
+
+// BridgeArgs returns the mcp-remote arguments r sets, placed after the
+// server URL.
+func (r RemoteConfig) BridgeArgs() ([]string, error) {
+	var args []string
+	if r.Port != 0 {
+		if r.Port < 0 || r.Port > 65535 {
+			return nil, fmt.Errorf("mcp.remote.port %d out of range", r.Port)
+		}
+		args = append(args, strconv.Itoa(r.Port))
+	}
+	if r.Host != "" {
+		args = append(args, "--host", r.Host)
+	}
+	names := make([]string, 0, len(r.Headers))
+	for name := range r.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		// No space after the colon: npx on Windows splits arguments on
+		// spaces.
+		args = append(args, "--header", name+":"+r.Headers[name])
+	}
+	if r.Transport != "" {
+		if !slices.Contains(remoteTransports, r.Transport) {
+			return nil, fmt.Errorf("mcp.remote.transport %q: want one of %s", r.Transport, strings.Join(remoteTransports, ", "))
+		}
+		args = append(args, "--transport", r.Transport)
+	}
+	if r.OAuthClientInfo != "" {
+		args = append(args, "--static-oauth-client-info", r.OAuthClientInfo)
+	}
+	if r.AuthTimeout > 0 {
+		args = append(args, "--auth-timeout", strconv.Itoa(int(r.AuthTimeout.Seconds())))
+	}
+	return append(args, r.Args...), nil
+}
+
+// DefaultKeepalive is the REPL ping interval when none is configured.
+const DefaultKeepalive = 30 * time.Second
+
+// KeepaliveInterval returns the effective keepalive interval; zero or
+// less means keepalive is disabled.
+func (m MCPConfig) KeepaliveInterval() time.Duration {
+	if m.Keepalive == 0 {
+		return DefaultKeepalive
+	}
+	return m.Keepalive
+}
+
+// Default returns a Config populated with built-in defaults.
+func Default() *Config {
+	return &Config{}
+}
+
+// Profile names the active profile, selected with --profile; empty is
+// the default profile.  Each named profile keeps its own config file,
+// data, cache and MCP login under a "profiles/<name>" subdirectory of
+// the default profile's directories, so work and personal accounts
+// never share state.
+var Profile string
+
+var reProfile = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// ValidateProfile checks that name can be used as a profile name (and
+// so as a directory name).
+func ValidateProfile(name string) error {
+	if !reProfile.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q (use letters, digits, '.', '-' and '_')", name)
+	}
+	return nil
+}
+
+// inProfile returns the active profile's subdirectory of dir.
+func inProfile(dir string) string {
+	if Profile == "" {
+		return dir
+	}
+	return filepath.Join(dir, "profiles", Profile)
+}
+
+// Dir returns flo's configuration directory for the active profile.
+func Dir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return inProfile(filepath.Join(dir, "flo")), nil
+}
+
+// DataDir returns the directory for flo's persistent user data (the
+// local index, and anything else that is not disposable cache) for the
+// active profile.  It honors $XDG_DATA_HOME and falls back to
+// ~/.local/share/flo on Unix and the user config directory elsewhere.
+func DataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return inProfile(filepath.Join(dir, "flo")), nil
+	}
+	if runtime.GOOS != "windows" && runtime.GOOS != "darwin" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return inProfile(filepath.Join(home, ".local", "share", "flo")), nil
+	}
+	return Dir()
+}
+
+// CacheDir returns the directory for flo's disposable cache files for
+// the active profile, inside the user cache directory.
+func CacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return inProfile(filepath.Join(dir, "flo")), nil
+}
+
+// BridgeDir returns where `flo setup` installs mcp-remote.  It is shared
+// by every profile: profiles keep apart only their logins.
+func BridgeDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "flo", "bridge"), nil
+}
+
+// Profiles lists the named profiles that have a config file or data.
+func Profiles() ([]string, error) {
+	saved := Profile
+	Profile = ""
+	defer func() { Profile = saved }()
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, dirFn := range []func() (string, error){Dir, DataDir} {
+		dir, err := dirFn()
+		if err != nil {
+			return nil, err
+		}
+		entries, err := os.ReadDir(filepath.Join(dir, "profiles"))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.IsDir() && !seen[e.Name()] {
+				seen[e.Name()] = true
+				names = append(names, e.Name())
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// DefaultPath returns the default location of the config file.
+func DefaultPath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.yaml"), nil
+}
+
+// ParseError reports a config file that is not valid YAML or does not
+// fit the Config layout.
+type ParseError struct {
+	Path string
+	Err  error
+}
+
+func (e *ParseError) Error() string { return fmt.Sprintf("parse config %s: %v", e.Path, e.Err) }
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// Load builds the configuration from its layers, each overriding the
+// one before: the built-in defaults, the config file at path (or the
+// default path when empty; a missing default file is not an error), the
+// nearest project file (see ProjectFile) and the FLO_* environment
+// variables (see EnvVars).  Command-line flags are applied by the
+// caller, on top.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	explicit := path != ""
+	if !explicit {
+		path, _ = DefaultPath() // no user config directory: skip the file
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case path == "":
+	case err == nil:
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, &ParseError{path, err}
+		}
+	case !errors.Is(err, fs.ErrNotExist) || explicit:
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+
+	if wd, err := os.Getwd(); err == nil {
+		if project := FindProject(wd); project != "" {
+			if err := cfg.applyProject(project); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// EnvVar is a FLO_* environment variable overriding a setting.
+type EnvVar struct {
+	Name string
+	// Setting is the config file key it overrides, for documentation.
+	Setting string
+	set     func(c *Config, v string) error
+}
+
+// EnvVars lists the environment variables Load applies, on top of the
+// config files and below command-line flags.  FLO_CONFIG and
+// FLO_PROFILE, which choose the config file itself, are handled by the
+// caller.
+var EnvVars = []EnvVar{
+	{"FLO_ANONYMOUS", "anonymous", setBool(func(c *Config) *bool { return &c.Anonymous })},
+	{"FLO_BACKEND", "backend", setString(func(c *Config) *string { return &c.Backend })},
+	{"FLO_MCP_URL", "mcp.url", setString(func(c *Config) *string { return &c.MCP.URL })},
+	{"FLO_MCP_CMD", "mcp.command", setString(func(c *Config) *string { return &c.MCP.Command })},
+	{"FLO_API_URL", "api.url", setString(func(c *Config) *string { return &c.API.URL })},
+	{"FLO_SITE", "api.site", setString(func(c *Config) *string { return &c.API.Site })},
+	{"FLO_API_KEY", "api.key", setString(func(c *Config) *string { return &c.API.Key })},
+	{"FLO_TIMEOUT", "timeouts.*", func(c *Config, v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		c.Timeouts.Connect, c.Timeouts.Search, c.Timeouts.Fetch = d, d, d
+		return nil
+	}},
+	{"FLO_CONNECT_TIMEOUT", "timeouts.connect", setDuration(func(c *Config) *time.Duration { return &c.Timeouts.Connect })},
+	{"FLO_THEME", "display.palette", setString(func(c *Config) *string { return &c.Display.Palette })},
+	{"FLO_IMAGES", "display.images", setString(func(c *Config) *string { return &c.Display.Images })},
+	{"FLO_FORMAT", "display.format", setString(func(c *Config) *string { return &c.Display.Format })},
+	{"FLO_WIDE_TABLES", "display.wide_tables", setString(func(c *Config) *string { return &c.Display.WideTables })},
+	{"FLO_NO_QUESTION", "display.no_question", setBool(func(c *Config) *bool { return &c.Display.NoQuestion })},
+	{"FLO_LANG", "display.language", setString(func(c *Config) *string { return &c.Display.Language })},
+	{"FLO_TRANSLATE", "translate.provider", setString(func(c *Config) *string { return &c.Translate.Provider })},
+	{"FLO_TRANSLATE_KEY", "translate.key", setString(func(c *Config) *string { return &c.Translate.Key })},
+	{"FLO_KEYS", "keys.preset", setString(func(c *Config) *string { return &c.Keys.Preset })},
+	{"FLO_NO_CACHE", "cache.disabled", setBool(func(c *Config) *bool { return &c.Cache.Disabled })},
+	{"FLO_CACHE_TTL", "cache.ttl", setDuration(func(c *Config) *time.Duration { return &c.Cache.TTL })},
+	{"FLO_SHARED_CACHE", "cache.shared", setString(func(c *Config) *string { return &c.Cache.Shared })},
+	{"FLO_NO_STATS", "stats.disabled", setBool(func(c *Config) *bool { return &c.Stats.Disabled })},
+	{"FLO_TELEMETRY", "telemetry.enabled", setBool(func(c *Config) *bool { return &c.Telemetry.Enabled })},
+	{"FLO_NO_ATTRIBUTION", "export.no_attribution", setBool(func(c *Config) *bool { return &c.Export.NoAttribution })},
+}
+
+// applyEnv sets the fields named by the FLO_* variables present in the
+// environment.
+func (c *Config) applyEnv() error {
+	for _, e := range EnvVars {
+		v, ok := os.LookupEnv(e.Name)
+		if !ok || v == "" {
+			continue
+		}
+		if err := e.set(c, v); err != nil {
+			return fmt.Errorf("%s=%q: %w", e.Name, v, err)
+		}
+	}
+	return nil
+}
+
+func setString(field func(*Config) *string) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		*field(c) = v
+		return nil
+	}
+}
+
+func setBool(field func(*Config) *bool) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return errors.New("want true or false")
+		}
+		*field(c) = b
+		return nil
+	}
+}
+
+func setDuration(field func(*Config) *time.Duration) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		*field(c) = d
+		return nil
+	}
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectFile is the name of a project config file, looked up in the
+// working directory and its parents.  It holds the same settings as the
+// user config and is applied on top of it, so a repository can pin a
+// site, tags or a backend for everyone working in it.
+const ProjectFile = ".flo.yaml"
+
+// projectDenied are the settings a project file may not set: they run
+// commands, send credentials and queries to a server of the file's
+// choosing, or opt the user in to telemetry, which a cloned repository
+// must not be able to do.
+var projectDenied = []string{
+	"mcp.url", "mcp.command", "mcp.remote", "mcp.env",
+	"api.url", "api.key",
+	"embeddings.url",
+	"translate.provider", "translate.url", "translate.key",
+	"sync.remote",
+	"gist.token", "gist.api_url",
+	"cache.shared", "cache.token",
+	"telemetry.enabled",
+}
+
+// FindProject returns the path of the nearest ProjectFile in dir or
+// its parents, or "" when there is none.
+func FindProject(dir string) string {
+	for {
+		path := filepath.Join(dir, ProjectFile)
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// applyProject reads the project file at path over c.
+func (c *Config) applyProject(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("read project config: %w", err)
+	}
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return &ParseError{path, err}
+	}
+	for _, key := range projectDenied {
+		section, field, _ := strings.Cut(key, ".")
+		if m, ok := doc[section].(map[string]any); ok {
+			if _, set := m[field]; set {
+				return fmt.Errorf("project config %s may not set %s; move it to your user config", path, key)
+			}
+		}
+	}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return &ParseError{path, err}
+	}
+	return nil
+}
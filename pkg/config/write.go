@@ -0,0 +1,116 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ratnesh-maurya/flo/pkg/atomicfile"
+	"gopkg.in/yaml.v3"
+)
+
+// Update sets settings, by dotted name ("display.palette"), in the
+// config file at path, creating it when missing.  A nil value removes
+// the setting.  Everything else in the file, comments included, is kept.
+// A new file is written owner-only, as it may hold keys; an existing one
+// keeps its mode.
+func Update(path string, settings map[string]any) error {
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return &ParseError{path, err}
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("read config: %w", err)
+	case !slices.ContainsFunc(slices.Collect(maps.Values(settings)), func(v any) bool { return v != nil }):
+		return nil // only removals, from a file that isn't there
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return &ParseError{path, errors.New("not a mapping of settings")}
+	}
+
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := setNode(root, strings.Split(name, "."), settings[name]); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	perm := fs.FileMode(0o600)
+	if fi, err := os.Stat(path); err == nil {
+		perm = fi.Mode().Perm()
+	}
+	return atomicfile.WriteFile(path, out.Bytes(), perm)
+}
+
+// setNode sets the setting at keys under the mapping m to value,
+// adding the mappings on the way as needed, or removes it when value is
+// nil.
+func setNode(m *yaml.Node, keys []string, value any) error {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value != keys[0] {
+			continue
+		}
+		if len(keys) == 1 {
+			if value == nil {
+				m.Content = append(m.Content[:i], m.Content[i+2:]...)
+				return nil
+			}
+			// Encoding replaces the node; keep the comment beside it.
+			v := m.Content[i+1]
+			comment := v.LineComment
+			if err := v.Encode(value); err != nil {
+				return err
+			}
+			v.LineComment = comment
+			return nil
+		}
+		child := m.Content[i+1]
+		if child.Kind != yaml.MappingNode {
+			if value == nil {
+				return nil
+			}
+			*child = yaml.Node{Kind: yaml.MappingNode}
+		}
+		return setNode(child, keys[1:], value)
+	}
+	if value == nil {
+		return nil
+	}
+	key := &yaml.Node{Kind: yaml.ScalarNode, Value: keys[0]}
+	child := &yaml.Node{Kind: yaml.MappingNode}
+	if len(keys) == 1 {
+		if err := child.Encode(value); err != nil {
+			return err
+		}
+		m.Content = append(m.Content, key, child)
+		return nil
+	}
+	if err := setNode(child, keys[1:], value); err != nil {
+		return err
+	}
+	m.Content = append(m.Content, key, child)
+	return nil
+}
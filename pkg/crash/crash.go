@@ -0,0 +1,330 @@
+// Package crash writes the diagnostic bundles flo leaves when it
+// crashes, and that `flo report` attaches to bug reports: a text file
+// with the panic and stack trace, the versions involved, the config and
+// the last log records, secrets redacted.
+//
+// Bundles and the crash output of running processes live in Dir.  A
+// panic in the main goroutine is recovered and bundled at once; any
+// other fatal error kills the process, so the runtime writes it to the
+// process's trace file (see TracePath and debug.SetCrashOutput) and the
+// next run bundles it (see Sweep).
+package crash
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Bundle is the content of a diagnostic bundle.
+type Bundle struct {
+	Time time.Time
+	// Reason is the panic or fatal error; empty for a bundle made on
+	// request.
+	Reason string
+	Stack  string
+	// Args is the command line, without the program name; nil when
+	// unknown.
+	Args []string
+	// Versions are name and value pairs: flo's, Go's, the OS's, ...
+	Versions [][2]string
+	// Config is the effective configuration; Encode redacts it.
+	Config any
+	// Log is the last log records, oldest first; nil when unknown.
+	Log []string
+}
+
+// Encode returns b as text, with secrets redacted.
+func (b *Bundle) Encode() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "flo diagnostic bundle, written %s\n", b.Time.UTC().Format(time.RFC3339))
+	buf.WriteString("Keys, tokens and passwords are redacted and your home directory is shown\n" +
+		"as ~, but read it through before sharing: queries and paths are kept.\n")
+
+	section := func(title, body string) {
+		fmt.Fprintf(&buf, "\n== %s ==\n%s\n", title, strings.TrimRight(body, "\n"))
+	}
+	if b.Reason != "" {
+		section("Crash", RedactString(b.Reason))
+	}
+	if b.Stack != "" {
+		section("Stack", RedactString(b.Stack))
+	}
+	if b.Args != nil {
+		section("Command", RedactString(strings.Join(append([]string{"flo"}, b.Args...), " ")))
+	}
+
+	width := 0
+	for _, v := range b.Versions {
+		width = max(width, len(v[0]))
+	}
+	var versions strings.Builder
+	for _, v := range b.Versions {
+		fmt.Fprintf(&versions, "%-*s  %s\n", width, v[0], RedactString(v[1]))
+	}
+	section("Versions", versions.String())
+
+	if b.Config != nil {
+		config, err := RedactConfig(b.Config)
+		if err != nil {
+			config = "(unavailable: " + err.Error() + ")"
+		}
+		section("Config", config)
+	}
+
+	if b.Log != nil {
+		log := "(empty)"
+		if len(b.Log) > 0 {
+			log = RedactString(strings.Join(b.Log, "\n"))
+		}
+		section(fmt.Sprintf("Log, last %d records", len(b.Log)), log)
+	}
+	return buf.Bytes()
+}
+
+// Dir returns the directory bundles and trace files are kept in: flo's
+// own in the user cache directory, where no other user can plant files.
+func Dir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "flo", "crash"), nil
+}
+
+// prepare creates dir readable only by the user, and checks that it is
+// a directory of theirs that nobody else can write to, so that the files
+// written in it are not redirected by links someone else put there.
+func prepare(dir string) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	if !ownedByUser(info) || info.Mode().Perm()&0o022 != 0 {
+		return fmt.Errorf("%s is not a private directory of yours; remove it and try again", dir)
+	}
+	return nil
+}
+
+// CreateTrace creates the trace file of the process pid in dir, failing
+// rather than writing through whatever already has its name.
+func CreateTrace(dir string, pid int) (*os.File, error) {
+	if err := prepare(dir); err != nil {
+		return nil, err
+	}
+	path := TracePath(dir, pid)
+	// Left by an earlier process with the same ID that did not crash:
+	// it would have removed an empty one, and Sweep one with a trace.
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+}
+
+// Save writes b into dir as a new flo-crash-<time>-*.txt file, or
+// flo-report-... for a bundle made on request, readable only by the
+// user, and returns its path.
+func Save(dir string, b *Bundle) (string, error) {
+	if err := prepare(dir); err != nil {
+		return "", err
+	}
+	kind := "crash"
+	if b.Reason == "" {
+		kind = "report"
+	}
+	f, err := os.CreateTemp(dir, "flo-"+kind+"-"+b.Time.Format("20060102-150405")+"-*.txt")
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Write(b.Encode()); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	// Latest goes by the time of the crash, not of the bundling.
+	return f.Name(), os.Chtimes(f.Name(), b.Time, b.Time)
+}
+
+// Latest returns the path of the newest crash bundle in dir and when it
+// was written; "" when there is none.
+func Latest(dir string) (string, time.Time, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "flo-crash-*.txt"))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	var (
+		latest string
+		when   time.Time
+	)
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err == nil && info.ModTime().After(when) {
+			latest, when = p, info.ModTime()
+		}
+	}
+	return latest, when, nil
+}
+
+// TracePath returns the file the process pid has the runtime write its
+// fatal errors to.
+func TracePath(dir string, pid int) string {
+	return filepath.Join(dir, "run-"+strconv.Itoa(pid)+".trace")
+}
+
+// Trace is the crash output a process left behind.
+type Trace struct {
+	Time   time.Time
+	Reason string
+	Stack  string
+}
+
+// staleTrace is the age past which an empty trace file, left by a
+// process that was killed, is removed.
+const staleTrace = 24 * time.Hour
+
+// Sweep returns, and removes, the trace files in dir that crashed
+// processes left, skipping self's own.  A process that is still running
+// has an empty trace file, which is left alone unless stale.
+func Sweep(dir string, self int) ([]Trace, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "run-*.trace"))
+	if err != nil {
+		return nil, err
+	}
+	var traces []Trace
+	for _, p := range paths {
+		if p == TracePath(dir, self) {
+			continue
+		}
+		info, err := os.Stat(p)
+		if err != nil {
+			continue
+		}
+		if info.Size() == 0 {
+			if time.Since(info.ModTime()) > staleTrace {
+				os.Remove(p)
+			}
+			continue
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		traces = append(traces, parseTrace(info.ModTime(), string(data)))
+		if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return traces, err
+		}
+	}
+	sort.Slice(traces, func(i, j int) bool { return traces[i].Time.Before(traces[j].Time) })
+	return traces, nil
+}
+
+// parseTrace splits the runtime's crash output into the error, its
+// first lines, and the goroutine dump after them.
+func parseTrace(t time.Time, out string) Trace {
+	reason, stack, found := strings.Cut(out, "\ngoroutine ")
+	if !found {
+		return Trace{Time: t, Reason: "fatal error", Stack: out}
+	}
+	return Trace{Time: t, Reason: strings.TrimSpace(reason), Stack: "goroutine " + stack}
+}
+
+// secretKey matches the config settings whose values are secret: key,
+// token, headers, env, ... and their prefixed forms, like api_key.
+var secretKey = regexp.MustCompile(`(?i)^(.*_)?(key|token|secret|password|headers|env)$`)
+
+// RedactConfig returns cfg as YAML with secret settings redacted: a
+// non-empty secret becomes "[redacted]", so that whether it is set still
+// shows, and a map of them, like mcp.env, keeps its names only.
+func RedactConfig(cfg any) (string, error) {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+	var tree map[string]any
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return "", err
+	}
+	redactTree(tree, false)
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(tree); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// redactTree redacts the secrets in a decoded YAML value in place;
+// secret is set below a secret setting.
+func redactTree(v any, secret bool) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, x := range v {
+			v[k] = redactTree(x, secret || secretKey.MatchString(k))
+		}
+		return v
+	case []any:
+		for i, x := range v {
+			v[i] = redactTree(x, secret)
+		}
+		return v
+	case string:
+		if secret && v != "" {
+			return "[redacted]"
+		}
+		return RedactString(v)
+	}
+	return v
+}
+
+var (
+	// urlUser matches the user info in a URL.
+	urlUser = regexp.MustCompile(`(://)[^/@\s]+@`)
+	// secretParam matches secret query parameters, as in "?key=...".
+	secretParam = regexp.MustCompile(`(?i)([?&](?:key|access_token|token|api_key|client_secret)=)[^&\s"']+`)
+	// secretAttr matches secret log attributes, as in `api_key=...` or
+	// `"token":"..."`, and Authorization headers.
+	secretAttr = regexp.MustCompile(`(?i)(\b\w*(?:token|secret|password|authorization)\w*"?\s*[=:]\s*)("(?:[^"\\]|\\.)*"|[^\s&"]+)`)
+	// bearer matches a bearer token.
+	bearer = regexp.MustCompile(`(?i)(bearer\s+)[\w\-.~+/=]+`)
+)
+
+// RedactString removes credentials from s: user info and secret
+// parameters in URLs, secret log attributes and bearer tokens.  The
+// user's home directory is shown as ~.
+func RedactString(s string) string {
+	s = urlUser.ReplaceAllString(s, "${1}[redacted]@")
+	s = secretParam.ReplaceAllString(s, "${1}[redacted]")
+	s = secretAttr.ReplaceAllStringFunc(s, func(m string) string {
+		sub := secretAttr.FindStringSubmatch(m)
+		// A flag, like has_token=true, says nothing secret.
+		if sub[2] == "true" || sub[2] == "false" {
+			return m
+		}
+		return sub[1] + "[redacted]"
+	})
+	s = bearer.ReplaceAllString(s, "${1}[redacted]")
+	if home, err := os.UserHomeDir(); err == nil && len(home) > 1 {
+		sep := string(filepath.Separator)
+		s = strings.ReplaceAll(s, home+sep, "~"+sep)
+	}
+	return s
+}
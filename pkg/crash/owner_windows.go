@@ -0,0 +1,10 @@
+//go:build windows
+
+package crash
+
+import "io/fs"
+
+// ownedByUser reports whether the file described by info belongs to the
+// user running flo.  The user cache directory on Windows is in the
+// user's profile, which only they can write to.
+func ownedByUser(info fs.FileInfo) bool { return true }
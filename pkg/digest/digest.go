@@ -0,0 +1,143 @@
+// Package digest compiles the new high-scoring questions in a set of
+// tags into one report, for a weekly catch-up, and remembers when the
+// last one was made so the next covers only what is new since.
+package digest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+)
+
+// Section is the questions found for one tag.
+type Section struct {
+	Tag string
+	// Options describes filters the tag's subscription adds, if any.
+	Options   string
+	Questions []mcp.QuestionData
+	// Err is why the tag could not be fetched, if it could not.
+	Err error
+}
+
+// Digest is a report covering Since to Until.
+type Digest struct {
+	Since, Until time.Time
+	MinScore     int
+	Sections     []Section
+}
+
+// Len returns the number of questions in d.
+func (d *Digest) Len() int {
+	n := 0
+	for _, s := range d.Sections {
+		n += len(s.Questions)
+	}
+	return n
+}
+
+// Markdown renders d as a Markdown document.  A question in several of
+// the tags is listed under the first only.
+func (d *Digest) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Stack Overflow digest: %s – %s\n\n", d.date(d.Since), d.Until.Format("Jan 2, 2006"))
+	fmt.Fprintf(&b, "New questions scoring %d or more in %s.\n\n", d.MinScore, tagList(d.Sections))
+	listed := make(map[int]bool)
+	for _, s := range d.Sections {
+		fmt.Fprintf(&b, "## %s\n\n", s.Tag)
+		if s.Options != "" {
+			fmt.Fprintf(&b, "*%s*\n\n", s.Options)
+		}
+		if s.Err != nil {
+			fmt.Fprintf(&b, "*Could not fetch: %s*\n\n", s.Err)
+			continue
+		}
+		n := 0
+		for _, q := range s.Questions {
+			if listed[q.QuestionID] {
+				continue
+			}
+			listed[q.QuestionID] = true
+			n++
+			status := "unanswered"
+			switch {
+			case q.AcceptedAnswerID > 0:
+				status = "✅ accepted answer"
+			case q.IsAnswered:
+				status = "answered"
+			}
+			fmt.Fprintf(&b, "%d. **[%s](%s)**  \n   Score %d · %d answers · %s · asked %s",
+				n, html.UnescapeString(q.Title), q.Link, q.Score, q.AnswerCount, status,
+				d.date(time.Unix(q.CreationDate, 0)))
+			if len(q.Tags) > 0 {
+				b.WriteString(" · `" + strings.Join(q.Tags, "` `") + "`")
+			}
+			b.WriteString("\n\n")
+		}
+		if n == 0 {
+			b.WriteString("*Nothing new.*\n\n")
+		}
+	}
+	return b.String()
+}
+
+// date formats t, with the year only when it differs from d.Until's.
+func (d *Digest) date(t time.Time) string {
+	if t.Year() != d.Until.Year() {
+		return t.Format("Jan 2, 2006")
+	}
+	return t.Format("Jan 2")
+}
+
+func tagList(sections []Section) string {
+	tags := make([]string, len(sections))
+	for i, s := range sections {
+		tags[i] = "`" + s.Tag + "`"
+	}
+	return strings.Join(tags, ", ")
+}
+
+// State is what is remembered between digests.
+type State struct {
+	LastRun time.Time `json:"last_run"`
+}
+
+// DefaultStatePath returns digest.json inside dataDir.
+func DefaultStatePath(dataDir string) string {
+	return filepath.Join(dataDir, "digest.json")
+}
+
+// LoadState reads the state at path; a missing file is the zero state.
+func LoadState(path string) (State, error) {
+	var st State
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return st, fmt.Errorf("read digest state: %w", err)
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return st, fmt.Errorf("parse digest state %s: %w", path, err)
+	}
+	return st, nil
+}
+
+// Save writes st to path.
+func (st State) Save(path string) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,421 @@
+// Package editor implements the protocol `flo serve --editor` speaks to
+// editor plugins, so a VS Code or Neovim extension can be a thin client
+// that never parses Stack Overflow content itself.
+//
+// Messages are JSON objects, one per line, in both directions.  Each
+// request carries an id that its response echoes; requests run
+// concurrently, so responses may arrive out of order.
+//
+//	→ {"id":1,"method":"search","params":{"query":"reverse a string in go"}}
+//	← {"id":1,"result":{"questions":[{"id":1752414,"title":"...",...}]}}
+//	→ {"id":2,"method":"answer","params":{"question_id":1752414}}
+//	← {"id":2,"result":{"id":10030772,"markdown":"...",...}}
+//	→ {"id":3,"method":"code","params":{"answer_id":10030772}}
+//	← {"id":3,"result":{"blocks":[{"lang":"go","code":"func Reverse..."}]}}
+//	→ {"method":"cancel","params":{"id":1}}
+//
+// Methods:
+//
+//	search    {query, verbatim?}           → {questions: [Question]}
+//	question  {id}                         → Question, with answers
+//	answer    {answer_id} or {question_id} → Answer; for a question,
+//	                                         its accepted or top answer
+//	code      same params as answer        → {blocks: [{lang, code}],
+//	                                         attribution}
+//	cancel    {id}                         → no response; the cancelled
+//	                                         request fails with "canceled"
+//	ping      {}                           → {}
+//
+// Errors are returned as {"id":N,"error":{"code":"...","message":"..."}}
+// with code "invalid_request", "not_found", "canceled" or "failed".
+package editor
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/export"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+	"github.com/ratnesh-maurya/flo/pkg/ui"
+)
+
+// Error codes.
+const (
+	CodeInvalidRequest = "invalid_request"
+	CodeNotFound       = "not_found"
+	CodeCanceled       = "canceled"
+	CodeFailed         = "failed"
+)
+
+// maxLine bounds one request line.
+const maxLine = 1 << 20
+
+// Request is a message from the editor.
+type Request struct {
+	ID     int64           `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// Response answers the Request with the same ID.
+type Response struct {
+	ID     int64  `json:"id"`
+	Result any    `json:"result,omitempty"`
+	Error  *Error `json:"error,omitempty"`
+}
+
+// Error describes a failed request.
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Question is a search result or fetched question.
+type Question struct {
+	ID          int      `json:"id"`
+	Title       string   `json:"title"`
+	Link        string   `json:"link"`
+	Tags        []string `json:"tags"`
+	Score       int      `json:"score"`
+	AnswerCount int      `json:"answer_count"`
+	Answered    bool     `json:"answered"`
+	Markdown    string   `json:"markdown,omitempty"`
+	Answers     []Answer `json:"answers,omitempty"`
+}
+
+// Answer is one answer, as Markdown.
+type Answer struct {
+	ID       int    `json:"id"`
+	Author   string `json:"author"`
+	Score    int    `json:"score"`
+	Accepted bool   `json:"accepted"`
+	Link     string `json:"link"`
+	Markdown string `json:"markdown"`
+	// Attribution is the CC BY-SA credit to paste along with copied
+	// content, unless attribution is turned off.
+	Attribution string `json:"attribution,omitempty"`
+}
+
+// Block is a code sample, as plain text.
+type Block struct {
+	Lang string `json:"lang"`
+	Code string `json:"code"`
+}
+
+// methods are the methods Server answers, besides cancel.
+var methods = []string{"ping", "search", "question", "answer", "code"}
+
+// Server answers editor requests from a provider.
+type Server struct {
+	p provider.Provider
+
+	// OnRequest, when set, is called as each request is answered, with
+	// its method ("unknown" for one the server does not have) and
+	// status: "ok" or the error code.
+	OnRequest func(method, status string)
+}
+
+// New returns a server over p.
+func New(p provider.Provider) *Server {
+	return &Server{p: p}
+}
+
+// Serve handles one session: it reads requests from in and writes
+// responses to out until in ends or ctx is done, then waits for the
+// requests in flight.
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	ctx, cancelAll := context.WithCancel(ctx)
+	defer cancelAll()
+
+	var (
+		writeMu  sync.Mutex
+		enc      = json.NewEncoder(out)
+		inflight sync.WaitGroup
+		mu       sync.Mutex
+		calls    = make(map[int64]*call)
+	)
+	reply := func(r Response) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		if err := enc.Encode(r); err != nil {
+			slog.Warn("editor: write response", "id", r.ID, "err", err)
+		}
+	}
+
+	sc := bufio.NewScanner(in)
+	sc.Buffer(make([]byte, 64*1024), maxLine)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var req Request
+		if err := json.Unmarshal(line, &req); err != nil {
+			reply(Response{Error: &Error{CodeInvalidRequest, "malformed request: " + err.Error()}})
+			continue
+		}
+
+		if req.Method == "cancel" {
+			var p struct {
+				ID int64 `json:"id"`
+			}
+			_ = json.Unmarshal(req.Params, &p)
+			mu.Lock()
+			if c, ok := calls[p.ID]; ok {
+				c.cancel()
+			}
+			mu.Unlock()
+			continue
+		}
+
+		rctx, cancel := context.WithCancel(ctx)
+		c := &call{cancel: cancel}
+		mu.Lock()
+		if old, ok := calls[req.ID]; ok {
+			old.cancel() // a reused ID replaces the request it named
+		}
+		calls[req.ID] = c
+		mu.Unlock()
+
+		inflight.Add(1)
+		go func() {
+			defer inflight.Done()
+			resp := Response{ID: req.ID}
+			result, err := s.handle(rctx, req)
+			if err != nil {
+				resp.Error = toError(rctx, err)
+			} else {
+				resp.Result = result
+			}
+			if s.OnRequest != nil {
+				method, status := req.Method, "ok"
+				if !slices.Contains(methods, method) {
+					method = "unknown"
+				}
+				if resp.Error != nil {
+					status = resp.Error.Code
+				}
+				s.OnRequest(method, status)
+			}
+			mu.Lock()
+			cancel()
+			if calls[req.ID] == c {
+				delete(calls, req.ID)
+			}
+			mu.Unlock()
+			reply(resp)
+		}()
+	}
+	err := sc.Err()
+	if err != nil {
+		cancelAll()
+	}
+	// On EOF the editor closed its end; let the requests in flight finish.
+	inflight.Wait()
+	return err
+}
+
+// call is a request in flight.
+type call struct {
+	cancel context.CancelFunc
+}
+
+// errInvalid marks a bad request.
+type errInvalid struct{ msg string }
+
+func (e errInvalid) Error() string { return e.msg }
+
+// toError classifies err for the editor.
+func toError(ctx context.Context, err error) *Error {
+	var inv errInvalid
+	switch {
+	case errors.As(err, &inv):
+		return &Error{CodeInvalidRequest, inv.msg}
+	case errors.Is(err, provider.ErrNotFound):
+		return &Error{CodeNotFound, "not found"}
+	case errors.Is(ctx.Err(), context.Canceled):
+		return &Error{CodeCanceled, "request canceled"}
+	default:
+		return &Error{CodeFailed, err.Error()}
+	}
+}
+
+// postParams names a question or an answer.
+type postParams struct {
+	ID         int `json:"id"`
+	QuestionID int `json:"question_id"`
+	AnswerID   int `json:"answer_id"`
+}
+
+// handle runs one request.
+func (s *Server) handle(ctx context.Context, req Request) (any, error) {
+	switch req.Method {
+	case "ping":
+		return struct{}{}, nil
+	case "search":
+		var p struct {
+			Query    string `json:"query"`
+			Verbatim bool   `json:"verbatim"`
+		}
+		if err := decode(req.Params, &p); err != nil {
+			return nil, err
+		}
+		return s.search(ctx, p.Query, p.Verbatim)
+	case "question":
+		var p postParams
+		if err := decode(req.Params, &p); err != nil {
+			return nil, err
+		}
+		if p.ID == 0 {
+			p.ID = p.QuestionID
+		}
+		if p.ID == 0 {
+			return nil, errInvalid{"question needs an id"}
+		}
+		q, err := s.p.GetQuestion(ctx, p.ID)
+		if err != nil {
+			return nil, err
+		}
+		out := toQuestion(q)
+		out.Markdown = html.UnescapeString(q.BodyMarkdown)
+		for _, a := range mcp.SortAnswers(q.Answers) {
+			out.Answers = append(out.Answers, toAnswer(&a))
+		}
+		return out, nil
+	case "answer", "code":
+		var p postParams
+		if err := decode(req.Params, &p); err != nil {
+			return nil, err
+		}
+		a, tags, err := s.answer(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		if req.Method == "answer" {
+			return toAnswer(a), nil
+		}
+		blocks := []Block{}
+		for _, b := range mcp.CodeBlocks(a.BodyMarkdown) {
+			if b.Lang == "" {
+				b.Lang = ui.CodeLanguage(b.Code, tags)
+			}
+			blocks = append(blocks, Block{Lang: b.Lang, Code: b.Code})
+		}
+		return map[string]any{"blocks": blocks, "attribution": attribution(a)}, nil
+	default:
+		return nil, errInvalid{fmt.Sprintf("unknown method %q", req.Method)}
+	}
+}
+
+// search runs a search and lists the questions found.
+func (s *Server) search(ctx context.Context, query string, verbatim bool) (any, error) {
+	if !verbatim {
+		query = mcp.NormalizeQuery(query)
+	}
+	if query == "" {
+		return nil, errInvalid{"search needs a query"}
+	}
+	resp, err := s.p.Search(ctx, query)
+	if errors.Is(err, provider.ErrNotFound) {
+		return map[string]any{"questions": []Question{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	qs := make([]Question, len(resp.Items))
+	for i := range resp.Items {
+		qs[i] = toQuestion(&resp.Items[i])
+	}
+	return map[string]any{"questions": qs}, nil
+}
+
+// answer fetches the answer p names, or the best answer of the question
+// it names, with the question's tags when known.
+func (s *Server) answer(ctx context.Context, p postParams) (*mcp.AnswerData, []string, error) {
+	if p.AnswerID == 0 && p.ID != 0 {
+		p.AnswerID = p.ID
+	}
+	if p.AnswerID != 0 {
+		a, err := s.p.GetAnswer(ctx, p.AnswerID)
+		return a, nil, err
+	}
+	if p.QuestionID == 0 {
+		return nil, nil, errInvalid{"need an answer_id or question_id"}
+	}
+	q, err := s.p.GetQuestion(ctx, p.QuestionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(q.Answers) == 0 {
+		if q.AcceptedAnswerID == 0 {
+			return nil, nil, provider.ErrNotFound
+		}
+		a, err := s.p.GetAnswer(ctx, q.AcceptedAnswerID)
+		return a, q.Tags, err
+	}
+	best := mcp.SortAnswers(q.Answers)[0]
+	return &best, q.Tags, nil
+}
+
+func decode(raw json.RawMessage, v any) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return errInvalid{"bad params: " + err.Error()}
+	}
+	return nil
+}
+
+func toQuestion(q *mcp.QuestionData) Question {
+	return Question{
+		ID:          q.QuestionID,
+		Title:       html.UnescapeString(q.Title),
+		Link:        q.Link,
+		Tags:        q.Tags,
+		Score:       q.Score,
+		AnswerCount: q.AnswerCount,
+		Answered:    q.IsAnswered,
+	}
+}
+
+func toAnswer(a *mcp.AnswerData) Answer {
+	return Answer{
+		ID:          a.AnswerID,
+		Author:      html.UnescapeString(a.Owner.DisplayName),
+		Score:       a.Score,
+		Accepted:    a.IsAccepted,
+		Link:        a.Link,
+		Markdown:    html.UnescapeString(a.BodyMarkdown),
+		Attribution: attribution(a),
+	}
+}
+
+// attribution is a's credit line, or "" when attribution is off.
+func attribution(a *mcp.AnswerData) string {
+	if !export.Attribute {
+		return ""
+	}
+	c := export.Credit{
+		Author:    html.UnescapeString(a.Owner.DisplayName),
+		AuthorURL: a.Owner.Link,
+		URL:       a.Link,
+	}
+	if a.AnswerID != 0 {
+		c.URL = fmt.Sprintf("https://stackoverflow.com/a/%d", a.AnswerID)
+	}
+	if a.CreationDate > 0 {
+		c.Posted = time.Unix(a.CreationDate, 0)
+	}
+	return c.Text()
+}
@@ -0,0 +1,119 @@
+// Package embed turns text into embedding vectors using a locally hosted
+// model, for flo's optional semantic search.
+//
+// flo does not bundle a model.  Instead it talks to a local embedding
+// server over HTTP; both the Ollama API (POST /api/embeddings) and the
+// OpenAI-compatible API (POST /v1/embeddings, as served by llama.cpp,
+// LM Studio, LocalAI, ...) are understood.
+package embed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+)
+
+// DefaultURL and DefaultModel target a stock Ollama install.
+const (
+	DefaultURL   = "http://localhost:11434/api/embeddings"
+	DefaultModel = "nomic-embed-text"
+)
+
+// Embedder produces an embedding vector for a piece of text.
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+	// Model identifies the model, so stored vectors can be invalidated
+	// when it changes.
+	Model() string
+}
+
+// HTTPEmbedder calls a local embedding server.
+type HTTPEmbedder struct {
+	URL    string
+	Name   string
+	Client *http.Client
+}
+
+// NewHTTPEmbedder returns an embedder for url/model, applying defaults
+// for empty values.
+func NewHTTPEmbedder(url, model string, hc *http.Client) *HTTPEmbedder {
+	if url == "" {
+		url = DefaultURL
+	}
+	if model == "" {
+		model = DefaultModel
+	}
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	return &HTTPEmbedder{URL: url, Name: model, Client: hc}
+}
+
+// Model implements Embedder.
+func (e *HTTPEmbedder) Model() string { return e.Name }
+
+// Embed implements Embedder.
+func (e *HTTPEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	// "prompt" is Ollama's field, "input" the OpenAI one; each server
+	// ignores the other.
+	body, err := json.Marshal(map[string]any{
+		"model":  e.Name,
+		"prompt": text,
+		"input":  text,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("embedding server %s: %w", e.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("embedding server %s: %s", e.URL, resp.Status)
+	}
+
+	var out struct {
+		Embedding []float32 `json:"embedding"` // Ollama
+		Data      []struct {
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"` // OpenAI-compatible
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decode embedding: %w", err)
+	}
+	if len(out.Embedding) > 0 {
+		return out.Embedding, nil
+	}
+	if len(out.Data) > 0 && len(out.Data[0].Embedding) > 0 {
+		return out.Data[0].Embedding, nil
+	}
+	return nil, fmt.Errorf("embedding server returned no vector")
+}
+
+// Cosine returns the cosine similarity of a and b (0 if either is empty
+// or their lengths differ).
+func Cosine(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
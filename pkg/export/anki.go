@@ -0,0 +1,99 @@
+// Package export turns saved bookmarks into files for other tools, and
+// into share blocks to paste elsewhere (share.go).
+//
+// anki.go writes flashcards in Anki's plain-text import format: one
+// tab-separated note per line (fields may be quoted to embed newlines)
+// with "#" header lines telling Anki the separator, that fields contain
+// HTML, and which column holds tags.  File > Import in Anki 2.1.54+
+// picks all of this up automatically.
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/ratnesh-maurya/flo/pkg/bookmarks"
+	"github.com/yuin/goldmark"
+)
+
+// WriteAnki writes one question-front/answer-back note per bookmark.
+func WriteAnki(w io.Writer, items []*bookmarks.Bookmark) error {
+	header := "#separator:tab\n#html:true\n#notetype:Basic\n#tags column:3\n"
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Comma = '\t'
+	for _, b := range items {
+		front, err := ankiFront(b)
+		if err != nil {
+			return err
+		}
+		back, err := ankiBack(b)
+		if err != nil {
+			return err
+		}
+		if err := cw.Write([]string{front, back, ankiTags(slices.Concat(b.Tags, b.LocalTags))}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ankiFront is the question side: title plus the question body.
+func ankiFront(b *bookmarks.Bookmark) (string, error) {
+	body, err := MarkdownToHTML(b.QuestionBody)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("<h3>%s</h3>%s", html.EscapeString(b.Title), body), nil
+}
+
+// ankiBack is the answer side: answer body, author and source link, or
+// the full attribution when Attribute is set.
+func ankiBack(b *bookmarks.Bookmark) (string, error) {
+	body, err := MarkdownToHTML(b.AnswerBody)
+	if err != nil {
+		return "", err
+	}
+	if Attribute {
+		return body + "<p><small>" + CreditOf(b).HTML() + "</small></p>", nil
+	}
+	var meta []string
+	if b.AnswerAuthor != "" {
+		meta = append(meta, "by "+html.EscapeString(b.AnswerAuthor))
+	}
+	if b.Link != "" {
+		meta = append(meta, fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(b.Link), html.EscapeString(b.Link)))
+	}
+	if len(meta) > 0 {
+		body += "<p><small>" + strings.Join(meta, " — ") + "</small></p>"
+	}
+	return body, nil
+}
+
+// ankiTags converts SO tags to Anki tags (space-separated, no spaces
+// inside a tag).
+func ankiTags(tags []string) string {
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		out = append(out, "so::"+strings.ReplaceAll(t, " ", "_"))
+	}
+	return strings.Join(out, " ")
+}
+
+// MarkdownToHTML renders Markdown to an HTML fragment.
+func MarkdownToHTML(md string) (string, error) {
+	var buf bytes.Buffer
+	if err := goldmark.Convert([]byte(html.UnescapeString(md)), &buf); err != nil {
+		return "", fmt.Errorf("render markdown: %w", err)
+	}
+	return buf.String(), nil
+}
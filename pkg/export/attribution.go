@@ -0,0 +1,153 @@
+package export
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/bookmarks"
+)
+
+// Attribute controls whether exports, gists and share blocks carry the
+// attribution CC BY-SA requires when reusing Stack Overflow content.  It
+// is on unless the user turns it off (export.no_attribution).
+var Attribute = true
+
+// License change-over dates: contributions are licensed under the
+// version in force when they were posted.
+var (
+	ccBySA3 = time.Date(2011, time.April, 8, 0, 0, 0, 0, time.UTC)
+	ccBySA4 = time.Date(2018, time.May, 2, 0, 0, 0, 0, time.UTC)
+)
+
+// License returns the name and deed URL of the license a post made at
+// posted is under.  An unknown date gets the current license.
+func License(posted time.Time) (name, url string) {
+	switch {
+	case !posted.IsZero() && posted.Before(ccBySA3):
+		return "CC BY-SA 2.5", "https://creativecommons.org/licenses/by-sa/2.5/"
+	case !posted.IsZero() && posted.Before(ccBySA4):
+		return "CC BY-SA 3.0", "https://creativecommons.org/licenses/by-sa/3.0/"
+	default:
+		return "CC BY-SA 4.0", "https://creativecommons.org/licenses/by-sa/4.0/"
+	}
+}
+
+// Credit is what attribution names: the author, the post and its
+// license.
+type Credit struct {
+	Author    string
+	AuthorURL string
+	URL       string
+	Posted    time.Time
+}
+
+// CreditOf is the credit for a bookmark's answer.  The question's date
+// stands in for the answer's in bookmarks saved without one.
+func CreditOf(b *bookmarks.Bookmark) Credit {
+	c := Credit{
+		Author:    b.AnswerAuthor,
+		AuthorURL: b.AnswerAuthorLink,
+		URL:       AnswerURL(b),
+		Posted:    b.AnswerDate,
+	}
+	if c.Posted.IsZero() {
+		c.Posted = b.CreationDate
+	}
+	return c
+}
+
+// AnswerURL is the link to a bookmark's answer, or to its question.
+func AnswerURL(b *bookmarks.Bookmark) string {
+	if b.AnswerID != 0 {
+		return fmt.Sprintf("https://stackoverflow.com/a/%d", b.AnswerID)
+	}
+	return b.Link
+}
+
+func (c Credit) author() string {
+	if c.Author == "" {
+		return "Anonymous"
+	}
+	return c.Author
+}
+
+// Text is the credit as one plain-text line.
+func (c Credit) Text() string {
+	name, url := License(c.Posted)
+	parts := []string{"Answer by " + c.author()}
+	if c.AuthorURL != "" {
+		parts[0] += " (" + c.AuthorURL + ")"
+	}
+	if c.URL != "" {
+		parts = append(parts, c.URL)
+	}
+	parts = append(parts, fmt.Sprintf("licensed under %s (%s)", name, url))
+	return strings.Join(parts, ", ")
+}
+
+// Markdown is the credit as one line of Markdown.
+func (c Credit) Markdown() string {
+	name, url := License(c.Posted)
+	author := c.author()
+	if c.AuthorURL != "" {
+		author = fmt.Sprintf("[%s](%s)", author, c.AuthorURL)
+	}
+	s := "Answer by " + author
+	if c.URL != "" {
+		s += fmt.Sprintf(" on [Stack Overflow](%s)", c.URL)
+	}
+	return s + fmt.Sprintf(", licensed under [%s](%s)", name, url)
+}
+
+// HTML is the credit as an HTML fragment.
+func (c Credit) HTML() string {
+	name, url := License(c.Posted)
+	author := html.EscapeString(c.author())
+	if c.AuthorURL != "" {
+		author = fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(c.AuthorURL), author)
+	}
+	s := "Answer by " + author
+	if c.URL != "" {
+		s += fmt.Sprintf(` on <a href="%s">Stack Overflow</a>`, html.EscapeString(c.URL))
+	}
+	return s + fmt.Sprintf(`, licensed under <a href="%s">%s</a>`, url, name)
+}
+
+// comments are how a line comment is opened, and closed, in the
+// languages of code fences and lexers, lower-cased.
+var comments = map[string][2]string{
+	"go": {"// ", ""}, "golang": {"// ", ""}, "c": {"// ", ""}, "cpp": {"// ", ""}, "c++": {"// ", ""},
+	"csharp": {"// ", ""}, "c#": {"// ", ""}, "java": {"// ", ""}, "kotlin": {"// ", ""},
+	"scala": {"// ", ""}, "swift": {"// ", ""}, "rust": {"// ", ""}, "dart": {"// ", ""},
+	"javascript": {"// ", ""}, "js": {"// ", ""}, "node": {"// ", ""}, "typescript": {"// ", ""},
+	"ts": {"// ", ""}, "jsx": {"// ", ""}, "tsx": {"// ", ""}, "php": {"// ", ""},
+	"python": {"# ", ""}, "py": {"# ", ""}, "python3": {"# ", ""}, "ruby": {"# ", ""},
+	"rb": {"# ", ""}, "perl": {"# ", ""}, "r": {"# ", ""}, "bash": {"# ", ""}, "sh": {"# ", ""},
+	"shell": {"# ", ""}, "zsh": {"# ", ""}, "powershell": {"# ", ""}, "yaml": {"# ", ""},
+	"yml": {"# ", ""}, "toml": {"# ", ""}, "dockerfile": {"# ", ""}, "makefile": {"# ", ""},
+	"make": {"# ", ""}, "nginx": {"# ", ""}, "ini": {"; ", ""},
+	"sql": {"-- ", ""}, "haskell": {"-- ", ""}, "lua": {"-- ", ""},
+	"lisp": {";; ", ""}, "clojure": {";; ", ""}, "elisp": {";; ", ""},
+	"matlab": {"% ", ""}, "latex": {"% ", ""}, "tex": {"% ", ""}, "erlang": {"% ", ""},
+	"vim": {`" `, ""},
+	"css": {"/* ", " */"}, "scss": {"// ", ""}, "less": {"// ", ""},
+	"html": {"<!-- ", " -->"}, "xml": {"<!-- ", " -->"}, "markdown": {"<!-- ", " -->"},
+	"md": {"<!-- ", " -->"},
+}
+
+// Code returns code with the credit added after it as a comment in
+// lang, ending in a newline, for code copied or saved on its own.  ok is
+// false, and code returned as it is, for a language flo does not know
+// the comments of, or that has none, such as JSON.
+func (c Credit) Code(code, lang string) (annotated string, ok bool) {
+	com, ok := comments[strings.ToLower(strings.TrimSpace(lang))]
+	if !ok {
+		return code, false
+	}
+	if !strings.HasSuffix(code, "\n") {
+		code += "\n"
+	}
+	return code + "\n" + com[0] + c.Text() + com[1] + "\n", true
+}
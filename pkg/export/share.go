@@ -0,0 +1,160 @@
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ratnesh-maurya/flo/pkg/bookmarks"
+)
+
+// ShareStyle selects the markup of a share block.
+type ShareStyle int
+
+const (
+	// SharePlain is plain text, for anywhere.
+	SharePlain ShareStyle = iota
+	// ShareSlack is Slack's mrkdwn.
+	ShareSlack
+	// ShareMarkdown is GitHub-flavoured Markdown, for PRs and issues.
+	ShareMarkdown
+)
+
+// excerptChars is roughly how much of the answer a share block quotes;
+// whole paragraphs and code blocks are kept, so it may run over.
+const excerptChars = 500
+
+var (
+	reMDLink   = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)[^)]*\)`)
+	reMDBold   = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	reFenceTag = regexp.MustCompile("^(\\s*)(```+|~~~+)\\S*\\s*$")
+)
+
+// Share formats b as a compact block to paste into chat or a pull
+// request: the question's title and link, an excerpt of the answer, its
+// author and, when Attribute is set, the license notice Stack Overflow
+// content requires.
+func Share(b *bookmarks.Bookmark, style ShareStyle) string {
+	var s strings.Builder
+	excerpt := Excerpt(b.AnswerBody, excerptChars)
+	answerURL := AnswerURL(b)
+	license, licenseURL := License(CreditOf(b).Posted)
+	author := b.AnswerAuthor
+	if author == "" {
+		author = "Anonymous"
+	}
+	meta := fmt.Sprintf("score %d", b.AnswerScore)
+	if b.Accepted {
+		meta = "accepted, " + meta
+	}
+
+	switch style {
+	case ShareSlack:
+		fmt.Fprintf(&s, "*<%s|%s>*\n", b.Link, slackEscape(b.Title))
+		if excerpt != "" {
+			s.WriteString(quote(slackMarkup(excerpt)) + "\n")
+		}
+		author = slackEscape(author)
+		if b.AnswerAuthorLink != "" {
+			author = fmt.Sprintf("<%s|%s>", b.AnswerAuthorLink, author)
+		}
+		fmt.Fprintf(&s, "— answer by %s (%s) · <%s|link>\n", author, meta, answerURL)
+		if Attribute {
+			fmt.Fprintf(&s, "_Stack Overflow content, licensed under <%s|%s>_\n", licenseURL, license)
+		}
+	case ShareMarkdown:
+		fmt.Fprintf(&s, "**[%s](%s)**\n\n", b.Title, b.Link)
+		if excerpt != "" {
+			s.WriteString(quote(excerpt) + "\n\n")
+		}
+		if b.AnswerAuthorLink != "" {
+			author = fmt.Sprintf("[%s](%s)", author, b.AnswerAuthorLink)
+		}
+		fmt.Fprintf(&s, "— [answer](%s) by %s (%s)\n", answerURL, author, meta)
+		if Attribute {
+			fmt.Fprintf(&s, "\n<sub>Stack Overflow content, licensed under [%s](%s)</sub>\n", license, licenseURL)
+		}
+	default:
+		fmt.Fprintf(&s, "%s\n%s\n", b.Title, b.Link)
+		if excerpt != "" {
+			s.WriteString("\n" + excerpt + "\n\n")
+		}
+		fmt.Fprintf(&s, "— answer by %s (%s): %s\n", author, meta, answerURL)
+		if Attribute {
+			fmt.Fprintf(&s, "Stack Overflow content, licensed under %s (%s)\n", license, licenseURL)
+		}
+	}
+	return s.String()
+}
+
+// Excerpt returns the leading paragraphs and code blocks of md, about
+// max characters of them but at least one.  Code blocks are never cut.
+func Excerpt(md string, max int) string {
+	var (
+		parts   []string
+		cur     []string
+		inFence bool
+		n       int
+	)
+	flush := func() bool {
+		if len(cur) == 0 {
+			return true
+		}
+		p := strings.Join(cur, "\n")
+		cur = nil
+		if len(parts) > 0 && n+len(p) > max {
+			return false
+		}
+		parts = append(parts, p)
+		n += len(p)
+		return n < max
+	}
+	for _, line := range strings.Split(strings.TrimSpace(md), "\n") {
+		if reFenceTag.MatchString(line) {
+			inFence = !inFence
+		}
+		if !inFence && strings.TrimSpace(line) == "" {
+			if !flush() {
+				return strings.Join(parts, "\n\n")
+			}
+			continue
+		}
+		cur = append(cur, line)
+	}
+	flush()
+	return strings.Join(parts, "\n\n")
+}
+
+// quote prefixes every line of s with "> ".
+func quote(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight("> "+l, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// slackMarkup converts the Markdown Slack does not understand: links,
+// bold, and fence languages (which Slack would show as code).
+func slackMarkup(md string) string {
+	lines := strings.Split(md, "\n")
+	inFence := false
+	for i, l := range lines {
+		if m := reFenceTag.FindStringSubmatch(l); m != nil {
+			inFence = !inFence
+			lines[i] = m[1] + "```"
+			continue
+		}
+		if inFence {
+			continue
+		}
+		l = reMDLink.ReplaceAllString(l, "<$2|$1>")
+		lines[i] = reMDBold.ReplaceAllString(l, "*$1*")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// slackEscape escapes the characters Slack treats as markup.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,243 @@
+// site.go writes bookmarks as a small static website — an index grouped
+// by tag and one page per question and answer — that can be served
+// from any web server or opened from disk as a team FAQ.
+
+package export
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ratnesh-maurya/flo/pkg/bookmarks"
+)
+
+// siteIndex is the name of the index page.
+const siteIndex = "index.html"
+
+// sitePage is one bookmark's page.
+type sitePage struct {
+	Title     string
+	File      string
+	Link      string
+	Tags      []string
+	LocalTags []string
+	Note      template.HTML
+	Question  template.HTML
+	Answer    template.HTML
+	Author    string
+	Accepted  bool
+	Score     int
+	Saved     string
+	Credit    template.HTML
+}
+
+// siteTag is one section of the index.
+type siteTag struct {
+	Name  string
+	Pages []*sitePage
+}
+
+// WriteSite writes an index.html listing the bookmarks by tag, and one
+// HTML page per bookmark named like its vault note, into dir.  Pages are
+// self-contained (inline CSS, relative links).  It returns the paths
+// written.
+func WriteSite(dir, title string, items []*bookmarks.Bookmark) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create site directory: %w", err)
+	}
+	var (
+		written []string
+		pages   []*sitePage
+	)
+	for _, b := range items {
+		p, err := newSitePage(b)
+		if err != nil {
+			return written, err
+		}
+		path := filepath.Join(dir, p.File)
+		if err := writeTemplate(path, pageTemplate, p); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+		pages = append(pages, p)
+	}
+
+	index := struct {
+		Title   string
+		Count   int
+		Tags    []siteTag
+		Updated string
+	}{title, len(pages), groupByTag(pages), time.Now().Format(time.DateOnly)}
+	path := filepath.Join(dir, siteIndex)
+	if err := writeTemplate(path, indexTemplate, index); err != nil {
+		return written, err
+	}
+	return append(written, path), nil
+}
+
+// newSitePage renders b's Markdown for its page.
+func newSitePage(b *bookmarks.Bookmark) (*sitePage, error) {
+	p := &sitePage{
+		Title:     b.Title,
+		File:      strings.TrimSuffix(NoteFileName(b), ".md") + ".html",
+		Link:      b.Link,
+		Tags:      b.Tags,
+		LocalTags: b.LocalTags,
+		Author:    b.AnswerAuthor,
+		Accepted:  b.Accepted,
+		Score:     b.AnswerScore,
+		Saved:     b.SavedAt.Format(time.DateOnly),
+	}
+	for _, part := range []struct {
+		md  string
+		out *template.HTML
+	}{{b.Note, &p.Note}, {b.QuestionBody, &p.Question}, {b.AnswerBody, &p.Answer}} {
+		if part.md == "" {
+			continue
+		}
+		// goldmark drops raw HTML, so the output is safe to embed.
+		h, err := MarkdownToHTML(part.md)
+		if err != nil {
+			return nil, err
+		}
+		*part.out = template.HTML(h)
+	}
+	if Attribute {
+		p.Credit = template.HTML(CreditOf(b).HTML())
+	}
+	return p, nil
+}
+
+// groupByTag sections pages by their Stack Overflow and local tags,
+// largest section first; untagged pages come last, under "other".
+func groupByTag(pages []*sitePage) []siteTag {
+	byTag := make(map[string][]*sitePage)
+	var untagged []*sitePage
+	for _, p := range pages {
+		tags := append(append([]string(nil), p.Tags...), p.LocalTags...)
+		if len(tags) == 0 {
+			untagged = append(untagged, p)
+		}
+		seen := make(map[string]bool)
+		for _, t := range tags {
+			if !seen[t] {
+				seen[t] = true
+				byTag[t] = append(byTag[t], p)
+			}
+		}
+	}
+	sections := make([]siteTag, 0, len(byTag)+1)
+	for t, ps := range byTag {
+		sections = append(sections, siteTag{t, ps})
+	}
+	sort.Slice(sections, func(i, j int) bool {
+		if len(sections[i].Pages) != len(sections[j].Pages) {
+			return len(sections[i].Pages) > len(sections[j].Pages)
+		}
+		return sections[i].Name < sections[j].Name
+	})
+	if len(untagged) > 0 {
+		sections = append(sections, siteTag{"other", untagged})
+	}
+	return sections
+}
+
+// writeTemplate executes t with data into the file at path.
+func writeTemplate(path string, t *template.Template, data any) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	if err := t.Execute(f, data); err != nil {
+		f.Close()
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// tagAnchor is the index's fragment ID for tag, spelling out the
+// characters tags like "c#" and "c++" use that IDs and URLs treat
+// specially.
+func tagAnchor(tag string) string {
+	var b strings.Builder
+	b.WriteString("tag-")
+	for _, r := range tag {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '.' {
+			b.WriteRune(r)
+		} else {
+			fmt.Fprintf(&b, "_%x", r)
+		}
+	}
+	return b.String()
+}
+
+var siteFuncs = template.FuncMap{"anchor": tagAnchor}
+
+// siteStyle is shared by every page.
+const siteStyle = `<style>
+body{font:16px/1.5 system-ui,sans-serif;max-width:52rem;margin:2rem auto;padding:0 1rem;color:#222}
+a{color:#0a66c2}
+h1{font-size:1.6rem}
+h2{font-size:1.2rem;margin-top:2rem;border-bottom:1px solid #ddd}
+pre{background:#f6f8fa;padding:.75rem;overflow-x:auto;border-radius:4px}
+code{font-size:.9em}
+blockquote{margin:0;padding-left:1rem;border-left:3px solid #ddd;color:#555}
+.tag{display:inline-block;background:#e1ecf4;color:#39739d;border-radius:3px;padding:0 .4rem;margin-right:.25rem;font-size:.85em;text-decoration:none}
+.local{background:#fdf2d0;color:#7a5c00}
+.meta,.credit{color:#666;font-size:.9em}
+.note{background:#fffbe6;padding:.5rem 1rem;border-radius:4px}
+nav a{margin-right:.5rem}
+</style>`
+
+var indexTemplate = template.Must(template.New("index").Funcs(siteFuncs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+` + siteStyle + `
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">{{.Count}} saved answers, updated {{.Updated}}.</p>
+<nav>{{range .Tags}}<a class="tag" href="#{{anchor .Name}}">{{.Name}} ({{len .Pages}})</a>{{end}}</nav>
+{{range .Tags}}
+<h2 id="{{anchor .Name}}">{{.Name}}</h2>
+<ul>
+{{- range .Pages}}
+<li><a href="{{.File}}">{{.Title}}</a>{{if .Accepted}} ✅{{end}}</li>
+{{- end}}
+</ul>
+{{end}}
+</body>
+</html>
+`))
+
+var pageTemplate = template.Must(template.New("page").Funcs(siteFuncs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+` + siteStyle + `
+</head>
+<body>
+<p><a href="index.html">← All answers</a></p>
+<h1>{{.Title}}</h1>
+<p>{{range .Tags}}<a class="tag" href="index.html#{{anchor .}}">{{.}}</a>{{end}}{{range .LocalTags}}<a class="tag local" href="index.html#{{anchor .}}">{{.}}</a>{{end}}</p>
+{{if .Note}}<div class="note">{{.Note}}</div>{{end}}
+{{if .Question}}<h2>Question</h2>
+{{.Question}}{{end}}
+<h2>Answer{{if .Author}} by {{.Author}}{{end}}{{if .Accepted}} ✅{{end}}</h2>
+<p class="meta">Score {{.Score}} · saved {{.Saved}}{{if .Link}} · <a href="{{.Link}}">view on Stack Overflow</a>{{end}}</p>
+{{.Answer}}
+{{if .Credit}}<p class="credit">{{.Credit}}</p>{{end}}
+</body>
+</html>
+`))
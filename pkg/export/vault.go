@@ -0,0 +1,142 @@
+// vault.go writes bookmarks as individual Markdown notes with YAML
+// frontmatter, the format Obsidian, Logseq, Notion's Markdown import and
+// most other knowledge-base tools understand.
+
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ratnesh-maurya/flo/pkg/bookmarks"
+	"gopkg.in/yaml.v3"
+)
+
+// noteFrontmatter is the YAML header of each note.
+type noteFrontmatter struct {
+	Title    string   `yaml:"title"`
+	URL      string   `yaml:"url"`
+	Tags     []string `yaml:"tags,omitempty"`
+	Date     string   `yaml:"date"`
+	Asked    string   `yaml:"asked,omitempty"`
+	Score    int      `yaml:"score"`
+	Answer   string   `yaml:"answer_author,omitempty"`
+	Accepted bool     `yaml:"accepted"`
+	Source   string   `yaml:"source"`
+	License  string   `yaml:"license,omitempty"`
+}
+
+// WriteVault writes one note per bookmark into dir, creating it if
+// needed.  Notes are named after the question title and answer ID, so
+// re-exporting updates notes in place instead of duplicating them.
+// It returns the paths written.
+func WriteVault(dir string, items []*bookmarks.Bookmark) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create vault directory: %w", err)
+	}
+	var written []string
+	for _, b := range items {
+		path := filepath.Join(dir, NoteFileName(b))
+		note, err := Note(b)
+		if err != nil {
+			return written, err
+		}
+		if err := os.WriteFile(path, []byte(note), 0o644); err != nil {
+			return written, fmt.Errorf("write %s: %w", path, err)
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// Note renders a bookmark as a Markdown note with YAML frontmatter.
+func Note(b *bookmarks.Bookmark) (string, error) {
+	fm := noteFrontmatter{
+		Title:    b.Title,
+		URL:      b.Link,
+		Tags:     slices.Concat(b.Tags, b.LocalTags),
+		Date:     b.SavedAt.Format(time.DateOnly),
+		Score:    b.AnswerScore,
+		Answer:   b.AnswerAuthor,
+		Accepted: b.Accepted,
+		Source:   "Stack Overflow",
+	}
+	if !b.CreationDate.IsZero() {
+		fm.Asked = b.CreationDate.Format(time.DateOnly)
+	}
+	credit := CreditOf(b)
+	if Attribute {
+		fm.License, _ = License(credit.Posted)
+	}
+	header, err := yaml.Marshal(fm)
+	if err != nil {
+		return "", err
+	}
+
+	var s strings.Builder
+	s.WriteString("---\n")
+	s.Write(header)
+	s.WriteString("---\n\n")
+	s.WriteString(fmt.Sprintf("# %s\n\n", b.Title))
+	if b.Note != "" {
+		s.WriteString("## My notes\n\n")
+		s.WriteString(b.Note + "\n\n")
+	}
+	if b.QuestionBody != "" {
+		s.WriteString("## Question\n\n")
+		s.WriteString(strings.TrimSpace(b.QuestionBody) + "\n\n")
+	}
+	label := "## Answer"
+	if b.AnswerAuthor != "" {
+		label += " by " + b.AnswerAuthor
+	}
+	if b.Accepted {
+		label += " ✅"
+	}
+	s.WriteString(label + "\n\n")
+	s.WriteString(strings.TrimSpace(b.AnswerBody) + "\n\n")
+	if b.Link != "" {
+		s.WriteString(fmt.Sprintf("Source: <%s>\n", b.Link))
+	}
+	if Attribute {
+		s.WriteString("\n" + credit.Markdown() + "\n")
+	}
+	return s.String(), nil
+}
+
+// NoteFileName returns a filesystem-safe note name for a bookmark.
+func NoteFileName(b *bookmarks.Bookmark) string {
+	slug := slugify(b.Title, 80)
+	if slug == "" {
+		slug = fmt.Sprintf("question-%d", b.QuestionID)
+	}
+	if b.AnswerID != 0 {
+		return fmt.Sprintf("%s-%d.md", slug, b.AnswerID)
+	}
+	return slug + ".md"
+}
+
+// slugify lowercases s and keeps letters/digits, joining words with "-".
+func slugify(s string, max int) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	out := strings.Trim(b.String(), "-")
+	if r := []rune(out); len(r) > max {
+		out = strings.Trim(string(r[:max]), "-")
+	}
+	return out
+}
@@ -0,0 +1,162 @@
+// Package flo is the embeddable core of the flo command: it searches
+// Stack Overflow through the official MCP server (or any
+// provider.Provider), picks the most useful question and answer, and
+// formats them as Markdown.  It has no terminal or command-line
+// dependencies.
+//
+//	c, err := flo.New(ctx, flo.Options{})
+//	if err != nil { ... }
+//	defer c.Close()
+//	q, err := c.Best(ctx, "reverse a string in go", flo.SearchOptions{})
+//	if err != nil { ... }
+//	fmt.Println(q.BestAnswer().Body)
+//
+// The first connection may open a browser for Stack Overflow's OAuth
+// login (handled by the mcp-remote bridge); later ones reuse its token.
+package flo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ratnesh-maurya/flo/pkg/cache"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/provider"
+)
+
+// ErrNotFound is returned when a search or lookup finds nothing.
+var ErrNotFound = errors.New("flo: not found")
+
+// Options configures a Client.
+type Options struct {
+	// MCP selects the server and bridge; the zero value uses the
+	// official server through npx mcp-remote.
+	MCP mcp.Options
+	// Provider, if set, is used instead of starting an MCP bridge —
+	// for example provider.NewREST for the public Stack Exchange API,
+	// or a fake in tests.  MCP is then ignored.
+	Provider provider.Provider
+	// Cache, if set, serves repeated lookups without a round trip.
+	Cache *cache.Cache
+}
+
+// SearchOptions adjusts a search.
+type SearchOptions struct {
+	// Verbatim sends the query as given instead of normalizing it
+	// (see mcp.DefaultQueryPipeline).
+	Verbatim bool
+	// Tags are preferred when choosing the best question, e.g. "go".
+	Tags []string
+}
+
+// Client is a connection to a Stack Overflow backend, by default the
+// MCP server.  It is safe for sequential use; a stale connection is
+// re-established before each call.
+type Client struct {
+	p   provider.Provider
+	mcp *mcp.Client // nil when Options.Provider was given
+}
+
+// New starts the MCP bridge and completes the handshake, unless
+// opts.Provider is set.
+func New(ctx context.Context, opts Options) (*Client, error) {
+	if opts.Provider != nil {
+		return &Client{p: provider.Cached(opts.Provider, opts.Cache)}, nil
+	}
+	mc, err := mcp.NewClient(ctx, opts.MCP)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{p: provider.Cached(provider.NewMCP(mc), opts.Cache), mcp: mc}, nil
+}
+
+// Close shuts the bridge down.
+func (c *Client) Close() error {
+	if c.mcp == nil {
+		return nil
+	}
+	return c.mcp.Close()
+}
+
+// Search returns the questions matching query, in the server's order.
+// It returns ErrNotFound when there are none.
+func (c *Client) Search(ctx context.Context, query string, opts SearchOptions) ([]Question, error) {
+	resp, err := c.search(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Question, len(resp.Items))
+	for i := range resp.Items {
+		out[i] = questionFromMCP(&resp.Items[i])
+	}
+	return out, nil
+}
+
+// Best searches and returns the single most useful question — one with
+// answers included if possible, else the highest scored, preferring
+// opts.Tags — with its accepted answer loaded when the search did not
+// include it.
+func (c *Client) Best(ctx context.Context, query string, opts SearchOptions) (*Question, error) {
+	resp, err := c.search(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	best := mcp.BestQuestionWithAnswers(resp, opts.Tags)
+	if best == nil {
+		best = mcp.BestQuestion(resp, opts.Tags)
+	}
+	q := questionFromMCP(best)
+	if len(q.Answers) == 0 && q.AcceptedAnswerID > 0 {
+		if a, err := c.Answer(ctx, q.AcceptedAnswerID); err == nil {
+			a.IsAccepted = true
+			q.Answers = append(q.Answers, *a)
+		}
+	}
+	return &q, nil
+}
+
+// Question fetches a question by ID, with the answers the backend
+// includes.
+func (c *Client) Question(ctx context.Context, id int) (*Question, error) {
+	raw, err := c.p.GetQuestion(ctx, id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	q := questionFromMCP(raw)
+	return &q, nil
+}
+
+// Answer fetches an answer by ID.
+func (c *Client) Answer(ctx context.Context, id int) (*Answer, error) {
+	raw, err := c.p.GetAnswer(ctx, id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	a := answerFromMCP(raw)
+	return &a, nil
+}
+
+// search normalizes query unless opts.Verbatim and runs it.
+func (c *Client) search(ctx context.Context, query string, opts SearchOptions) (*mcp.SOResponse, error) {
+	if !opts.Verbatim {
+		query = mcp.NormalizeQuery(query)
+	}
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("flo: empty query")
+	}
+	resp, err := c.p.Search(ctx, query)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return resp, nil
+}
+
+// notFound maps the provider's not-found error to ErrNotFound.
+func notFound(err error) error {
+	if errors.Is(err, provider.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
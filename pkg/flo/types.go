@@ -0,0 +1,156 @@
+package flo
+
+import (
+	"html"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+)
+
+// Question is a Stack Overflow question with whatever answers have been
+// loaded for it.  Text fields are decoded (no HTML entities); bodies are
+// Markdown.
+type Question struct {
+	ID               int
+	Title            string
+	Body             string
+	Link             string
+	Tags             []string
+	Author           string
+	Score            int
+	Views            int
+	AnswerCount      int // total on the site; Answers may hold fewer
+	IsAnswered       bool
+	AcceptedAnswerID int
+	Created          time.Time
+	LastActivity     time.Time
+	Answers          []Answer
+}
+
+// Answer is one answer to a question.
+type Answer struct {
+	ID           int
+	Body         string
+	Link         string
+	Author       string
+	Score        int
+	IsAccepted   bool
+	Created      time.Time
+	LastActivity time.Time
+}
+
+// BestAnswer returns the accepted answer, or else the highest-scored
+// one; nil when no answers are loaded.
+func (q *Question) BestAnswer() *Answer {
+	var best *Answer
+	for i := range q.Answers {
+		a := &q.Answers[i]
+		switch {
+		case best == nil,
+			a.IsAccepted && !best.IsAccepted,
+			a.IsAccepted == best.IsAccepted && a.Score > best.Score:
+			best = a
+		}
+	}
+	return best
+}
+
+// Markdown renders the question and up to maxAnswers of its answers
+// (all of them when maxAnswers <= 0) as a Markdown document.
+func (q *Question) Markdown(maxAnswers int) string {
+	return mcp.FormatQuestionMarkdown(q.toMCP(), maxAnswers)
+}
+
+// Markdown renders the answer as a Markdown document.
+func (a *Answer) Markdown() string {
+	raw := a.toMCP()
+	return mcp.FormatSingleAnswer(&raw)
+}
+
+// questionFromMCP converts the wire format into a Question.
+func questionFromMCP(d *mcp.QuestionData) Question {
+	q := Question{
+		ID:               d.QuestionID,
+		Title:            html.UnescapeString(d.Title),
+		Body:             html.UnescapeString(d.BodyMarkdown),
+		Link:             d.Link,
+		Tags:             d.Tags,
+		Author:           html.UnescapeString(d.Owner.DisplayName),
+		Score:            d.Score,
+		Views:            d.ViewCount,
+		AnswerCount:      d.AnswerCount,
+		IsAnswered:       d.IsAnswered,
+		AcceptedAnswerID: d.AcceptedAnswerID,
+		Created:          unixTime(d.CreationDate),
+		LastActivity:     unixTime(d.LastActivityDate),
+	}
+	for i := range d.Answers {
+		q.Answers = append(q.Answers, answerFromMCP(&d.Answers[i]))
+	}
+	return q
+}
+
+// answerFromMCP converts the wire format into an Answer.
+func answerFromMCP(d *mcp.AnswerData) Answer {
+	return Answer{
+		ID:           d.AnswerID,
+		Body:         html.UnescapeString(d.BodyMarkdown),
+		Link:         d.Link,
+		Author:       html.UnescapeString(d.Owner.DisplayName),
+		Score:        d.Score,
+		IsAccepted:   d.IsAccepted,
+		Created:      unixTime(d.CreationDate),
+		LastActivity: unixTime(d.LastActivityDate),
+	}
+}
+
+// toMCP converts back to the wire format, for the formatters in package
+// mcp.  Decoded text stays decoded; the formatters tolerate that.
+func (q *Question) toMCP() *mcp.QuestionData {
+	d := &mcp.QuestionData{
+		QuestionID:       q.ID,
+		Title:            q.Title,
+		BodyMarkdown:     q.Body,
+		Link:             q.Link,
+		Tags:             q.Tags,
+		Owner:            mcp.OwnerData{DisplayName: q.Author},
+		Score:            q.Score,
+		ViewCount:        q.Views,
+		AnswerCount:      q.AnswerCount,
+		IsAnswered:       q.IsAnswered,
+		AcceptedAnswerID: q.AcceptedAnswerID,
+		CreationDate:     unixSeconds(q.Created),
+		LastActivityDate: unixSeconds(q.LastActivity),
+	}
+	for i := range q.Answers {
+		d.Answers = append(d.Answers, q.Answers[i].toMCP())
+	}
+	return d
+}
+
+func (a *Answer) toMCP() mcp.AnswerData {
+	return mcp.AnswerData{
+		AnswerID:         a.ID,
+		BodyMarkdown:     a.Body,
+		Link:             a.Link,
+		Owner:            mcp.OwnerData{DisplayName: a.Author},
+		Score:            a.Score,
+		IsAccepted:       a.IsAccepted,
+		CreationDate:     unixSeconds(a.Created),
+		LastActivityDate: unixSeconds(a.LastActivity),
+	}
+}
+
+func unixTime(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+func unixSeconds(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
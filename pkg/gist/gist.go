@@ -0,0 +1,93 @@
+// Package gist publishes snippets as GitHub gists, so an answer can be
+// shared with teammates as a link.
+package gist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DefaultAPIURL is the GitHub REST API root.
+const DefaultAPIURL = "https://api.github.com"
+
+// ErrNoToken is returned when no GitHub token is configured.
+var ErrNoToken = errors.New("no GitHub token")
+
+// Gist is a gist to create.
+type Gist struct {
+	Description string
+	Public      bool
+	// Files maps file names to their contents.
+	Files map[string]string
+}
+
+// Client creates gists with a GitHub token that has the gist scope.
+type Client struct {
+	APIURL string
+	Token  string
+	HTTP   *http.Client
+}
+
+// New returns a client for token, with the default API URL.
+func New(hc *http.Client, token string) *Client {
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	return &Client{APIURL: DefaultAPIURL, Token: token, HTTP: hc}
+}
+
+// Create publishes g and returns the gist's web URL.
+func (c *Client) Create(ctx context.Context, g Gist) (string, error) {
+	if c.Token == "" {
+		return "", ErrNoToken
+	}
+	type file struct {
+		Content string `json:"content"`
+	}
+	files := make(map[string]file, len(g.Files))
+	for name, content := range g.Files {
+		files[name] = file{content}
+	}
+	body, err := json.Marshal(map[string]any{
+		"description": g.Description,
+		"public":      g.Public,
+		"files":       files,
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		strings.TrimRight(c.APIURL, "/")+"/gists", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("create gist: %w", err)
+	}
+	defer resp.Body.Close()
+	var out struct {
+		HTMLURL string `json:"html_url"`
+		Message string `json:"message"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	if resp.StatusCode != http.StatusCreated {
+		if out.Message != "" {
+			return "", fmt.Errorf("create gist: %s: %s", resp.Status, out.Message)
+		}
+		return "", fmt.Errorf("create gist: %s", resp.Status)
+	}
+	if out.HTMLURL == "" {
+		return "", errors.New("create gist: no URL in response")
+	}
+	return out.HTMLURL, nil
+}
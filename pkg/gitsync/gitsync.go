@@ -0,0 +1,212 @@
+// Package gitsync keeps small data files in step with a git remote by
+// driving the git CLI, so any remote git understands works: a private
+// repository over SSH or HTTPS, or a GitHub gist's clone URL (gists are
+// git repositories that only allow top-level files).
+//
+// The working copy is private to flo.  Each sync starts from the
+// remote's latest commit, lets the caller merge the remote files with
+// local state, and pushes the result; a push that loses a race with
+// another machine is retried from the new remote head.  Credentials come
+// from the user's usual git setup (SSH agent, credential helper).
+package gitsync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// defaultBranch is used when the remote is still empty.
+const defaultBranch = "main"
+
+// maxAttempts bounds the fetch/merge/push retries after a rejected push.
+const maxAttempts = 3
+
+// Repo is a local working copy tracking a single remote.
+type Repo struct {
+	// Dir is the working copy directory; it is created on first use.
+	Dir string
+	// Remote is the git URL to sync with.
+	Remote string
+}
+
+// MergeFunc receives the remote's copy of each synced file (nil when a
+// file does not exist remotely yet) and returns the contents to push.
+type MergeFunc func(remote map[string][]byte) (map[string][]byte, error)
+
+// Result summarizes a sync.
+type Result struct {
+	// Pushed reports whether a new commit was pushed.
+	Pushed bool
+	// Branch is the remote branch synced with.
+	Branch string
+}
+
+// Sync fetches the remote, calls merge with the remote copies of files,
+// commits what merge returns and pushes it.  merge may be called more
+// than once if another machine pushes concurrently; it must therefore
+// not commit local side effects until Sync returns successfully.
+func (r *Repo) Sync(ctx context.Context, files []string, message string, merge MergeFunc) (*Result, error) {
+	if err := r.prepare(ctx); err != nil {
+		return nil, err
+	}
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		res, err := r.syncOnce(ctx, files, message, merge)
+		if err == nil {
+			return res, nil
+		}
+		if !errors.Is(err, errRejected) {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// errRejected marks a push refused because the remote moved on.
+var errRejected = errors.New("push rejected: remote has newer commits")
+
+func (r *Repo) syncOnce(ctx context.Context, files []string, message string, merge MergeFunc) (*Result, error) {
+	branch, err := r.remoteBranch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	empty := branch == ""
+	if empty {
+		branch = defaultBranch
+	} else {
+		if _, err := r.git(ctx, "fetch", "-q", "origin", branch); err != nil {
+			return nil, err
+		}
+		// Start from exactly the remote state; the merge below is where
+		// local changes come back in.
+		if _, err := r.git(ctx, "checkout", "-q", "-f", "-B", branch, "origin/"+branch); err != nil {
+			return nil, err
+		}
+	}
+
+	remote := make(map[string][]byte, len(files))
+	for _, name := range files {
+		data, err := os.ReadFile(filepath.Join(r.Dir, name))
+		switch {
+		case err == nil && !empty:
+			remote[name] = data
+		case err == nil, errors.Is(err, fs.ErrNotExist):
+			remote[name] = nil
+		default:
+			return nil, err
+		}
+	}
+
+	out, err := merge(remote)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range files {
+		data, ok := out[name]
+		if !ok {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(r.Dir, name), data, 0o644); err != nil {
+			return nil, err
+		}
+		if _, err := r.git(ctx, "add", "--", name); err != nil {
+			return nil, err
+		}
+	}
+
+	res := &Result{Branch: branch}
+	if empty {
+		if _, err := r.git(ctx, "symbolic-ref", "HEAD", "refs/heads/"+branch); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := r.git(ctx, "diff", "--cached", "--quiet"); err == nil && !empty {
+		return res, nil
+	}
+	if _, err := r.git(ctx, "commit", "-q", "--allow-empty", "-m", message); err != nil {
+		return nil, err
+	}
+	if out, err := r.git(ctx, "push", "-q", "origin", "HEAD:refs/heads/"+branch); err != nil {
+		if strings.Contains(out, "rejected") || strings.Contains(out, "fetch first") {
+			return nil, errRejected
+		}
+		return nil, err
+	}
+	res.Pushed = true
+	return res, nil
+}
+
+// prepare creates the working copy and points it at r.Remote.
+func (r *Repo) prepare(ctx context.Context) error {
+	if err := os.MkdirAll(r.Dir, 0o755); err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(r.Dir, ".git")); errors.Is(err, fs.ErrNotExist) {
+		if _, err := r.git(ctx, "init", "-q"); err != nil {
+			return err
+		}
+		_, err := r.git(ctx, "remote", "add", "origin", r.Remote)
+		return err
+	}
+	_, err := r.git(ctx, "remote", "set-url", "origin", r.Remote)
+	return err
+}
+
+// remoteBranch returns the remote's default branch, or "" when the
+// remote has no commits yet.  A bare repository whose HEAD names a
+// branch that was never pushed reports no HEAD, so defaultBranch is
+// checked explicitly too.
+func (r *Repo) remoteBranch(ctx context.Context) (string, error) {
+	out, err := r.git(ctx, "ls-remote", "--symref", "origin", "HEAD", "refs/heads/"+defaultBranch)
+	if err != nil {
+		return "", err
+	}
+	branch := ""
+	for _, line := range strings.Split(out, "\n") {
+		// ref: refs/heads/main	HEAD
+		if rest, ok := strings.CutPrefix(line, "ref: refs/heads/"); ok {
+			if i := strings.IndexAny(rest, " \t"); i > 0 {
+				return rest[:i], nil
+			}
+		}
+		if strings.HasSuffix(line, "\trefs/heads/"+defaultBranch) {
+			branch = defaultBranch
+		}
+	}
+	return branch, nil
+}
+
+// git runs a git command in the working copy and returns its combined
+// output; failures include git's own message.
+func (r *Repo) git(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = r.Dir
+	// Commits are authored by flo rather than depending on (or
+	// requiring) a configured git identity.
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=flo", "GIT_AUTHOR_EMAIL=flo@localhost",
+		"GIT_COMMITTER_NAME=flo", "GIT_COMMITTER_EMAIL=flo@localhost")
+	var buf bytes.Buffer
+	cmd.Stdout = &buf
+	cmd.Stderr = &buf
+	err := cmd.Run()
+	out := buf.String()
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return out, fmt.Errorf("git is not installed or not on PATH")
+		}
+		if msg := strings.TrimSpace(out); msg != "" {
+			return out, fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return out, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
@@ -0,0 +1,124 @@
+// Package httpx builds the HTTP clients and subprocess environment flo
+// uses for every network path, so proxy and TLS settings are applied
+// consistently.
+//
+// Proxies are taken from the standard HTTP_PROXY / HTTPS_PROXY /
+// NO_PROXY environment variables.  Corporate TLS interception is
+// supported via an extra CA bundle (--ca-file) or, as a last resort,
+// by disabling certificate verification (--insecure-skip-verify).
+package httpx
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Options holds the network settings shared by all clients.
+type Options struct {
+	// CAFile is a PEM bundle added to the system trust store.
+	CAFile string
+	// InsecureSkipVerify disables TLS certificate verification.
+	InsecureSkipVerify bool
+}
+
+// NewClient returns an http.Client honoring the proxy environment and
+// the TLS settings in opts.
+func NewClient(opts Options, timeout time.Duration) (*http.Client, error) {
+	tlsConf, err := tlsConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	tr.Proxy = http.ProxyFromEnvironment
+	tr.TLSClientConfig = tlsConf
+	return &http.Client{Transport: tr, Timeout: timeout}, nil
+}
+
+// tlsConfig builds the TLS configuration described by opts.
+func tlsConfig(opts Options) (*tls.Config, error) {
+	// InsecureSkipVerify is an explicit user opt-in (--insecure-skip-verify).
+	conf := &tls.Config{InsecureSkipVerify: opts.InsecureSkipVerify}
+	if opts.CAFile == "" {
+		return conf, nil
+	}
+
+	pem, err := os.ReadFile(opts.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("read CA bundle: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", opts.CAFile)
+	}
+	conf.RootCAs = pool
+	return conf, nil
+}
+
+// proxyVars are the proxy environment variables forwarded to subprocesses,
+// in both the conventional upper- and lower-case spellings.
+var proxyVars = []string{
+	"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY",
+	"http_proxy", "https_proxy", "no_proxy",
+}
+
+// SubprocessEnv returns extra environment entries for the Node-based
+// mcp-remote bridge so that it (and the npx download that precedes it)
+// use the same proxy and TLS settings as flo itself.
+//
+// Node does not read HTTP(S)_PROXY on its own, so NODE_USE_ENV_PROXY is
+// set when a proxy is configured, and the npm_config_* equivalents are
+// populated for npx.
+func SubprocessEnv(opts Options) []string {
+	var env []string
+
+	hasProxy := false
+	for _, k := range proxyVars {
+		if v := os.Getenv(k); v != "" {
+			env = append(env, k+"="+v)
+			hasProxy = true
+		}
+	}
+	if hasProxy {
+		env = append(env, "NODE_USE_ENV_PROXY=1")
+		if v := firstEnv("HTTPS_PROXY", "https_proxy"); v != "" {
+			env = append(env, "npm_config_https_proxy="+v)
+		}
+		if v := firstEnv("HTTP_PROXY", "http_proxy"); v != "" {
+			env = append(env, "npm_config_proxy="+v)
+		}
+		if v := firstEnv("NO_PROXY", "no_proxy"); v != "" {
+			env = append(env, "npm_config_noproxy="+v)
+		}
+	}
+
+	if opts.CAFile != "" {
+		env = append(env,
+			"NODE_EXTRA_CA_CERTS="+opts.CAFile,
+			"npm_config_cafile="+opts.CAFile,
+		)
+	}
+	if opts.InsecureSkipVerify {
+		env = append(env,
+			"NODE_TLS_REJECT_UNAUTHORIZED=0",
+			"npm_config_strict_ssl=false",
+		)
+	}
+	return env
+}
+
+// firstEnv returns the value of the first non-empty variable in keys.
+func firstEnv(keys ...string) string {
+	for _, k := range keys {
+		if v := os.Getenv(k); v != "" {
+			return v
+		}
+	}
+	return ""
+}
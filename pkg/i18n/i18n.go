@@ -0,0 +1,129 @@
+// Package i18n translates flo's messages.  Messages are looked up by
+// their English text, as with gettext, so the code stays readable and a
+// message missing from a catalog shows in English.  Each language is a
+// JSON catalog, catalogs/<lang>.json, mapping the English text of a
+// message to its translation; format strings keep their verbs, which
+// may be reordered with explicit indexes (%[2]d).
+//
+// The language is English until Set is called.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// English is the language of the messages in the code.
+const English = "en"
+
+//go:embed catalogs/*.json
+var catalogs embed.FS
+
+// current is the catalog in use; nil for English.
+var current atomic.Pointer[map[string]string]
+
+// lang is the language in use.
+var lang atomic.Value
+
+// Languages returns the languages flo speaks, English first.
+func Languages() []string {
+	langs := []string{English}
+	entries, _ := catalogs.ReadDir("catalogs")
+	var rest []string
+	for _, e := range entries {
+		rest = append(rest, strings.TrimSuffix(e.Name(), ".json"))
+	}
+	sort.Strings(rest)
+	return append(langs, rest...)
+}
+
+// Set switches to the language of locale, a language code such as
+// "ja" or a POSIX locale such as "pt_BR.UTF-8"; "", "C" and "POSIX" are
+// English.  It fails, leaving the language as it was, when flo has no
+// catalog for the language.
+func Set(locale string) error {
+	code := Language(locale)
+	if code == English {
+		current.Store(nil)
+		lang.Store(English)
+		return nil
+	}
+	data, err := catalogs.ReadFile(path.Join("catalogs", code+".json"))
+	if err != nil {
+		return fmt.Errorf("no messages in %q (have %s)", locale, strings.Join(Languages(), ", "))
+	}
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("catalog %s: %w", code, err)
+	}
+	current.Store(&m)
+	lang.Store(code)
+	return nil
+}
+
+// Current returns the language in use.
+func Current() string {
+	if l, ok := lang.Load().(string); ok {
+		return l
+	}
+	return English
+}
+
+// Language returns the language code of locale: "pt_BR.UTF-8" and
+// "pt-BR" give "pt".  "", "C" and "POSIX" give English.
+func Language(locale string) string {
+	code, _, _ := strings.Cut(locale, ".")
+	code, _, _ = strings.Cut(code, "@")
+	code, _, _ = strings.Cut(strings.ReplaceAll(code, "-", "_"), "_")
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" || code == "c" || code == "posix" {
+		return English
+	}
+	return code
+}
+
+// FromEnv returns the locale the environment asks for, the way POSIX
+// programs read it: LC_ALL, then LC_MESSAGES, then LANG.  LANGUAGE, a
+// GNU list of preferences, comes first when set.
+func FromEnv() string {
+	if l := os.Getenv("LANGUAGE"); l != "" {
+		first, _, _ := strings.Cut(l, ":")
+		return first
+	}
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if l := os.Getenv(name); l != "" {
+			return l
+		}
+	}
+	return ""
+}
+
+// T returns the translation of msg, or msg when there is none.
+func T(msg string) string {
+	if m := current.Load(); m != nil {
+		if t, ok := (*m)[msg]; ok && t != "" {
+			return t
+		}
+	}
+	return msg
+}
+
+// Tf formats the translation of format with args, like fmt.Sprintf.
+func Tf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// Plural returns the translation of one when n is 1, else of many.
+// Languages without plurals translate both alike.
+func Plural(n int, one, many string) string {
+	if n == 1 {
+		return T(one)
+	}
+	return T(many)
+}
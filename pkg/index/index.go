@@ -0,0 +1,394 @@
+// Package index is flo's local, offline full-text index of every
+// question and answer the user has viewed — a personal Stack Overflow
+// memory searchable without a network connection.
+//
+// Documents are appended as JSON lines to <data dir>/index.jsonl, so
+// recording a view writes only that view however large the index grows;
+// a later line for the same ID replaces an earlier one, and Save
+// compacts the file once most of it is replaced.  The inverted index is
+// rebuilt in memory on load; ranking uses Okapi BM25 over the title
+// (boosted), tags and body.
+package index
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Kind distinguishes question and answer documents.
+type Kind string
+
+const (
+	KindQuestion Kind = "question"
+	KindAnswer   Kind = "answer"
+)
+
+// Doc is one indexed post.
+type Doc struct {
+	// ID is "q<question_id>" or "a<answer_id>".
+	ID         string    `json:"id"`
+	Kind       Kind      `json:"kind"`
+	QuestionID int       `json:"question_id"`
+	AnswerID   int       `json:"answer_id,omitempty"`
+	Title      string    `json:"title"`
+	Body       string    `json:"body"`
+	Tags       []string  `json:"tags,omitempty"`
+	Author     string    `json:"author,omitempty"`
+	Score      int       `json:"score"`
+	Accepted   bool      `json:"accepted,omitempty"`
+	Link       string    `json:"link,omitempty"`
+	ViewedAt   time.Time `json:"viewed_at"`
+	// Source records where a document came from when it was not viewed
+	// live, e.g. "dump" for data-dump imports.
+	Source string `json:"source,omitempty"`
+}
+
+// Hit is a search result.
+type Hit struct {
+	Doc   *Doc    `json:"doc"`
+	Score float64 `json:"score"`
+}
+
+// BM25 parameters and the title boost.
+const (
+	bm25K1     = 1.2
+	bm25B      = 0.75
+	titleBoost = 3
+)
+
+// Index is an in-memory inverted index backed by a JSON Lines file.
+type Index struct {
+	path string
+	docs map[string]*Doc
+	// pending are the IDs added since the last Save, and lines how many
+	// documents the file holds, replaced ones included.
+	pending []string
+	lines   int
+	// legacy is set when the documents were read from an index.json
+	// written before the index was kept as JSON lines.
+	legacy bool
+
+	// postings maps term → doc ID → weighted term frequency.
+	postings map[string]map[string]float64
+	// lengths holds each document's weighted token count.
+	lengths map[string]float64
+	avgLen  float64
+}
+
+// DefaultPath returns index.jsonl inside dataDir.
+func DefaultPath(dataDir string) string {
+	return filepath.Join(dataDir, "index.jsonl")
+}
+
+// legacyPath is the index.json, next to path, that the index was kept in
+// as a single JSON array before.
+func legacyPath(path string) string {
+	return filepath.Join(filepath.Dir(path), "index.json")
+}
+
+// maxDocLine bounds one document's line in the index file.
+const maxDocLine = 16 << 20
+
+// Open loads the index at path; a missing file yields an empty index.
+// Lines that do not parse — a write cut short — are skipped.
+func Open(path string) (*Index, error) {
+	idx := &Index{path: path, docs: make(map[string]*Doc)}
+
+	if err := idx.readLegacy(); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("read index: %w", err)
+	}
+	if f != nil {
+		defer f.Close()
+		sc := bufio.NewScanner(f)
+		sc.Buffer(make([]byte, 64*1024), maxDocLine)
+		for sc.Scan() {
+			var d Doc
+			if json.Unmarshal(sc.Bytes(), &d) != nil || d.ID == "" {
+				continue
+			}
+			idx.docs[d.ID] = &d
+			idx.lines++
+		}
+		if err := sc.Err(); err != nil {
+			return nil, fmt.Errorf("read index %s: %w", path, err)
+		}
+	}
+	idx.rebuild()
+	return idx, nil
+}
+
+// readLegacy loads the documents of an index.json left by an older flo;
+// Save moves them to the JSON lines file.
+func (idx *Index) readLegacy() error {
+	data, err := os.ReadFile(legacyPath(idx.path))
+	if errors.Is(err, fs.ErrNotExist) || len(data) == 0 && err == nil {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read index: %w", err)
+	}
+	var docs []*Doc
+	if err := json.Unmarshal(data, &docs); err != nil {
+		return fmt.Errorf("parse index %s: %w", legacyPath(idx.path), err)
+	}
+	for _, d := range docs {
+		idx.docs[d.ID] = d
+	}
+	idx.legacy = true
+	return nil
+}
+
+// Append records docs in the index file at path without reading it, for
+// adding what was just viewed cheaply.
+func Append(path string, docs ...Doc) error {
+	for i := range docs {
+		if docs[i].ViewedAt.IsZero() {
+			docs[i].ViewedAt = time.Now()
+		}
+	}
+	return appendDocs(path, docs)
+}
+
+// appendDocs writes docs to the end of the file at path in one write, so
+// that processes appending at once do not interleave their lines.
+func appendDocs(path string, docs []Doc) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for i := range docs {
+		if err := enc.Encode(&docs[i]); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Len returns the number of indexed documents.
+func (idx *Index) Len() int { return len(idx.docs) }
+
+// Get returns the document with the given ID, or nil.
+func (idx *Index) Get(id string) *Doc { return idx.docs[id] }
+
+// Docs returns all documents, most recently viewed first.
+func (idx *Index) Docs() []*Doc {
+	out := make([]*Doc, 0, len(idx.docs))
+	for _, d := range idx.docs {
+		out = append(out, d)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ViewedAt.After(out[j].ViewedAt) })
+	return out
+}
+
+// Add inserts or replaces docs (matched by ID) without saving.
+func (idx *Index) Add(docs ...Doc) {
+	for i := range docs {
+		d := docs[i]
+		if d.ViewedAt.IsZero() {
+			d.ViewedAt = time.Now()
+		}
+		idx.docs[d.ID] = &d
+		idx.pending = append(idx.pending, d.ID)
+	}
+	idx.rebuild()
+}
+
+// compactSlack is how many replaced lines the index file may hold
+// beyond its documents before Save rewrites it.
+const compactSlack = 1000
+
+// Save appends the documents added since it was opened to its file.  A
+// file mostly made of replaced documents, or an index.json of an older
+// flo, is rewritten in full instead.
+func (idx *Index) Save() error {
+	if idx.legacy || idx.lines+len(idx.pending) > 2*len(idx.docs)+compactSlack {
+		return idx.compact()
+	}
+	docs := make([]Doc, 0, len(idx.pending))
+	for _, id := range uniq(idx.pending) {
+		docs = append(docs, *idx.docs[id])
+	}
+	if err := appendDocs(idx.path, docs); err != nil {
+		return err
+	}
+	idx.lines += len(docs)
+	idx.pending = nil
+	return nil
+}
+
+// compact rewrites the index file atomically with one line per
+// document, and removes a legacy index.json.
+func (idx *Index) compact() error {
+	if err := os.MkdirAll(filepath.Dir(idx.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(idx.path), filepath.Base(idx.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	docs := idx.Docs()
+	for _, d := range docs {
+		if err := enc.Encode(d); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(0o644); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(f.Name(), idx.path); err != nil {
+		return err
+	}
+	if idx.legacy {
+		if err := os.Remove(legacyPath(idx.path)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		idx.legacy = false
+	}
+	idx.lines, idx.pending = len(docs), nil
+	return nil
+}
+
+// Search ranks documents against query with BM25 and returns at most
+// limit hits (all when limit <= 0).
+func (idx *Index) Search(query string, limit int) []Hit {
+	terms := Tokenize(query)
+	if len(terms) == 0 || len(idx.docs) == 0 {
+		return nil
+	}
+
+	n := float64(len(idx.docs))
+	scores := make(map[string]float64)
+	for _, t := range uniq(terms) {
+		posting := idx.postings[t]
+		if len(posting) == 0 {
+			continue
+		}
+		df := float64(len(posting))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for id, tf := range posting {
+			norm := tf + bm25K1*(1-bm25B+bm25B*idx.lengths[id]/idx.avgLen)
+			scores[id] += idf * tf * (bm25K1 + 1) / norm
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, s := range scores {
+		hits = append(hits, Hit{Doc: idx.docs[id], Score: s})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Doc.ViewedAt.After(hits[j].Doc.ViewedAt)
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// rebuild recomputes the inverted index from docs.
+func (idx *Index) rebuild() {
+	idx.postings = make(map[string]map[string]float64)
+	idx.lengths = make(map[string]float64, len(idx.docs))
+	var total float64
+
+	add := func(id string, tokens []string, weight float64) {
+		for _, t := range tokens {
+			p := idx.postings[t]
+			if p == nil {
+				p = make(map[string]float64)
+				idx.postings[t] = p
+			}
+			p[id] += weight
+			idx.lengths[id] += weight
+		}
+	}
+	for id, d := range idx.docs {
+		add(id, Tokenize(d.Title), titleBoost)
+		add(id, Tokenize(strings.Join(d.Tags, " ")), titleBoost)
+		add(id, Tokenize(d.Body), 1)
+		total += idx.lengths[id]
+	}
+	if len(idx.docs) > 0 {
+		idx.avgLen = total / float64(len(idx.docs))
+	}
+	if idx.avgLen == 0 {
+		idx.avgLen = 1
+	}
+}
+
+// Tokenize lowercases text and splits it into terms.  "+", "#" and "."
+// inside a word are kept so tags like c++, c#, node.js stay intact.
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#' && r != '.' && r != '_'
+	})
+	out := fields[:0]
+	for _, f := range fields {
+		f = strings.Trim(f, ".")
+		if f == "" || stopWords[f] {
+			continue
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// stopWords are dropped from both documents and queries.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "how": true, "i": true,
+	"in": true, "is": true, "it": true, "of": true, "on": true, "or": true,
+	"that": true, "the": true, "this": true, "to": true, "what": true,
+	"with": true, "do": true, "does": true, "can": true, "my": true,
+}
+
+// uniq returns terms without duplicates, preserving order.
+func uniq(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	out := terms[:0:0]
+	for _, t := range terms {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
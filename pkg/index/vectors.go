@@ -0,0 +1,141 @@
+// Package index – vectors.go stores embedding vectors for indexed
+// documents, enabling semantic (meaning-based) search alongside BM25.
+// Vectors live in <data dir>/vectors.json and are keyed by document ID
+// plus a hash of the embedded text, so edited documents are re-embedded.
+package index
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ratnesh-maurya/flo/pkg/atomicfile"
+	"github.com/ratnesh-maurya/flo/pkg/embed"
+)
+
+// maxEmbedChars caps the text sent to the embedding model per document.
+const maxEmbedChars = 4000
+
+// Vectors is the persisted embedding store.
+type Vectors struct {
+	path string
+
+	Model   string                `json:"model"`
+	Entries map[string]vectorItem `json:"entries"`
+}
+
+type vectorItem struct {
+	Hash   string    `json:"hash"`
+	Vector []float32 `json:"vector"`
+}
+
+// VectorsPath returns vectors.json inside dataDir.
+func VectorsPath(dataDir string) string {
+	return filepath.Join(dataDir, "vectors.json")
+}
+
+// OpenVectors loads the vector store at path (empty if missing).
+func OpenVectors(path string) (*Vectors, error) {
+	v := &Vectors{path: path, Entries: make(map[string]vectorItem)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return v, nil
+		}
+		return nil, fmt.Errorf("read vectors: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, fmt.Errorf("parse vectors %s: %w", path, err)
+	}
+	if v.Entries == nil {
+		v.Entries = make(map[string]vectorItem)
+	}
+	return v, nil
+}
+
+// Save writes the store back to disk.
+func (v *Vectors) Save() error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(v.path, data, 0o644)
+}
+
+// Sync embeds every document in idx that has no up-to-date vector and
+// drops vectors for documents no longer indexed.  progress, if non-nil,
+// is called after each embedding.  It returns the number of documents
+// embedded.
+func (v *Vectors) Sync(ctx context.Context, idx *Index, e embed.Embedder, progress func(done, total int)) (int, error) {
+	if v.Model != e.Model() {
+		v.Model = e.Model()
+		v.Entries = make(map[string]vectorItem)
+	}
+
+	var todo []*Doc
+	for id, d := range idx.docs {
+		if item, ok := v.Entries[id]; !ok || item.Hash != embedHash(d) {
+			todo = append(todo, d)
+		}
+	}
+	for id := range v.Entries {
+		if idx.docs[id] == nil {
+			delete(v.Entries, id)
+		}
+	}
+
+	for i, d := range todo {
+		vec, err := e.Embed(ctx, embedText(d))
+		if err != nil {
+			return i, err
+		}
+		v.Entries[d.ID] = vectorItem{Hash: embedHash(d), Vector: vec}
+		if progress != nil {
+			progress(i+1, len(todo))
+		}
+	}
+	return len(todo), nil
+}
+
+// Search ranks idx documents by cosine similarity to the query vector.
+func (v *Vectors) Search(idx *Index, query []float32, limit int) []Hit {
+	var hits []Hit
+	for id, item := range v.Entries {
+		d := idx.docs[id]
+		if d == nil {
+			continue
+		}
+		hits = append(hits, Hit{Doc: d, Score: embed.Cosine(query, item.Vector)})
+	}
+	sort.Slice(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// embedText is the text embedded for a document: title, tags and body.
+func embedText(d *Doc) string {
+	text := d.Title + "\n"
+	for _, t := range d.Tags {
+		text += "[" + t + "] "
+	}
+	text += "\n" + d.Body
+	if r := []rune(text); len(r) > maxEmbedChars {
+		text = string(r[:maxEmbedChars])
+	}
+	return text
+}
+
+// embedHash fingerprints the embedded text of d.
+func embedHash(d *Doc) string {
+	sum := sha256.Sum256([]byte(embedText(d)))
+	return hex.EncodeToString(sum[:8])
+}
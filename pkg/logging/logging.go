@@ -0,0 +1,96 @@
+// Package logging configures flo's structured logger.
+//
+// flo has two output paths that are deliberately kept apart:
+//
+//   - the pretty, human-facing output (banners, spinners, rendered
+//     answers) written to stdout by the cmd package, and
+//   - a log/slog event stream meant for automation and debugging.
+//
+// By default the structured stream is discarded so interactive users
+// only see the pretty output.  Passing --log-level and/or --log-file
+// enables it, as text or JSON (--log-format).
+//
+// Whatever the options, the last RecentLines records, at every level,
+// are also kept in memory for crash reports (see Recent).
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Options controls where and how structured log records are written.
+type Options struct {
+	// Level is one of "debug", "info", "warn", "error".  Empty means
+	// "info" when File is set and "logging disabled" otherwise.
+	Level string
+	// Format is "text" (default) or "json".
+	Format string
+	// File is an optional path that log records are appended to.
+	// When empty, records go to stderr (if a Level was given).
+	File string
+}
+
+// Setup installs the slog default logger according to opts.
+// The returned io.Closer must be closed on exit to flush the log file;
+// it is always non-nil.
+func Setup(opts Options) (io.Closer, error) {
+	if opts.Level == "" && opts.File == "" {
+		slog.SetDefault(slog.New(recentHandler))
+		return nopCloser{}, nil
+	}
+
+	level, err := parseLevel(opts.Level)
+	if err != nil {
+		return nopCloser{}, err
+	}
+
+	var (
+		w      io.Writer = os.Stderr
+		closer io.Closer = nopCloser{}
+	)
+	if opts.File != "" {
+		f, err := os.OpenFile(opts.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nopCloser{}, fmt.Errorf("open log file: %w", err)
+		}
+		w, closer = f, f
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch strings.ToLower(opts.Format) {
+	case "", "text":
+		handler = slog.NewTextHandler(w, handlerOpts)
+	case "json":
+		handler = slog.NewJSONHandler(w, handlerOpts)
+	default:
+		closer.Close()
+		return nopCloser{}, fmt.Errorf("unknown log format %q (want text or json)", opts.Format)
+	}
+
+	slog.SetDefault(slog.New(tee{handler, recentHandler}))
+	return closer, nil
+}
+
+// parseLevel maps a level name to a slog.Level.
+func parseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(s) {
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "debug":
+		return slog.LevelDebug, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", s)
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
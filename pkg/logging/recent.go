@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// RecentLines is how many records Recent keeps.
+const RecentLines = 200
+
+// recent holds the last records as text lines, oldest first.
+var recent = &ring{lines: make([]string, 0, RecentLines)}
+
+// recentHandler formats every record, debug included, into recent.
+var recentHandler slog.Handler = slog.NewTextHandler(recent, &slog.HandlerOptions{Level: slog.LevelDebug})
+
+// Recent returns the last RecentLines log records, oldest first, as
+// text lines, whether or not logging is enabled.
+func Recent() []string {
+	recent.mu.Lock()
+	defer recent.mu.Unlock()
+	out := make([]string, 0, len(recent.lines))
+	out = append(out, recent.lines[recent.next:]...)
+	return append(out, recent.lines[:recent.next]...)
+}
+
+// ring is an io.Writer keeping the last RecentLines writes; a text
+// handler writes each record at once.
+type ring struct {
+	mu    sync.Mutex
+	lines []string
+	next  int // where the next line goes once lines is full
+}
+
+func (r *ring) Write(p []byte) (int, error) {
+	line := strings.TrimSuffix(string(p), "\n")
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.lines) < RecentLines {
+		r.lines = append(r.lines, line)
+		return len(p), nil
+	}
+	r.lines[r.next] = line
+	r.next = (r.next + 1) % RecentLines
+	return len(p), nil
+}
+
+// tee sends each record to every handler that takes its level.
+type tee []slog.Handler
+
+func (t tee) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range t {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t tee) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range t {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (t tee) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(tee, len(t))
+	for i, h := range t {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (t tee) WithGroup(name string) slog.Handler {
+	out := make(tee, len(t))
+	for i, h := range t {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
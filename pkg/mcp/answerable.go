@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// answerableHalfLife is how long a question takes to lose half of what
+// its age adds to its answerability: past a few days the asker has
+// mostly moved on.
+const answerableHalfLife = 48 * time.Hour
+
+// Answerability scores how worth answering q is at now, for Answerable:
+// new questions score higher, and so do questions read by many (views
+// count by their order of magnitude) and questions others voted up.  A
+// negative score, usually an unclear question, counts against it.
+func Answerability(q *QuestionData, now time.Time) float64 {
+	age := max(now.Sub(time.Unix(q.CreationDate, 0)), 0)
+	s := 3 * math.Pow(0.5, float64(age)/float64(answerableHalfLife))
+	s += math.Log10(1 + float64(max(q.ViewCount, 0)))
+	switch {
+	case q.Score > 0:
+		s += 0.5 * float64(min(q.Score, 5))
+	case q.Score < 0:
+		s -= float64(min(-q.Score, 3))
+	}
+	return s
+}
+
+// Answerable returns the questions in resp viewed at least minViews
+// times, most answerable at now first (see Answerability), and up to n
+// of them when n > 0.
+func Answerable(resp *SOResponse, minViews, n int, now time.Time) []QuestionData {
+	if resp == nil {
+		return nil
+	}
+	type scored struct {
+		q     QuestionData
+		score float64
+	}
+	var found []scored
+	seen := make(map[int]bool)
+	for _, q := range resp.Items {
+		if seen[q.QuestionID] || q.ViewCount < minViews {
+			continue
+		}
+		seen[q.QuestionID] = true
+		found = append(found, scored{q, Answerability(&q, now)})
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].score > found[j].score })
+	if n > 0 && len(found) > n {
+		found = found[:n]
+	}
+	out := make([]QuestionData, len(found))
+	for i, f := range found {
+		out[i] = f.q
+	}
+	return out
+}
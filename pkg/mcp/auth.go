@@ -0,0 +1,190 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrUnauthorized marks a call the MCP server refused because the login
+// expired or was revoked, and renewing it did not help (or was not
+// allowed; see Options.OnReauth).
+var ErrUnauthorized = errors.New("login expired")
+
+// authStatus matches the errors the server and mcp-remote give when the
+// OAuth token is not accepted: an HTTP 401 status, an OAuth error code,
+// the MCP SDK's UnauthorizedError, or the token's own expiry.  They are
+// anchored to how a status or code is written, since the text around
+// them may echo a query or a post ID ("question 401234", a search for
+// "401 unauthorized").  mcp-remote renews an expired access token with
+// its refresh token by itself, so a match means the refresh token is no
+// good either.
+var authStatus = regexp.MustCompile(`(?i)` +
+	`\b(?:http(?:/[\d.]+)?|status(?:[ _]?code)?)[\s:=]*401\b` +
+	`|"?error"?\s*[:=]\s*"?(?:invalid_token|invalid_grant)\b` +
+	`|\bUnauthorizedError\b` +
+	`|\b(?:access|refresh) token (?:has )?expired\b`)
+
+// isAuthError reports whether msg is an authorization failure.
+func isAuthError(msg string) bool {
+	return authStatus.MatchString(msg)
+}
+
+// TokenDir returns where mcp-remote, run with opts, keeps its tokens:
+// $MCP_REMOTE_CONFIG_DIR from opts.Env or the environment, else
+// ~/.mcp-auth.
+func TokenDir(opts Options) string {
+	dir := os.Getenv("MCP_REMOTE_CONFIG_DIR")
+	for _, kv := range opts.Env {
+		if v, ok := strings.CutPrefix(kv, "MCP_REMOTE_CONFIG_DIR="); ok {
+			dir = v
+		}
+	}
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".mcp-auth")
+	}
+	return dir
+}
+
+// drainStderr logs what the bridge writes to stderr, noting the
+// authorization failures among it and passing the login addresses it
+// prints to onLoginURL.  Reading it also keeps a chatty bridge from
+// blocking on a full pipe.
+func (b *bridge) drainStderr(r io.Reader, onLoginURL func(string)) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		slog.Debug("bridge", "stderr", line)
+		if isAuthError(line) {
+			b.authFailed.Store(true)
+		}
+		if u := loginURL(line); u != "" && onLoginURL != nil {
+			onLoginURL(u)
+		}
+	}
+}
+
+// urlPattern finds the addresses in a line of bridge output.
+var urlPattern = regexp.MustCompile(`https?://[^\s"'<>]+`)
+
+// loginURL returns the OAuth authorization address in line, if any: one
+// asking for a code to be sent to a redirect_uri.
+func loginURL(line string) string {
+	for _, s := range urlPattern.FindAllString(line, -1) {
+		u, err := url.Parse(s)
+		if err != nil {
+			continue
+		}
+		q := u.Query()
+		if q.Get("response_type") == "code" && q.Get("redirect_uri") != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// CompleteLogin finishes a login begun at loginURL (as passed to
+// Options.OnLoginURL) in a browser on another machine.  The browser is
+// sent to the bridge's callback on this machine's localhost, which it
+// cannot reach; pasted is the address it was sent to (or only the code
+// in it), and CompleteLogin delivers it to the callback itself.
+func CompleteLogin(ctx context.Context, loginURL, pasted string) error {
+	login, err := url.Parse(loginURL)
+	if err != nil {
+		return err
+	}
+	callback, err := url.Parse(login.Query().Get("redirect_uri"))
+	if err != nil {
+		return fmt.Errorf("login address: %w", err)
+	}
+	switch callback.Hostname() {
+	case "localhost", "127.0.0.1", "::1":
+	default:
+		// The code is only ever handed to the bridge on this machine.
+		return fmt.Errorf("login callback %s is not on this machine", callback.Host)
+	}
+
+	pasted = strings.TrimSpace(pasted)
+	code, state := pasted, login.Query().Get("state")
+	if u, err := url.Parse(pasted); err == nil && u.RawQuery != "" {
+		q := u.Query()
+		if e := q.Get("error"); e != "" {
+			return fmt.Errorf("login refused: %s", strings.TrimSpace(e+" "+q.Get("error_description")))
+		}
+		code = q.Get("code")
+		if s := q.Get("state"); s != "" {
+			state = s
+		}
+	}
+	if code == "" || strings.ContainsAny(code, " /") {
+		return errors.New("no login code in what was pasted; paste the whole address from the browser's address bar")
+	}
+
+	q := callback.Query()
+	q.Set("code", code)
+	if state != "" {
+		q.Set("state", state)
+	}
+	callback.RawQuery = q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, callback.String(), nil)
+	if err != nil {
+		return err
+	}
+	// Straight to localhost: never through a proxy.
+	hc := &http.Client{Transport: &http.Transport{}, Timeout: 30 * time.Second}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("hand the code to the bridge: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("bridge refused the code: %s %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// refused reports whether err, from a call over b, was an authorization
+// failure: the error says so, or the bridge reported one since its last
+// successful call (mcp-remote may just exit, closing the transport).
+func (b *bridge) refused(err error) bool {
+	return isAuthError(err.Error()) || b.authFailed.Load()
+}
+
+// loginContext returns a context with timeout in place of ctx's own
+// deadline, for starting a bridge that may have to log in through the
+// browser — far longer than a call is given — and for making the call
+// again after.  It is still cancelled with ctx (Ctrl+C).
+func loginContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	lctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	stop := context.AfterFunc(ctx, func() {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			cancel()
+		}
+	})
+	return lctx, func() {
+		stop()
+		cancel()
+	}
+}
@@ -0,0 +1,247 @@
+// Package mcp – bridge.go manages a single bridge subprocess (by default
+// mcp-remote run through npx) and the MCP session running over its
+// stdin/stdout.  A Client owns one bridge at a time and replaces it when
+// the connection goes stale.
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	mcpprotocol "github.com/mark3labs/mcp-go/mcp"
+)
+
+// closeGrace is how long close waits for the bridge to exit after its
+// stdin is closed before killing the whole process tree.
+const closeGrace = 2 * time.Second
+
+// bridge is one running bridge subprocess with an initialized MCP session.
+type bridge struct {
+	inner mcpclient.MCPClient
+
+	// cmd is the bridge subprocess, captured so close can clean up the
+	// entire process group rather than only the direct child.
+	cmd       *exec.Cmd
+	closeOnce sync.Once
+	closeErr  error
+
+	// authFailed is set when the bridge reports an authorization
+	// failure, and cleared by a successful call.
+	authFailed atomic.Bool
+
+	timings Timings
+}
+
+// Timings breaks down how long establishing a connection took.
+type Timings struct {
+	// Spawn is the time to start the bridge subprocess.
+	Spawn time.Duration
+	// Handshake is the time for the MCP initialize round trip, which
+	// includes the bridge connecting (and possibly authenticating) to
+	// the remote server.
+	Handshake time.Duration
+}
+
+// startBridge spawns the bridge described by opts and performs the MCP
+// "initialize" handshake.
+func startBridge(ctx context.Context, opts Options) (*bridge, error) {
+	argv, err := BridgeCommand(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &bridge{}
+	start := time.Now()
+	inner, err := mcpclient.NewStdioMCPClientWithOptions(argv[0], opts.Env, argv[1:],
+		transport.WithCommandFunc(func(_ context.Context, command string, env, args []string) (*exec.Cmd, error) {
+			cmd := exec.Command(command, args...)
+			cmd.Env = append(os.Environ(), env...)
+			setProcessGroup(cmd)
+			b.cmd = cmd
+			return cmd, nil
+		}),
+	)
+	if err != nil {
+		killTree(b.cmd)
+		return nil, fmt.Errorf("failed to spawn MCP server: %w", err)
+	}
+	b.inner = inner
+	b.timings.Spawn = time.Since(start)
+	if stderr, ok := mcpclient.GetStderr(inner); ok {
+		go b.drainStderr(stderr, opts.OnLoginURL)
+	}
+
+	// Send MCP "initialize" handshake.
+	initReq := mcpprotocol.InitializeRequest{}
+	initReq.Method = "initialize"
+	initReq.Params.ProtocolVersion = mcpprotocol.LATEST_PROTOCOL_VERSION
+	initReq.Params.ClientInfo = mcpprotocol.Implementation{
+		Name:    "flo",
+		Version: "1.0.0",
+	}
+	initReq.Params.Capabilities = mcpprotocol.ClientCapabilities{}
+
+	start = time.Now()
+	_, err = inner.Initialize(ctx, initReq)
+	if err != nil {
+		b.close()
+		if b.refused(err) {
+			return nil, fmt.Errorf("MCP initialize handshake failed: %w: %w", ErrUnauthorized, err)
+		}
+		return nil, fmt.Errorf("MCP initialize handshake failed: %w", err)
+	}
+	b.timings.Handshake = time.Since(start)
+	// A first login reports the 401 that started it.
+	b.authFailed.Store(false)
+
+	return b, nil
+}
+
+// close shuts down the session and the subprocess tree.  The bridge is
+// first asked to exit by closing its stdin; if it (or any grandchild such
+// as the node process started by npx) is still alive after closeGrace,
+// the whole process group is killed.  close is idempotent.
+func (b *bridge) close() error {
+	b.closeOnce.Do(func() {
+		if b.inner == nil {
+			killTree(b.cmd)
+			return
+		}
+		done := make(chan error, 1)
+		go func() { done <- b.inner.Close() }()
+
+		var err error
+		select {
+		case err = <-done:
+		case <-time.After(closeGrace):
+			killTree(b.cmd)
+			err = <-done
+		}
+		// Reap any stragglers left in the group.
+		killTree(b.cmd)
+
+		// A non-zero exit status of a bridge we just shut down is expected.
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			b.closeErr = err
+		}
+	})
+	return b.closeErr
+}
+
+// BridgeCommand expands opts into the argv used to launch the bridge.
+func BridgeCommand(opts Options) ([]string, error) {
+	url := opts.URL
+	if url == "" {
+		url = DefaultURL
+	}
+	command := opts.Command
+	if command == "" {
+		command = DefaultCommand
+	}
+
+	argv, err := splitCommand(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(argv) == 0 {
+		return nil, fmt.Errorf("empty MCP bridge command")
+	}
+
+	at := -1
+	for i, a := range argv {
+		if strings.Contains(a, "{url}") {
+			argv[i] = strings.ReplaceAll(a, "{url}", url)
+			at = i
+		}
+	}
+	if at < 0 {
+		argv = append(argv, url)
+		at = len(argv) - 1
+	}
+	if len(opts.RemoteArgs) > 0 && isRemote(argv) {
+		argv = slices.Insert(argv, at+1, opts.RemoteArgs...)
+	}
+	return argv, nil
+}
+
+// isRemote reports whether argv runs mcp-remote.
+func isRemote(argv []string) bool {
+	return slices.ContainsFunc(argv, func(a string) bool { return strings.Contains(a, "mcp-remote") })
+}
+
+// NeedsLogin reports whether starting the bridge described by opts
+// would have to log in through the browser: the bridge is mcp-remote and
+// its token store ($MCP_REMOTE_CONFIG_DIR, from opts.Env or the
+// environment, else ~/.mcp-auth) holds no tokens yet.  Other bridges
+// are assumed to need no login.  A stored token may still have expired;
+// this only catches the first run.
+func NeedsLogin(opts Options) bool {
+	argv, err := BridgeCommand(opts)
+	if err != nil || !isRemote(argv) {
+		return false
+	}
+	dir := TokenDir(opts)
+	if dir == "" {
+		return false
+	}
+	// mcp-remote keeps <server hash>_tokens.json in a per-version
+	// subdirectory.
+	for _, pattern := range []string{"*_tokens.json", filepath.Join("*", "*_tokens.json")} {
+		if m, _ := filepath.Glob(filepath.Join(dir, pattern)); len(m) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// splitCommand splits a command line into arguments, honoring single and
+// double quotes so paths containing spaces can be used.
+func splitCommand(s string) ([]string, error) {
+	var (
+		args  []string
+		cur   strings.Builder
+		quote rune
+		inArg bool
+	)
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in command %q", s)
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
@@ -9,79 +9,346 @@ package mcp
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
 
-	mcpclient "github.com/mark3labs/mcp-go/client"
 	mcpprotocol "github.com/mark3labs/mcp-go/mcp"
 )
 
-// Client wraps an MCP client connected to the Stack Exchange server subprocess.
+// Default bridge settings: mcp-remote (run via npx, at the version flo
+// is tested with) talking to the official Stack Overflow MCP server.
+const (
+	DefaultURL     = "https://mcp.stackoverflow.com"
+	DefaultCommand = "npx -y mcp-remote@" + DefaultRemoteVersion + " {url}"
+)
+
+// healthCheckTimeout bounds the ping sent before each query.  A healthy
+// bridge answers in milliseconds; anything slower is treated as stale.
+const healthCheckTimeout = 5 * time.Second
+
+// Options configures how the MCP bridge subprocess is launched.
+type Options struct {
+	// URL is the MCP endpoint; empty means DefaultURL.
+	URL string
+	// Command is the bridge command line; empty means DefaultCommand.
+	// The placeholder {url} is replaced by URL, otherwise URL is
+	// appended as the final argument.
+	Command string
+	// RemoteArgs are mcp-remote options, placed right after the URL.
+	// They are dropped when the bridge is not mcp-remote.
+	RemoteArgs []string
+	// Env holds extra "KEY=value" entries appended to the inherited
+	// environment of the bridge subprocess (proxy, CA bundle, ...).
+	Env []string
+	// OnReconnect, if set, is called just before a stale bridge is
+	// replaced, so the UI can tell the user why there is a pause.
+	OnReconnect func()
+	// OnReauth, if set, is called before a bridge whose login expired is
+	// replaced to renew it, which may open a browser.  Returning false
+	// refuses, failing the call with ErrUnauthorized instead.
+	OnReauth func() bool
+	// OnLoginURL, if set, is called with the address the bridge asks
+	// the user to log in at, so it can be shown where no browser opens
+	// (see CompleteLogin).  It runs on the goroutine reading the bridge's
+	// stderr, which it may block while the user logs in.
+	OnLoginURL func(url string)
+	// OnResult, if set, is called after every tool call with the reply
+	// as the server sent it, so it can be kept for inspection (flo raw)
+	// and timed (flo telemetry).
+	OnResult func(Call)
+	// LoginTimeout bounds starting the replacement bridge, browser login
+	// included; zero means the deadline of the call that needed it.
+	LoginTimeout time.Duration
+}
+
+// Client wraps an MCP session with the Stack Exchange server subprocess.
+// The underlying bridge can be replaced transparently (see EnsureHealthy),
+// so callers keep a single *Client for the whole session.
 type Client struct {
-	inner mcpclient.MCPClient
+	opts Options
+
+	mu     sync.Mutex
+	bridge *bridge
+	closed bool
+
+	// stale is set by the keepalive loop when a ping fails, so the next
+	// EnsureHealthy reconnects without waiting for another ping.
+	stale atomic.Bool
+
+	// reauthMu makes concurrent calls refused by one bridge share a
+	// single renewal.
+	reauthMu sync.Mutex
+
+	stopKeepalive chan struct{}
+	keepaliveOnce sync.Once
 }
 
-// NewClient spawns the mcp-remote bridge via npx, which connects to
-// the official Stack Overflow MCP server at mcp.stackoverflow.com
-// using the stdio transport (JSON-RPC over stdin/stdout).
+// NewClient spawns the MCP bridge subprocess (by default mcp-remote via
+// npx), which connects to the remote MCP server using the stdio
+// transport (JSON-RPC over stdin/stdout).
 // On first run the user is taken through a browser-based OAuth flow;
 // mcp-remote caches the token for subsequent calls.
-func NewClient(ctx context.Context) (*Client, error) {
-	inner, err := mcpclient.NewStdioMCPClient(
-		"npx",
-		nil,
-		"-y", "mcp-remote", "https://mcp.stackoverflow.com",
-	)
+func NewClient(ctx context.Context, opts Options) (*Client, error) {
+	b, err := startBridge(ctx, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to spawn MCP server: %w", err)
+		return nil, err
 	}
+	return &Client{opts: opts, bridge: b, stopKeepalive: make(chan struct{})}, nil
+}
 
-	// Send MCP "initialize" handshake.
-	initReq := mcpprotocol.InitializeRequest{}
-	initReq.Method = "initialize"
-	initReq.Params.ProtocolVersion = mcpprotocol.LATEST_PROTOCOL_VERSION
-	initReq.Params.ClientInfo = mcpprotocol.Implementation{
-		Name:    "flo",
-		Version: "1.0.0",
+// current returns the active bridge.
+func (c *Client) current() (*bridge, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil, fmt.Errorf("MCP client is closed")
 	}
-	initReq.Params.Capabilities = mcpprotocol.ClientCapabilities{}
+	return c.bridge, nil
+}
 
-	_, err = inner.Initialize(ctx, initReq)
+// CallTool invokes a named tool on the MCP server.  When the server
+// refuses the call because the login expired, the bridge is restarted
+// to renew it (see Options.OnReauth) and the call made again.
+func (c *Client) CallTool(ctx context.Context, toolName string, args map[string]any) (*mcpprotocol.CallToolResult, error) {
+	b, err := c.current()
 	if err != nil {
-		inner.Close()
-		return nil, fmt.Errorf("MCP initialize handshake failed: %w", err)
+		return nil, err
 	}
-
-	return &Client{inner: inner}, nil
+	start := time.Now()
+	result, err := c.callTool(ctx, b, toolName, args)
+	if !errors.Is(err, ErrUnauthorized) || ctx.Err() != nil {
+		return result, err
+	}
+	slog.Warn("MCP login expired; renewing it", "tool", toolName, "err", err)
+	if rerr := c.reauthenticate(ctx, b); rerr != nil {
+		if errors.Is(rerr, ErrUnauthorized) {
+			return nil, rerr
+		}
+		return nil, fmt.Errorf("%w: %w", err, rerr)
+	}
+	if b, err = c.current(); err != nil {
+		return nil, err
+	}
+	// The login may have taken longer than the call was given: the
+	// call made again gets the same time afresh.
+	if deadline, ok := ctx.Deadline(); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = loginContext(ctx, deadline.Sub(start))
+		defer cancel()
+	}
+	return c.callTool(ctx, b, toolName, args)
 }
 
-// CallTool invokes a named tool on the MCP server.
-func (c *Client) CallTool(ctx context.Context, toolName string, args map[string]any) (*mcpprotocol.CallToolResult, error) {
+// callTool makes one call over b, marking authorization failures with
+// ErrUnauthorized.
+func (c *Client) callTool(ctx context.Context, b *bridge, toolName string, args map[string]any) (*mcpprotocol.CallToolResult, error) {
 	req := mcpprotocol.CallToolRequest{}
 	req.Method = "tools/call"
 	req.Params.Name = toolName
 	req.Params.Arguments = args
 
-	result, err := c.inner.CallTool(ctx, req)
+	start := time.Now()
+	result, err := b.inner.CallTool(ctx, req)
+	if c.opts.OnResult != nil {
+		call := newCall(toolName, args, result, err)
+		call.Duration = time.Since(start)
+		c.opts.OnResult(call)
+	}
+	if err == nil && result.IsError {
+		err = fmt.Errorf("tool %q returned error: %s", toolName, ExtractText(result))
+	} else if err != nil {
+		err = fmt.Errorf("tool call %q failed: %w", toolName, err)
+	}
+	switch {
+	case err == nil:
+		b.authFailed.Store(false)
+		return result, nil
+	case ctx.Err() == nil && b.refused(err):
+		return nil, fmt.Errorf("%w: %w", ErrUnauthorized, err)
+	}
+	return nil, err
+}
+
+// Call is one tool call and its reply, for Options.OnResult.
+type Call struct {
+	Tool string
+	Args map[string]any
+	// Result is the reply as JSON, nil when the call failed.
+	Result json.RawMessage
+	// Text is the text of the reply, which ParseResponse reads.
+	Text string
+	Err  error
+	// Duration is how long the server took to reply.
+	Duration time.Duration
+}
+
+// newCall returns the Call for a reply to toolName, or the error it
+// failed with.
+func newCall(toolName string, args map[string]any, result *mcpprotocol.CallToolResult, err error) Call {
+	c := Call{Tool: toolName, Args: args, Err: err}
+	if err == nil && result != nil {
+		c.Result, _ = json.Marshal(result)
+		c.Text = ExtractText(result)
+	}
+	return c
+}
+
+// reauthenticate replaces failed, a bridge whose call was refused for an
+// expired login: mcp-remote renews the login as it starts, through the
+// browser if its refresh token is no good either.  Callers refused by
+// the same bridge share one replacement.
+func (c *Client) reauthenticate(ctx context.Context, failed *bridge) error {
+	c.reauthMu.Lock()
+	defer c.reauthMu.Unlock()
+	cur, err := c.current()
 	if err != nil {
-		return nil, fmt.Errorf("tool call %q failed: %w", toolName, err)
+		return err
+	}
+	if cur != failed {
+		return nil // renewed by another caller meanwhile
+	}
+	if c.opts.OnReauth != nil && !c.opts.OnReauth() {
+		return fmt.Errorf("%w; renewing it needs a browser login", ErrUnauthorized)
 	}
-	if result.IsError {
-		text := ExtractText(result)
-		return nil, fmt.Errorf("tool %q returned error: %s", toolName, text)
+	lctx, cancel := loginContext(ctx, c.opts.LoginTimeout)
+	defer cancel()
+	if err := c.replace(lctx, failed); err != nil {
+		return err
 	}
+	slog.Info("MCP login renewed")
+	return nil
+}
 
-	return result, nil
+// Timings reports how long the current connection took to establish.
+func (c *Client) Timings() Timings {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.bridge.timings
 }
 
-// Close shuts down the MCP client and kills the subprocess.
-func (c *Client) Close() error {
-	if c.inner != nil {
-		return c.inner.Close()
+// Ping sends an MCP ping request over the current connection.
+func (c *Client) Ping(ctx context.Context) error {
+	b, err := c.current()
+	if err != nil {
+		return err
+	}
+	return b.inner.Ping(ctx)
+}
+
+// EnsureHealthy verifies the connection with a quick ping and, if the
+// bridge has died or stopped answering, replaces it with a fresh one.
+// Call it before each query so a stale connection surfaces as a short
+// reconnect rather than a "broken pipe" from the next tool call.
+func (c *Client) EnsureHealthy(ctx context.Context) error {
+	if !c.stale.Load() {
+		pingCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		err := c.Ping(pingCtx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		slog.Warn("MCP health check failed; reconnecting", "err", err)
+	}
+	return c.Reconnect(ctx)
+}
+
+// Reconnect tears down the current bridge and starts a new one with the
+// same options.
+func (c *Client) Reconnect(ctx context.Context) error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return fmt.Errorf("MCP client is closed")
+	}
+	old := c.bridge
+	c.mu.Unlock()
+
+	if c.opts.OnReconnect != nil {
+		c.opts.OnReconnect()
+	}
+	if err := c.replace(ctx, old); err != nil {
+		return err
 	}
+	slog.Info("MCP connection re-established")
 	return nil
 }
 
+// replace closes old and starts a new bridge in its place.
+func (c *Client) replace(ctx context.Context, old *bridge) error {
+	old.close()
+	b, err := startBridge(ctx, c.opts)
+	if err != nil {
+		return fmt.Errorf("reconnect: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		b.close()
+		return fmt.Errorf("MCP client is closed")
+	}
+	c.bridge = b
+	c.stale.Store(false)
+	return nil
+}
+
+// StartKeepalive pings the server every interval in the background while
+// the client is idle, marking the connection stale when a ping fails so
+// the next EnsureHealthy reconnects.  It stops when the client is closed.
+func (c *Client) StartKeepalive(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	c.keepaliveOnce.Do(func() {
+		go func() {
+			t := time.NewTicker(interval)
+			defer t.Stop()
+			for {
+				select {
+				case <-c.stopKeepalive:
+					return
+				case <-t.C:
+				}
+				if c.stale.Load() {
+					continue
+				}
+				ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+				err := c.Ping(ctx)
+				cancel()
+				if err != nil {
+					slog.Debug("keepalive ping failed", "err", err)
+					c.stale.Store(true)
+				}
+			}
+		}()
+	})
+}
+
+// Close shuts down the MCP client and its subprocess tree.  It is safe
+// to call repeatedly and from a signal handler.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	b := c.bridge
+	c.mu.Unlock()
+
+	close(c.stopKeepalive)
+	return b.close()
+}
+
 // ExtractText concatenates all TextContent items from a CallToolResult.
 func ExtractText(result *mcpprotocol.CallToolResult) string {
 	if result == nil {
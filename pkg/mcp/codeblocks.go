@@ -0,0 +1,75 @@
+package mcp
+
+import (
+	"regexp"
+	"strings"
+)
+
+// CodeBlock is a code sample from a post.
+type CodeBlock struct {
+	// Lang is the fence's language, or "" when none was given.
+	Lang string
+	Code string
+}
+
+// reCodeFence matches an opening or closing fence and its info string.
+var reCodeFence = regexp.MustCompile("^\\s*(```+|~~~+)\\s*([^`\\s]*)")
+
+// CodeBlocks returns the code samples in a Markdown body, in order:
+// fenced blocks and, as Stack Overflow posts often use them, blocks
+// indented by four spaces or a tab.  HTML entities are decoded.
+func CodeBlocks(md string) []CodeBlock {
+	var blocks []CodeBlock
+	lines := strings.Split(md, "\n")
+	prevBlank := true
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if m := reCodeFence.FindStringSubmatch(line); m != nil {
+			marker := m[1]
+			var code []string
+			j := i + 1
+			for ; j < len(lines); j++ {
+				if c := reCodeFence.FindStringSubmatch(lines[j]); c != nil && c[2] == "" &&
+					c[1][0] == marker[0] && len(c[1]) >= len(marker) {
+					break
+				}
+				code = append(code, lines[j])
+			}
+			blocks = append(blocks, CodeBlock{Lang: m[2], Code: decodeHTML(strings.Join(code, "\n"))})
+			i = j
+			prevBlank = true
+			continue
+		}
+		if prevBlank && isIndentedCode(line) {
+			var code []string
+			j := i
+			for ; j < len(lines); j++ {
+				if isIndentedCode(lines[j]) {
+					code = append(code, unindent(lines[j]))
+				} else if strings.TrimSpace(lines[j]) == "" {
+					code = append(code, "")
+				} else {
+					break
+				}
+			}
+			blocks = append(blocks, CodeBlock{Code: decodeHTML(strings.TrimRight(strings.Join(code, "\n"), "\n"))})
+			i = j - 1
+			continue
+		}
+		prevBlank = strings.TrimSpace(line) == ""
+	}
+	return blocks
+}
+
+// isIndentedCode reports whether line belongs to an indented code block.
+func isIndentedCode(line string) bool {
+	return (strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")) && strings.TrimSpace(line) != ""
+}
+
+// unindent removes one level of code-block indentation.
+func unindent(line string) string {
+	if strings.HasPrefix(line, "\t") {
+		return line[1:]
+	}
+	return strings.TrimPrefix(line, "    ")
+}
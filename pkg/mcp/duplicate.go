@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// reDuplicateLink matches a link to a question in a duplicate notice.
+var reDuplicateLink = regexp.MustCompile(`stackoverflow\.com/(?:questions|q)/(\d+)`)
+
+// duplicateNoticeLines bounds how far into a body a duplicate notice is
+// looked for; Stack Overflow puts it at the very top.
+const duplicateNoticeLines = 12
+
+// DuplicateOf returns the IDs of the questions q was closed as a
+// duplicate of, or nil when it is not a closed duplicate.  The API's
+// closed_details is used when present; otherwise a title ending in
+// "[duplicate]" and the notice quoted at the top of the body.
+func DuplicateOf(q *QuestionData) []int {
+	if q == nil {
+		return nil
+	}
+	var ids []int
+	if q.ClosedDetails != nil {
+		for _, o := range q.ClosedDetails.OriginalQuestions {
+			if o.QuestionID != 0 && o.QuestionID != q.QuestionID {
+				ids = append(ids, o.QuestionID)
+			}
+		}
+		if len(ids) > 0 {
+			return ids
+		}
+	}
+	title := strings.ToLower(strings.TrimSpace(decodeHTML(q.Title)))
+	if !strings.EqualFold(q.ClosedReason, "duplicate") && !strings.HasSuffix(title, "[duplicate]") {
+		return nil
+	}
+	lines := strings.SplitN(q.BodyMarkdown, "\n", duplicateNoticeLines+1)
+	notice := strings.Join(lines[:min(len(lines), duplicateNoticeLines)], "\n")
+	seen := make(map[int]bool)
+	for _, m := range reDuplicateLink.FindAllStringSubmatch(notice, -1) {
+		id, err := strconv.Atoi(m[1])
+		if err != nil || id == q.QuestionID || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// MergeAnswers adds the answers of other that q lacks to q, keeping
+// q.AnswerCount in step.  Acceptance on other is dropped, so q's own
+// accepted answer stays first.  It returns how many were added.
+func MergeAnswers(q, other *QuestionData) int {
+	have := make(map[int]bool, len(q.Answers))
+	for _, a := range q.Answers {
+		have[a.AnswerID] = true
+	}
+	added := 0
+	for _, a := range other.Answers {
+		if a.AnswerID != 0 && have[a.AnswerID] {
+			continue
+		}
+		a.IsAccepted = false
+		q.Answers = append(q.Answers, a)
+		have[a.AnswerID] = true
+		added++
+	}
+	q.AnswerCount = max(q.AnswerCount, len(q.Answers))
+	return added
+}
@@ -0,0 +1,202 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// DefaultRemoteVersion is the mcp-remote release flo is tested with; the
+// default bridge runs it rather than whatever npm calls latest.
+const DefaultRemoteVersion = "0.1.29"
+
+// minNodeMajor is the oldest Node.js mcp-remote runs on.
+const minNodeMajor = 18
+
+// remotePackage is mcp-remote's npm package.
+const remotePackage = "mcp-remote"
+
+// RemoteCommand returns the bridge command running mcp-remote version
+// through npx, which downloads it on first use.
+func RemoteCommand(version string) string {
+	return "npx -y " + remotePackage + "@" + version + " {url}"
+}
+
+// InstalledCommand returns the bridge command running the mcp-remote
+// entry script of an install made by InstallRemote.
+func InstalledCommand(script string) string {
+	return `node "` + script + `" {url}`
+}
+
+// Installed is an mcp-remote installed by InstallRemote.
+type Installed struct {
+	Version string
+	// Script is the package's entry point, run with node.
+	Script string
+}
+
+// packageJSON is the part of a package.json flo reads.
+type packageJSON struct {
+	Version string          `json:"version"`
+	Bin     json.RawMessage `json:"bin"`
+}
+
+// InstalledRemote returns the mcp-remote installed in dir, or nil when
+// there is none.
+func InstalledRemote(dir string) (*Installed, error) {
+	pkgDir := filepath.Join(dir, "node_modules", remotePackage)
+	data, err := os.ReadFile(filepath.Join(pkgDir, "package.json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var pkg packageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, fmt.Errorf("%s: %w", remotePackage, err)
+	}
+	// "bin" is either the script or a map of command names to scripts.
+	var bin string
+	if err := json.Unmarshal(pkg.Bin, &bin); err != nil {
+		var bins map[string]string
+		if err := json.Unmarshal(pkg.Bin, &bins); err != nil {
+			return nil, fmt.Errorf("%s %s: no bin in package.json", remotePackage, pkg.Version)
+		}
+		bin = bins[remotePackage]
+	}
+	if bin == "" {
+		return nil, fmt.Errorf("%s %s: no %s command in package.json", remotePackage, pkg.Version, remotePackage)
+	}
+	script := filepath.Join(pkgDir, filepath.FromSlash(bin))
+	if _, err := os.Stat(script); err != nil {
+		return nil, fmt.Errorf("%s %s is incomplete: %w", remotePackage, pkg.Version, err)
+	}
+	return &Installed{Version: pkg.Version, Script: script}, nil
+}
+
+// NodeVersion returns the version of the node on $PATH, failing when
+// it is missing or too old for mcp-remote.
+func NodeVersion(ctx context.Context) (string, error) {
+	out, err := exec.CommandContext(ctx, "node", "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("node: %w", err)
+	}
+	v := strings.TrimSpace(string(out))
+	major, _, _ := strings.Cut(strings.TrimPrefix(v, "v"), ".")
+	if n, err := strconv.Atoi(major); err != nil || n < minNodeMajor {
+		return v, fmt.Errorf("%s needs Node.js %d or newer; found %s", remotePackage, minNodeMajor, v)
+	}
+	return v, nil
+}
+
+// InstallRemote installs mcp-remote version into dir with npm, passing
+// each line npm prints to progress.  env is added to npm's environment
+// (a proxy or CA bundle).  The new copy is built beside dir and only
+// replaces it once it is complete and node can load it, so a failed
+// install leaves the previous one working.
+func InstallRemote(ctx context.Context, dir, version string, env []string, progress func(line string)) (*Installed, error) {
+	staging := dir + ".new"
+	if err := os.RemoveAll(staging); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(staging, 0o755); err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(staging)
+
+	npm := "npm"
+	if runtime.GOOS == "windows" {
+		npm = "npm.cmd"
+	}
+	cmd := exec.CommandContext(ctx, npm, "install", "--prefix", staging,
+		"--save-exact", "--no-audit", "--no-fund", "--loglevel", "http",
+		remotePackage+"@"+version)
+	cmd.Env = append(os.Environ(), env...)
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stderr = cmd.Stdout
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("npm: %w", err)
+	}
+	var tail []string
+	sc := bufio.NewScanner(out)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		tail = append(tail[max(0, len(tail)-4):], line)
+		if progress != nil {
+			progress(line)
+		}
+	}
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("npm install %s@%s: %w\n%s", remotePackage, version, err, strings.Join(tail, "\n"))
+	}
+
+	inst, err := InstalledRemote(staging)
+	if err == nil && inst == nil {
+		err = fmt.Errorf("npm did not install %s", remotePackage)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Parsing the entry script catches a truncated download and a node
+	// too old for its syntax, without connecting to anything.
+	if out, err := exec.CommandContext(ctx, "node", "--check", inst.Script).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("node cannot load %s %s: %w\n%s", remotePackage, inst.Version, err, strings.TrimSpace(string(out)))
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(staging, dir); err != nil {
+		return nil, err
+	}
+	return InstalledRemote(dir)
+}
+
+// RemoteCached reports whether npx already has mcp-remote version in its
+// cache, so that running it does not start with a download.  It looks
+// in npm's default cache (or $npm_config_cache) and may miss one npm
+// keeps elsewhere.
+func RemoteCached(version string) bool {
+	cache := os.Getenv("npm_config_cache")
+	if cache == "" {
+		if runtime.GOOS == "windows" {
+			cache = filepath.Join(os.Getenv("LocalAppData"), "npm-cache")
+		} else if home, err := os.UserHomeDir(); err == nil {
+			cache = filepath.Join(home, ".npm")
+		}
+	}
+	if cache == "" {
+		return false
+	}
+	matches, _ := filepath.Glob(filepath.Join(cache, "_npx", "*", "node_modules", remotePackage, "package.json"))
+	for _, m := range matches {
+		data, err := os.ReadFile(m)
+		if err != nil {
+			continue
+		}
+		var pkg packageJSON
+		if json.Unmarshal(data, &pkg) == nil && (pkg.Version == version || version == "latest") {
+			return true
+		}
+	}
+	return false
+}
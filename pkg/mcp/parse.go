@@ -4,20 +4,17 @@
 // Both tools return responses in this envelope:
 //
 //	{
-//	  "Items": [
-//	    {
-//	      "Site":  "Stack Overflow",
-//	      "Type":  "Question",
-//	      "Id":    "1752414",
-//	      "Data":  { ... question/answer fields ... }
-//	    }
+//	  "items": [
+//	    { ... question/answer fields ... }
 //	  ],
-//	  "Errors": []
+//	  "errors": []
 //	}
 //
-// "Data" includes tags, score, body_markdown, owner, answers (when
+// The fields include tags, score, body_markdown, owner, answers (when
 // the item was returned by so_search), and more.  The "answers"
-// sub-array is embedded inside each question's Data block.
+// sub-array is embedded inside each question.  Older servers wrapped
+// each item as {"Site", "Type", "Id", "Data": { ... }}; that shape, and
+// others the server may move to, are read by schema.go.
 package mcp
 
 import (
@@ -25,8 +22,11 @@ import (
 	"fmt"
 	"html"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
 )
 
 // ---------- JSON structs matching the MCP server response ----------
@@ -36,6 +36,10 @@ import (
 type SOResponse struct {
 	Items  []QuestionData `json:"items"`
 	Errors []any          `json:"errors"`
+
+	// Drift is set when the reply was not in this shape and was read
+	// as far as it could be (see ParseResponse).
+	Drift *Drift `json:"-"`
 }
 
 // QuestionData holds the rich payload for a question (and inline answers).
@@ -57,43 +61,120 @@ type QuestionData struct {
 	Title            string       `json:"title"`
 	Answers          []AnswerData `json:"answers"` // embedded in so_search results
 
+	// ClosedReason is set on closed questions, e.g. "Duplicate", with
+	// the originals of a duplicate in ClosedDetails.
+	ClosedReason  string         `json:"closed_reason,omitempty"`
+	ClosedDetails *ClosedDetails `json:"closed_details,omitempty"`
+
+	// BountyAmount is the reputation offered by an open bounty on the
+	// question, which closes at BountyClosesDate.
+	BountyAmount     int   `json:"bounty_amount,omitempty"`
+	BountyClosesDate int64 `json:"bounty_closes_date,omitempty"`
+
 	// Answer-specific fields (populated when this item is an answer,
 	// e.g. from get_content "SO_A<id>").
 	AnswerID   int  `json:"answer_id"`
 	IsAccepted bool `json:"is_accepted"`
 }
 
+// ClosedDetails describes why a question was closed.
+type ClosedDetails struct {
+	OriginalQuestions []OriginalQuestion `json:"original_questions,omitempty"`
+}
+
+// OriginalQuestion is a question a duplicate was closed in favour of.
+type OriginalQuestion struct {
+	QuestionID int    `json:"question_id"`
+	Title      string `json:"title"`
+}
+
 // AnswerData holds a single answer embedded inside a question's search result.
 type AnswerData struct {
 	Owner            OwnerData `json:"owner"`
 	IsAccepted       bool      `json:"is_accepted"`
 	LastActivityDate int64     `json:"last_activity_date"`
+	CreationDate     int64     `json:"creation_date"`
 	AnswerID         int       `json:"answer_id"`
 	Score            int       `json:"score"`
 	BodyMarkdown     string    `json:"body_markdown"`
 	Link             string    `json:"link"`
 	Title            string    `json:"title"`
+	// QuestionID is the question answered, when the backend sends it
+	// with an answer listed on its own.
+	QuestionID int `json:"question_id,omitempty"`
+}
+
+// CommentData holds a comment on a question or answer.
+type CommentData struct {
+	CommentID    int       `json:"comment_id"`
+	PostID       int       `json:"post_id"`
+	Owner        OwnerData `json:"owner"`
+	Score        int       `json:"score"`
+	BodyMarkdown string    `json:"body_markdown"`
+	CreationDate int64     `json:"creation_date"`
 }
 
 // OwnerData holds the author information.
 type OwnerData struct {
 	DisplayName string `json:"display_name"`
 	Link        string `json:"link"`
+	UserID      int    `json:"user_id,omitempty"`
+	Reputation  int    `json:"reputation,omitempty"`
+	// GoldTags are tags the author holds a gold badge in.  Backends
+	// leave it empty; flo fills it in, lazily, with the question's
+	// main tag when shown.
+	GoldTags []string `json:"gold_tags,omitempty"`
+}
+
+// ID returns the author's user ID, from the link to their profile
+// ("https://stackoverflow.com/users/211499/...") when the backend did
+// not send it, or 0 when unknown.
+func (o OwnerData) ID() int {
+	if o.UserID > 0 {
+		return o.UserID
+	}
+	_, rest, ok := strings.Cut(o.Link, "/users/")
+	if !ok {
+		return 0
+	}
+	rest, _, _ = strings.Cut(rest, "/")
+	id, err := strconv.Atoi(rest)
+	if err != nil || id <= 0 {
+		return 0
+	}
+	return id
 }
 
 // ---------- Parsing helpers ----------
 
 // ParseResponse deserializes the JSON text returned by an MCP tool call.
+// A reply whose shape has drifted — the posts in another envelope,
+// fields renamed or of other types — is read as far as it can be, with
+// resp.Drift saying what differed; only a reply that is not JSON, or
+// holds no list of posts at all, is an error.
 func ParseResponse(text string) (*SOResponse, error) {
+	var d Drift
+	v, err := decodeTolerant(text, &d)
+	if err != nil {
+		return nil, fmt.Errorf("parse SO response: %w", err)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("parse SO response: %w", err)
+	}
 	var resp SOResponse
-	if err := json.Unmarshal([]byte(text), &resp); err != nil {
+	if err := json.Unmarshal(data, &resp); err != nil {
 		return nil, fmt.Errorf("parse SO response: %w", err)
 	}
+	if !d.Empty() {
+		resp.Drift = &d
+	}
 	return &resp, nil
 }
 
 // BestQuestion returns the highest-scored question from the response,
 // optionally preferring questions whose tags intersect with hints.
+// Answered questions rank above unanswered ones whatever their score.
 // Tag hints are lowercase strings like "go", "python", "javascript".
 func BestQuestion(resp *SOResponse, tagHints []string) *QuestionData {
 	if resp == nil || len(resp.Items) == 0 {
@@ -129,8 +210,11 @@ func BestQuestion(resp *SOResponse, tagHints []string) *QuestionData {
 		}
 	}
 
-	// Sort by score descending; tie-break by view count.
+	// Answered first, then by score descending; tie-break by view count.
 	sort.Slice(questions, func(i, j int) bool {
+		if questions[i].IsAnswered != questions[j].IsAnswered {
+			return questions[i].IsAnswered
+		}
 		if questions[i].Score != questions[j].Score {
 			return questions[i].Score > questions[j].Score
 		}
@@ -140,6 +224,32 @@ func BestQuestion(resp *SOResponse, tagHints []string) *QuestionData {
 	return questions[0]
 }
 
+// NextAnswered returns the best answered question in resp other than
+// q, as BestQuestion ranks them, or nil when there is none.
+func NextAnswered(resp *SOResponse, q *QuestionData, tagHints []string) *QuestionData {
+	if resp == nil {
+		return nil
+	}
+	others := &SOResponse{}
+	var index []int
+	for i := range resp.Items {
+		if resp.Items[i].IsAnswered && resp.Items[i].QuestionID != q.QuestionID {
+			others.Items = append(others.Items, resp.Items[i])
+			index = append(index, i)
+		}
+	}
+	best := BestQuestion(others, tagHints)
+	if best == nil {
+		return nil
+	}
+	for i := range others.Items {
+		if &others.Items[i] == best {
+			return &resp.Items[index[i]]
+		}
+	}
+	return nil
+}
+
 // ---------- Markdown formatting ----------
 
 // FormatQuestionMarkdown builds a human-readable Markdown document from
@@ -157,10 +267,10 @@ func FormatQuestionMarkdown(q *QuestionData, maxAnswers int) string {
 	b.WriteString(fmt.Sprintf("# %s\n\n", title))
 
 	// --- Meta line ---
-	meta := fmt.Sprintf("Score: **%d**  |  Views: **%s**  |  Answers: **%d**",
+	meta := i18n.Tf("Score: **%d**  |  Views: **%s**  |  Answers: **%d**",
 		q.Score, formatNumber(q.ViewCount), q.AnswerCount)
 	if q.IsAnswered {
-		meta += "  |  ✅ Answered"
+		meta += "  |  " + i18n.T("✅ Answered")
 	}
 	b.WriteString(meta + "\n\n")
 
@@ -173,17 +283,9 @@ func FormatQuestionMarkdown(q *QuestionData, maxAnswers int) string {
 		b.WriteString(strings.Join(tagParts, "  ") + "\n\n")
 	}
 
-	// --- Asked by / date ---
-	if q.Owner.DisplayName != "" {
-		dateStr := ""
-		if q.CreationDate > 0 {
-			dateStr = time.Unix(q.CreationDate, 0).Format("Jan 2, 2006")
-		}
-		b.WriteString(fmt.Sprintf("Asked by **%s**", decodeHTML(q.Owner.DisplayName)))
-		if dateStr != "" {
-			b.WriteString(fmt.Sprintf(" on %s", dateStr))
-		}
-		b.WriteString("\n\n")
+	// --- Asked by / dates ---
+	if line := askedLine(q); line != "" {
+		b.WriteString(line + "\n\n")
 	}
 
 	b.WriteString("---\n\n")
@@ -215,21 +317,28 @@ func FormatQuestionMarkdown(q *QuestionData, maxAnswers int) string {
 		}
 
 		b.WriteString("---\n\n")
-		b.WriteString(fmt.Sprintf("## Top %d Answer(s)\n\n", shown))
+		b.WriteString("## " + fmt.Sprintf(i18n.Plural(shown, "Top %d Answer", "Top %d Answers"), shown) + "\n\n")
 
 		for i := 0; i < shown; i++ {
 			a := answers[i]
-			label := fmt.Sprintf("### Answer %d", i+1)
+			label := "### " + i18n.Tf("Answer %d", i+1)
 			if a.IsAccepted {
-				label += "  ✅ Accepted"
+				label += "  " + i18n.T("✅ Accepted")
 			}
 			if a.Score > 0 {
-				label += fmt.Sprintf("  (Score: %d)", a.Score)
+				label += "  " + i18n.Tf("(Score: %d)", a.Score)
 			}
 			b.WriteString(label + "\n\n")
 
 			if a.Owner.DisplayName != "" {
-				b.WriteString(fmt.Sprintf("By **%s**\n\n", decodeHTML(a.Owner.DisplayName)))
+				b.WriteString(i18n.Tf("By **%s**", decodeHTML(a.Owner.DisplayName)))
+				if ctx := authorLine(a.Owner); ctx != "" {
+					b.WriteString(" · " + ctx)
+				}
+				if act := answeredLine(&a); act != "" {
+					b.WriteString(" · " + act)
+				}
+				b.WriteString("\n\n")
 			}
 
 			ansBody := decodeHTML(a.BodyMarkdown)
@@ -263,11 +372,11 @@ func FormatQuestionMarkdown(q *QuestionData, maxAnswers int) string {
 // can be identified.
 func FormatSearchResults(resp *SOResponse, maxResults int) string {
 	if resp == nil || len(resp.Items) == 0 {
-		return "No results found."
+		return i18n.T("No results found.")
 	}
 
 	var b strings.Builder
-	b.WriteString("# Stack Overflow Search Results\n\n")
+	b.WriteString("# " + i18n.T("Stack Overflow Search Results") + "\n\n")
 
 	shown := maxResults
 	if shown <= 0 || shown > len(resp.Items) {
@@ -285,12 +394,12 @@ func FormatSearchResults(resp *SOResponse, maxResults int) string {
 			}
 			tags = " — " + strings.Join(ts, " ")
 		}
-		accepted := ""
+		accepted := " *(" + i18n.T("unanswered") + ")*"
 		if q.IsAnswered {
 			accepted = " ✅"
 		}
-		b.WriteString(fmt.Sprintf("%d. **%s**%s  \n   Score: %d | Answers: %d%s  \n   %s\n\n",
-			i+1, title, accepted, q.Score, q.AnswerCount, tags, q.Link))
+		b.WriteString(fmt.Sprintf("%d. **%s**%s  \n   %s%s  \n   %s\n\n",
+			i+1, title, accepted, i18n.Tf("Score: %d | Answers: %d", q.Score, q.AnswerCount), tags, q.Link))
 	}
 
 	return b.String()
@@ -300,7 +409,8 @@ func FormatSearchResults(resp *SOResponse, maxResults int) string {
 
 // BestQuestionWithAnswers returns the highest-scored question that has
 // at least one embedded answer, preferring questions whose tags match
-// the provided hints.  Returns nil if no question has answers.
+// the provided hints and answered questions over unanswered ones.
+// Returns nil if no question has answers.
 func BestQuestionWithAnswers(resp *SOResponse, tagHints []string) *QuestionData {
 	if resp == nil || len(resp.Items) == 0 {
 		return nil
@@ -337,6 +447,9 @@ func BestQuestionWithAnswers(resp *SOResponse, tagHints []string) *QuestionData
 	}
 
 	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].IsAnswered != candidates[j].IsAnswered {
+			return candidates[i].IsAnswered
+		}
 		if candidates[i].Score != candidates[j].Score {
 			return candidates[i].Score > candidates[j].Score
 		}
@@ -357,7 +470,9 @@ func AnswerFromItem(item QuestionData) AnswerData {
 		BodyMarkdown:     item.BodyMarkdown,
 		Link:             item.Link,
 		Title:            item.Title,
+		CreationDate:     item.CreationDate,
 		LastActivityDate: item.LastActivityDate,
+		QuestionID:       item.QuestionID,
 	}
 }
 
@@ -382,36 +497,46 @@ func FormatAnswerPreview(a *AnswerData, index int) string {
 	if a.IsAccepted {
 		badge = "✅"
 	}
+	if a.Score > 0 {
+		return fmt.Sprintf("%s #%d [%s] %s", badge, index+1, i18n.Tf("Score: %d", a.Score), FormatAnswerSummary(a))
+	}
+	return fmt.Sprintf("%s #%d %s", badge, index+1, FormatAnswerSummary(a))
+}
+
+// FormatAnswerSummary returns "by <author> — <snippet>", the part of an
+// answer preview that does not depend on its score, for callers that
+// draw score and acceptance themselves.  The snippet is the answer's
+// first sentence and the languages and size of its code, e.g.
+// "Use a strings.Builder. [go, 24 lines]".
+func FormatAnswerSummary(a *AnswerData) string {
 	name := decodeHTML(a.Owner.DisplayName)
 	if name == "" {
-		name = "Anonymous"
-	}
-	body := decodeHTML(a.BodyMarkdown)
-	body = strings.SplitN(body, "\n", 2)[0]
-	body = strings.TrimSpace(body)
-	if len(body) > 55 {
-		body = body[:52] + "..."
+		name = i18n.T("Anonymous")
 	}
-	if a.Score > 0 {
-		return fmt.Sprintf("%s #%d [Score: %d] by %s — %s", badge, index+1, a.Score, name, body)
-	}
-	return fmt.Sprintf("%s #%d by %s — %s", badge, index+1, name, body)
+	return i18n.Tf("by %s — %s", name, answerSnippet(a.BodyMarkdown))
 }
 
 // FormatSingleAnswer builds a Markdown document for one answer.
 func FormatSingleAnswer(a *AnswerData) string {
 	var b strings.Builder
 
-	header := "## Answer"
+	header := "## " + i18n.T("Answer")
 	if a.IsAccepted {
-		header += "  ✅ Accepted"
+		header += "  " + i18n.T("✅ Accepted")
 	}
 	name := decodeHTML(a.Owner.DisplayName)
 	if name == "" {
-		name = "Anonymous"
+		name = i18n.T("Anonymous")
+	}
+	b.WriteString(header + "  " + i18n.Tf("(Score: %d)", a.Score) + "\n\n")
+	b.WriteString(i18n.Tf("By **%s**", name))
+	if ctx := authorLine(a.Owner); ctx != "" {
+		b.WriteString(" · " + ctx)
 	}
-	b.WriteString(fmt.Sprintf("%s  (Score: %d)\n\n", header, a.Score))
-	b.WriteString(fmt.Sprintf("By **%s**\n\n", name))
+	if act := answeredLine(a); act != "" {
+		b.WriteString(" · " + act)
+	}
+	b.WriteString("\n\n")
 	b.WriteString("---\n\n")
 	b.WriteString(decodeHTML(a.BodyMarkdown))
 	b.WriteString("\n")
@@ -430,10 +555,10 @@ func FormatQuestionHeader(q *QuestionData) string {
 	title := decodeHTML(q.Title)
 	b.WriteString(fmt.Sprintf("# %s\n\n", title))
 
-	meta := fmt.Sprintf("Score: **%d**  |  Views: **%s**  |  Answers: **%d**",
+	meta := i18n.Tf("Score: **%d**  |  Views: **%s**  |  Answers: **%d**",
 		q.Score, formatNumber(q.ViewCount), q.AnswerCount)
 	if q.IsAnswered {
-		meta += "  |  ✅ Answered"
+		meta += "  |  " + i18n.T("✅ Answered")
 	}
 	b.WriteString(meta + "\n\n")
 
@@ -445,16 +570,8 @@ func FormatQuestionHeader(q *QuestionData) string {
 		b.WriteString(strings.Join(tagParts, "  ") + "\n\n")
 	}
 
-	if q.Owner.DisplayName != "" {
-		dateStr := ""
-		if q.CreationDate > 0 {
-			dateStr = time.Unix(q.CreationDate, 0).Format("Jan 2, 2006")
-		}
-		b.WriteString(fmt.Sprintf("Asked by **%s**", decodeHTML(q.Owner.DisplayName)))
-		if dateStr != "" {
-			b.WriteString(fmt.Sprintf(" on %s", dateStr))
-		}
-		b.WriteString("\n\n")
+	if line := askedLine(q); line != "" {
+		b.WriteString(line + "\n\n")
 	}
 
 	b.WriteString("---\n\n")
@@ -477,6 +594,90 @@ func decodeHTML(s string) string {
 	return html.UnescapeString(s)
 }
 
+// askedLine returns "Asked by **name** 3 years ago (Nov 12, 2009),
+// active 2 months ago", leaving out whatever is unknown.
+func askedLine(q *QuestionData) string {
+	var b strings.Builder
+	if q.Owner.DisplayName != "" {
+		b.WriteString(i18n.Tf("Asked by **%s**", decodeHTML(q.Owner.DisplayName)))
+	} else if q.CreationDate > 0 {
+		b.WriteString(i18n.T("Asked"))
+	}
+	if q.CreationDate > 0 {
+		created := time.Unix(q.CreationDate, 0)
+		b.WriteString(fmt.Sprintf(" %s (%s)", RelativeTime(created, time.Now()), created.Format(i18n.T("Jan 2, 2006"))))
+	}
+	if q.LastActivityDate > q.CreationDate && b.Len() > 0 {
+		b.WriteString(", " + i18n.Tf("active %s", RelativeTime(time.Unix(q.LastActivityDate, 0), time.Now())))
+	}
+	return b.String()
+}
+
+// authorLine returns what is known of an author's standing, as
+// "12.3k rep · 🥇 go", or "".
+func authorLine(o OwnerData) string {
+	var parts []string
+	if o.Reputation > 0 {
+		parts = append(parts, i18n.Tf("%s rep", CompactNumber(o.Reputation)))
+	}
+	for _, t := range o.GoldTags {
+		parts = append(parts, "🥇 "+t)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// answeredLine returns "answered 5 years ago, active 2 months ago" for
+// an answer, or "" when it carries no dates.
+func answeredLine(a *AnswerData) string {
+	now := time.Now()
+	var parts []string
+	if a.CreationDate > 0 {
+		parts = append(parts, i18n.Tf("answered %s", RelativeTime(time.Unix(a.CreationDate, 0), now)))
+	}
+	if a.LastActivityDate > a.CreationDate {
+		parts = append(parts, i18n.Tf("active %s", RelativeTime(time.Unix(a.LastActivityDate, 0), now)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// RelativeTime describes t relative to now in the largest whole unit
+// ("just now", "5 minutes ago", "1 day ago", "3 years ago").
+func RelativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	if d < time.Minute {
+		return i18n.T("just now")
+	}
+	units := []struct {
+		one, many string
+		size      time.Duration
+	}{
+		{"%d year ago", "%d years ago", 365 * 24 * time.Hour},
+		{"%d month ago", "%d months ago", 30 * 24 * time.Hour},
+		{"%d week ago", "%d weeks ago", 7 * 24 * time.Hour},
+		{"%d day ago", "%d days ago", 24 * time.Hour},
+		{"%d hour ago", "%d hours ago", time.Hour},
+		{"%d minute ago", "%d minutes ago", time.Minute},
+	}
+	for _, u := range units {
+		if n := int(d / u.size); n >= 1 {
+			return fmt.Sprintf(i18n.Plural(n, u.one, u.many), n)
+		}
+	}
+	return i18n.T("just now")
+}
+
+// CompactNumber abbreviates n the way Stack Overflow shows reputation:
+// 845, 12.3k, 1.2m.
+func CompactNumber(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", float64(n)/1e6), ".0") + "m"
+	case n >= 10_000:
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", float64(n)/1e3), ".0") + "k"
+	}
+	return formatNumber(n)
+}
+
 // formatNumber returns a human-friendly number string (e.g., 178410 → "178,410").
 func formatNumber(n int) string {
 	if n < 0 {
@@ -0,0 +1,115 @@
+package mcp
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// previewWidth is the most characters of prose an answer preview shows.
+const previewWidth = 55
+
+// fenceLangAliases maps the fence languages posts use to one name each.
+var fenceLangAliases = map[string]string{
+	"golang": "go", "js": "javascript", "py": "python", "python3": "python",
+	"sh": "bash", "shell": "bash", "console": "bash", "ts": "typescript",
+	"c++": "cpp", "cs": "csharp", "c#": "csharp", "rb": "ruby", "yml": "yaml",
+}
+
+var (
+	reMdLink     = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	reMdEmphasis = regexp.MustCompile("\\*\\*|__|`")
+	reSentence   = regexp.MustCompile(`[.!?](\s|$)`)
+)
+
+// answerSnippet returns the one-line preview of an answer body: its
+// first sentence of prose and, when it has code, the languages and
+// line count of the code, e.g. "Use strings.Builder. [go, 24 lines]".
+func answerSnippet(md string) string {
+	text := []rune(firstSentence(md))
+	if len(text) > previewWidth {
+		text = append(text[:previewWidth-1], '…')
+	}
+	snippet := string(text)
+	if code := codeSummary(md); code != "" {
+		snippet = strings.TrimSpace(snippet + " " + code)
+	}
+	return snippet
+}
+
+// firstSentence returns the first sentence of md's first paragraph of
+// prose, skipping code and with inline Markdown removed.
+func firstSentence(md string) string {
+	var para []string
+	lines := strings.Split(decodeHTML(md), "\n")
+	prevBlank := true
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if m := reCodeFence.FindStringSubmatch(lines[i]); m != nil {
+			if len(para) > 0 {
+				break
+			}
+			for i++; i < len(lines); i++ {
+				if c := reCodeFence.FindStringSubmatch(lines[i]); c != nil && c[2] == "" && c[1][0] == m[1][0] {
+					break
+				}
+			}
+			prevBlank = true
+			continue
+		}
+		switch {
+		case line == "" || strings.HasPrefix(line, "<!--"):
+			if len(para) > 0 {
+				i = len(lines)
+			}
+			prevBlank = true
+			continue
+		case prevBlank && isIndentedCode(lines[i]):
+			// An indented code block runs until the next blank line or
+			// so; its lines are not prose either way.
+			continue
+		}
+		prevBlank = false
+		para = append(para, strings.TrimLeft(line, "#> "))
+	}
+
+	text := strings.Join(para, " ")
+	text = reMdLink.ReplaceAllString(text, "$1")
+	text = reMdEmphasis.ReplaceAllString(text, "")
+	if loc := reSentence.FindStringIndex(text); loc != nil {
+		text = text[:loc[0]+1]
+	}
+	return strings.TrimSpace(text)
+}
+
+// codeSummary describes the code in md as "[go, bash, 24 lines]", or
+// "[code, 3 lines]" when no block names its language; "" when md has
+// no code.
+func codeSummary(md string) string {
+	blocks := CodeBlocks(md)
+	if len(blocks) == 0 {
+		return ""
+	}
+	var langs []string
+	seen := make(map[string]bool)
+	lines := 0
+	for _, b := range blocks {
+		lines += strings.Count(strings.TrimRight(b.Code, "\n"), "\n") + 1
+		lang := strings.TrimPrefix(strings.ToLower(b.Lang), "lang-")
+		if alias, ok := fenceLangAliases[lang]; ok {
+			lang = alias
+		}
+		if lang != "" && !seen[lang] {
+			seen[lang] = true
+			langs = append(langs, lang)
+		}
+	}
+	if len(langs) == 0 {
+		langs = []string{"code"}
+	}
+	unit := "lines"
+	if lines == 1 {
+		unit = "line"
+	}
+	return fmt.Sprintf("[%s, %d %s]", strings.Join(langs, ", "), lines, unit)
+}
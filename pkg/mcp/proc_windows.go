@@ -0,0 +1,25 @@
+//go:build windows
+
+package mcp
+
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// setProcessGroup starts the bridge in a new process group so console
+// Ctrl+C events aimed at flo are not delivered to it mid-cleanup.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// killTree kills the bridge and all of its descendants via taskkill.
+// Once the bridge has been reaped its PID may be reused, so nothing is
+// done in that case.
+func killTree(cmd *exec.Cmd) {
+	if cmd == nil || cmd.Process == nil || cmd.ProcessState != nil {
+		return
+	}
+	_ = exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+}
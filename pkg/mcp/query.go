@@ -0,0 +1,263 @@
+package mcp
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// QueryStage rewrites a search query.  Stages must return their input
+// unchanged when they have nothing to do.
+type QueryStage func(string) string
+
+// QueryPipeline runs stages in order.
+type QueryPipeline []QueryStage
+
+// DefaultQueryPipeline turns a naturally phrased question or a pasted
+// error message into terms so_search matches well.  Callers may build
+// their own pipeline from the exported stages, or append custom ones.
+var DefaultQueryPipeline = QueryPipeline{
+	StripErrorNoise,
+	NormalizeVersions,
+	RemoveStopWords,
+	ExpandSynonyms,
+}
+
+// Run applies every stage to query.  If the result would be empty, the
+// trimmed original is returned instead: a query made only of stop words
+// is still better sent as typed.
+func (p QueryPipeline) Run(query string) string {
+	out := query
+	for _, stage := range p {
+		out = stage(out)
+	}
+	out = strings.Join(strings.Fields(out), " ")
+	if out == "" {
+		return strings.TrimSpace(query)
+	}
+	return out
+}
+
+// NormalizeQuery runs DefaultQueryPipeline over query.
+func NormalizeQuery(query string) string {
+	return DefaultQueryPipeline.Run(query)
+}
+
+var (
+	// /home/me/proj/main.go:12:5 or C:\src\app\main.cs(12,5) → main.go / main.cs.
+	// The path must start at a root (/, ./, ../, ~/ or a drive) so that
+	// names like node:internal/modules/cjs/loader are left whole.
+	rePath = regexp.MustCompile(`(^|[\s"'(\[=])(?:[A-Za-z]:[\\/]|~[\\/]|\.\.?[\\/]|[\\/])(?:[\w.\-@]+[\\/])*([\w.\-@]+)`)
+	// :12 / :12:5 / (12,5) / line 12 position suffixes
+	reLineCol = regexp.MustCompile(`(?i)(?::\d+)+\b|\(\d+(?:,\d+)?\)|\bline \d+\b`)
+	// 0x7ffd5e8c, long hex hashes, UUIDs
+	reHex = regexp.MustCompile(`(?i)\b0x[0-9a-f]+\b|\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b|\b[0-9a-f]*\d[0-9a-f]*[a-f][0-9a-f]*\b`)
+	// 2024-01-02T15:04:05Z, 15:04:05.123
+	reTimestamp = regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}(?:[T ]\d{2}:\d{2}(?::\d{2})?(?:\.\d+)?(?:Z|[+-]\d{2}:?\d{2})?)?\b|\b\d{2}:\d{2}:\d{2}(?:\.\d+)?\b`)
+	// goroutine 17 [running]:, pid=1234, [12345]
+	reRuntimeIDs = regexp.MustCompile(`(?i)\bgoroutine \d+ \[[^\]]*\]:?|\b(?:pid|tid|port)[=: ]\d+\b|\[\d+\]`)
+	// brackets, and what removals leave behind: "addr=", a lone "+"
+	reLeftovers = regexp.MustCompile(`[\[\](){}]|\b\w+=(?:\s|$)|(?:^|\s)[^\w\s]+(?:\s|$)`)
+)
+
+// StripErrorNoise removes the parts of a pasted error message that are
+// specific to one machine or run — directory paths, line/column
+// positions, memory addresses, hashes, timestamps, process IDs — and
+// would otherwise stop it matching anyone else's report of the error.
+func StripErrorNoise(q string) string {
+	q = rePath.ReplaceAllString(q, "$1$2")
+	q = reTimestamp.ReplaceAllString(q, " ")
+	q = reRuntimeIDs.ReplaceAllString(q, " ")
+	q = reLineCol.ReplaceAllString(q, " ")
+	q = reHex.ReplaceAllStringFunc(q, func(m string) string {
+		// Keep short tokens like "e2e" or "h264"; only drop hash-like runs.
+		if strings.HasPrefix(strings.ToLower(m), "0x") || len(m) >= 8 {
+			return " "
+		}
+		return m
+	})
+	// Run twice: adjacent leftovers share the separating space.
+	q = reLeftovers.ReplaceAllString(q, " ")
+	return reLeftovers.ReplaceAllString(q, " ")
+}
+
+var (
+	// Words that mark a line as stating an error.
+	reErrorWord = regexp.MustCompile(`(?i)\b(?:error|exception|panic|fatal|failed|failure|cannot|can't|undefined|denied|refused|not found|unexpected)\b|\w(?:Error|Exception)\b`)
+	// Stack frames and trace headers: "at Foo.bar(...)", `File "x.py"`,
+	// "#3 0x...", "Traceback (most recent call last):".
+	reFrameLine = regexp.MustCompile(`^\s*(?:at |File "|\.\.\.|#\d+ |goroutine \d|Traceback \(|Caused by:)`)
+)
+
+// maxErrorWords caps the words ErrorLine keeps from a very long line.
+const maxErrorWords = 30
+
+// ErrorLine picks the line of a multi-line paste — a stack trace, build
+// log or terminal output — that states the error, since the frames and
+// context around it only dilute a search.  Without such a line the
+// first other line is used.  Long lines are cut to maxErrorWords.
+func ErrorLine(text string) string {
+	var first string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || reFrameLine.MatchString(line) {
+			continue
+		}
+		if reErrorWord.MatchString(line) {
+			first = line
+			break
+		}
+		if first == "" {
+			first = line
+		}
+	}
+	if first == "" {
+		first = text // only frames; better than nothing
+	}
+	words := strings.Fields(first)
+	if len(words) > maxErrorWords {
+		words = words[:maxErrorWords]
+	}
+	return strings.Join(words, " ")
+}
+
+var (
+	// python3.11 / node18.2 → python 3.11 / node 18.2 (but not v1.2)
+	reGluedVersion = regexp.MustCompile(`\b([A-Za-z][A-Za-z+#]+?)(\d+\.\d+(?:\.\d+)*)\b`)
+	// v1.21.3 / 1.21.3-rc1 → 1.21
+	reVersion = regexp.MustCompile(`\bv?(\d+)\.(\d+)(?:\.\d+)+(?:[-+][\w.]+)?\b|\bv(\d+(?:\.\d+)?)\b`)
+)
+
+// NormalizeVersions reduces version numbers to major.minor — patch
+// releases rarely change the answer but split the search — and separates
+// versions glued to a name ("python3.11" → "python 3.11").
+func NormalizeVersions(q string) string {
+	q = reGluedVersion.ReplaceAllString(q, "$1 $2")
+	return reVersion.ReplaceAllStringFunc(q, func(m string) string {
+		sm := reVersion.FindStringSubmatch(m)
+		if sm[3] != "" {
+			return sm[3]
+		}
+		return sm[1] + "." + sm[2]
+	})
+}
+
+// querySynonyms maps shorthand and slang to the wording Stack Overflow
+// posts use.  Keys are lower case single words.
+var querySynonyms = map[string]string{
+	"segfault":    "segmentation fault",
+	"segv":        "segmentation fault",
+	"npe":         "NullPointerException",
+	"oom":         "out of memory",
+	"k8s":         "kubernetes",
+	"js":          "javascript",
+	"ts":          "typescript",
+	"py":          "python",
+	"pg":          "postgresql",
+	"postgres":    "postgresql",
+	"mongo":       "mongodb",
+	"envvar":      "environment variable",
+	"dict":        "dictionary",
+	"async/await": "async await",
+	"cant":        "cannot",
+	"can't":       "cannot",
+	"doesnt":      "does not",
+	"doesn't":     "does not",
+	"wont":        "will not",
+	"won't":       "will not",
+}
+
+// ExpandSynonyms replaces shorthand terms with their canonical wording.
+func ExpandSynonyms(q string) string {
+	words := strings.Fields(q)
+	for i, w := range words {
+		if repl, ok := querySynonyms[strings.ToLower(w)]; ok {
+			words[i] = repl
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// queryStopWords are conversational filler dropped from queries.  The
+// list is deliberately short: words like "not", "without" or "why"
+// change what is being asked and are kept, and so are words that are
+// also technology names ("go", "r", "c").
+var queryStopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "i": true, "me": true, "my": true,
+	"we": true, "our": true, "you": true, "please": true, "pls": true,
+	"help": true, "hi": true, "hello": true, "thanks": true, "thank": true,
+	"how": true, "do": true, "does": true, "can": true, "could": true,
+	"would": true, "should": true, "is": true, "are": true, "there": true,
+	"way": true, "to": true, "what": true, "which": true, "some": true,
+	"any": true, "in": true, "of": true, "for": true, "on": true,
+	"using": true, "with": true, "want": true, "need": true, "trying": true,
+	"get": true, "getting": true, "this": true, "that": true, "it": true,
+	"best": true, "possible": true, "someone": true, "anyone": true,
+}
+
+// reQuoted matches a span quoted with double quotes or backticks, such
+// as a pasted error message.
+var reQuoted = regexp.MustCompile("\"[^\"]*\"|`[^`]*`")
+
+// RemoveStopWords drops conversational filler such as "how do I" and
+// trailing punctuation, keeping technical terms and negations.  Quoted
+// spans are kept as they are: "index out of range" is the exact text.
+func RemoveStopWords(q string) string {
+	var kept []string
+	last := 0
+	for _, m := range reQuoted.FindAllStringIndex(q, -1) {
+		kept = dropStopWords(kept, q[last:m[0]])
+		kept = append(kept, q[m[0]:m[1]])
+		last = m[1]
+	}
+	return strings.Join(dropStopWords(kept, q[last:]), " ")
+}
+
+// dropStopWords appends the words of text that are not stop words to
+// kept, without trailing punctuation.
+func dropStopWords(kept []string, text string) []string {
+	for _, w := range strings.Fields(text) {
+		bare := strings.ToLower(strings.Trim(w, "?!.,;:"))
+		if bare == "" || queryStopWords[bare] {
+			continue
+		}
+		kept = append(kept, strings.TrimRight(w, "?!.,;:"))
+	}
+	return kept
+}
+
+// termAliases merge spellings of the same technology for QueryTerms.
+// Unlike querySynonyms they never reach the server, which matches
+// either spelling anyway.
+var termAliases = map[string]string{
+	"golang": "go", "nodejs": "node.js", "node": "node.js",
+	"cpp": "c++", "csharp": "c#", "reactjs": "react",
+	"js": "javascript", "ts": "typescript",
+	"py": "python", "python3": "python",
+}
+
+// QueryTerms reduces query to the set of terms that decide what is
+// being asked — normalized as for the server, lower-cased, with
+// spellings and plurals merged — sorted, so "how to reverse a string in
+// go" and "reverse strings golang" give the same terms.
+func QueryTerms(query string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, w := range strings.Fields(strings.ToLower(NormalizeQuery(query))) {
+		w = strings.Trim(w, "?!.,;:'\"`")
+		if w == "" || queryStopWords[w] {
+			continue
+		}
+		if alias, ok := termAliases[w]; ok {
+			w = alias
+		} else if len(w) > 3 && strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss") {
+			w = strings.TrimSuffix(w, "s")
+		}
+		if !seen[w] {
+			seen[w] = true
+			terms = append(terms, w)
+		}
+	}
+	sort.Strings(terms)
+	return terms
+}
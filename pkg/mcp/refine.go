@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"sort"
+	"strings"
+)
+
+// negations introduce terms a refinement wants to exclude
+// ("without generics", "not async", "no jquery").
+var negations = map[string]bool{"without": true, "not": true, "no": true, "except": true}
+
+// RefinementTerms splits a follow-up such as "only with generics, no
+// reflection" into terms to require and terms to exclude.
+func RefinementTerms(text string) (include, exclude []string) {
+	negate := false
+	for _, raw := range strings.Fields(strings.ToLower(text)) {
+		w := strings.Trim(raw, `?!.,;:"'()`)
+		switch {
+		case w == "":
+		case negations[w]:
+			negate = true
+		case w == "only" || w == "and" || w == "but" || queryStopWords[w]:
+		case negate:
+			exclude = append(exclude, w)
+		default:
+			include = append(include, w)
+		}
+		if strings.HasSuffix(raw, ",") {
+			negate = false
+		}
+	}
+	return include, exclude
+}
+
+// Refine narrows a previous result set to the items that best match a
+// follow-up: items mentioning an excluded term are dropped, and of the
+// rest only those matching the most include terms (title and tags count
+// more than bodies) are kept, most relevant first.  It returns nil when
+// nothing matches.
+func Refine(resp *SOResponse, include, exclude []string) *SOResponse {
+	if resp == nil {
+		return nil
+	}
+	type scored struct {
+		q     QuestionData
+		score int
+	}
+	var kept []scored
+	best := 0
+	for _, q := range resp.Items {
+		title := strings.ToLower(decodeHTML(q.Title))
+		tags := " " + strings.ToLower(strings.Join(q.Tags, " ")) + " "
+		body := strings.ToLower(q.BodyMarkdown)
+		var answers strings.Builder
+		for _, a := range q.Answers {
+			answers.WriteString(strings.ToLower(a.BodyMarkdown))
+		}
+
+		excluded := false
+		for _, t := range exclude {
+			if strings.Contains(title, t) || strings.Contains(tags, " "+t+" ") || strings.Contains(body, t) {
+				excluded = true
+				break
+			}
+		}
+		if excluded {
+			continue
+		}
+
+		score := 0
+		for _, t := range include {
+			switch {
+			case strings.Contains(tags, " "+t+" "), strings.Contains(title, t):
+				score += 3
+			case strings.Contains(body, t):
+				score += 2
+			case strings.Contains(answers.String(), t):
+				score++
+			}
+		}
+		if len(include) > 0 && score == 0 {
+			continue
+		}
+		kept = append(kept, scored{q, score})
+		if score > best {
+			best = score
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].score > kept[j].score })
+
+	out := &SOResponse{}
+	for _, k := range kept {
+		if k.score < best {
+			break
+		}
+		out.Items = append(out.Items, k.q)
+	}
+	return out
+}
+
+// MergeResponses combines result sets, dropping repeated questions.
+func MergeResponses(resps ...*SOResponse) *SOResponse {
+	out := &SOResponse{}
+	seen := make(map[int]bool)
+	for _, r := range resps {
+		if r == nil {
+			continue
+		}
+		for _, q := range r.Items {
+			if q.QuestionID != 0 && seen[q.QuestionID] {
+				continue
+			}
+			seen[q.QuestionID] = true
+			out.Items = append(out.Items, q)
+		}
+	}
+	return out
+}
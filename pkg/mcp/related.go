@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Related ranks the questions in resp by what they share with q — tags
+// and title words — and returns up to n of them, closest first.  q
+// itself and questions sharing nothing with it are left out.
+func Related(q *QuestionData, resp *SOResponse, n int) []QuestionData {
+	if resp == nil {
+		return nil
+	}
+	words, tags := titleWords(q.Title), lowerSet(q.Tags)
+	type scored struct {
+		q     QuestionData
+		score float64
+	}
+	var found []scored
+	seen := map[int]bool{q.QuestionID: true}
+	for _, c := range resp.Items {
+		if seen[c.QuestionID] {
+			continue
+		}
+		seen[c.QuestionID] = true
+		// Title words count twice as much as tags: most results of a
+		// search share the language tag.
+		s := 2*jaccard(words, titleWords(c.Title)) + jaccard(tags, lowerSet(c.Tags))
+		if s > 0 {
+			found = append(found, scored{c, s})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].score != found[j].score {
+			return found[i].score > found[j].score
+		}
+		return found[i].q.Score > found[j].q.Score
+	})
+	out := make([]QuestionData, 0, min(n, len(found)))
+	for _, f := range found[:min(n, len(found))] {
+		out = append(out, f.q)
+	}
+	return out
+}
+
+// titleWords is the set of lower-case words in a title, without stop
+// words.
+func titleWords(title string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(html.UnescapeString(title)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(".#+-_", r)
+	}) {
+		w = strings.Trim(w, ".-")
+		if w != "" && !queryStopWords[w] {
+			set[w] = true
+		}
+	}
+	return set
+}
+
+func lowerSet(items []string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, s := range items {
+		set[strings.ToLower(s)] = true
+	}
+	return set
+}
+
+// jaccard is the size of the intersection of a and b over that of their
+// union.
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for k := range a {
+		if b[k] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
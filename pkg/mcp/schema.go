@@ -0,0 +1,656 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrUnexpectedShape reports a reply that is JSON but holds no list of
+// posts flo can find.
+var ErrUnexpectedShape = errors.New("unexpected reply shape")
+
+// Drift describes how a reply departed from the shape flo expects: where
+// the posts were, which fields came under other names or types, and
+// which expected fields were missing.  ParseResponse reads such replies
+// anyway, as far as it can, and sets SOResponse.Drift.
+type Drift struct {
+	// Envelope is where the posts were found when not under "items",
+	// e.g. "result.questions", or "[]" for a bare list.
+	Envelope string
+	// Unwrapped is set when the posts sat in "data" objects.
+	Unwrapped bool
+	// Renamed maps the names fields came under to flo's names.
+	Renamed map[string]string
+	// Retyped lists the fields whose values had another type: those
+	// converted ("score: string") and those left out ("tags: object,
+	// dropped").
+	Retyped []string
+	// Missing counts, for each expected field, the posts without it.
+	Missing map[string]int
+	// Posts is the number of posts read, Dropped the number left out
+	// for having neither an id nor a title.
+	Posts, Dropped int
+	// Unknown lists fields flo does not read.  New fields alone are
+	// no drift, but next to a missing one they are likely its new name.
+	Unknown []string
+}
+
+// expectedFields are the fields a post is read by, whatever else it
+// has: questions, answers (embedded or fetched alone) and authors.
+var expectedFields = map[string][]string{
+	"question": {"question_id", "title", "link", "score", "tags"},
+	"answer":   {"answer_id", "score", "body_markdown"},
+	"owner":    {"display_name"},
+}
+
+// fieldAliases maps the other names a field may come under, after
+// conversion to snake_case, to flo's name.
+var fieldAliases = map[string]map[string]string{
+	"post": {
+		"body": "body_markdown", "body_md": "body_markdown", "markdown": "body_markdown", "content": "body_markdown",
+		"url": "link", "question_url": "link", "answer_url": "link",
+		"votes": "score", "vote_count": "score",
+		"views": "view_count", "answers_count": "answer_count", "num_answers": "answer_count",
+		"accepted_answer": "accepted_answer_id", "answered": "is_answered", "accepted": "is_accepted",
+		"created": "creation_date", "created_at": "creation_date", "creation_time": "creation_date",
+		"last_activity": "last_activity_date", "last_activity_at": "last_activity_date", "updated_at": "last_activity_date",
+		"author": "owner", "user": "owner", "tag_list": "tags", "question_title": "title",
+	},
+	"owner": {
+		"name": "display_name", "username": "display_name", "user_name": "display_name",
+		"url": "link", "profile_url": "link", "id": "user_id", "rep": "reputation",
+	},
+}
+
+// The kinds of the fields flo reads.
+var (
+	intFields  = []string{"question_id", "answer_id", "score", "view_count", "answer_count", "accepted_answer_id"}
+	dateFields = []string{"creation_date", "last_activity_date"}
+	boolFields = []string{"is_answered", "is_accepted"}
+	textFields = []string{"body_markdown", "link", "title", "closed_reason"}
+)
+
+// envelopeKeys are where a list of posts may be, most likely first.
+var envelopeKeys = []string{"items", "results", "questions", "answers", "posts", "data", "result", "response"}
+
+// Empty reports whether the reply was in the expected shape.
+func (d *Drift) Empty() bool {
+	return d == nil || d.Envelope == "" && !d.Unwrapped && len(d.Renamed) == 0 &&
+		len(d.Retyped) == 0 && len(d.Missing) == 0 && d.Dropped == 0
+}
+
+// Summary describes the drift in a few words, e.g. "2 fields missing,
+// 1 renamed".
+func (d *Drift) Summary() string {
+	var parts []string
+	if d.Envelope != "" || d.Unwrapped {
+		parts = append(parts, "posts in another envelope")
+	}
+	if n := len(d.Missing); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s missing", n, plural(n, "field", "fields")))
+	}
+	if n := len(d.Renamed); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d renamed", n))
+	}
+	if n := len(d.Retyped); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d of another type", n))
+	}
+	if d.Dropped > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s unreadable", d.Dropped, plural(d.Dropped, "post", "posts")))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Details describes the drift one finding per line.
+func (d *Drift) Details() []string {
+	var lines []string
+	if d.Envelope != "" {
+		lines = append(lines, fmt.Sprintf("posts found at %s, not items", d.Envelope))
+	}
+	if d.Unwrapped {
+		lines = append(lines, "each post wrapped in a data object")
+	}
+	for _, f := range sortedKeys(d.Missing) {
+		lines = append(lines, fmt.Sprintf("missing: %s (%d of %d %s)", f, d.Missing[f], d.Posts, plural(d.Posts, "post", "posts")))
+	}
+	for _, from := range sortedKeys(d.Renamed) {
+		lines = append(lines, fmt.Sprintf("renamed: %s → %s", from, d.Renamed[from]))
+	}
+	for _, r := range d.Retyped {
+		lines = append(lines, "retyped: "+r)
+	}
+	if d.Dropped > 0 {
+		lines = append(lines, fmt.Sprintf("left out: %d %s with neither an id nor a title", d.Dropped, plural(d.Dropped, "post", "posts")))
+	}
+	if len(d.Unknown) > 0 {
+		lines = append(lines, "not read: "+strings.Join(d.Unknown, ", "))
+	}
+	return lines
+}
+
+// decodeTolerant reads a reply whatever its envelope, field names and
+// value types, as far as they can be recognized, into the shape
+// SOResponse decodes, noting what differed in d.
+func decodeTolerant(text string, d *Drift) (map[string]any, error) {
+	dec := json.NewDecoder(strings.NewReader(text))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	items, path, ok := findPosts(v, "", 0)
+	if !ok {
+		return nil, fmt.Errorf("%w: no list of posts among %s", ErrUnexpectedShape, describeShape(v))
+	}
+	if !strings.EqualFold(path, "items") {
+		d.Envelope = path
+	}
+
+	seen := map[string]bool{}
+	var posts []any
+	for _, it := range items {
+		post, ok := it.(map[string]any)
+		if !ok {
+			d.Dropped++
+			continue
+		}
+		p := d.normalizePost(post, "", seen)
+		if p["question_id"] == nil && p["answer_id"] == nil && p["title"] == nil {
+			d.Dropped++
+			continue
+		}
+		posts = append(posts, p)
+	}
+	d.Posts = len(posts)
+	out := map[string]any{"items": posts}
+	if m, ok := v.(map[string]any); ok {
+		if errs, ok := m["errors"].([]any); ok {
+			out["errors"] = errs
+		}
+	}
+	return out, nil
+}
+
+// findPosts looks for the list of posts in v: v itself, a single post,
+// or a list under one of envelopeKeys, at most three levels down.
+func findPosts(v any, path string, depth int) ([]any, string, bool) {
+	switch v := v.(type) {
+	case []any:
+		if path == "" {
+			path = "[]"
+		}
+		return v, path, true
+	case map[string]any:
+		if path == "" && len(v) == 0 {
+			return nil, "items", true
+		}
+		if looksLikePost(v) {
+			if path == "" {
+				path = "(a single post)"
+			}
+			return []any{v}, path, true
+		}
+		if depth > 3 {
+			return nil, "", false
+		}
+		for _, key := range envelopeKeys {
+			for k, inner := range v {
+				if snakeCase(k) != key {
+					continue
+				}
+				if inner == nil {
+					return nil, joinPath(path, k), true // {"items": null}
+				}
+				if items, p, ok := findPosts(inner, joinPath(path, k), depth+1); ok {
+					return items, p, true
+				}
+			}
+		}
+	}
+	return nil, "", false
+}
+
+// looksLikePost reports whether m has any of the fields of a post.
+func looksLikePost(m map[string]any) bool {
+	for k := range m {
+		switch postField(snakeCase(k)) {
+		case "question_id", "answer_id", "title", "body_markdown":
+			return true
+		}
+	}
+	return false
+}
+
+// describeShape names the keys of an object reply, or its type.
+func describeShape(v any) string {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return fmt.Sprintf("a reply of type %s", jsonType(v))
+	}
+	keys := sortedKeys(m)
+	return "the keys " + strings.Join(keys, ", ")
+}
+
+// normalizePost returns post with flo's field names and types, noting
+// the differences in d.  prefix is the path of embedded posts, e.g.
+// "answers[].".
+func (d *Drift) normalizePost(post map[string]any, prefix string, seen map[string]bool) map[string]any {
+	// {"Site": ..., "Type": "Question", "Id": "1", "Data": {...}}: the
+	// fields are in Data, the kind of post beside it.
+	isAnswer := prefix == "answers[]."
+	for k, v := range post {
+		switch snakeCase(k) {
+		case "type", "post_type", "kind":
+			if s, ok := v.(string); ok && strings.EqualFold(s, "answer") {
+				isAnswer = true
+			}
+		case "data":
+			if inner, ok := v.(map[string]any); ok && !looksLikePost(post) {
+				d.Unwrapped = true
+				merged := make(map[string]any, len(post)+len(inner))
+				for k, v := range post {
+					if !strings.EqualFold(k, "data") {
+						merged[k] = v
+					}
+				}
+				for k, v := range inner {
+					merged[k] = v
+				}
+				post = merged
+			}
+		}
+	}
+	fieldName := func(k string) string {
+		name := postField(snakeCase(k))
+		if name != "id" {
+			return name
+		}
+		if isAnswer {
+			return "answer_id"
+		}
+		return "question_id"
+	}
+	for k, v := range post {
+		if n, ok := toInt(v); ok && n != 0 && fieldName(k) == "answer_id" && prefix == "" {
+			isAnswer = true // fetched alone, e.g. get_content SO_A<id>
+		}
+	}
+
+	// The fields under flo's own names first, so that they win over
+	// an alias of the same field.
+	keys := sortedKeys(post)
+	sort.SliceStable(keys, func(i, j int) bool {
+		return strings.EqualFold(keys[i], fieldName(keys[i])) && !strings.EqualFold(keys[j], fieldName(keys[j]))
+	})
+	out := make(map[string]any)
+	for _, k := range keys {
+		name := fieldName(k)
+		if !knownField(name) {
+			if !seen[prefix+k] && !ignoredField(name) {
+				seen[prefix+k] = true
+				d.Unknown = append(d.Unknown, prefix+k)
+			}
+			continue
+		}
+		if _, dup := out[name]; dup {
+			continue
+		}
+		if !strings.EqualFold(k, name) {
+			d.rename(prefix+k, prefix+name)
+		}
+		if v := d.convert(prefix, name, post[k], seen); v != nil {
+			out[name] = v
+		}
+	}
+
+	kind := "question"
+	if isAnswer {
+		kind = "answer"
+	}
+	d.expect(out, prefix, kind)
+	return out
+}
+
+// convert returns v as the type field has, or nil (noting it) when it
+// cannot be.
+func (d *Drift) convert(prefix, field string, v any, seen map[string]bool) any {
+	if v == nil {
+		return nil
+	}
+	retyped := func(how string) {
+		r := fmt.Sprintf("%s%s: %s", prefix, field, how)
+		if !seen["retyped "+r] {
+			seen["retyped "+r] = true
+			d.Retyped = append(d.Retyped, r)
+		}
+	}
+	switch {
+	case contains(intFields, field):
+		n, ok := toInt(v)
+		if !ok {
+			retyped(jsonType(v) + ", dropped")
+			return nil
+		}
+		if _, isNum := v.(json.Number); !isNum || strings.ContainsAny(string(v.(json.Number)), ".eE") {
+			retyped(jsonType(v))
+		}
+		return n
+	case contains(dateFields, field):
+		n, ok := toUnix(v)
+		if !ok {
+			retyped(jsonType(v) + ", dropped")
+			return nil
+		}
+		if num, isNum := v.(json.Number); !isNum {
+			retyped(jsonType(v))
+		} else if i, err := num.Int64(); err != nil || i > 1e11 {
+			retyped("milliseconds")
+		}
+		return n
+	case contains(boolFields, field):
+		switch b := v.(type) {
+		case bool:
+			return b
+		case string:
+			if parsed, err := strconv.ParseBool(b); err == nil {
+				retyped("string")
+				return parsed
+			}
+		case json.Number:
+			retyped("number")
+			return b.String() != "0"
+		}
+		retyped(jsonType(v) + ", dropped")
+		return nil
+	case contains(textFields, field):
+		switch s := v.(type) {
+		case string:
+			return s
+		case json.Number:
+			retyped("number")
+			return s.String()
+		}
+		retyped(jsonType(v) + ", dropped")
+		return nil
+	case field == "tags":
+		tags, ok := toTags(v)
+		if !ok {
+			retyped(jsonType(v) + ", dropped")
+			return nil
+		}
+		if _, isList := v.([]any); !isList {
+			retyped(jsonType(v))
+		}
+		return tags
+	case field == "owner":
+		switch o := v.(type) {
+		case map[string]any:
+			return d.normalizeOwner(o, prefix+"owner.")
+		case string:
+			retyped("string")
+			return map[string]any{"display_name": o}
+		}
+		retyped(jsonType(v) + ", dropped")
+		return nil
+	case field == "answers":
+		list, ok := v.([]any)
+		if !ok {
+			retyped(jsonType(v) + ", dropped")
+			return nil
+		}
+		var answers []any
+		for _, a := range list {
+			if m, ok := a.(map[string]any); ok {
+				answers = append(answers, d.normalizePost(m, "answers[].", seen))
+			}
+		}
+		return answers
+	case field == "closed_details":
+		// Read as is when it decodes; it only adds the duplicate links.
+		data, _ := json.Marshal(v)
+		if json.Unmarshal(data, new(ClosedDetails)) != nil {
+			retyped(jsonType(v) + ", dropped")
+			return nil
+		}
+		return v
+	}
+	return v
+}
+
+// normalizeOwner returns the author object o with flo's field names.
+func (d *Drift) normalizeOwner(o map[string]any, prefix string) map[string]any {
+	out := make(map[string]any)
+	for k, v := range o {
+		name := snakeCase(k)
+		if alias, ok := fieldAliases["owner"][name]; ok {
+			name = alias
+		}
+		switch name {
+		case "display_name", "link", "user_id", "reputation":
+		default:
+			continue
+		}
+		if _, dup := out[name]; dup && !strings.EqualFold(k, name) {
+			continue
+		}
+		if !strings.EqualFold(k, name) {
+			d.rename(prefix+k, prefix+name)
+		}
+		if name == "display_name" || name == "link" {
+			if s, ok := v.(string); ok {
+				out[name] = s
+			}
+		} else if n, ok := toInt(v); ok {
+			out[name] = n
+		}
+	}
+	return out
+}
+
+// expect counts the fields of kind missing from post.
+func (d *Drift) expect(post map[string]any, prefix, kind string) {
+	for _, f := range expectedFields[kind] {
+		if post[f] == nil {
+			d.missing(prefix + f)
+		}
+	}
+	if o, ok := post["owner"].(map[string]any); ok {
+		for _, f := range expectedFields["owner"] {
+			if o[f] == nil {
+				d.missing(prefix + "owner." + f)
+			}
+		}
+	}
+}
+
+func (d *Drift) missing(field string) {
+	if d.Missing == nil {
+		d.Missing = make(map[string]int)
+	}
+	d.Missing[field]++
+}
+
+func (d *Drift) rename(from, to string) {
+	if d.Renamed == nil {
+		d.Renamed = make(map[string]string)
+	}
+	d.Renamed[from] = to
+}
+
+// postField returns flo's name for the snake_case field name of a post.
+func postField(name string) string {
+	if alias, ok := fieldAliases["post"][name]; ok {
+		return alias
+	}
+	return name
+}
+
+// knownField reports whether flo reads the post field name.
+func knownField(name string) bool {
+	switch name {
+	case "tags", "owner", "answers", "closed_details":
+		return true
+	}
+	return contains(intFields, name) || contains(dateFields, name) ||
+		contains(boolFields, name) || contains(textFields, name)
+}
+
+// ignoredField reports whether name is one of the fields flo knows the
+// server sends and has no use for, which are not worth reporting.
+func ignoredField(name string) bool {
+	switch name {
+	case "site", "type", "post_type", "kind", "id":
+		return true
+	}
+	return false
+}
+
+// snakeCase converts camelCase, PascalCase and kebab-case names to
+// snake_case: "BodyMarkdown" and "questionID" become "body_markdown"
+// and "question_id".
+func snakeCase(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case r == '-' || r == ' ':
+			b.WriteByte('_')
+			continue
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])):
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// toInt reads a number given as a number or a string.
+func toInt(v any) (int, bool) {
+	var s string
+	switch v := v.(type) {
+	case json.Number:
+		s = v.String()
+	case string:
+		s = strings.TrimSpace(v)
+	default:
+		return 0, false
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, true
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return 0, false
+	}
+	return int(f), true
+}
+
+// toUnix reads a time given in Unix seconds or milliseconds, or as an
+// RFC 3339 or ISO date string.
+func toUnix(v any) (int64, bool) {
+	switch v := v.(type) {
+	case json.Number:
+		n, err := v.Int64()
+		if err != nil {
+			return 0, false
+		}
+		if n > 1e11 { // milliseconds: 1e11 seconds is the year 5138
+			n /= 1000
+		}
+		return n, true
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", time.DateTime, time.DateOnly} {
+			if t, err := time.Parse(layout, strings.TrimSpace(v)); err == nil {
+				return t.Unix(), true
+			}
+		}
+		if n, ok := toInt(v); ok {
+			return toUnix(json.Number(strconv.Itoa(n)))
+		}
+	}
+	return 0, false
+}
+
+// toTags reads tags given as a list, or a string such as "<go><string>",
+// "go string" or "go,string".
+func toTags(v any) ([]string, bool) {
+	var tags []string
+	switch v := v.(type) {
+	case []any:
+		for _, t := range v {
+			switch t := t.(type) {
+			case string:
+				tags = append(tags, t)
+			case map[string]any:
+				// [{"name": "go"}]
+				for k, name := range t {
+					if s, ok := name.(string); ok && snakeCase(k) == "name" {
+						tags = append(tags, s)
+					}
+				}
+			}
+		}
+		return tags, true
+	case string:
+		return strings.FieldsFunc(v, func(r rune) bool {
+			return r == '<' || r == '>' || r == ',' || r == ';' || unicode.IsSpace(r)
+		}), true
+	}
+	return nil, false
+}
+
+// jsonType names the JSON type of v.
+func jsonType(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number, float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "list"
+	}
+	return "object"
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxSuggestions caps the alternatives offered for an empty search.
+const maxSuggestions = 5
+
+// commonTypos maps frequent misspellings in programming questions to
+// their correction.  Keys are lower case.
+var commonTypos = map[string]string{
+	"pyhton": "python", "pyton": "python", "phyton": "python",
+	"javscript": "javascript", "javacsript": "javascript", "javasript": "javascript",
+	"typscript": "typescript", "tyepscript": "typescript",
+	"kubernets": "kubernetes", "kuberentes": "kubernetes", "kubernates": "kubernetes",
+	"dokcer": "docker", "doker": "docker", "postgress": "postgresql",
+	"golnag": "golang", "rsut": "rust", "jquey": "jquery",
+	"fucntion": "function", "funtion": "function", "fuction": "function",
+	"retrun": "return", "reutrn": "return", "stirng": "string", "strign": "string",
+	"intger": "integer", "interger": "integer", "arrary": "array", "arrray": "array",
+	"lenght": "length", "widht": "width", "heigth": "height",
+	"excpetion": "exception", "exeption": "exception", "dictonary": "dictionary",
+	"paramter": "parameter", "parmeter": "parameter", "arguement": "argument",
+	"initalize": "initialize", "intialize": "initialize",
+	"asyncronous": "asynchronous", "syncronous": "synchronous",
+	"enviroment": "environment", "dependancy": "dependency",
+	"compatability": "compatibility", "acess": "access", "sucess": "success",
+	"recieve": "receive", "seperate": "separate", "occured": "occurred",
+	"querry": "query", "reponse": "response", "resposne": "response",
+	"reqeust": "request", "requset": "request", "udpate": "update",
+	"delte": "delete", "remvoe": "remove", "conection": "connection",
+	"varible": "variable", "vairable": "variable",
+}
+
+// Suggestions proposes alternative queries for a search that returned
+// nothing, most promising first: the query with typos corrected, with
+// quotes relaxed, with a technology word moved into a [tag] filter, and
+// with fewer terms.  tagOf maps a lower-case query word to a Stack
+// Overflow tag ("" if it is not one); it may be nil.
+func Suggestions(query string, tagOf func(word string) string) []string {
+	query = strings.Join(strings.Fields(query), " ")
+	var out []string
+	seen := map[string]bool{strings.ToLower(query): true}
+	add := func(s string) {
+		s = strings.Join(strings.Fields(s), " ")
+		if s == "" || seen[strings.ToLower(s)] || len(out) >= maxSuggestions {
+			return
+		}
+		seen[strings.ToLower(s)] = true
+		out = append(out, s)
+	}
+
+	// Typo fixes.
+	words := strings.Fields(query)
+	fixed := make([]string, len(words))
+	for i, w := range words {
+		fixed[i] = w
+		if c, ok := commonTypos[strings.ToLower(w)]; ok {
+			fixed[i] = c
+		}
+	}
+	add(strings.Join(fixed, " "))
+	words = fixed
+
+	// Exact-phrase quotes are the most common reason for zero hits.
+	if strings.ContainsAny(query, `"'`) {
+		words = strings.Fields(strings.NewReplacer(`"`, "", "'", "").Replace(strings.Join(words, " ")))
+		add(strings.Join(words, " "))
+	}
+
+	// "reverse string go" → "[go] reverse string"
+	if tagOf != nil {
+		for i, w := range words {
+			tag := tagOf(strings.ToLower(w))
+			if tag == "" || len(words) < 2 {
+				continue
+			}
+			rest := append(append([]string{}, words[:i]...), words[i+1:]...)
+			add("[" + tag + "] " + strings.Join(rest, " "))
+			break
+		}
+	}
+
+	// Fewer terms: drop the last one, then keep only the three longest
+	// (longer words tend to be the specific ones).
+	if len(words) > 2 {
+		add(strings.Join(words[:len(words)-1], " "))
+	}
+	if len(words) > 3 {
+		idx := make([]int, len(words))
+		for i := range idx {
+			idx[i] = i
+		}
+		sort.SliceStable(idx, func(a, b int) bool { return len(words[idx[a]]) > len(words[idx[b]]) })
+		keep := idx[:3]
+		sort.Ints(keep)
+		var short []string
+		for _, i := range keep {
+			short = append(short, words[i])
+		}
+		add(strings.Join(short, " "))
+	}
+	return out
+}
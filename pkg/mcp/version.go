@@ -0,0 +1,190 @@
+package mcp
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// VersionTarget is the language version the user works with, as given
+// to `flo ask --version`, e.g. "go1.22" or "python3.12".  Posts that
+// mention a version it lacks (a newer release, or another major version
+// of a language that broke compatibility) are demoted.
+type VersionTarget struct {
+	Lang  string // canonical language name: "go", "python", ...
+	Parts []int  // version numbers, e.g. [1 22]
+}
+
+// VersionCompat is how a post relates to a VersionTarget.
+type VersionCompat int
+
+const (
+	// VersionUnknown means the post mentions no version of the language.
+	VersionUnknown VersionCompat = iota
+	// VersionMatch means the post mentions a version the target has.
+	VersionMatch
+	// VersionMismatch means the post only mentions versions the target
+	// lacks.
+	VersionMismatch
+)
+
+// versionLang describes how a language's versions are written and
+// compared.
+type versionLang struct {
+	tag string         // the Stack Overflow tag
+	re  *regexp.Regexp // matches a mention; group 1 is the version
+	// strictMajor marks languages whose major versions are mutually
+	// incompatible (Python 2/3), so older majors do not match either.
+	strictMajor bool
+}
+
+var versionLangs = map[string]versionLang{
+	"go":     {tag: "go", re: regexp.MustCompile(`(?i)\bgo(?:lang)?[ -]?(1\.\d+)`)},
+	"python": {tag: "python", re: regexp.MustCompile(`(?i)\bpython[ -]?([23](?:\.\d+)?)\b`), strictMajor: true},
+	"java":   {tag: "java", re: regexp.MustCompile(`(?i)\b(?:java|jdk)[ -]?((?:1\.)?\d+)\b`)},
+	"node":   {tag: "node.js", re: regexp.MustCompile(`(?i)\bnode(?:\.?js)?[ -]?v?(\d+)(?:\.\d+)*\b`)},
+	"ruby":   {tag: "ruby", re: regexp.MustCompile(`(?i)\bruby[ -]?(\d\.\d+)`), strictMajor: true},
+	"php":    {tag: "php", re: regexp.MustCompile(`(?i)\bphp[ -]?(\d(?:\.\d+)?)\b`), strictMajor: true},
+}
+
+// versionAliases maps the spellings accepted by ParseVersionTarget to
+// canonical language names.
+var versionAliases = map[string]string{
+	"go": "go", "golang": "go",
+	"python": "python", "py": "python",
+	"java": "java", "jdk": "java",
+	"node": "node", "nodejs": "node", "node.js": "node",
+	"ruby": "ruby", "php": "php",
+}
+
+var reVersionTarget = regexp.MustCompile(`^([a-z.]+?)[ -]?v?(\d+(?:\.\d+)*)$`)
+
+// ParseVersionTarget parses a language and version such as "go1.22",
+// "python3.12", "python3", "java17" or "node20".
+func ParseVersionTarget(s string) (*VersionTarget, error) {
+	m := reVersionTarget.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if m == nil {
+		return nil, fmt.Errorf("invalid version %q (want e.g. go1.22 or python3.12)", s)
+	}
+	lang, ok := versionAliases[m[1]]
+	if !ok {
+		return nil, fmt.Errorf("unsupported language %q in version %q (supported: go, python, java, node, ruby, php)", m[1], s)
+	}
+	return &VersionTarget{Lang: lang, Parts: parseVersion(lang, m[2])}, nil
+}
+
+// parseVersion splits a dotted version, reading Java's "1.8" as 8.
+func parseVersion(lang, v string) []int {
+	var parts []int
+	for _, f := range strings.Split(v, ".") {
+		n, _ := strconv.Atoi(f)
+		parts = append(parts, n)
+	}
+	if lang == "java" && len(parts) > 1 && parts[0] == 1 {
+		parts = parts[1:]
+	}
+	return parts
+}
+
+// String formats t like its flag value.
+func (t *VersionTarget) String() string {
+	nums := make([]string, len(t.Parts))
+	for i, n := range t.Parts {
+		nums[i] = strconv.Itoa(n)
+	}
+	return t.Lang + strings.Join(nums, ".")
+}
+
+// Tag is the Stack Overflow tag of t's language.
+func (t *VersionTarget) Tag() string {
+	return versionLangs[t.Lang].tag
+}
+
+// Classify reports how text relates to t, with the first mismatching
+// mention when it does not match.  A single compatible mention is
+// enough to match: posts often cover several versions.
+func (t *VersionTarget) Classify(text string) (VersionCompat, string) {
+	l := versionLangs[t.Lang]
+	compat, mismatch := VersionUnknown, ""
+	for _, m := range l.re.FindAllStringSubmatch(text, -1) {
+		if t.accepts(parseVersion(t.Lang, m[1]), l.strictMajor) {
+			return VersionMatch, ""
+		}
+		if compat == VersionUnknown {
+			compat, mismatch = VersionMismatch, strings.TrimSpace(m[0])
+		}
+	}
+	return compat, mismatch
+}
+
+// accepts reports whether a post about version v applies to t: v is not
+// newer than t (comparing the parts both give) and, for strictMajor
+// languages, shares its major version.
+func (t *VersionTarget) accepts(v []int, strictMajor bool) bool {
+	if strictMajor && v[0] != t.Parts[0] {
+		return false
+	}
+	for i := 0; i < len(v) && i < len(t.Parts); i++ {
+		if v[i] != t.Parts[i] {
+			return v[i] < t.Parts[i]
+		}
+	}
+	return true
+}
+
+// QuestionCompat classifies q by its title, tags and body.
+func (t *VersionTarget) QuestionCompat(q *QuestionData) (VersionCompat, string) {
+	return t.Classify(q.Title + "\n" + strings.Join(q.Tags, " ") + "\n" + q.BodyMarkdown)
+}
+
+// FilterByVersion drops the results about versions t lacks — unless that
+// would drop them all — and moves results that match t to the front.
+// It returns how many results were dropped.
+func FilterByVersion(resp *SOResponse, t *VersionTarget) int {
+	if resp == nil || t == nil {
+		return 0
+	}
+	compat := make(map[int]VersionCompat, len(resp.Items))
+	var kept []QuestionData
+	for i := range resp.Items {
+		c, _ := t.QuestionCompat(&resp.Items[i])
+		if c != VersionMismatch {
+			compat[len(kept)] = c
+			kept = append(kept, resp.Items[i])
+		}
+	}
+	if len(kept) == 0 {
+		return 0
+	}
+	order := make([]int, len(kept))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return compat[order[i]] == VersionMatch && compat[order[j]] != VersionMatch
+	})
+	items := make([]QuestionData, len(kept))
+	for i, k := range order {
+		items[i] = kept[k]
+	}
+	dropped := len(resp.Items) - len(items)
+	resp.Items = items
+	return dropped
+}
+
+// SortAnswersForVersion is SortAnswers with the answers about versions
+// t lacks moved to the end.
+func SortAnswersForVersion(answers []AnswerData, t *VersionTarget) []AnswerData {
+	sorted := SortAnswers(answers)
+	if t == nil {
+		return sorted
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ci, _ := t.Classify(sorted[i].BodyMarkdown)
+		cj, _ := t.Classify(sorted[j].BodyMarkdown)
+		return ci != VersionMismatch && cj == VersionMismatch
+	})
+	return sorted
+}
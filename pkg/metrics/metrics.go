@@ -0,0 +1,171 @@
+// Package metrics counts what a long-running flo serve does — the
+// requests it answers, the backend calls it makes and how long they
+// take, and how often the cache answers instead — and exposes the
+// counts in the Prometheus text format, for teams running flo as a
+// shared service.
+//
+// Unlike telemetry, nothing is kept on disk: the counts start at zero
+// with the process, as Prometheus expects of counters.  Labels carry
+// only method and call names and error classes, never queries or IDs.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Buckets are the upper bounds of the latency histogram buckets.
+var Buckets = []time.Duration{
+	100 * time.Millisecond,
+	300 * time.Millisecond,
+	time.Second,
+	3 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+}
+
+// histogram counts durations by bucket; Write adds the counts up, as
+// Prometheus wants them.
+type histogram struct {
+	counts []int64 // per bucket, the last for past every bound
+	sum    time.Duration
+	n      int64
+}
+
+func (h *histogram) observe(d time.Duration) {
+	if h.counts == nil {
+		h.counts = make([]int64, len(Buckets)+1)
+	}
+	i := sort.Search(len(Buckets), func(i int) bool { return d <= Buckets[i] })
+	h.counts[i]++
+	h.sum += d
+	h.n++
+}
+
+// pair keys a counter by its two label values.
+type pair struct{ a, b string }
+
+// Set holds the counts of one process.  It is safe for concurrent use,
+// and serves them over HTTP as an http.Handler.
+type Set struct {
+	// Cache, when set, reports the cache's lookups so far, read at
+	// every scrape.
+	Cache func() (hits, misses int64)
+
+	mu       sync.Mutex
+	requests map[pair]int64 // by method and status
+	latency  map[string]*histogram
+	errors   map[pair]int64 // by call and error class
+}
+
+// New returns an empty Set.
+func New() *Set {
+	return &Set{requests: map[pair]int64{}, latency: map[string]*histogram{}, errors: map[pair]int64{}}
+}
+
+// Request counts a request for method answered with status: "ok", or
+// the error code it failed with.
+func (s *Set) Request(method, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests[pair{method, status}]++
+}
+
+// Call counts a backend call, e.g. "mcp so_search", that took d and
+// ended with the error class, "" for success.
+func (s *Set) Call(name string, d time.Duration, class string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h := s.latency[name]
+	if h == nil {
+		h = &histogram{}
+		s.latency[name] = h
+	}
+	h.observe(d)
+	if class != "" {
+		s.errors[pair{name, class}]++
+	}
+}
+
+// Write writes the counts to w in the Prometheus text format.
+func (s *Set) Write(w io.Writer) error {
+	var b strings.Builder
+	s.mu.Lock()
+	header(&b, "flo_requests_total", "counter", "Editor requests answered, by method and status (ok or the error code).")
+	for _, k := range sortedPairs(s.requests) {
+		fmt.Fprintf(&b, "flo_requests_total{method=%s,status=%s} %d\n", quote(k.a), quote(k.b), s.requests[k])
+	}
+	header(&b, "flo_backend_call_duration_seconds", "histogram", "Backend calls, by call, and how long they took.")
+	names := make([]string, 0, len(s.latency))
+	for name := range s.latency {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		h := s.latency[name]
+		var cum int64
+		for i, n := range h.counts {
+			cum += n
+			le := "+Inf"
+			if i < len(Buckets) {
+				le = strconv.FormatFloat(Buckets[i].Seconds(), 'g', -1, 64)
+			}
+			fmt.Fprintf(&b, "flo_backend_call_duration_seconds_bucket{call=%s,le=%s} %d\n", quote(name), quote(le), cum)
+		}
+		fmt.Fprintf(&b, "flo_backend_call_duration_seconds_sum{call=%s} %g\n", quote(name), h.sum.Seconds())
+		fmt.Fprintf(&b, "flo_backend_call_duration_seconds_count{call=%s} %d\n", quote(name), h.n)
+	}
+	header(&b, "flo_backend_call_errors_total", "counter", "Failed backend calls, by call and error class.")
+	for _, k := range sortedPairs(s.errors) {
+		fmt.Fprintf(&b, "flo_backend_call_errors_total{call=%s,class=%s} %d\n", quote(k.a), quote(k.b), s.errors[k])
+	}
+	s.mu.Unlock()
+
+	if s.Cache != nil {
+		hits, misses := s.Cache()
+		header(&b, "flo_cache_hits_total", "counter", "Cache lookups answered from the cache.")
+		fmt.Fprintf(&b, "flo_cache_hits_total %d\n", hits)
+		header(&b, "flo_cache_misses_total", "counter", "Cache lookups that went to the backend.")
+		fmt.Fprintf(&b, "flo_cache_misses_total %d\n", misses)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ServeHTTP answers a scrape.
+func (s *Set) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = s.Write(w)
+}
+
+// header writes the HELP and TYPE lines of a metric.
+func header(b *strings.Builder, name, typ, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// quote quotes a label value as the text format wants it.
+func quote(v string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(v) + `"`
+}
+
+// sortedPairs returns the keys of m in order.
+func sortedPairs(m map[pair]int64) []pair {
+	keys := make([]pair, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].a != keys[j].a {
+			return keys[i].a < keys[j].a
+		}
+		return keys[i].b < keys[j].b
+	})
+	return keys
+}
@@ -0,0 +1,221 @@
+// Package mockserver is a stand-in for the Stack Overflow MCP server:
+// it speaks MCP over stdio and answers so_search and get_content from
+// canned fixtures, so flo can be developed and tested without network
+// access or an OAuth login.
+//
+//	flo --mcp-cmd "flo dev mock"
+//
+// Fixtures are a JSON array of questions in the Stack Exchange shape
+// (see mcp.QuestionData), each with all of its answers.  Searches embed
+// the top answers like the real server does, unless a question sets
+// "search_answers": false, in which case flo has to fetch them with
+// get_content.  Queries containing "empty" match nothing.
+package mockserver
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode"
+
+	mcpprotocol "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+)
+
+//go:embed fixtures.json
+var defaultFixtures []byte
+
+// searchAnswers is how many answers a search result embeds.
+const searchAnswers = 2
+
+// Fixture is one canned question.
+type Fixture struct {
+	mcp.QuestionData
+	// SearchAnswers, when false, leaves answers out of search results.
+	SearchAnswers *bool `json:"search_answers,omitempty"`
+}
+
+// Options configures a Server.
+type Options struct {
+	// Fixtures replaces the built-in questions when non-empty.
+	Fixtures []Fixture
+	// Latency delays every tool call, to mimic a remote server.
+	Latency time.Duration
+	// ExpireAfter, when positive, refuses the tool calls after that
+	// many with HTTP 401, as the server does once a login expires.  A
+	// new server (a restarted bridge) starts counting again.
+	ExpireAfter int
+}
+
+// Server answers tool calls from fixtures.
+type Server struct {
+	fixtures    []Fixture
+	latency     time.Duration
+	expireAfter int64
+	calls       atomic.Int64
+}
+
+// New returns a server over opts.Fixtures, or the built-in ones.
+func New(opts Options) (*Server, error) {
+	fixtures := opts.Fixtures
+	if len(fixtures) == 0 {
+		var err error
+		if fixtures, err = ParseFixtures(defaultFixtures); err != nil {
+			return nil, err
+		}
+	}
+	return &Server{fixtures: fixtures, latency: opts.Latency, expireAfter: int64(opts.ExpireAfter)}, nil
+}
+
+// ParseFixtures decodes a fixtures file.
+func ParseFixtures(data []byte) ([]Fixture, error) {
+	var fixtures []Fixture
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("parse fixtures: %w", err)
+	}
+	return fixtures, nil
+}
+
+// Serve speaks MCP on in and out until in is closed or ctx is done.
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	srv := server.NewMCPServer("flo-mock", "1.0.0", server.WithToolCapabilities(false))
+	srv.AddTool(mcpprotocol.NewTool("so_search",
+		mcpprotocol.WithDescription("Search the canned Stack Overflow questions."),
+		mcpprotocol.WithString("query", mcpprotocol.Required()),
+	), s.handle(s.Search))
+	srv.AddTool(mcpprotocol.NewTool("get_content",
+		mcpprotocol.WithDescription("Fetch a canned question (SO_Q<id>) or answer (SO_A<id>)."),
+		mcpprotocol.WithString("query", mcpprotocol.Required()),
+	), s.handle(s.Content))
+	return server.NewStdioServer(srv).Listen(ctx, in, out)
+}
+
+// handle adapts a query function to a tool handler.
+func (s *Server) handle(fn func(query string) (*mcp.SOResponse, error)) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcpprotocol.CallToolRequest) (*mcpprotocol.CallToolResult, error) {
+		if s.latency > 0 {
+			select {
+			case <-time.After(s.latency):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		if n := s.calls.Add(1); s.expireAfter > 0 && n > s.expireAfter {
+			return nil, errors.New("HTTP 401: invalid_token: the access token expired")
+		}
+		resp, err := fn(req.GetString("query", ""))
+		if err != nil {
+			return mcpprotocol.NewToolResultError(err.Error()), nil
+		}
+		data, err := json.Marshal(resp)
+		if err != nil {
+			return nil, err
+		}
+		return mcpprotocol.NewToolResultText(string(data)), nil
+	}
+}
+
+// Search returns the fixtures sharing words with query, best match
+// first, with their top answers embedded.
+func (s *Server) Search(query string) (*mcp.SOResponse, error) {
+	resp := &mcp.SOResponse{Items: []mcp.QuestionData{}}
+	words := tokens(query)
+	if len(words) == 0 || strings.Contains(strings.ToLower(query), "empty") {
+		return resp, nil
+	}
+
+	type hit struct {
+		f     *Fixture
+		score int
+	}
+	var hits []hit
+	for i := range s.fixtures {
+		f := &s.fixtures[i]
+		vocab := make(map[string]bool)
+		for _, w := range tokens(f.Title + " " + strings.Join(f.Tags, " ")) {
+			vocab[w] = true
+		}
+		score := 0
+		for _, w := range words {
+			if vocab[w] {
+				score++
+			}
+		}
+		if score > 0 {
+			hits = append(hits, hit{f, score})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].score > hits[j].score })
+
+	for _, h := range hits {
+		q := h.f.QuestionData
+		q.Answers = nil
+		if h.f.SearchAnswers == nil || *h.f.SearchAnswers {
+			sorted := mcp.SortAnswers(h.f.Answers)
+			q.Answers = sorted[:min(searchAnswers, len(sorted))]
+		}
+		resp.Items = append(resp.Items, q)
+	}
+	return resp, nil
+}
+
+// tokens splits s into lower-case words; tag punctuation such as the
+// dots in "node.js" is kept.
+func tokens(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(".#+-", r)
+	})
+}
+
+// Content returns the question or answer a get_content reference names.
+func (s *Server) Content(ref string) (*mcp.SOResponse, error) {
+	kind, num, ok := strings.Cut(ref, "_")
+	if !ok || kind != "SO" || len(num) < 2 {
+		return nil, fmt.Errorf("unsupported reference %q", ref)
+	}
+	id, err := strconv.Atoi(num[1:])
+	if err != nil {
+		return nil, fmt.Errorf("unsupported reference %q", ref)
+	}
+	for _, f := range s.fixtures {
+		switch num[0] {
+		case 'Q':
+			if f.QuestionID == id {
+				return &mcp.SOResponse{Items: []mcp.QuestionData{f.QuestionData}}, nil
+			}
+		case 'A':
+			for _, a := range f.Answers {
+				if a.AnswerID == id {
+					return &mcp.SOResponse{Items: []mcp.QuestionData{answerItem(&f.QuestionData, &a)}}, nil
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("%s not found", ref)
+}
+
+// answerItem is a as get_content returns it: an item with the answer
+// fields set and the question's title.
+func answerItem(q *mcp.QuestionData, a *mcp.AnswerData) mcp.QuestionData {
+	return mcp.QuestionData{
+		Owner:            a.Owner,
+		Score:            a.Score,
+		CreationDate:     a.CreationDate,
+		LastActivityDate: a.LastActivityDate,
+		QuestionID:       q.QuestionID,
+		BodyMarkdown:     a.BodyMarkdown,
+		Link:             a.Link,
+		Title:            q.Title,
+		AnswerID:         a.AnswerID,
+		IsAccepted:       a.IsAccepted,
+	}
+}
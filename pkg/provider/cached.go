@@ -0,0 +1,412 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/cache"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+)
+
+// cached serves repeated calls from a response cache.
+type cached struct {
+	next  Provider
+	cache *cache.Cache
+
+	onRefresh func(Refresh)
+	onStale   func(age time.Duration, err error)
+	saving    func() bool
+	refreshed sync.Map // keys refreshed in the background, to do each once
+}
+
+// fetchFunc fetches a response from the wrapped provider.
+type fetchFunc func(ctx context.Context) (*mcp.SOResponse, error)
+
+// Cached returns p with its results stored in c.  Entries use the MCP
+// tool names and arguments as keys and the tool's response envelope as
+// values, so caches written before providers existed stay valid and
+// every backend shares them.  Empty results are not cached.
+//
+// A cached response older than c.SoftTTL is returned and refetched in
+// the background; see Refresher.
+//
+// A nil p serves from the cache alone, reporting misses as ErrNotFound —
+// an offline source.  A nil c returns p unchanged.
+func Cached(p Provider, c *cache.Cache) Provider {
+	if c == nil {
+		return p
+	}
+	return &cached{next: p, cache: c}
+}
+
+// Search also serves a query from the entry of an earlier, similar
+// search when the cache has a query index: one whose terms (see
+// mcp.QueryTerms) overlap by at least MinQuerySimilarity.
+func (c *cached) Search(ctx context.Context, query string) (*mcp.SOResponse, error) {
+	key := c.key("so_search", query)
+	terms := mcp.QueryTerms(query)
+	fetch := func(ctx context.Context) (*mcp.SOResponse, error) {
+		resp, err := c.next.Search(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		for i := range resp.Items {
+			c.putAnswers(ctx, resp.Items[i].Answers)
+		}
+		if c.cache.Queries != nil && c.cache.Site == "" && len(resp.Items) > 0 {
+			if err := c.cache.Queries.Add(terms, key, time.Now()); err != nil {
+				slog.Warn("query index write failed", "err", err)
+			}
+		}
+		return resp, nil
+	}
+	if resp, ok := c.get(ctx, "so_search", key, 0, fetch); ok {
+		return resp, nil
+	}
+	if resp := c.similar(ctx, terms); resp != nil {
+		return resp, nil
+	}
+	return c.fetch(ctx, "so_search", key, fetch)
+}
+
+// MinQuerySimilarity is the share of terms two searches must have in
+// common for one to be served from the other's cache entry.  With three
+// terms, only the same three match.
+const MinQuerySimilarity = 0.8
+
+// similar returns the cached response of the search most like terms,
+// or nil.  The query index holds Stack Overflow searches only.
+func (c *cached) similar(ctx context.Context, terms []string) *mcp.SOResponse {
+	if c.cache.Queries == nil || c.cache.Site != "" {
+		return nil
+	}
+	key, sim, ok := c.cache.Queries.Nearest(terms, MinQuerySimilarity, time.Now().Add(-c.cache.TTL()))
+	if !ok {
+		return nil
+	}
+	// Refetching would need the other search's query, which the index
+	// does not keep; the entry is refreshed when that search is repeated.
+	resp, ok := c.get(ctx, "so_search", key, 0, nil)
+	if !ok {
+		return nil
+	}
+	slog.Info("served from cache for a similar search", "terms", terms, "similarity", sim)
+	return resp
+}
+
+func (c *cached) GetQuestion(ctx context.Context, id int) (*mcp.QuestionData, error) {
+	resp, err := c.lookup(ctx, "get_content", fmt.Sprintf("SO_Q%d", id), 0, func(ctx context.Context) (*mcp.SOResponse, error) {
+		q, err := c.next.GetQuestion(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		c.putAnswers(ctx, q.Answers)
+		return &mcp.SOResponse{Items: []mcp.QuestionData{*q}}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return first(resp)
+}
+
+func (c *cached) GetAnswer(ctx context.Context, id int) (*mcp.AnswerData, error) {
+	resp, err := c.lookup(ctx, "get_content", fmt.Sprintf("SO_A%d", id), 0, func(ctx context.Context) (*mcp.SOResponse, error) {
+		a, err := c.next.GetAnswer(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.SOResponse{Items: []mcp.QuestionData{itemFromAnswer(a)}}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	item, err := first(resp)
+	if err != nil {
+		return nil, err
+	}
+	a := mcp.AnswerFromItem(*item)
+	return &a, nil
+}
+
+// GetAnswers caches the answers AllAnswers fetches from the wrapped
+// provider under a key of its own, since they may be more than the
+// question's get_content entry holds.
+func (c *cached) GetAnswers(ctx context.Context, id int) ([]mcp.AnswerData, error) {
+	resp, err := c.lookup(ctx, "answers", fmt.Sprintf("SO_Q%d", id), id, func(ctx context.Context) (*mcp.SOResponse, error) {
+		answers, err := AllAnswers(ctx, c.next, id)
+		if err != nil {
+			return nil, err
+		}
+		c.putAnswers(ctx, answers)
+		resp := &mcp.SOResponse{}
+		for i := range answers {
+			resp.Items = append(resp.Items, itemFromAnswer(&answers[i]))
+		}
+		return resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	answers := make([]mcp.AnswerData, len(resp.Items))
+	for i, item := range resp.Items {
+		answers[i] = mcp.AnswerFromItem(item)
+	}
+	return answers, nil
+}
+
+// GetRelated caches the candidates RelatedCandidates fetches from the
+// wrapped provider.
+func (c *cached) GetRelated(ctx context.Context, q *mcp.QuestionData) (*mcp.SOResponse, error) {
+	return c.lookup(ctx, "related", fmt.Sprintf("SO_Q%d", q.QuestionID), 0, func(ctx context.Context) (*mcp.SOResponse, error) {
+		return RelatedCandidates(ctx, c.next, q)
+	})
+}
+
+// TagQuestions is not cached: a digest asks for what is new.
+func (c *cached) TagQuestions(ctx context.Context, tag string, since time.Time, minScore, limit int) (*mcp.SOResponse, error) {
+	if c.next == nil {
+		return nil, ErrNotFound
+	}
+	return NewQuestions(ctx, c.next, tag, since, minScore, limit)
+}
+
+// UnansweredQuestions is not cached either: answerers want the
+// questions still open now.
+func (c *cached) UnansweredQuestions(ctx context.Context, tag string, noAnswers bool, limit int) (*mcp.SOResponse, error) {
+	if c.next == nil {
+		return nil, ErrNotFound
+	}
+	return Unanswered(ctx, c.next, tag, noAnswers, limit)
+}
+
+// BountiedQuestions is not cached: bounties close.
+func (c *cached) BountiedQuestions(ctx context.Context, tag string, limit int) (*mcp.SOResponse, error) {
+	if c.next == nil {
+		return nil, ErrUnsupported
+	}
+	return Bountied(ctx, c.next, tag, limit)
+}
+
+// GetAuthor is not cached: the command layer keeps authors for the
+// session.
+func (c *cached) GetAuthor(ctx context.Context, id int) (*Author, error) {
+	if c.next == nil {
+		return nil, ErrUnsupported
+	}
+	return AuthorOf(ctx, c.next, id)
+}
+
+// TopAnswerers and TopAnswers are not cached: leaderboards move.
+func (c *cached) TopAnswerers(ctx context.Context, tag string, allTime bool, limit int) ([]Answerer, error) {
+	if c.next == nil {
+		return nil, ErrUnsupported
+	}
+	return TagAnswerers(ctx, c.next, tag, allTime, limit)
+}
+
+func (c *cached) TopAnswers(ctx context.Context, id int, tag string, since time.Time, limit int) ([]mcp.AnswerData, error) {
+	if c.next == nil {
+		return nil, ErrUnsupported
+	}
+	return UserTopAnswers(ctx, c.next, id, tag, since, limit)
+}
+
+// GetComments is not cached: comments come and go.
+func (c *cached) GetComments(ctx context.Context, ids []int) ([]mcp.CommentData, error) {
+	if c.next == nil {
+		return nil, ErrUnsupported
+	}
+	return Comments(ctx, c.next, ids)
+}
+
+// StartKeepalive forwards to the wrapped provider when it has one.
+func (c *cached) StartKeepalive(interval time.Duration) {
+	if k, ok := c.next.(interface{ StartKeepalive(time.Duration) }); ok {
+		k.StartKeepalive(interval)
+	}
+}
+
+// key returns the cache key of tool called with query.  Stack Overflow
+// keys carry no site, as they did before flo served other sites.
+func (c *cached) key(tool, query string) string {
+	args := map[string]any{"query": query}
+	if c.cache.Site != "" {
+		args["site"] = c.cache.Site
+	}
+	return cache.Key(tool, args)
+}
+
+// toolNamespaces files each kind of lookup in its cache namespace.
+var toolNamespaces = map[string]string{
+	"so_search":   cache.NSSearches,
+	"get_content": cache.NSPosts,
+	"answers":     cache.NSPosts,
+	"related":     cache.NSRelated,
+}
+
+// lookup returns the cached response for tool and query, or calls fetch
+// and stores a non-empty result.  questionID is set when the response
+// lists that question's answers (see refresh).
+func (c *cached) lookup(ctx context.Context, tool, query string, questionID int, fetch fetchFunc) (*mcp.SOResponse, error) {
+	key := c.key(tool, query)
+	if resp, ok := c.get(ctx, tool, key, questionID, fetch); ok {
+		return resp, nil
+	}
+	return c.fetch(ctx, tool, key, fetch)
+}
+
+// fetch calls fetch and stores a non-empty result under key.  While
+// requests are being saved, an expired response cached under key is
+// served instead.
+func (c *cached) fetch(ctx context.Context, tool, key string, fetch fetchFunc) (*mcp.SOResponse, error) {
+	if c.next == nil {
+		return nil, ErrNotFound
+	}
+	if c.saving != nil && c.saving() {
+		if resp, age := c.stale(ctx, key); resp != nil {
+			slog.Info("serving an expired cached copy to save requests", "tool", tool, "age", age.Round(time.Minute))
+			return resp, nil
+		}
+	}
+	resp, err := fetch(ctx)
+	if err != nil {
+		if stale := c.expired(ctx, tool, key, err); stale != nil {
+			return stale, nil
+		}
+		return nil, err
+	}
+	c.store(ctx, tool, key, resp)
+	return resp, nil
+}
+
+// StaleReporter is implemented by the providers Cached returns.
+type StaleReporter interface {
+	// OnStale sets the function told when an expired response of the
+	// given age is served because the backend failed with err.
+	OnStale(f func(age time.Duration, err error))
+}
+
+// OnStale implements StaleReporter.  Set it before the first call.
+func (c *cached) OnStale(f func(age time.Duration, err error)) {
+	c.onStale = f
+}
+
+// Saver is implemented by the providers Cached returns.
+type Saver interface {
+	// SaveRequests sets the function asked before each fetch whether to
+	// save backend requests, as when the API quota runs low: while it
+	// says so, an expired cached response is served rather than
+	// refetched.  Set it before the first call.
+	SaveRequests(f func() bool)
+}
+
+// SaveRequests implements Saver.
+func (c *cached) SaveRequests(f func() bool) {
+	c.saving = f
+}
+
+// stale returns the non-empty response cached under key, expired or
+// not, with its age; nil when there is none.
+func (c *cached) stale(ctx context.Context, key string) (*mcp.SOResponse, time.Duration) {
+	data, age, ok := c.cache.GetExpired(ctx, key)
+	if !ok {
+		return nil, 0
+	}
+	resp, err := mcp.ParseResponse(string(data))
+	if err != nil || len(resp.Items) == 0 {
+		return nil, 0
+	}
+	return resp, age
+}
+
+// expired returns the expired response cached under key when the
+// cache's StaleIfError allows it and err is a backend failure, or nil.
+func (c *cached) expired(ctx context.Context, tool, key string, err error) *mcp.SOResponse {
+	if !c.cache.StaleIfError || errors.Is(err, ErrNotFound) || ctx.Err() == context.Canceled {
+		return nil
+	}
+	resp, age := c.stale(ctx, key)
+	if resp == nil {
+		return nil
+	}
+	slog.Warn("backend failed; serving an expired cached copy", "tool", tool, "age", age.Round(time.Minute), "err", err)
+	if c.onStale != nil {
+		c.onStale(age, err)
+	}
+	return resp
+}
+
+// putAnswers stores each of answers as get_content returns it, so
+// opening one again — from the history, a bookmark or a related
+// question — is served from the cache however it was first fetched.
+func (c *cached) putAnswers(ctx context.Context, answers []mcp.AnswerData) {
+	for i := range answers {
+		if answers[i].AnswerID == 0 || answers[i].BodyMarkdown == "" {
+			continue
+		}
+		key := c.key("get_content", fmt.Sprintf("SO_A%d", answers[i].AnswerID))
+		c.put(ctx, cache.NSPosts, key, &mcp.SOResponse{Items: []mcp.QuestionData{itemFromAnswer(&answers[i])}}, c.cache.PostTTL)
+	}
+}
+
+// get returns the non-empty response cached under key.  When it is
+// older than the cache's SoftTTL, refetch, if not nil, refreshes it in
+// the background.
+func (c *cached) get(ctx context.Context, tool, key string, questionID int, refetch fetchFunc) (*mcp.SOResponse, bool) {
+	data, age, ok := c.cache.GetAge(ctx, key)
+	if !ok {
+		return nil, false
+	}
+	resp, err := mcp.ParseResponse(string(data))
+	if err != nil || len(resp.Items) == 0 {
+		return nil, false
+	}
+	slog.Info("served from cache", "tool", tool)
+	if refetch != nil && c.next != nil && c.cache.SoftTTL > 0 && age > c.cache.SoftTTL {
+		c.refresh(tool, key, data, questionID, refetch)
+	}
+	return resp, true
+}
+
+// store puts a non-empty response under key, in the namespace and for
+// the ttl of tool's lookups.
+func (c *cached) store(ctx context.Context, tool, key string, resp *mcp.SOResponse) {
+	ns, ttl := toolNamespaces[tool], time.Duration(0)
+	if ns == cache.NSPosts {
+		ttl = c.cache.PostTTL
+	}
+	c.put(ctx, ns, key, resp, ttl)
+}
+
+// put stores a non-empty response under key in namespace ns, for ttl
+// (zero: the cache's).
+func (c *cached) put(ctx context.Context, ns, key string, resp *mcp.SOResponse, ttl time.Duration) {
+	if len(resp.Items) == 0 {
+		return
+	}
+	if data, err := json.Marshal(resp); err == nil {
+		c.cache.PutIn(ctx, ns, key, data, ttl)
+	}
+}
+
+// itemFromAnswer is the inverse of mcp.AnswerFromItem: it wraps an
+// answer in the item shape get_content returns.
+func itemFromAnswer(a *mcp.AnswerData) mcp.QuestionData {
+	return mcp.QuestionData{
+		Owner:            a.Owner,
+		IsAccepted:       a.IsAccepted,
+		AnswerID:         a.AnswerID,
+		Score:            a.Score,
+		BodyMarkdown:     a.BodyMarkdown,
+		Link:             a.Link,
+		Title:            a.Title,
+		CreationDate:     a.CreationDate,
+		LastActivityDate: a.LastActivityDate,
+		QuestionID:       a.QuestionID,
+	}
+}
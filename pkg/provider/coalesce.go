@@ -0,0 +1,188 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+)
+
+// coalesced shares identical calls that are in flight at the same time.
+type coalesced struct {
+	next Provider
+
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+// call is a backend call shared by every caller asking for the same
+// thing while it runs.
+type call struct {
+	done    chan struct{}
+	val     any
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// Coalesced returns p with identical concurrent calls — the same search
+// or the same post, asked for by several clients of a server at once —
+// made once, the result going to each caller.  Callers get copies, so
+// they may change what they are given.
+//
+// The shared call is not tied to any one caller: it is cancelled only
+// when every caller waiting for it has given up.
+func Coalesced(p Provider) Provider {
+	return &coalesced{next: p, calls: map[string]*call{}}
+}
+
+// coalesce runs fn once for every concurrent caller with the same key.
+func coalesce[T any](ctx context.Context, c *coalesced, key string, fn func(context.Context) (T, error)) (T, error) {
+	c.mu.Lock()
+	cl, shared := c.calls[key]
+	if !shared {
+		cctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		cl = &call{done: make(chan struct{}), cancel: cancel}
+		c.calls[key] = cl
+		go func() {
+			defer cancel()
+			cl.val, cl.err = fn(cctx)
+			c.mu.Lock()
+			if c.calls[key] == cl {
+				delete(c.calls, key)
+			}
+			c.mu.Unlock()
+			close(cl.done)
+		}()
+	}
+	cl.waiters++
+	c.mu.Unlock()
+	if shared {
+		slog.Debug("coalesced with a call in flight", "call", key)
+	}
+
+	var zero T
+	select {
+	case <-cl.done:
+	case <-ctx.Done():
+		c.mu.Lock()
+		if cl.waiters--; cl.waiters == 0 {
+			// Later callers start afresh rather than join a cancelled call.
+			cl.cancel()
+			if c.calls[key] == cl {
+				delete(c.calls, key)
+			}
+		}
+		c.mu.Unlock()
+		return zero, ctx.Err()
+	}
+	if cl.err != nil {
+		return zero, cl.err
+	}
+	return clone(cl.val.(T))
+}
+
+// clone deep-copies v through JSON, the shape every result has on the
+// wire anyway.
+func clone[T any](v T) (T, error) {
+	var out T
+	data, err := json.Marshal(v)
+	if err != nil {
+		return out, err
+	}
+	err = json.Unmarshal(data, &out)
+	return out, err
+}
+
+func (c *coalesced) Search(ctx context.Context, query string) (*mcp.SOResponse, error) {
+	return coalesce(ctx, c, "search\x00"+query, func(ctx context.Context) (*mcp.SOResponse, error) {
+		return c.next.Search(ctx, query)
+	})
+}
+
+func (c *coalesced) GetQuestion(ctx context.Context, id int) (*mcp.QuestionData, error) {
+	return coalesce(ctx, c, fmt.Sprintf("question\x00%d", id), func(ctx context.Context) (*mcp.QuestionData, error) {
+		return c.next.GetQuestion(ctx, id)
+	})
+}
+
+func (c *coalesced) GetAnswer(ctx context.Context, id int) (*mcp.AnswerData, error) {
+	return coalesce(ctx, c, fmt.Sprintf("answer\x00%d", id), func(ctx context.Context) (*mcp.AnswerData, error) {
+		return c.next.GetAnswer(ctx, id)
+	})
+}
+
+func (c *coalesced) GetAnswers(ctx context.Context, id int) ([]mcp.AnswerData, error) {
+	return coalesce(ctx, c, fmt.Sprintf("answers\x00%d", id), func(ctx context.Context) ([]mcp.AnswerData, error) {
+		return AllAnswers(ctx, c.next, id)
+	})
+}
+
+func (c *coalesced) GetRelated(ctx context.Context, q *mcp.QuestionData) (*mcp.SOResponse, error) {
+	return coalesce(ctx, c, fmt.Sprintf("related\x00%d", q.QuestionID), func(ctx context.Context) (*mcp.SOResponse, error) {
+		return RelatedCandidates(ctx, c.next, q)
+	})
+}
+
+func (c *coalesced) TagQuestions(ctx context.Context, tag string, since time.Time, minScore, limit int) (*mcp.SOResponse, error) {
+	key := fmt.Sprintf("tag\x00%s\x00%d\x00%d\x00%d", tag, since.Unix(), minScore, limit)
+	return coalesce(ctx, c, key, func(ctx context.Context) (*mcp.SOResponse, error) {
+		return NewQuestions(ctx, c.next, tag, since, minScore, limit)
+	})
+}
+
+func (c *coalesced) UnansweredQuestions(ctx context.Context, tag string, noAnswers bool, limit int) (*mcp.SOResponse, error) {
+	key := fmt.Sprintf("unanswered\x00%s\x00%t\x00%d", tag, noAnswers, limit)
+	return coalesce(ctx, c, key, func(ctx context.Context) (*mcp.SOResponse, error) {
+		return Unanswered(ctx, c.next, tag, noAnswers, limit)
+	})
+}
+
+func (c *coalesced) BountiedQuestions(ctx context.Context, tag string, limit int) (*mcp.SOResponse, error) {
+	return coalesce(ctx, c, fmt.Sprintf("bounties\x00%s\x00%d", tag, limit), func(ctx context.Context) (*mcp.SOResponse, error) {
+		return Bountied(ctx, c.next, tag, limit)
+	})
+}
+
+func (c *coalesced) GetAuthor(ctx context.Context, id int) (*Author, error) {
+	return coalesce(ctx, c, fmt.Sprintf("author\x00%d", id), func(ctx context.Context) (*Author, error) {
+		return AuthorOf(ctx, c.next, id)
+	})
+}
+
+func (c *coalesced) TopAnswerers(ctx context.Context, tag string, allTime bool, limit int) ([]Answerer, error) {
+	return coalesce(ctx, c, fmt.Sprintf("answerers\x00%s\x00%t\x00%d", tag, allTime, limit), func(ctx context.Context) ([]Answerer, error) {
+		return TagAnswerers(ctx, c.next, tag, allTime, limit)
+	})
+}
+
+func (c *coalesced) TopAnswers(ctx context.Context, id int, tag string, since time.Time, limit int) ([]mcp.AnswerData, error) {
+	key := fmt.Sprintf("top-answers\x00%d\x00%s\x00%d\x00%d", id, tag, since.Unix(), limit)
+	return coalesce(ctx, c, key, func(ctx context.Context) ([]mcp.AnswerData, error) {
+		return UserTopAnswers(ctx, c.next, id, tag, since, limit)
+	})
+}
+
+func (c *coalesced) GetComments(ctx context.Context, ids []int) ([]mcp.CommentData, error) {
+	return coalesce(ctx, c, fmt.Sprintf("comments\x00%v", ids), func(ctx context.Context) ([]mcp.CommentData, error) {
+		return Comments(ctx, c.next, ids)
+	})
+}
+
+// StartKeepalive forwards to the wrapped provider when it has one.
+func (c *coalesced) StartKeepalive(interval time.Duration) {
+	if k, ok := c.next.(interface{ StartKeepalive(time.Duration) }); ok {
+		k.StartKeepalive(interval)
+	}
+}
+
+// OnRefresh forwards to the wrapped provider when it is a Refresher.
+func (c *coalesced) OnRefresh(f func(Refresh)) {
+	if r, ok := c.next.(Refresher); ok {
+		r.OnRefresh(f)
+	}
+}
@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/breaker"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/quota"
+)
+
+// Backend is a named provider in a Failover chain.
+type Backend struct {
+	Name string
+	Provider
+}
+
+// Failover calls the first of its backends whose circuit is not open,
+// moving on to the next when a call fails.  Failures other than "not
+// found", the caller's cancellation or deadline and an extra request
+// skipped to save the API quota count against a backend's circuit; a
+// backend that does not support a call is passed over without one.  The
+// last backend is always tried: there is nothing left to fail over to.
+type Failover struct {
+	Backends []Backend
+	Circuits *breaker.Breakers
+	// OnFailover, if set, is called when a call moves on from the
+	// backend name after err, so the UI can say why the source changed.
+	// err is nil when the backend's circuit was open.
+	OnFailover func(name string, err error)
+}
+
+// NewFailover returns a provider trying backends in order, keeping their
+// circuits in circuits.
+func NewFailover(circuits *breaker.Breakers, backends ...Backend) *Failover {
+	return &Failover{Backends: backends, Circuits: circuits}
+}
+
+// failover makes call on each backend in turn until one does not fail.
+func failover[T any](ctx context.Context, f *Failover, call func(Provider) (T, error)) (T, error) {
+	var (
+		zero    T
+		lastErr error
+	)
+	for i, b := range f.Backends {
+		last := i == len(f.Backends)-1
+		if !last && !f.Circuits.Allow(b.Name, time.Now()) {
+			slog.Debug("skipping backend with an open circuit", "backend", b.Name)
+			if f.OnFailover != nil {
+				f.OnFailover(b.Name, nil)
+			}
+			continue
+		}
+		v, err := call(b.Provider)
+		if err == nil || errors.Is(err, ErrNotFound) {
+			f.Circuits.Success(b.Name)
+			return v, err
+		}
+		if errors.Is(err, quota.ErrSkipped) {
+			return v, err
+		}
+		if errors.Is(err, ErrUnsupported) {
+			if lastErr == nil {
+				lastErr = err
+			}
+			continue
+		}
+		if errors.Is(err, context.Canceled) || ctx.Err() != nil {
+			// The caller gave up or ran out of time: the backend was not
+			// really tried, and neither would the rest be.
+			return zero, err
+		}
+		f.Circuits.Failure(b.Name, err, time.Now())
+		lastErr = err
+		if !last {
+			slog.Warn("backend failed; failing over", "backend", b.Name, "next", f.Backends[i+1].Name, "err", err)
+			if f.OnFailover != nil {
+				f.OnFailover(b.Name, err)
+			}
+		}
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("%w: no backend configured", ErrConnectionLost)
+	}
+	return zero, lastErr
+}
+
+func (f *Failover) Search(ctx context.Context, query string) (*mcp.SOResponse, error) {
+	return failover(ctx, f, func(p Provider) (*mcp.SOResponse, error) { return p.Search(ctx, query) })
+}
+
+func (f *Failover) GetQuestion(ctx context.Context, id int) (*mcp.QuestionData, error) {
+	return failover(ctx, f, func(p Provider) (*mcp.QuestionData, error) { return p.GetQuestion(ctx, id) })
+}
+
+func (f *Failover) GetAnswer(ctx context.Context, id int) (*mcp.AnswerData, error) {
+	return failover(ctx, f, func(p Provider) (*mcp.AnswerData, error) { return p.GetAnswer(ctx, id) })
+}
+
+func (f *Failover) GetAnswers(ctx context.Context, id int) ([]mcp.AnswerData, error) {
+	return failover(ctx, f, func(p Provider) ([]mcp.AnswerData, error) { return AllAnswers(ctx, p, id) })
+}
+
+func (f *Failover) GetRelated(ctx context.Context, q *mcp.QuestionData) (*mcp.SOResponse, error) {
+	return failover(ctx, f, func(p Provider) (*mcp.SOResponse, error) { return RelatedCandidates(ctx, p, q) })
+}
+
+func (f *Failover) TagQuestions(ctx context.Context, tag string, since time.Time, minScore, limit int) (*mcp.SOResponse, error) {
+	return failover(ctx, f, func(p Provider) (*mcp.SOResponse, error) {
+		return NewQuestions(ctx, p, tag, since, minScore, limit)
+	})
+}
+
+func (f *Failover) UnansweredQuestions(ctx context.Context, tag string, noAnswers bool, limit int) (*mcp.SOResponse, error) {
+	return failover(ctx, f, func(p Provider) (*mcp.SOResponse, error) {
+		return Unanswered(ctx, p, tag, noAnswers, limit)
+	})
+}
+
+func (f *Failover) BountiedQuestions(ctx context.Context, tag string, limit int) (*mcp.SOResponse, error) {
+	return failover(ctx, f, func(p Provider) (*mcp.SOResponse, error) { return Bountied(ctx, p, tag, limit) })
+}
+
+func (f *Failover) GetAuthor(ctx context.Context, id int) (*Author, error) {
+	return failover(ctx, f, func(p Provider) (*Author, error) { return AuthorOf(ctx, p, id) })
+}
+
+func (f *Failover) TopAnswerers(ctx context.Context, tag string, allTime bool, limit int) ([]Answerer, error) {
+	return failover(ctx, f, func(p Provider) ([]Answerer, error) { return TagAnswerers(ctx, p, tag, allTime, limit) })
+}
+
+func (f *Failover) TopAnswers(ctx context.Context, id int, tag string, since time.Time, limit int) ([]mcp.AnswerData, error) {
+	return failover(ctx, f, func(p Provider) ([]mcp.AnswerData, error) { return UserTopAnswers(ctx, p, id, tag, since, limit) })
+}
+
+func (f *Failover) GetComments(ctx context.Context, ids []int) ([]mcp.CommentData, error) {
+	return failover(ctx, f, func(p Provider) ([]mcp.CommentData, error) { return Comments(ctx, p, ids) })
+}
+
+// StartKeepalive forwards to every backend that has one.
+func (f *Failover) StartKeepalive(interval time.Duration) {
+	for _, b := range f.Backends {
+		if k, ok := b.Provider.(interface{ StartKeepalive(time.Duration) }); ok {
+			k.StartKeepalive(interval)
+		}
+	}
+}
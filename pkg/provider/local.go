@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"html"
+	"sort"
+	"strconv"
+
+	"github.com/ratnesh-maurya/flo/pkg/index"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+)
+
+// Local serves the posts of the local index — those viewed before,
+// imported from a data dump or loaded from an offline bundle — with no
+// network connection.  It knows only what the index holds: a question
+// comes with the answers indexed, which may be none.
+type Local struct {
+	idx *index.Index
+	// byQuestion holds the documents of each question, the question
+	// first when indexed.
+	byQuestion map[int][]*index.Doc
+}
+
+// NewLocal returns a provider serving idx, which must not change while
+// it is in use.
+func NewLocal(idx *index.Index) *Local {
+	l := &Local{idx: idx, byQuestion: make(map[int][]*index.Doc)}
+	for _, d := range idx.Docs() {
+		if d.Kind == index.KindQuestion {
+			l.byQuestion[d.QuestionID] = append([]*index.Doc{d}, l.byQuestion[d.QuestionID]...)
+		} else {
+			l.byQuestion[d.QuestionID] = append(l.byQuestion[d.QuestionID], d)
+		}
+	}
+	return l
+}
+
+// Search ranks the index against query and returns the questions of the
+// best posts, as many as a search of the API does.
+func (l *Local) Search(ctx context.Context, query string) (*mcp.SOResponse, error) {
+	resp := &mcp.SOResponse{}
+	seen := make(map[int]bool)
+	for _, h := range l.idx.Search(query, 0) {
+		id := h.Doc.QuestionID
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		resp.Items = append(resp.Items, *l.question(id))
+		if len(resp.Items) == searchPageSize {
+			break
+		}
+	}
+	return nonEmpty(resp)
+}
+
+// GetQuestion returns question id with its indexed answers.
+func (l *Local) GetQuestion(ctx context.Context, id int) (*mcp.QuestionData, error) {
+	if len(l.byQuestion[id]) == 0 {
+		return nil, ErrNotFound
+	}
+	return l.question(id), nil
+}
+
+// GetAnswer returns answer id.
+func (l *Local) GetAnswer(ctx context.Context, id int) (*mcp.AnswerData, error) {
+	d := l.idx.Get("a" + strconv.Itoa(id))
+	if d == nil {
+		return nil, ErrNotFound
+	}
+	a := localAnswer(d)
+	return &a, nil
+}
+
+// question builds question id from its documents.  An answer indexed
+// without its question lends it its title, tags and link.
+func (l *Local) question(id int) *mcp.QuestionData {
+	docs := l.byQuestion[id]
+	q := &mcp.QuestionData{QuestionID: id}
+	for _, d := range docs {
+		if d.Kind == index.KindQuestion {
+			q.Title = html.EscapeString(d.Title)
+			q.BodyMarkdown = html.EscapeString(d.Body)
+			q.Tags = d.Tags
+			q.Score = d.Score
+			q.Link = d.Link
+			q.Owner = mcp.OwnerData{DisplayName: html.EscapeString(d.Author)}
+			continue
+		}
+		a := localAnswer(d)
+		if q.Title == "" {
+			q.Title, q.Tags = a.Title, d.Tags
+		}
+		if a.IsAccepted {
+			q.AcceptedAnswerID = a.AnswerID
+			q.IsAnswered = true
+		}
+		if a.Score > 0 {
+			q.IsAnswered = true
+		}
+		q.Answers = append(q.Answers, a)
+	}
+	sort.SliceStable(q.Answers, func(i, j int) bool { return q.Answers[i].Score > q.Answers[j].Score })
+	q.AnswerCount = len(q.Answers)
+	return q
+}
+
+// localAnswer converts an answer document.  The index keeps text
+// unescaped, and posts carry it escaped, as the site sends it.
+func localAnswer(d *index.Doc) mcp.AnswerData {
+	return mcp.AnswerData{
+		AnswerID:     d.AnswerID,
+		QuestionID:   d.QuestionID,
+		Title:        html.EscapeString(d.Title),
+		BodyMarkdown: html.EscapeString(d.Body),
+		Owner:        mcp.OwnerData{DisplayName: html.EscapeString(d.Author)},
+		Score:        d.Score,
+		IsAccepted:   d.Accepted,
+		Link:         d.Link,
+	}
+}
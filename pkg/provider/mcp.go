@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/quota"
+)
+
+// MCP serves content through the official Stack Overflow MCP server,
+// using its so_search and get_content tools.
+type MCP struct {
+	Client *mcp.Client
+	// Answers, when set, pages through the answers get_content leaves
+	// out of a question; the server has no tool listing them.
+	Answers AnswerLister
+}
+
+// NewMCP returns a provider over an established MCP client.  The caller
+// keeps ownership of the client and closes it.
+func NewMCP(client *mcp.Client) *MCP {
+	return &MCP{Client: client}
+}
+
+// Search calls so_search.
+func (m *MCP) Search(ctx context.Context, query string) (*mcp.SOResponse, error) {
+	resp, err := m.call(ctx, "so_search", query)
+	if err != nil {
+		return nil, err
+	}
+	return nonEmpty(resp)
+}
+
+// GetQuestion calls get_content for "SO_Q<id>".
+func (m *MCP) GetQuestion(ctx context.Context, id int) (*mcp.QuestionData, error) {
+	resp, err := m.call(ctx, "get_content", fmt.Sprintf("SO_Q%d", id))
+	if err != nil {
+		return nil, err
+	}
+	return first(resp)
+}
+
+// GetAnswer calls get_content for "SO_A<id>".
+func (m *MCP) GetAnswer(ctx context.Context, id int) (*mcp.AnswerData, error) {
+	resp, err := m.call(ctx, "get_content", fmt.Sprintf("SO_A%d", id))
+	if err != nil {
+		return nil, err
+	}
+	item, err := first(resp)
+	if err != nil {
+		return nil, err
+	}
+	a := mcp.AnswerFromItem(*item)
+	return &a, nil
+}
+
+// GetAnswers returns the answers get_content includes with the
+// question, completed from m.Answers when some are missing.  A failure
+// of m.Answers keeps the answers already fetched.  Its requests are
+// extras, which a low API budget skips (see quota.Extra).
+func (m *MCP) GetAnswers(ctx context.Context, id int) ([]mcp.AnswerData, error) {
+	q, err := m.GetQuestion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if m.Answers == nil || len(q.Answers) >= q.AnswerCount {
+		return q.Answers, nil
+	}
+	more, err := m.Answers.GetAnswers(quota.Extra(ctx), id)
+	if err != nil {
+		slog.Warn("list answers failed", "question_id", id, "err", err)
+		return q.Answers, nil
+	}
+	return mergeAnswers(q.Answers, more), nil
+}
+
+// StartKeepalive pings the bridge while the session is idle (see
+// mcp.Client.StartKeepalive).
+func (m *MCP) StartKeepalive(interval time.Duration) {
+	m.Client.StartKeepalive(interval)
+}
+
+// call checks the connection, reconnecting a stale bridge, then invokes
+// tool with a query argument and parses the result.  An empty result is
+// an empty response, not an error.
+func (m *MCP) call(ctx context.Context, tool, query string) (*mcp.SOResponse, error) {
+	if err := m.Client.EnsureHealthy(ctx); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrConnectionLost, err)
+	}
+	res, err := m.Client.CallTool(ctx, tool, map[string]any{"query": query})
+	if err != nil {
+		return nil, err
+	}
+	text := mcp.ExtractText(res)
+	if text == "" {
+		return &mcp.SOResponse{}, nil
+	}
+	resp, err := mcp.ParseResponse(text)
+	if err == nil && resp.Drift != nil {
+		slog.Warn("MCP reply not in the expected shape; read what could be", "tool", tool,
+			"drift", strings.Join(resp.Drift.Details(), "; "))
+	}
+	return resp, err
+}
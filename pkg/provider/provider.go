@@ -0,0 +1,324 @@
+// Package provider abstracts where Stack Overflow content comes from.
+//
+// A Provider searches and fetches posts; the command layer depends only
+// on this interface, so the same flows run against the official MCP
+// server (NewMCP), the public Stack Exchange REST API (NewREST), or
+// either one behind the response cache (Cached).  Every provider returns
+// the Stack Exchange wire types from package mcp.
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"sort"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+)
+
+// ErrNotFound is returned when a search or lookup finds nothing.
+var ErrNotFound = errors.New("not found")
+
+// ErrConnectionLost wraps failures to reach a backend whose connection
+// went away and could not be re-established.
+var ErrConnectionLost = errors.New("connection lost")
+
+// ErrUnsupported is returned for a call the backend has no way to
+// answer, such as listing bounties through the MCP server.
+var ErrUnsupported = errors.New("not supported by this backend")
+
+// Provider is a source of Stack Overflow questions and answers.
+type Provider interface {
+	// Search returns the questions matching query, in the backend's
+	// order, sometimes with their answers embedded.  It returns
+	// ErrNotFound when there are none.
+	Search(ctx context.Context, query string) (*mcp.SOResponse, error)
+	// GetQuestion fetches a question by ID with the answers the backend
+	// includes.
+	GetQuestion(ctx context.Context, id int) (*mcp.QuestionData, error)
+	// GetAnswer fetches an answer by ID.
+	GetAnswer(ctx context.Context, id int) (*mcp.AnswerData, error)
+}
+
+// Unavailable returns a Provider failing every call with err, for a
+// backend that cannot be reached.  Behind Cached it still serves what
+// is cached.
+func Unavailable(err error) Provider {
+	return unavailable{err}
+}
+
+type unavailable struct{ err error }
+
+func (u unavailable) Search(context.Context, string) (*mcp.SOResponse, error) { return nil, u.err }
+
+func (u unavailable) GetQuestion(context.Context, int) (*mcp.QuestionData, error) { return nil, u.err }
+
+func (u unavailable) GetAnswer(context.Context, int) (*mcp.AnswerData, error) { return nil, u.err }
+
+// AnswerLister is implemented by providers that can page through every
+// answer to a question, including those GetQuestion leaves out.
+type AnswerLister interface {
+	// GetAnswers fetches the answers to question id, highest voted
+	// first.
+	GetAnswers(ctx context.Context, id int) ([]mcp.AnswerData, error)
+}
+
+// AllAnswers fetches every answer to question id: through GetAnswers
+// when p is an AnswerLister, otherwise the answers GetQuestion includes.
+func AllAnswers(ctx context.Context, p Provider, id int) ([]mcp.AnswerData, error) {
+	if l, ok := p.(AnswerLister); ok {
+		return l.GetAnswers(ctx, id)
+	}
+	q, err := p.GetQuestion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return q.Answers, nil
+}
+
+// RelatedLister is implemented by providers that know which questions
+// are related to a question.
+type RelatedLister interface {
+	// GetRelated fetches the questions related to q.
+	GetRelated(ctx context.Context, q *mcp.QuestionData) (*mcp.SOResponse, error)
+}
+
+// RelatedCandidates fetches questions that may be related to q: through
+// GetRelated when p is a RelatedLister, otherwise by searching for q's
+// title.  The caller ranks them (see mcp.Related).
+func RelatedCandidates(ctx context.Context, p Provider, q *mcp.QuestionData) (*mcp.SOResponse, error) {
+	if l, ok := p.(RelatedLister); ok {
+		return l.GetRelated(ctx, q)
+	}
+	return p.Search(ctx, mcp.NormalizeQuery(html.UnescapeString(q.Title)))
+}
+
+// TagFeed is implemented by providers that can list a tag's questions
+// by date and score.
+type TagFeed interface {
+	// TagQuestions fetches up to limit questions tagged tag, asked since
+	// since (any time when zero) and scoring at least minScore, highest
+	// scored first.
+	TagQuestions(ctx context.Context, tag string, since time.Time, minScore, limit int) (*mcp.SOResponse, error)
+}
+
+// NewQuestions fetches the questions tagged tag asked since since with
+// at least minScore, highest scored first: through TagQuestions when p
+// is a TagFeed, otherwise with Stack Overflow's search operators,
+// checking the dates and scores of what comes back.  A zero since
+// means any date.
+func NewQuestions(ctx context.Context, p Provider, tag string, since time.Time, minScore, limit int) (*mcp.SOResponse, error) {
+	var (
+		resp *mcp.SOResponse
+		err  error
+	)
+	if f, ok := p.(TagFeed); ok {
+		resp, err = f.TagQuestions(ctx, tag, since, minScore, limit)
+	} else {
+		query := fmt.Sprintf("[%s] is:question score:%d", tag, minScore)
+		if !since.IsZero() {
+			query += " created:" + since.UTC().Format(time.DateOnly) + ".."
+		}
+		resp, err = p.Search(ctx, query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := &mcp.SOResponse{}
+	for _, q := range resp.Items {
+		if q.Score >= minScore && !time.Unix(q.CreationDate, 0).Before(since) {
+			out.Items = append(out.Items, q)
+		}
+	}
+	sort.SliceStable(out.Items, func(i, j int) bool { return out.Items[i].Score > out.Items[j].Score })
+	if limit > 0 && len(out.Items) > limit {
+		out.Items = out.Items[:limit]
+	}
+	return out, nil
+}
+
+// UnansweredFeed is implemented by providers that can list a tag's
+// unanswered questions.
+type UnansweredFeed interface {
+	// UnansweredQuestions fetches up to limit of the newest open
+	// questions tagged tag with no upvoted or accepted answer, or, with
+	// noAnswers, no answer at all.
+	UnansweredQuestions(ctx context.Context, tag string, noAnswers bool, limit int) (*mcp.SOResponse, error)
+}
+
+// Unanswered fetches the newest open questions tagged tag that are
+// unanswered — with noAnswers, that have no answer at all: through
+// UnansweredQuestions when p is an UnansweredFeed, otherwise with Stack
+// Overflow's search operators, checking what comes back.  It returns
+// ErrNotFound when there are none.
+func Unanswered(ctx context.Context, p Provider, tag string, noAnswers bool, limit int) (*mcp.SOResponse, error) {
+	var (
+		resp *mcp.SOResponse
+		err  error
+	)
+	if f, ok := p.(UnansweredFeed); ok {
+		resp, err = f.UnansweredQuestions(ctx, tag, noAnswers, limit)
+	} else {
+		query := fmt.Sprintf("[%s] is:question closed:no hasaccepted:no", tag)
+		if noAnswers {
+			query += " answers:0"
+		}
+		resp, err = p.Search(ctx, query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := &mcp.SOResponse{}
+	for _, q := range resp.Items {
+		if q.ClosedReason == "" && !q.IsAnswered && (!noAnswers || q.AnswerCount == 0) {
+			out.Items = append(out.Items, q)
+		}
+	}
+	if limit > 0 && len(out.Items) > limit {
+		out.Items = out.Items[:limit]
+	}
+	return nonEmpty(out)
+}
+
+// BountyFeed is implemented by providers that can list the questions
+// with an open bounty.
+type BountyFeed interface {
+	// BountiedQuestions fetches up to limit of the questions tagged tag,
+	// or with any tag when tag is "", that have an open bounty.
+	BountiedQuestions(ctx context.Context, tag string, limit int) (*mcp.SOResponse, error)
+}
+
+// Bountied fetches the questions tagged tag with an open bounty when p
+// is a BountyFeed; search does not tell bounties apart, so other
+// providers return ErrUnsupported.  It returns ErrNotFound when there
+// are none.
+func Bountied(ctx context.Context, p Provider, tag string, limit int) (*mcp.SOResponse, error) {
+	f, ok := p.(BountyFeed)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	resp, err := f.BountiedQuestions(ctx, tag, limit)
+	if err != nil {
+		return nil, err
+	}
+	out := &mcp.SOResponse{}
+	for _, q := range resp.Items {
+		if q.BountyAmount > 0 {
+			out.Items = append(out.Items, q)
+		}
+	}
+	return nonEmpty(out)
+}
+
+// Author is what flo shows of a user to help weigh their answers.
+type Author struct {
+	Reputation int
+	// GoldTags are the tags the user holds a gold badge in.
+	GoldTags []string
+}
+
+// AuthorLister is implemented by providers that can look up users.
+type AuthorLister interface {
+	// GetAuthor fetches the reputation and gold tag badges of user id.
+	GetAuthor(ctx context.Context, id int) (*Author, error)
+}
+
+// AuthorOf fetches user id when p is an AuthorLister; other providers
+// return ErrUnsupported.
+func AuthorOf(ctx context.Context, p Provider, id int) (*Author, error) {
+	l, ok := p.(AuthorLister)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	return l.GetAuthor(ctx, id)
+}
+
+// Answerer is one of a tag's top answerers: the score their answers in
+// the tag earned over the period asked for, and how many they posted.
+type Answerer struct {
+	User    mcp.OwnerData
+	Score   int
+	Answers int
+}
+
+// Leaderboard is implemented by providers that know a tag's top
+// answerers.
+type Leaderboard interface {
+	// TopAnswerers fetches up to limit of the users whose answers in
+	// tag scored highest over the last month or, with allTime, ever.
+	TopAnswerers(ctx context.Context, tag string, allTime bool, limit int) ([]Answerer, error)
+	// TopAnswers fetches up to limit of user id's answers in tag posted
+	// since since, highest voted first, with their questions' titles.
+	TopAnswers(ctx context.Context, id int, tag string, since time.Time, limit int) ([]mcp.AnswerData, error)
+}
+
+// TagAnswerers fetches tag's top answerers when p is a Leaderboard;
+// other providers return ErrUnsupported.
+func TagAnswerers(ctx context.Context, p Provider, tag string, allTime bool, limit int) ([]Answerer, error) {
+	l, ok := p.(Leaderboard)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	return l.TopAnswerers(ctx, tag, allTime, limit)
+}
+
+// UserTopAnswers fetches user id's best recent answers in tag when p is
+// a Leaderboard; other providers return ErrUnsupported.
+func UserTopAnswers(ctx context.Context, p Provider, id int, tag string, since time.Time, limit int) ([]mcp.AnswerData, error) {
+	l, ok := p.(Leaderboard)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	return l.TopAnswers(ctx, id, tag, since, limit)
+}
+
+// CommentLister is implemented by providers that can list the comments
+// on posts.
+type CommentLister interface {
+	// GetComments fetches the comments on the questions and answers
+	// ids, oldest first.
+	GetComments(ctx context.Context, ids []int) ([]mcp.CommentData, error)
+}
+
+// Comments fetches the comments on posts ids when p is a
+// CommentLister; other providers return ErrUnsupported.
+func Comments(ctx context.Context, p Provider, ids []int) ([]mcp.CommentData, error) {
+	l, ok := p.(CommentLister)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	return l.GetComments(ctx, ids)
+}
+
+// mergeAnswers appends the answers in more that are not in answers.
+func mergeAnswers(answers, more []mcp.AnswerData) []mcp.AnswerData {
+	have := make(map[int]bool, len(answers))
+	for _, a := range answers {
+		have[a.AnswerID] = true
+	}
+	for _, a := range more {
+		if !have[a.AnswerID] {
+			answers = append(answers, a)
+			have[a.AnswerID] = true
+		}
+	}
+	return answers
+}
+
+// first returns the first item of resp, or ErrNotFound.
+func first(resp *mcp.SOResponse) (*mcp.QuestionData, error) {
+	if resp == nil || len(resp.Items) == 0 {
+		return nil, ErrNotFound
+	}
+	return &resp.Items[0], nil
+}
+
+// nonEmpty returns resp, or ErrNotFound when it has no items.
+func nonEmpty(resp *mcp.SOResponse) (*mcp.SOResponse, error) {
+	if resp == nil || len(resp.Items) == 0 {
+		return nil, ErrNotFound
+	}
+	return resp, nil
+}
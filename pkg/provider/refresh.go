@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/quota"
+)
+
+// refreshTimeout bounds a background refresh.  Nothing waits for it, so
+// it can be generous.
+const refreshTimeout = 30 * time.Second
+
+// Refresh reports a question that a background refresh found changed
+// since it was cached.
+type Refresh struct {
+	QuestionID int
+	// Question is the refetched question, with the answers its response
+	// embeds; nil when only the question's answer list was refetched.
+	Question *mcp.QuestionData
+	// Answers are the refetched answers.
+	Answers []mcp.AnswerData
+	// NewAnswers counts the answers the cached copy did not have, and
+	// Edited those whose text has changed since.
+	NewAnswers, Edited int
+}
+
+// Refresher is implemented by the providers Cached returns.  They serve
+// a cached response older than the cache's SoftTTL at once and refetch
+// it in the background, once per session.
+type Refresher interface {
+	// OnRefresh sets the function told about each question a refresh
+	// found changed.  It is called from a background goroutine.
+	OnRefresh(f func(Refresh))
+}
+
+// OnRefresh implements Refresher.  Set it before the first call.
+func (c *cached) OnRefresh(f func(Refresh)) {
+	c.onRefresh = f
+}
+
+// refresh refetches the response cached under key in the background,
+// stores it and reports what changed from old, the cached copy.
+// questionID is set when the response lists one question's answers.
+func (c *cached) refresh(tool, key string, old []byte, questionID int, fetch fetchFunc) {
+	if _, busy := c.refreshed.LoadOrStore(key, true); busy {
+		return
+	}
+	go func() {
+		// Nothing needs a refresh: a low API budget skips it.
+		ctx, cancel := context.WithTimeout(quota.Extra(context.Background()), refreshTimeout)
+		defer cancel()
+		fresh, err := fetch(ctx)
+		if err != nil {
+			slog.Debug("background refresh failed", "tool", tool, "err", err)
+			return
+		}
+		c.store(ctx, tool, key, fresh)
+		slog.Debug("refreshed cache entry", "tool", tool)
+		prev, err := mcp.ParseResponse(string(old))
+		if err != nil || c.onRefresh == nil {
+			return
+		}
+		for _, r := range changes(prev, fresh, questionID) {
+			c.onRefresh(r)
+		}
+	}()
+}
+
+// changes compares a refetched response with the cached one, question
+// by question.  questionID is set when the items are one question's
+// answers rather than questions.
+func changes(old, fresh *mcp.SOResponse, questionID int) []Refresh {
+	if questionID != 0 {
+		var prev, next []mcp.AnswerData
+		for _, item := range old.Items {
+			prev = append(prev, mcp.AnswerFromItem(item))
+		}
+		for _, item := range fresh.Items {
+			next = append(next, mcp.AnswerFromItem(item))
+		}
+		r := Refresh{QuestionID: questionID, Answers: next}
+		r.NewAnswers, r.Edited = compareAnswers(prev, next)
+		if r.NewAnswers+r.Edited == 0 {
+			return nil
+		}
+		return []Refresh{r}
+	}
+	cached := make(map[int]*mcp.QuestionData, len(old.Items))
+	for i := range old.Items {
+		if q := &old.Items[i]; q.QuestionID != 0 && q.AnswerID == 0 {
+			cached[q.QuestionID] = q
+		}
+	}
+	var out []Refresh
+	for i := range fresh.Items {
+		q := &fresh.Items[i]
+		prev, ok := cached[q.QuestionID]
+		if !ok || q.AnswerID != 0 {
+			continue
+		}
+		r := Refresh{QuestionID: q.QuestionID, Question: q, Answers: q.Answers}
+		r.NewAnswers, r.Edited = compareAnswers(prev.Answers, q.Answers)
+		// A response without answer bodies still counts them.
+		r.NewAnswers = max(r.NewAnswers, q.AnswerCount-prev.AnswerCount)
+		if r.NewAnswers+r.Edited > 0 {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// compareAnswers counts the answers in next that are not in prev, and
+// those in both whose body differs.
+func compareAnswers(prev, next []mcp.AnswerData) (added, edited int) {
+	bodies := make(map[int]string, len(prev))
+	for _, a := range prev {
+		bodies[a.AnswerID] = a.BodyMarkdown
+	}
+	for _, a := range next {
+		body, ok := bodies[a.AnswerID]
+		switch {
+		case !ok:
+			added++
+		case body != "" && a.BodyMarkdown != "" && body != a.BodyMarkdown:
+			edited++
+		}
+	}
+	return added, edited
+}
@@ -0,0 +1,605 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+	"github.com/ratnesh-maurya/flo/pkg/quota"
+)
+
+// DefaultAPIURL is the Stack Exchange REST API root.
+const DefaultAPIURL = "https://api.stackexchange.com/2.3"
+
+// DefaultSite is the Stack Exchange site queried when none is set.
+const DefaultSite = "stackoverflow"
+
+// searchPageSize matches the number of results so_search returns.
+const searchPageSize = 10
+
+// answersPageSize is the largest page the API serves.  GetAnswers stops
+// after maxAnswerPages, so one question cannot drain the quota.
+const (
+	answersPageSize = 100
+	maxAnswerPages  = 5
+)
+
+// maxTagPages bounds PopularTags the same way.
+const maxTagPages = 10
+
+// filterFields are added to the API's default filter so responses carry
+// what the MCP server returns: Markdown bodies and embedded answers.
+var filterFields = []string{
+	"question.body_markdown",
+	"question.answers",
+	"question.closed_details",
+	"answer.body_markdown",
+	"answer.link",
+	"answer.title",
+	"comment.body_markdown",
+}
+
+// REST serves content from the public Stack Exchange API.  It needs no
+// login; a registered app key raises the daily quota from 300 requests
+// per IP to 10,000.
+type REST struct {
+	// BaseURL is the API root; empty means DefaultAPIURL.
+	BaseURL string
+	// Site is the site, as SiteParam takes it; empty means DefaultSite.
+	Site string
+	// Key is an optional Stack Apps key.
+	Key string
+	// HTTP is the client used for requests.
+	HTTP *http.Client
+	// OnRequest, if set, is called after every request with its path,
+	// e.g. "/search/advanced", how long it took and how it failed.
+	OnRequest func(path string, d time.Duration, err error)
+	// Budget, if set, counts every request against the day's budget and
+	// learns the quota the API reports; a request it refuses is not
+	// made.
+	Budget *quota.Budget
+
+	mu        sync.Mutex
+	filter    string    // created on first use
+	notBefore time.Time // set when the API asks us to back off
+}
+
+// NewREST returns a provider for site using hc; key may be empty.
+func NewREST(hc *http.Client, site, key string) *REST {
+	return &REST{Site: site, Key: key, HTTP: hc}
+}
+
+// apiResponse is the API's common wrapper.  Errors come back as an
+// error_id and message rather than items.
+// APIError is an error reported by the Stack Exchange API in its
+// error_id, error_name and error_message fields.
+type APIError struct {
+	ID      int
+	Name    string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("Stack Exchange API: %s (%s)", e.Message, e.Name)
+}
+
+// Auth reports whether the request was refused for want of valid
+// credentials: a missing, invalid or compromised key or access token.
+func (e *APIError) Auth() bool {
+	switch e.ID {
+	case 401, 403, 405, 406:
+		return true
+	}
+	return false
+}
+
+type apiResponse struct {
+	mcp.SOResponse
+	HasMore        bool   `json:"has_more"`
+	ErrorID        int    `json:"error_id"`
+	ErrorName      string `json:"error_name"`
+	ErrorMessage   string `json:"error_message"`
+	Backoff        int    `json:"backoff"`
+	QuotaRemaining int    `json:"quota_remaining"`
+	QuotaMax       int    `json:"quota_max"`
+}
+
+// Search calls /search/advanced, ordered by relevance.
+func (r *REST) Search(ctx context.Context, query string) (*mcp.SOResponse, error) {
+	resp, err := r.get(ctx, "/search/advanced", url.Values{
+		"q":        {query},
+		"order":    {"desc"},
+		"sort":     {"relevance"},
+		"pagesize": {strconv.Itoa(searchPageSize)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nonEmpty(resp)
+}
+
+// GetQuestion calls /questions/{id}.
+func (r *REST) GetQuestion(ctx context.Context, id int) (*mcp.QuestionData, error) {
+	resp, err := r.get(ctx, "/questions/"+strconv.Itoa(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	return first(resp)
+}
+
+// GetAnswer calls /answers/{id}.
+func (r *REST) GetAnswer(ctx context.Context, id int) (*mcp.AnswerData, error) {
+	resp, err := r.get(ctx, "/answers/"+strconv.Itoa(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	item, err := first(resp)
+	if err != nil {
+		return nil, err
+	}
+	a := mcp.AnswerFromItem(*item)
+	return &a, nil
+}
+
+// GetAnswers calls /questions/{id}/answers, highest voted first,
+// following pages while the API reports more.  The pages after the
+// first are extras, which a low budget skips (see quota.Extra).
+func (r *REST) GetAnswers(ctx context.Context, id int) ([]mcp.AnswerData, error) {
+	var answers []mcp.AnswerData
+	for page := 1; page <= maxAnswerPages; page++ {
+		pageCtx := ctx
+		if page > 1 {
+			pageCtx = quota.Extra(ctx)
+		}
+		resp, err := r.getPage(pageCtx, "/questions/"+strconv.Itoa(id)+"/answers", url.Values{
+			"order":    {"desc"},
+			"sort":     {"votes"},
+			"pagesize": {strconv.Itoa(answersPageSize)},
+			"page":     {strconv.Itoa(page)},
+		})
+		if errors.Is(err, quota.ErrSkipped) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range resp.Items {
+			answers = append(answers, mcp.AnswerFromItem(item))
+		}
+		if !resp.HasMore {
+			break
+		}
+	}
+	if len(answers) == 0 {
+		return nil, ErrNotFound
+	}
+	return answers, nil
+}
+
+// GetRelated calls /questions/{id}/related.
+func (r *REST) GetRelated(ctx context.Context, q *mcp.QuestionData) (*mcp.SOResponse, error) {
+	resp, err := r.get(ctx, "/questions/"+strconv.Itoa(q.QuestionID)+"/related", url.Values{
+		"order":    {"desc"},
+		"sort":     {"rank"},
+		"pagesize": {strconv.Itoa(searchPageSize)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nonEmpty(resp)
+}
+
+// TagQuestions calls /questions filtered by tag, creation date and
+// score, paging for more than answersPageSize up to maxTagPages.
+func (r *REST) TagQuestions(ctx context.Context, tag string, since time.Time, minScore, limit int) (*mcp.SOResponse, error) {
+	if limit <= 0 {
+		limit = answersPageSize
+	}
+	params := url.Values{
+		"tagged":   {tag},
+		"order":    {"desc"},
+		"sort":     {"votes"},
+		"min":      {strconv.Itoa(minScore)},
+		"pagesize": {strconv.Itoa(min(limit, answersPageSize))},
+	}
+	if !since.IsZero() {
+		params.Set("fromdate", strconv.FormatInt(since.Unix(), 10))
+	}
+	out := &mcp.SOResponse{}
+	for page := 1; len(out.Items) < limit && page <= maxTagPages; page++ {
+		pageCtx := ctx
+		if page > 1 {
+			pageCtx = quota.Extra(ctx)
+		}
+		params.Set("page", strconv.Itoa(page))
+		resp, err := r.getPage(pageCtx, "/questions", params)
+		if errors.Is(err, quota.ErrSkipped) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		out.Items = append(out.Items, resp.Items...)
+		if !resp.HasMore {
+			break
+		}
+	}
+	if len(out.Items) > limit {
+		out.Items = out.Items[:limit]
+	}
+	return out, nil
+}
+
+// UnansweredQuestions calls /questions/unanswered, or with noAnswers
+// /questions/no-answers, filtered by tag, newest first.
+func (r *REST) UnansweredQuestions(ctx context.Context, tag string, noAnswers bool, limit int) (*mcp.SOResponse, error) {
+	if limit <= 0 || limit > answersPageSize {
+		limit = answersPageSize
+	}
+	path := "/questions/unanswered"
+	if noAnswers {
+		path = "/questions/no-answers"
+	}
+	return r.get(ctx, path, url.Values{
+		"tagged":   {tag},
+		"order":    {"desc"},
+		"sort":     {"creation"},
+		"pagesize": {strconv.Itoa(limit)},
+	})
+}
+
+// BountiedQuestions calls /questions/featured, filtered by tag when
+// one is given.
+func (r *REST) BountiedQuestions(ctx context.Context, tag string, limit int) (*mcp.SOResponse, error) {
+	if limit <= 0 || limit > answersPageSize {
+		limit = answersPageSize
+	}
+	params := url.Values{
+		"order":    {"desc"},
+		"sort":     {"activity"},
+		"pagesize": {strconv.Itoa(limit)},
+	}
+	if tag != "" {
+		params.Set("tagged", tag)
+	}
+	return r.get(ctx, "/questions/featured", params)
+}
+
+// GetAuthor calls /users/{id}/badges for the user's gold badges, which
+// carry their reputation too, and /users/{id} for a user with none.
+func (r *REST) GetAuthor(ctx context.Context, id int) (*Author, error) {
+	type user struct {
+		Reputation int `json:"reputation"`
+	}
+	var badges struct {
+		Items []struct {
+			Name      string `json:"name"`
+			BadgeType string `json:"badge_type"`
+			User      user   `json:"user"`
+		} `json:"items"`
+	}
+	err := r.do(ctx, "/users/"+strconv.Itoa(id)+"/badges", url.Values{
+		"site":     {r.site()},
+		"order":    {"desc"},
+		"sort":     {"rank"},
+		"min":      {"gold"},
+		"max":      {"gold"},
+		"pagesize": {strconv.Itoa(answersPageSize)},
+	}, &badges)
+	if err != nil {
+		return nil, err
+	}
+	a := &Author{}
+	for _, b := range badges.Items {
+		a.Reputation = b.User.Reputation
+		if b.BadgeType == "tag_based" {
+			a.GoldTags = append(a.GoldTags, b.Name)
+		}
+	}
+	if a.Reputation > 0 {
+		return a, nil
+	}
+	var users struct {
+		Items []user `json:"items"`
+	}
+	if err := r.do(ctx, "/users/"+strconv.Itoa(id), url.Values{"site": {r.site()}}, &users); err != nil {
+		return nil, err
+	}
+	if len(users.Items) == 0 {
+		return nil, ErrNotFound
+	}
+	a.Reputation = users.Items[0].Reputation
+	return a, nil
+}
+
+// TopAnswerers calls /tags/{tag}/top-answerers/month, or all_time.
+func (r *REST) TopAnswerers(ctx context.Context, tag string, allTime bool, limit int) ([]Answerer, error) {
+	period := "month"
+	if allTime {
+		period = "all_time"
+	}
+	if limit <= 0 || limit > answersPageSize {
+		limit = answersPageSize
+	}
+	var resp struct {
+		Items []struct {
+			User      mcp.OwnerData `json:"user"`
+			Score     int           `json:"score"`
+			PostCount int           `json:"post_count"`
+		} `json:"items"`
+	}
+	err := r.do(ctx, "/tags/"+url.PathEscape(tag)+"/top-answerers/"+period, url.Values{
+		"site":     {r.site()},
+		"pagesize": {strconv.Itoa(limit)},
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Items) == 0 {
+		return nil, ErrNotFound
+	}
+	out := make([]Answerer, len(resp.Items))
+	for i, it := range resp.Items {
+		out[i] = Answerer{User: it.User, Score: it.Score, Answers: it.PostCount}
+	}
+	return out, nil
+}
+
+// TopAnswers calls /users/{id}/tags/{tag}/top-answers.
+func (r *REST) TopAnswers(ctx context.Context, id int, tag string, since time.Time, limit int) ([]mcp.AnswerData, error) {
+	if limit <= 0 || limit > answersPageSize {
+		limit = answersPageSize
+	}
+	resp, err := r.get(ctx, "/users/"+strconv.Itoa(id)+"/tags/"+url.PathEscape(tag)+"/top-answers", url.Values{
+		"fromdate": {strconv.FormatInt(since.Unix(), 10)},
+		"order":    {"desc"},
+		"sort":     {"votes"},
+		"pagesize": {strconv.Itoa(limit)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	answers := make([]mcp.AnswerData, len(resp.Items))
+	for i, item := range resp.Items {
+		answers[i] = mcp.AnswerFromItem(item)
+	}
+	return answers, nil
+}
+
+// GetComments calls /posts/{ids}/comments, oldest first.  The API takes
+// at most answersPageSize ids a request, so more are sent in batches.
+func (r *REST) GetComments(ctx context.Context, ids []int) ([]mcp.CommentData, error) {
+	filter, err := r.bodyFilter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var comments []mcp.CommentData
+	for len(ids) > 0 {
+		batch := ids[:min(len(ids), answersPageSize)]
+		ids = ids[len(batch):]
+		parts := make([]string, len(batch))
+		for i, id := range batch {
+			parts[i] = strconv.Itoa(id)
+		}
+		for page := 1; page <= maxAnswerPages; page++ {
+			var resp struct {
+				Items   []mcp.CommentData `json:"items"`
+				HasMore bool              `json:"has_more"`
+			}
+			err := r.do(ctx, "/posts/"+strings.Join(parts, ";")+"/comments", url.Values{
+				"site":     {r.site()},
+				"filter":   {filter},
+				"order":    {"asc"},
+				"sort":     {"creation"},
+				"page":     {strconv.Itoa(page)},
+				"pagesize": {strconv.Itoa(answersPageSize)},
+			}, &resp)
+			if err != nil {
+				return comments, err
+			}
+			comments = append(comments, resp.Items...)
+			if !resp.HasMore {
+				break
+			}
+		}
+	}
+	return comments, nil
+}
+
+// PopularTags calls /tags, returning the names of up to n tags, most
+// used first.  Tags carry no bodies, so the body filter is left out.
+func (r *REST) PopularTags(ctx context.Context, n int) ([]string, error) {
+	size := min(n, answersPageSize)
+	var names []string
+	for page := 1; len(names) < n && page <= maxTagPages; page++ {
+		var resp struct {
+			Items []struct {
+				Name string `json:"name"`
+			} `json:"items"`
+			HasMore bool `json:"has_more"`
+		}
+		err := r.do(ctx, "/tags", url.Values{
+			"site":     {r.site()},
+			"order":    {"desc"},
+			"sort":     {"popular"},
+			"page":     {strconv.Itoa(page)},
+			"pagesize": {strconv.Itoa(size)},
+		}, &resp)
+		if err != nil {
+			return names, err
+		}
+		for _, it := range resp.Items {
+			names = append(names, it.Name)
+		}
+		if !resp.HasMore {
+			break
+		}
+	}
+	if len(names) > n {
+		names = names[:n]
+	}
+	return names, nil
+}
+
+// get calls an API method with the site, key and body filter added.
+func (r *REST) get(ctx context.Context, path string, params url.Values) (*mcp.SOResponse, error) {
+	resp, err := r.getPage(ctx, path, params)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.SOResponse, nil
+}
+
+// getPage is get returning the whole wrapper, for paged methods.
+func (r *REST) getPage(ctx context.Context, path string, params url.Values) (*apiResponse, error) {
+	filter, err := r.bodyFilter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("site", r.site())
+	params.Set("filter", filter)
+	var resp apiResponse
+	if err := r.do(ctx, path, params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// bodyFilter returns the ID of a filter adding filterFields, creating it
+// on first use.  Filters are immutable and never expire, so one request
+// per process is enough.
+func (r *REST) bodyFilter(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	filter := r.filter
+	r.mu.Unlock()
+	if filter != "" {
+		return filter, nil
+	}
+
+	var resp struct {
+		Items []struct {
+			Filter string `json:"filter"`
+		} `json:"items"`
+	}
+	params := url.Values{
+		"include": {strings.Join(filterFields, ";")},
+		"base":    {"default"},
+		"unsafe":  {"false"},
+	}
+	if err := r.do(ctx, "/filters/create", params, &resp); err != nil {
+		return "", fmt.Errorf("create API filter: %w", err)
+	}
+	if len(resp.Items) == 0 || resp.Items[0].Filter == "" {
+		return "", fmt.Errorf("create API filter: no filter returned")
+	}
+	r.mu.Lock()
+	r.filter = resp.Items[0].Filter
+	r.mu.Unlock()
+	return resp.Items[0].Filter, nil
+}
+
+// do sends a GET request and decodes the JSON reply into out, turning
+// API errors into Go errors and honoring backoff requests.
+func (r *REST) do(ctx context.Context, path string, params url.Values, out any) error {
+	if err := r.wait(ctx); err != nil {
+		return err
+	}
+	if r.Budget != nil {
+		if err := r.Budget.Take(ctx); err != nil {
+			return err
+		}
+	}
+	if r.OnRequest == nil {
+		return r.request(ctx, path, params, out)
+	}
+	start := time.Now()
+	err := r.request(ctx, path, params, out)
+	r.OnRequest(path, time.Since(start), err)
+	return err
+}
+
+// request makes the request for do.
+func (r *REST) request(ctx context.Context, path string, params url.Values, out any) error {
+	if r.Key != "" {
+		params.Set("key", r.Key)
+	}
+	base := r.BaseURL
+	if base == "" {
+		base = DefaultAPIURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(base, "/")+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	hc := r.HTTP
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	res, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	// The body is decoded twice: once for the error and quota fields
+	// every method shares, once into the caller's type.
+	var raw json.RawMessage
+	if err := json.NewDecoder(res.Body).Decode(&raw); err != nil {
+		return fmt.Errorf("Stack Exchange API: %s: %w", res.Status, err)
+	}
+	var meta apiResponse
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return fmt.Errorf("Stack Exchange API: %w", err)
+	}
+	if r.Budget != nil {
+		r.Budget.Observe(meta.QuotaRemaining, meta.QuotaMax)
+	}
+	if meta.Backoff > 0 {
+		r.mu.Lock()
+		r.notBefore = time.Now().Add(time.Duration(meta.Backoff) * time.Second)
+		r.mu.Unlock()
+	}
+	if meta.ErrorID != 0 {
+		return &APIError{meta.ErrorID, meta.ErrorName, meta.ErrorMessage}
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("Stack Exchange API: %s", res.Status)
+	}
+	slog.Debug("Stack Exchange API call", "path", path, "quota_remaining", meta.QuotaRemaining)
+	return json.Unmarshal(raw, out)
+}
+
+// wait sleeps until a backoff the API asked for has passed.
+func (r *REST) wait(ctx context.Context) error {
+	r.mu.Lock()
+	d := time.Until(r.notBefore)
+	r.mu.Unlock()
+	if d <= 0 {
+		return nil
+	}
+	slog.Info("Stack Exchange API backoff", "wait", d)
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *REST) site() string {
+	return SiteParam(r.Site)
+}
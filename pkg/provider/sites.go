@@ -0,0 +1,59 @@
+package provider
+
+import "strings"
+
+// Site is a Stack Exchange site flo knows by name.
+type Site struct {
+	// Param is the API's site parameter, e.g. "es.stackoverflow".
+	Param string
+	// Host is the site's domain, e.g. "es.stackoverflow.com".
+	Host string
+	// Name is what the site calls itself.
+	Name string
+	// Language is the language questions on the site are asked in.
+	Language string
+}
+
+// Sites are Stack Overflow and its editions in other languages.
+var Sites = []Site{
+	{DefaultSite, "stackoverflow.com", "Stack Overflow", "en"},
+	{"es.stackoverflow", "es.stackoverflow.com", "Stack Overflow en español", "es"},
+	{"pt.stackoverflow", "pt.stackoverflow.com", "Stack Overflow em Português", "pt"},
+	{"ru.stackoverflow", "ru.stackoverflow.com", "Stack Overflow на русском", "ru"},
+	{"ja.stackoverflow", "ja.stackoverflow.com", "スタック・オーバーフロー", "ja"},
+}
+
+// LookupSite finds the site name stands for: its API parameter
+// ("es.stackoverflow"), its address ("https://es.stackoverflow.com/")
+// or its language ("es").
+func LookupSite(name string) (Site, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.TrimPrefix(strings.TrimPrefix(name, "https://"), "http://")
+	name = strings.TrimPrefix(strings.TrimSuffix(name, "/"), "www.")
+	for _, s := range Sites {
+		if name == s.Param || name == s.Host || name == s.Language {
+			return s, true
+		}
+	}
+	return Site{}, false
+}
+
+// SiteParam returns the API parameter of the site name stands for: that
+// of a site in Sites, name itself for any other, and DefaultSite when
+// name is empty.
+func SiteParam(name string) string {
+	if name == "" {
+		return DefaultSite
+	}
+	if s, ok := LookupSite(name); ok {
+		return s.Param
+	}
+	return name
+}
+
+// ServedByMCP reports whether the MCP server can answer for site.  It
+// serves Stack Overflow in English only; every other site goes through
+// the API.
+func ServedByMCP(site string) bool {
+	return SiteParam(site) == DefaultSite
+}
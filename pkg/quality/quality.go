@@ -0,0 +1,141 @@
+// Package quality flags answers that are likely obsolete, so flo can
+// show a caution banner before the user applies an outdated fix.
+//
+// Three heuristics are applied:
+//
+//   - the answer matches a deprecation rule for one of the question's
+//     tags (a regular expression over the answer body; built-in rules
+//     cover well-known removals, and users can add their own);
+//   - the author or an editor marked it as broken ("EDIT: this no longer
+//     works", "UPDATE: deprecated as of ...");
+//   - a newer answer has many times its score, which on Stack Overflow
+//     usually means the accepted or first answer has been superseded.
+package quality
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+)
+
+// Rule flags answers matching Pattern under any of Tags (any question
+// when Tags is empty).
+type Rule struct {
+	Tags    []string `yaml:"tags"`
+	Pattern string   `yaml:"pattern"`
+	Message string   `yaml:"message"`
+}
+
+// Builtin are the rules applied unless disabled; user rules are added
+// to them.
+var Builtin = []Rule{
+	{Tags: []string{"go"}, Pattern: `\bioutil\.`, Message: "uses io/ioutil, deprecated since Go 1.16 (use io and os)"},
+	{Tags: []string{"python", "python-3.x"}, Pattern: `(?m)^\s*print\s+["'\w]`, Message: "uses the Python 2 print statement"},
+	{Tags: []string{"python", "python-3.x"}, Pattern: `\burllib2\b|\.has_key\(`, Message: "uses Python 2 APIs removed in Python 3"},
+	{Tags: []string{"javascript", "node.js"}, Pattern: `\bnew Buffer\(`, Message: "uses the deprecated Buffer constructor (use Buffer.from)"},
+	{Tags: []string{"jquery"}, Pattern: `\.(live|die|size)\(`, Message: "uses jQuery methods removed in jQuery 1.9 / 3.0"},
+	{Tags: []string{"reactjs"}, Pattern: `\bcomponentWill(Mount|ReceiveProps|Update)\b`, Message: "uses legacy React lifecycle methods"},
+	{Tags: []string{"php", "mysql"}, Pattern: `\bmysql_(query|connect|fetch_\w+|real_escape_string)\(`, Message: "uses the mysql_* functions removed in PHP 7"},
+	{Tags: []string{"java"}, Pattern: `\bnew (Integer|Long|Double|Boolean)\(`, Message: "uses boxed-type constructors deprecated since Java 9 (use valueOf)"},
+	{Tags: []string{"c#", ".net"}, Pattern: `\bnew WebClient\(`, Message: "uses WebClient, obsolete since .NET 6 (use HttpClient)"},
+}
+
+// reBrokenMarker matches notes saying an answer stopped working.
+var reBrokenMarker = regexp.MustCompile(`(?i)\b(edit|update|note)\b[^\n]{0,60}?\b(no longer works?|(does not|doesn't|don't) work any ?more|stopped working|is (now )?(deprecated|obsolete|outdated)|has been (deprecated|removed))`)
+
+// Outscored thresholds: a newer answer must have at least this many
+// times the score, and this many more votes.
+const (
+	outscoreRatio = 3
+	outscoreDiff  = 20
+)
+
+// Checker applies the heuristics with a compiled rule set.
+type Checker struct {
+	rules []compiled
+}
+
+type compiled struct {
+	tags    map[string]bool
+	re      *regexp.Regexp
+	message string
+}
+
+// NewChecker compiles rules.  An invalid pattern is an error naming the
+// rule.
+func NewChecker(rules []Rule) (*Checker, error) {
+	c := &Checker{}
+	for _, r := range rules {
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("quality rule %q: %w", r.Pattern, err)
+		}
+		cr := compiled{re: re, message: r.Message}
+		if cr.message == "" {
+			cr.message = "matches " + r.Pattern
+		}
+		if len(r.Tags) > 0 {
+			cr.tags = make(map[string]bool, len(r.Tags))
+			for _, t := range r.Tags {
+				cr.tags[strings.ToLower(t)] = true
+			}
+		}
+		c.rules = append(c.rules, cr)
+	}
+	return c, nil
+}
+
+// Check returns the cautions for answer a to question q, one short
+// sentence each, or nil.  q.Answers are compared against a for the
+// outscored heuristic.
+func (c *Checker) Check(q *mcp.QuestionData, a *mcp.AnswerData) []string {
+	var warnings []string
+	body := html.UnescapeString(a.BodyMarkdown)
+
+	for _, r := range c.rules {
+		if r.tags != nil && !anyTag(q.Tags, r.tags) {
+			continue
+		}
+		if r.re.MatchString(body) {
+			warnings = append(warnings, "This answer "+r.message+".")
+		}
+	}
+
+	if m := reBrokenMarker.FindString(body); m != "" {
+		warnings = append(warnings, fmt.Sprintf("The answer itself notes it may be outdated: %q.", strings.TrimSpace(m)))
+	}
+
+	if a.CreationDate > 0 {
+		for _, b := range q.Answers {
+			if b.AnswerID == a.AnswerID || b.CreationDate <= a.CreationDate {
+				continue
+			}
+			if b.Score >= outscoreRatio*max(a.Score, 1) && b.Score-a.Score >= outscoreDiff {
+				warnings = append(warnings, fmt.Sprintf(
+					"A newer answer by %s scores %d to this one's %d; it may supersede it.",
+					nameOf(&b), b.Score, a.Score))
+				break
+			}
+		}
+	}
+	return warnings
+}
+
+func anyTag(tags []string, want map[string]bool) bool {
+	for _, t := range tags {
+		if want[strings.ToLower(t)] {
+			return true
+		}
+	}
+	return false
+}
+
+func nameOf(a *mcp.AnswerData) string {
+	if a.Owner.DisplayName == "" {
+		return "someone else"
+	}
+	return html.UnescapeString(a.Owner.DisplayName)
+}
@@ -0,0 +1,272 @@
+// Package quota keeps a daily budget of Stack Exchange API requests.
+// The API allows an IP 300 requests a day, or 10,000 with an app key,
+// and refuses every request past that until the quota renews at
+// midnight UTC.  flo counts its requests against a budget, by default
+// the API's own quota, so that it can save requests while some are left
+// instead of failing once none are.
+//
+// The count is saved to a file, since each flo command is a process of
+// its own.
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLimit is the budget when none is set and the API has not yet
+// reported its quota: its quota for requests without a key.
+const DefaultLimit = 300
+
+// LowShare is the share of the budget left below which the budget is
+// low.
+const LowShare = 0.2
+
+// ErrSkipped is returned for an extra request (see Extra) refused to
+// save the budget.
+var ErrSkipped = errors.New("skipped to save the API quota")
+
+// SpentError is returned for a request refused because the budget is
+// spent.
+type SpentError struct {
+	Limit int
+	// Renews is when the budget starts over.
+	Renews time.Time
+}
+
+func (e *SpentError) Error() string {
+	return fmt.Sprintf("today's budget of %d Stack Exchange API requests is spent; it renews in %s",
+		e.Limit, Wait(time.Until(e.Renews)))
+}
+
+// Wait formats the time until the budget renews, to the minute: "4h48m".
+func Wait(d time.Duration) string {
+	s := d.Round(time.Minute).String()
+	if d >= time.Minute {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	return s
+}
+
+// Usage is the day's count, as saved.
+type Usage struct {
+	// Day is the UTC date counted, as "2006-01-02".
+	Day string `json:"day"`
+	// Used counts the requests made that day.
+	Used int `json:"used"`
+	// QuotaRemaining and QuotaMax are what the API last reported; zero
+	// QuotaMax means it has not.
+	QuotaRemaining int `json:"quota_remaining"`
+	QuotaMax       int `json:"quota_max"`
+}
+
+// Budget counts the requests of the day against Limit.  It is safe for
+// concurrent use.
+type Budget struct {
+	path string
+	// Limit is the number of requests a day; zero means the API's
+	// quota.
+	Limit int
+
+	mu    sync.Mutex
+	usage Usage
+}
+
+// Load reads the count saved at path; a missing or unreadable file
+// means nothing was used.
+func Load(path string, limit int) *Budget {
+	b := &Budget{path: path, Limit: limit}
+	b.reload()
+	return b
+}
+
+// DefaultPath returns the count's file inside cacheDir.
+func DefaultPath(cacheDir string) string {
+	return filepath.Join(cacheDir, "quota.json")
+}
+
+// day returns the UTC date of t, which the API's quota follows.
+func day(t time.Time) string {
+	return t.UTC().Format(time.DateOnly)
+}
+
+// Renews returns when the budget starts over after now.
+func Renews(now time.Time) time.Time {
+	return now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+}
+
+// Status is the state of the budget at a time.
+type Status struct {
+	Used  int
+	Limit int
+	// Left is what remains of the budget, or of the API's quota when
+	// that is less: other programs on the same IP or key spend it too.
+	Left int
+	// QuotaRemaining is the API's count; -1 when it has not reported
+	// one today.
+	QuotaRemaining int
+}
+
+// Low reports whether less than LowShare of the budget is left.
+func (s Status) Low() bool {
+	return float64(s.Left) < LowShare*float64(s.Limit)
+}
+
+// Spent reports whether nothing is left.
+func (s Status) Spent() bool {
+	return s.Left <= 0
+}
+
+// Status returns the state of the budget at now.
+func (b *Budget) Status(now time.Time) Status {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.status(now)
+}
+
+// status is Status; the caller holds b.mu.
+func (b *Budget) status(now time.Time) Status {
+	u := b.usage
+	if u.Day != day(now) {
+		u = Usage{}
+	}
+	s := Status{Used: u.Used, Limit: b.Limit, QuotaRemaining: -1}
+	if s.Limit <= 0 {
+		s.Limit = DefaultLimit
+		if u.QuotaMax > 0 {
+			s.Limit = u.QuotaMax
+		}
+	}
+	s.Left = s.Limit - s.Used
+	if u.QuotaMax > 0 {
+		s.QuotaRemaining = u.QuotaRemaining
+		s.Left = min(s.Left, u.QuotaRemaining)
+	}
+	s.Left = max(s.Left, 0)
+	return s
+}
+
+// Take counts a request about to be made with ctx, or refuses it: with
+// a *SpentError once the budget is spent, and with ErrSkipped for an
+// extra request while it is low.
+func (b *Budget) Take(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	// Other flo processes may have counted since.
+	b.reload()
+	s := b.status(now)
+	switch {
+	case s.Spent():
+		return &SpentError{Limit: s.Limit, Renews: Renews(now)}
+	case s.Low() && IsExtra(ctx):
+		slog.Info("API request skipped to save the quota", "left", s.Left, "limit", s.Limit)
+		return ErrSkipped
+	}
+	if b.usage.Day != day(now) {
+		b.usage = Usage{Day: day(now)}
+	}
+	b.usage.Used++
+	b.save()
+	return nil
+}
+
+// Observe records the quota the API reported in a reply.
+func (b *Budget) Observe(remaining, max int) {
+	if max <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	b.reload()
+	if b.usage.Day != day(now) {
+		b.usage = Usage{Day: day(now)}
+	}
+	b.usage.QuotaRemaining, b.usage.QuotaMax = remaining, max
+	b.save()
+}
+
+// reload replaces the count with the saved one.  A missing file means
+// nothing was used; one that cannot be read or parsed keeps the count
+// this process last knew, rather than starting the day over.  The caller
+// holds b.mu, or owns b.
+func (b *Budget) reload() {
+	data, err := os.ReadFile(b.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		b.usage = Usage{}
+		return
+	}
+	if err != nil {
+		slog.Warn("API quota count unreadable", "err", err)
+		return
+	}
+	var u Usage
+	if err := json.Unmarshal(data, &u); err != nil {
+		slog.Warn("API quota count unreadable", "err", err)
+		return
+	}
+	b.usage = u
+}
+
+// save writes the count atomically, through a temporary file of its own
+// so that processes saving at once never rename a half-written one.
+// Failures are only logged, since a lost update costs at most a request
+// counted short.  The caller holds b.mu.
+func (b *Budget) save() {
+	if err := b.write(); err != nil {
+		slog.Warn("save API quota count", "err", err)
+	}
+}
+
+// write is save, returning its error.
+func (b *Budget) write() error {
+	data, err := json.MarshalIndent(b.usage, "", "  ")
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(b.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, filepath.Base(b.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(0o644); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), b.path)
+}
+
+type extraKey struct{}
+
+// Extra marks the requests made with the returned context as extras,
+// which flo can do without: a low budget refuses them.
+func Extra(ctx context.Context) context.Context {
+	return context.WithValue(ctx, extraKey{}, true)
+}
+
+// IsExtra reports whether ctx was marked with Extra.
+func IsExtra(ctx context.Context) bool {
+	extra, _ := ctx.Value(extraKey{}).(bool)
+	return extra
+}
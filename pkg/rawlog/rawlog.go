@@ -0,0 +1,147 @@
+// Package rawlog keeps the last replies of the MCP server exactly as they
+// came, for `flo raw`: when the parser misreads the server after a change
+// of its format, the reply itself shows what changed.  Each call is a
+// JSON file <cache dir>/raw/<id>.json; ids count up from 1 and only the
+// newest Keep calls are kept.
+package rawlog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Keep is the number of calls kept.
+const Keep = 50
+
+// Entry is one call and the reply to it.
+type Entry struct {
+	ID   int            `json:"id"`
+	Time time.Time      `json:"time"`
+	Tool string         `json:"tool"`
+	Args map[string]any `json:"args,omitempty"`
+	// Error is why the call failed; the other reply fields are empty
+	// then.
+	Error string `json:"error,omitempty"`
+	// Result is the reply as the server sent it.
+	Result json.RawMessage `json:"result,omitempty"`
+	// Text is the text of the reply, the part flo parses.
+	Text string `json:"text,omitempty"`
+}
+
+// DefaultDir returns the raw directory inside cacheDir.
+func DefaultDir(cacheDir string) string {
+	return filepath.Join(cacheDir, "raw")
+}
+
+// Record stores e in dir under the next id, stamping it with the current
+// time when it has none, and drops the calls older than the newest Keep.
+// It returns the id.
+func Record(dir string, e Entry) (int, error) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return 0, err
+	}
+	// Another flo may take the same id meanwhile; O_EXCL makes the
+	// loser take the next one.
+	for range 10 {
+		ids, err := IDs(dir)
+		if err != nil {
+			return 0, err
+		}
+		e.ID = 1
+		if len(ids) > 0 {
+			e.ID = ids[len(ids)-1] + 1
+		}
+		data, err := json.Marshal(e)
+		if err != nil {
+			return 0, err
+		}
+		f, err := os.OpenFile(path(dir, e.ID), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		if _, err := f.Write(data); err != nil {
+			f.Close()
+			return 0, err
+		}
+		if err := f.Close(); err != nil {
+			return 0, err
+		}
+		for _, id := range ids[:max(len(ids)+1-Keep, 0)] {
+			os.Remove(path(dir, id))
+		}
+		return e.ID, nil
+	}
+	return 0, fmt.Errorf("no free id in %s", dir)
+}
+
+// IDs returns the ids of the calls kept in dir, oldest first; a missing
+// dir has none.
+func IDs(dir string) ([]int, error) {
+	names, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ids []int
+	for _, n := range names {
+		s, ok := strings.CutSuffix(n.Name(), ".json")
+		if !ok {
+			continue
+		}
+		if id, err := strconv.Atoi(s); err == nil && id > 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// Load reads call id from dir.  The error wraps fs.ErrNotExist when the
+// call is not kept.
+func Load(dir string, id int) (*Entry, error) {
+	data, err := os.ReadFile(path(dir, id))
+	if err != nil {
+		return nil, err
+	}
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("call %d: %w", id, err)
+	}
+	return &e, nil
+}
+
+// List reads the calls kept in dir, oldest first, skipping any that do
+// not parse.
+func List(dir string) ([]*Entry, error) {
+	ids, err := IDs(dir)
+	if err != nil {
+		return nil, err
+	}
+	var entries []*Entry
+	for _, id := range ids {
+		if e, err := Load(dir, id); err == nil {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+func path(dir string, id int) string {
+	return filepath.Join(dir, strconv.Itoa(id)+".json")
+}
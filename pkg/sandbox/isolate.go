@@ -0,0 +1,39 @@
+package sandbox
+
+import "errors"
+
+// Isolation is how Run confines a program, named for the tool it uses.
+type Isolation string
+
+const (
+	// NoIsolation runs the program as the user, with their files and
+	// network; Run does so only with Options.Unconfined.
+	NoIsolation Isolation = ""
+	// Bubblewrap runs it with bwrap in new namespaces: no network, and
+	// every file read-only but its own directory.
+	Bubblewrap Isolation = "bwrap"
+	// Unshare runs it with unshare in a new user, network and mount
+	// namespace: no network, and the home directory read-only.
+	Unshare Isolation = "unshare"
+	// SandboxExec runs it under macOS's sandbox-exec: no network, and
+	// nothing written outside its own directory.
+	SandboxExec Isolation = "sandbox-exec"
+)
+
+// ErrNoIsolation is returned by Run when this machine offers no way to
+// confine the program and Options.Unconfined is not set.
+var ErrNoIsolation = errors.New("no sandbox available (install bubblewrap on Linux)")
+
+// Describe says what a program run with iso can reach, for the user to
+// read before agreeing to run it.
+func (iso Isolation) Describe() string {
+	switch iso {
+	case Bubblewrap:
+		return "in a bubblewrap sandbox, with no network and your files read-only"
+	case Unshare:
+		return "in a new namespace (unshare), with no network and your home directory read-only"
+	case SandboxExec:
+		return "under sandbox-exec, with no network and your files read-only"
+	}
+	return "unconfined: as you, with your files and network"
+}
@@ -0,0 +1,42 @@
+//go:build darwin
+
+package sandbox
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Available returns SandboxExec when sandbox-exec is installed, as it is
+// on every macOS, else NoIsolation.
+func Available() Isolation {
+	if _, err := exec.LookPath("sandbox-exec"); err == nil {
+		return SandboxExec
+	}
+	return NoIsolation
+}
+
+// confine returns argv run under iso, able to write only to writable.
+func confine(iso Isolation, writable, argv []string) ([]string, error) {
+	if iso != SandboxExec {
+		return argv, nil
+	}
+	path, err := exec.LookPath("sandbox-exec")
+	if err != nil {
+		return nil, err
+	}
+	var profile strings.Builder
+	profile.WriteString("(version 1)\n(allow default)\n(deny network*)\n(deny file-write*)\n")
+	profile.WriteString(`(allow file-write* (literal "/dev/null") (regex #"^/dev/tty") (regex #"^/dev/fd/")`)
+	for _, dir := range writable {
+		// The profile matches real paths: /var is a link to /private/var.
+		if real, err := filepath.EvalSymlinks(dir); err == nil {
+			dir = real
+		}
+		profile.WriteString(" (subpath " + strconv.Quote(dir) + ")")
+	}
+	profile.WriteString(")\n")
+	return append([]string{path, "-p", profile.String()}, argv...), nil
+}
@@ -0,0 +1,67 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"os"
+	"os/exec"
+)
+
+// Available returns the isolation Run uses here: bwrap when it is
+// installed and works, else unshare when unprivileged user namespaces
+// are allowed, else NoIsolation.
+func Available() Isolation {
+	if path, err := exec.LookPath("bwrap"); err == nil &&
+		exec.Command(path, "--ro-bind", "/", "/", "--unshare-all", "true").Run() == nil {
+		return Bubblewrap
+	}
+	if path, err := exec.LookPath("unshare"); err == nil && exec.Command(path, "-rnm", "true").Run() == nil {
+		return Unshare
+	}
+	return NoIsolation
+}
+
+// unshareScript makes the home directory given first read-only, then
+// the directories after it up to "--" writable again, and runs the rest.
+const unshareScript = `set -e
+home=$1; shift
+mount --bind "$home" "$home"
+mount -o remount,bind,ro "$home"
+while [ "$1" != -- ]; do
+	mount --bind "$1" "$1"
+	mount -o remount,bind,rw "$1"
+	shift
+done
+shift
+exec "$@"`
+
+// confine returns argv run under iso, able to write only to writable.
+func confine(iso Isolation, writable, argv []string) ([]string, error) {
+	switch iso {
+	case Bubblewrap:
+		path, err := exec.LookPath("bwrap")
+		if err != nil {
+			return nil, err
+		}
+		args := []string{path, "--ro-bind", "/", "/", "--dev", "/dev", "--proc", "/proc"}
+		for _, dir := range writable {
+			args = append(args, "--bind", dir, dir)
+		}
+		args = append(args, "--unshare-all", "--die-with-parent", "--chdir", writable[0], "--")
+		return append(args, argv...), nil
+	case Unshare:
+		path, err := exec.LookPath("unshare")
+		if err != nil {
+			return nil, err
+		}
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		args := []string{path, "-rnm", "--", "sh", "-c", unshareScript, "sh", home}
+		args = append(args, writable...)
+		args = append(args, "--")
+		return append(args, argv...), nil
+	}
+	return argv, nil
+}
@@ -0,0 +1,25 @@
+//go:build windows
+
+package sandbox
+
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// setProcessGroup starts the program in a new process group, so that
+// console Ctrl+C events aimed at flo are left to flo.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// killTree kills the program and all of its descendants via taskkill.
+// Once the program has been reaped its PID may be reused, so nothing is
+// done in that case.
+func killTree(cmd *exec.Cmd) {
+	if cmd == nil || cmd.Process == nil || cmd.ProcessState != nil {
+		return
+	}
+	_ = exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+}
@@ -0,0 +1,264 @@
+// Package sandbox runs a code sample from an answer as a scratch
+// program: written to a new temporary directory and run there by the
+// language's own tool (go run, python3, node, bash), with no input, a
+// time limit and a cap on the output kept.
+//
+// The program is confined with what the system offers (see Available):
+// bwrap, or else unshare, on Linux and sandbox-exec on macOS, with no
+// network and the user's files read-only.  Elsewhere Run refuses, unless
+// told to run it unconfined.  The program starts in its own process
+// group, killed as a whole when time runs out, and sees little of the
+// environment: PATH, a locale, and HOME and TMPDIR pointing into the
+// scratch directory, which is removed afterwards.  It is still code
+// from a stranger: callers must show it and ask before running it.
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxOutput is how much of a program's output Run passes on; the rest
+// is read and dropped.
+const MaxOutput = 64 << 10
+
+// ErrUnsupported is returned for a language Run cannot run.
+var ErrUnsupported = errors.New("language not supported")
+
+// runner is how one language is run: the file the code goes in and the
+// command run on it, the tool first.
+type runner struct {
+	file string
+	tool []string // tried in order; the first on PATH is used
+	args []string
+	// check returns why code cannot run as it is, or nil.
+	check func(code string) error
+}
+
+var runners = map[string]runner{
+	"go":     {file: "main.go", tool: []string{"go"}, args: []string{"run", "main.go"}, check: checkGo},
+	"python": {file: "main.py", tool: []string{"python3", "python"}, args: []string{"main.py"}},
+	"node":   {file: "main.js", tool: []string{"node", "nodejs"}, args: []string{"main.js"}},
+	"bash":   {file: "main.sh", tool: []string{"bash"}, args: []string{"main.sh"}},
+}
+
+// aliases maps the names code fences and lexers use to a runner.
+var aliases = map[string]string{
+	"go": "go", "golang": "go",
+	"python": "python", "py": "python", "python3": "python",
+	"node": "node", "nodejs": "node", "javascript": "node", "js": "node",
+	"bash": "bash", "sh": "bash", "shell": "bash",
+}
+
+// Languages returns the languages Run takes, sorted.
+func Languages() []string {
+	names := make([]string, 0, len(runners))
+	for name := range runners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Normalize returns the language Run knows lang by — a code fence's
+// language or a lexer name such as "golang" or "javascript" — or "".
+func Normalize(lang string) string {
+	return aliases[strings.ToLower(strings.TrimSpace(lang))]
+}
+
+// Command returns the command line Run uses for lang, as shown to the
+// user before running.
+func Command(lang string) (string, error) {
+	r, ok := runners[lang]
+	if !ok {
+		return "", fmt.Errorf("%w: %q (want %s)", ErrUnsupported, lang, strings.Join(Languages(), ", "))
+	}
+	return r.tool[0] + " " + strings.Join(r.args, " "), nil
+}
+
+var rePackageMain = regexp.MustCompile(`(?m)^\s*package\s+main\b`)
+
+// Check returns why Run cannot run code as a program in lang, or nil:
+// the language is not one it takes, the code is not a whole program,
+// or the tool is not installed.
+func Check(lang, code string) error {
+	r, ok := runners[lang]
+	if !ok {
+		return fmt.Errorf("%w: %q (want %s)", ErrUnsupported, lang, strings.Join(Languages(), ", "))
+	}
+	if r.check != nil {
+		if err := r.check(code); err != nil {
+			return err
+		}
+	}
+	_, err := lookTool(r.tool)
+	return err
+}
+
+// checkGo requires a whole program: go run takes nothing less.
+func checkGo(code string) error {
+	if !rePackageMain.MatchString(code) {
+		return errors.New("the Go code is not a whole program: it has no package main")
+	}
+	return nil
+}
+
+// Options are the settings of a Run.
+type Options struct {
+	// Timeout is how long the program may run, building included.
+	Timeout time.Duration
+	// Output gets the program's output, stdout and stderr together, as
+	// it comes, up to MaxOutput bytes.
+	Output io.Writer
+	// Unconfined runs the program as the user, with their files and
+	// network, when the system offers no isolation.
+	Unconfined bool
+	// GoCache is the build cache Go programs use, kept between runs so
+	// that each does not build the standard library afresh.  It must be
+	// flo's own, never the user's, since programs may write to it.  When
+	// empty, a program gets a new cache in the scratch directory.
+	GoCache string
+}
+
+// Result is how a program ended.
+type Result struct {
+	// ExitCode is the program's exit status; -1 when it was killed.
+	ExitCode int
+	// TimedOut is set when the program was stopped at the time limit.
+	TimedOut bool
+	// Truncated is set when the output was longer than MaxOutput.
+	Truncated bool
+	Elapsed   time.Duration
+	// Isolation is how the program was confined.
+	Isolation Isolation
+}
+
+// Run writes code to a new temporary directory and runs it there as a
+// program in lang (one of Languages), as described in the package
+// comment.  The error reports what kept the program from starting —
+// ErrNoIsolation when it cannot be confined; a program that fails or
+// runs out of time is a Result.
+func Run(ctx context.Context, lang, code string, opts Options) (*Result, error) {
+	if err := Check(lang, code); err != nil {
+		return nil, err
+	}
+	r := runners[lang]
+	tool, err := lookTool(r.tool)
+	if err != nil {
+		return nil, err
+	}
+	iso := Available()
+	if iso == NoIsolation && !opts.Unconfined {
+		return nil, ErrNoIsolation
+	}
+
+	dir, err := os.MkdirTemp("", "flo-run-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	if !strings.HasSuffix(code, "\n") {
+		code += "\n"
+	}
+	if err := os.WriteFile(filepath.Join(dir, r.file), []byte(code), 0o600); err != nil {
+		return nil, err
+	}
+
+	rctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+	out := &limitWriter{w: opts.Output, left: MaxOutput}
+	if out.w == nil {
+		out.w = io.Discard
+	}
+	writable := []string{dir}
+	goCache := filepath.Join(dir, "go-build")
+	if lang == "go" && opts.GoCache != "" && os.MkdirAll(opts.GoCache, 0o700) == nil {
+		goCache = opts.GoCache
+		writable = append(writable, goCache)
+	}
+	argv, err := confine(iso, writable, append([]string{tool}, r.args...))
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.CommandContext(rctx, argv[0], argv[1:]...)
+	cmd.Dir = dir
+	cmd.Env = environ(lang, dir, goCache)
+	cmd.Stdout, cmd.Stderr = out, out
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error {
+		killTree(cmd)
+		return nil
+	}
+	// Children holding the output open must not keep Run waiting.
+	cmd.WaitDelay = time.Second
+
+	start := time.Now()
+	err = cmd.Run()
+	res := &Result{ExitCode: -1, Elapsed: time.Since(start), Truncated: out.dropped, Isolation: iso}
+	res.TimedOut = errors.Is(rctx.Err(), context.DeadlineExceeded)
+	if cmd.ProcessState != nil {
+		res.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) && !res.TimedOut && !errors.Is(err, exec.ErrWaitDelay) {
+		return nil, err
+	}
+	return res, nil
+}
+
+// lookTool returns the path of the first of names on PATH.
+func lookTool(names []string) (string, error) {
+	for _, name := range names {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%s is not installed, or not on PATH", names[0])
+}
+
+// environ is the environment a program in lang runs with in dir, Go
+// programs building into goCache.
+func environ(lang, dir, goCache string) []string {
+	env := []string{"PATH=" + os.Getenv("PATH"), "HOME=" + dir, "TMPDIR=" + dir, "TMP=" + dir, "TEMP=" + dir}
+	for _, k := range []string{"LANG", "LC_ALL", "SYSTEMROOT"} {
+		if v, ok := os.LookupEnv(k); ok {
+			env = append(env, k+"="+v)
+		}
+	}
+	if lang == "go" {
+		// Fetch nothing and use the Go installed.
+		env = append(env, "GOCACHE="+goCache, "GOPATH="+filepath.Join(dir, "go"), "GOPROXY=off", "GOTOOLCHAIN=local", "GOFLAGS=")
+	}
+	return env
+}
+
+// limitWriter passes on up to left bytes and drops the rest, noting
+// that it did.
+type limitWriter struct {
+	w       io.Writer
+	left    int
+	dropped bool
+}
+
+func (l *limitWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if len(p) > l.left {
+		p, l.dropped = p[:l.left], true
+	}
+	l.left -= len(p)
+	if len(p) > 0 {
+		if _, err := l.w.Write(p); err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
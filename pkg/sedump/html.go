@@ -0,0 +1,79 @@
+// Package sedump – html.go converts the HTML post bodies found in data
+// dumps into the Markdown dialect flo renders elsewhere.  It handles the
+// subset of HTML Stack Exchange emits (paragraphs, code, lists, links,
+// emphasis, headings, quotes); anything else is reduced to its text.
+package sedump
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	rePre       = regexp.MustCompile(`(?is)<pre[^>]*>\s*(?:<code[^>]*>)?(.*?)(?:</code>)?\s*</pre>`)
+	reCode      = regexp.MustCompile(`(?is)<code>(.*?)</code>`)
+	reLink      = regexp.MustCompile(`(?is)<a\s[^>]*href="([^"]*)"[^>]*>(.*?)</a>`)
+	reImg       = regexp.MustCompile(`(?is)<img\s[^>]*src="([^"]*)"[^>]*>`)
+	reAlt       = regexp.MustCompile(`(?i)alt="([^"]*)"`)
+	reHeading   = regexp.MustCompile(`(?is)<h([1-6])[^>]*>(.*?)</h[1-6]>`)
+	reStrong    = regexp.MustCompile(`(?is)<(?:strong|b)>(.*?)</(?:strong|b)>`)
+	reEm        = regexp.MustCompile(`(?is)<(?:em|i)>(.*?)</(?:em|i)>`)
+	reLi        = regexp.MustCompile(`(?is)<li[^>]*>(.*?)</li>`)
+	reBlockq    = regexp.MustCompile(`(?is)<blockquote[^>]*>(.*?)</blockquote>`)
+	reBreak     = regexp.MustCompile(`(?i)<br\s*/?>`)
+	reHr        = regexp.MustCompile(`(?i)<hr\s*/?>`)
+	reParaEnd   = regexp.MustCompile(`(?i)</p>|</ul>|</ol>|</div>`)
+	reTag       = regexp.MustCompile(`(?s)<[^>]+>`)
+	reBlankRuns = regexp.MustCompile(`\n{3,}`)
+)
+
+// HTMLToMarkdown converts a Stack Exchange HTML body to Markdown.
+func HTMLToMarkdown(s string) string {
+	// Code blocks first, protected from later rewriting by placeholders.
+	var blocks []string
+	s = rePre.ReplaceAllStringFunc(s, func(m string) string {
+		code := html.UnescapeString(rePre.FindStringSubmatch(m)[1])
+		blocks = append(blocks, "\n```\n"+strings.TrimRight(code, "\n")+"\n```\n")
+		return placeholder(len(blocks) - 1)
+	})
+
+	s = reCode.ReplaceAllString(s, "`$1`")
+	s = reImg.ReplaceAllStringFunc(s, func(m string) string {
+		alt := ""
+		if sub := reAlt.FindStringSubmatch(m); sub != nil {
+			alt = sub[1]
+		}
+		return "![" + alt + "](" + reImg.FindStringSubmatch(m)[1] + ")"
+	})
+	s = reLink.ReplaceAllString(s, "[$2]($1)")
+	s = reHeading.ReplaceAllStringFunc(s, func(m string) string {
+		sub := reHeading.FindStringSubmatch(m)
+		return "\n" + strings.Repeat("#", int(sub[1][0]-'0')) + " " + sub[2] + "\n"
+	})
+	s = reStrong.ReplaceAllString(s, "**$1**")
+	s = reEm.ReplaceAllString(s, "*$1*")
+	s = reLi.ReplaceAllString(s, "- $1\n")
+	s = reBlockq.ReplaceAllStringFunc(s, func(m string) string {
+		inner := strings.TrimSpace(reTag.ReplaceAllString(reBlockq.FindStringSubmatch(m)[1], ""))
+		return "\n> " + strings.ReplaceAll(inner, "\n", "\n> ") + "\n"
+	})
+	s = reBreak.ReplaceAllString(s, "  \n")
+	s = reHr.ReplaceAllString(s, "\n---\n")
+	s = reParaEnd.ReplaceAllString(s, "\n\n")
+	s = reTag.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+
+	for i, b := range blocks {
+		s = strings.Replace(s, placeholder(i), b, 1)
+	}
+	s = reBlankRuns.ReplaceAllString(s, "\n\n")
+	return strings.TrimSpace(s)
+}
+
+// placeholder marks where code block i is re-inserted.  NUL never occurs
+// in post bodies, so it cannot collide with real text.
+func placeholder(i int) string {
+	return "\x00" + strconv.Itoa(i) + "\x00"
+}
@@ -0,0 +1,174 @@
+// Package sedump reads the Posts.xml file from an official Stack Exchange
+// data dump (https://archive.org/details/stackexchange) so its questions
+// and answers can be loaded into flo's local index for fully offline use.
+//
+// Each post is a single <row/> element:
+//
+//	<row Id="4" PostTypeId="1" AcceptedAnswerId="7" Score="630"
+//	     Title="..." Tags="|c#|floating-point|" Body="&lt;p&gt;...&lt;/p&gt;" ... />
+//
+// PostTypeId 1 is a question and 2 an answer (with ParentId pointing at
+// its question); other post types are skipped.  Bodies are HTML and are
+// converted to Markdown so they render like live results.
+package sedump
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Post is a decoded question or answer.
+type Post struct {
+	ID               int
+	IsQuestion       bool
+	ParentID         int // answers only
+	AcceptedAnswerID int // questions only
+	Title            string
+	Tags             []string
+	BodyMarkdown     string
+	Score            int
+	ViewCount        int
+	AnswerCount      int
+	Owner            string
+	CreationDate     time.Time
+
+	// ParentTitle and ParentTags are copied from the question for answers.
+	ParentTitle string
+	ParentTags  []string
+	// Accepted reports whether an answer is its question's accepted one.
+	Accepted bool
+}
+
+// Filter selects which posts are imported.
+type Filter struct {
+	// Tags keeps questions having at least one of these tags (all when
+	// empty).  Answers are kept when their question is.
+	Tags []string
+	// MinScore drops posts scoring below it.
+	MinScore int
+}
+
+// row mirrors the attributes of a Posts.xml <row/>.
+type row struct {
+	ID               int    `xml:"Id,attr"`
+	PostTypeID       int    `xml:"PostTypeId,attr"`
+	ParentID         int    `xml:"ParentId,attr"`
+	AcceptedAnswerID int    `xml:"AcceptedAnswerId,attr"`
+	CreationDate     string `xml:"CreationDate,attr"`
+	Score            int    `xml:"Score,attr"`
+	ViewCount        int    `xml:"ViewCount,attr"`
+	Body             string `xml:"Body,attr"`
+	OwnerDisplayName string `xml:"OwnerDisplayName,attr"`
+	OwnerUserID      int    `xml:"OwnerUserId,attr"`
+	Title            string `xml:"Title,attr"`
+	Tags             string `xml:"Tags,attr"`
+	AnswerCount      int    `xml:"AnswerCount,attr"`
+}
+
+// parentInfo is what answers need to know about their question.
+type parentInfo struct {
+	title    string
+	tags     []string
+	accepted int
+}
+
+// Read streams posts from r, calling fn for every post that passes f.
+// Dumps list questions before their answers, so a single pass suffices;
+// answers whose question was filtered out (or not yet seen) are skipped.
+func Read(r io.Reader, f Filter, fn func(*Post) error) error {
+	want := make(map[string]bool, len(f.Tags))
+	for _, t := range f.Tags {
+		want[strings.ToLower(strings.TrimSpace(t))] = true
+	}
+	parents := make(map[int]parentInfo)
+
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read dump: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+		var rw row
+		if err := dec.DecodeElement(&rw, &start); err != nil {
+			return fmt.Errorf("decode row: %w", err)
+		}
+
+		var p *Post
+		switch rw.PostTypeID {
+		case 1:
+			tags := parseTags(rw.Tags)
+			if len(want) > 0 && !anyTag(tags, want) || rw.Score < f.MinScore {
+				continue
+			}
+			parents[rw.ID] = parentInfo{title: rw.Title, tags: tags, accepted: rw.AcceptedAnswerID}
+			p = &Post{
+				IsQuestion:       true,
+				AcceptedAnswerID: rw.AcceptedAnswerID,
+				Title:            rw.Title,
+				Tags:             tags,
+				ViewCount:        rw.ViewCount,
+				AnswerCount:      rw.AnswerCount,
+			}
+		case 2:
+			parent, ok := parents[rw.ParentID]
+			if !ok || rw.Score < f.MinScore {
+				continue
+			}
+			p = &Post{
+				ParentID:    rw.ParentID,
+				ParentTitle: parent.title,
+				ParentTags:  parent.tags,
+				Accepted:    parent.accepted == rw.ID,
+			}
+		default:
+			continue
+		}
+
+		p.ID = rw.ID
+		p.Score = rw.Score
+		p.BodyMarkdown = HTMLToMarkdown(rw.Body)
+		p.Owner = rw.OwnerDisplayName
+		if p.Owner == "" && rw.OwnerUserID != 0 {
+			p.Owner = fmt.Sprintf("user%d", rw.OwnerUserID)
+		}
+		p.CreationDate, _ = time.Parse("2006-01-02T15:04:05.000", rw.CreationDate)
+
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+}
+
+// parseTags handles both the legacy "<a><b>" and the current "|a|b|"
+// tag encodings.
+func parseTags(s string) []string {
+	s = strings.NewReplacer("<", "|", ">", "|").Replace(s)
+	var tags []string
+	for _, t := range strings.Split(s, "|") {
+		if t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// anyTag reports whether any of tags is in want.
+func anyTag(tags []string, want map[string]bool) bool {
+	for _, t := range tags {
+		if want[strings.ToLower(t)] {
+			return true
+		}
+	}
+	return false
+}
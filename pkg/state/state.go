@@ -0,0 +1,244 @@
+// Package state archives flo's files — configuration, user data and
+// cache — into one .tar.gz bundle, to move them to another machine or
+// keep a backup, and restores such bundles.
+//
+// Each directory is stored under a root name ("config", "data",
+// "cache"), so a bundle restores into whatever those directories are on
+// the importing machine.  A manifest, flo-state.json, comes first.
+package state
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/atomicfile"
+)
+
+// Version is the bundle format version written to the manifest.
+const Version = 1
+
+// manifestName is the first entry of every bundle.
+const manifestName = "flo-state.json"
+
+// Root is a directory stored in a bundle under Name.
+type Root struct {
+	Name string
+	Dir  string
+}
+
+// Manifest describes a bundle.
+type Manifest struct {
+	Version int       `json:"version"`
+	Created time.Time `json:"created"`
+	Host    string    `json:"host,omitempty"`
+	Roots   []string  `json:"roots"`
+	Files   int       `json:"files"`
+	Bytes   int64     `json:"bytes"`
+}
+
+// SkipFunc reports whether the file rel (slash-separated, relative to
+// the root) should be left out of a bundle or an import.
+type SkipFunc func(root, rel string) bool
+
+// MergeFunc combines a file already present on disk with the bundle's
+// copy, returning what to write.
+type MergeFunc func(root, rel string, existing, incoming []byte) ([]byte, error)
+
+// Export writes the files under roots to w as a gzipped tar, skipping
+// temporary files and those skip selects (skip may be nil).  Missing
+// directories are left out.
+func Export(w io.Writer, roots []Root, skip SkipFunc) (*Manifest, error) {
+	type file struct {
+		root, rel, abs string
+		info           fs.FileInfo
+	}
+	m := &Manifest{Version: Version, Created: time.Now().UTC()}
+	m.Host, _ = os.Hostname()
+	var files []file
+	for _, r := range roots {
+		found := false
+		err := filepath.WalkDir(r.Dir, func(abs string, d fs.DirEntry, err error) error {
+			if errors.Is(err, fs.ErrNotExist) && abs == r.Dir {
+				return filepath.SkipDir
+			}
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(r.Dir, abs)
+			if err != nil || rel == "." {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+			if skip != nil && skip(r.Name, rel) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() || strings.HasSuffix(rel, ".tmp") {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			files = append(files, file{r.Name, rel, abs, info})
+			m.Bytes += info.Size()
+			found = true
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", r.Dir, err)
+		}
+		if found {
+			m.Roots = append(m.Roots, r.Name)
+		}
+	}
+	m.Files = len(files)
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeEntry(tw, manifestName, 0o644, m.Created, manifest); err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(f.abs)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeEntry(tw, f.root+"/"+f.rel, f.info.Mode().Perm(), f.info.ModTime(), data); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// writeEntry adds a regular file to tw.
+func writeEntry(tw *tar.Writer, name string, mode fs.FileMode, mtime time.Time, data []byte) error {
+	hdr := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     int64(mode),
+		Size:     int64(len(data)),
+		ModTime:  mtime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// Result reports what Import did.
+type Result struct {
+	Manifest *Manifest
+	// Written lists the files restored, as root/rel.
+	Written []string
+	// Skipped counts files left alone: roots not imported and files
+	// skip selected.
+	Skipped int
+}
+
+// Import restores the bundle read from r into roots, replacing existing
+// files — except where merge (which may be nil) returns their
+// combination.  Entries for roots not listed, and those skip selects,
+// are skipped.  With dryRun set nothing is written, but Result lists
+// what would be.
+func Import(r io.Reader, roots []Root, skip SkipFunc, merge MergeFunc, dryRun bool) (*Result, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a flo state bundle: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != manifestName {
+		return nil, errors.New("not a flo state bundle: no " + manifestName)
+	}
+	res := &Result{Manifest: &Manifest{}}
+	if err := json.NewDecoder(tr).Decode(res.Manifest); err != nil {
+		return nil, fmt.Errorf("read %s: %w", manifestName, err)
+	}
+	if res.Manifest.Version > Version {
+		return nil, fmt.Errorf("bundle format %d is newer than this flo supports (%d); update flo", res.Manifest.Version, Version)
+	}
+
+	dirs := make(map[string]string, len(roots))
+	for _, rt := range roots {
+		dirs[rt.Name] = rt.Dir
+	}
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return res, fmt.Errorf("read bundle: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		root, rel, ok := strings.Cut(hdr.Name, "/")
+		if !ok || !filepath.IsLocal(filepath.FromSlash(rel)) || path.Clean(rel) != rel {
+			return res, fmt.Errorf("bundle entry %q escapes its directory", hdr.Name)
+		}
+		dir, ok := dirs[root]
+		if !ok || (skip != nil && skip(root, rel)) {
+			res.Skipped++
+			continue
+		}
+		res.Written = append(res.Written, hdr.Name)
+		if dryRun {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return res, fmt.Errorf("read %s: %w", hdr.Name, err)
+		}
+		dst := filepath.Join(dir, filepath.FromSlash(rel))
+		if merge != nil {
+			if existing, err := os.ReadFile(dst); err == nil {
+				if data, err = merge(root, rel, existing, data); err != nil {
+					return res, fmt.Errorf("merge %s: %w", hdr.Name, err)
+				}
+			}
+		}
+		if err := writeFile(dst, data, fs.FileMode(hdr.Mode).Perm(), hdr.ModTime); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// writeFile replaces path atomically with data, dated mtime.
+func writeFile(path string, data []byte, perm fs.FileMode, mtime time.Time) error {
+	if perm == 0 {
+		perm = 0o644
+	}
+	if err := atomicfile.WriteFile(path, data, perm); err != nil {
+		return err
+	}
+	_ = os.Chtimes(path, mtime, mtime)
+	return nil
+}
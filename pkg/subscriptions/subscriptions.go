@@ -0,0 +1,145 @@
+// Package subscriptions persists the tags a user follows, with per-tag
+// options, for the features that watch tags rather than answer queries
+// (flo digest among them).  The store is a JSON file in the user data
+// directory.
+package subscriptions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/atomicfile"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+)
+
+// Subscription is a followed tag.
+type Subscription struct {
+	Tag string `json:"tag"`
+	// MinScore is the lowest score of questions reported; zero leaves it
+	// to the feature's default.
+	MinScore int `json:"min_score,omitempty"`
+	// UnansweredOnly reports only questions without an accepted or
+	// upvoted answer — for people who want to answer them.
+	UnansweredOnly bool      `json:"unanswered_only,omitempty"`
+	SubscribedAt   time.Time `json:"subscribed_at"`
+}
+
+// Matches reports whether q passes s's options, with minScore standing
+// in for an unset MinScore.
+func (s *Subscription) Matches(q *mcp.QuestionData, minScore int) bool {
+	if s.MinScore > 0 {
+		minScore = s.MinScore
+	}
+	return q.Score >= minScore && !(s.UnansweredOnly && q.IsAnswered)
+}
+
+// Options describes s's options in a few words, or "" when it has none.
+func (s *Subscription) Options() string {
+	var opts []string
+	if s.MinScore > 0 {
+		opts = append(opts, fmt.Sprintf("score ≥ %d", s.MinScore))
+	}
+	if s.UnansweredOnly {
+		opts = append(opts, "unanswered only")
+	}
+	return strings.Join(opts, ", ")
+}
+
+// NormalizeTag lower-cases a tag and checks it is a single word, as
+// Stack Overflow tags are.
+func NormalizeTag(tag string) (string, error) {
+	t := strings.ToLower(strings.TrimSpace(tag))
+	if t == "" || strings.ContainsAny(t, " \t[]") {
+		return "", fmt.Errorf("invalid tag %q", tag)
+	}
+	return t, nil
+}
+
+// Store is the subscription list backed by a JSON file.
+type Store struct {
+	path  string
+	items []*Subscription
+}
+
+// DefaultPath returns subscriptions.json inside dataDir.
+func DefaultPath(dataDir string) string {
+	return filepath.Join(dataDir, "subscriptions.json")
+}
+
+// Open loads the store at path; a missing file yields an empty store.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("read subscriptions: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.items); err != nil {
+		return nil, fmt.Errorf("parse subscriptions %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// All returns the subscriptions sorted by tag.
+func (s *Store) All() []*Subscription {
+	out := make([]*Subscription, len(s.items))
+	copy(out, s.items)
+	sort.Slice(out, func(i, j int) bool { return out[i].Tag < out[j].Tag })
+	return out
+}
+
+// Get returns the subscription to tag, or nil.
+func (s *Store) Get(tag string) *Subscription {
+	for _, sub := range s.items {
+		if sub.Tag == tag {
+			return sub
+		}
+	}
+	return nil
+}
+
+// Set adds sub, replacing the options of an existing subscription to
+// the same tag but keeping when it was made.  It reports whether sub
+// was new.
+func (s *Store) Set(sub Subscription) bool {
+	if old := s.Get(sub.Tag); old != nil {
+		sub.SubscribedAt = old.SubscribedAt
+		*old = sub
+		return false
+	}
+	if sub.SubscribedAt.IsZero() {
+		sub.SubscribedAt = time.Now()
+	}
+	s.items = append(s.items, &sub)
+	return true
+}
+
+// Remove drops the subscription to tag and reports whether there was
+// one.
+func (s *Store) Remove(tag string) bool {
+	for i, sub := range s.items {
+		if sub.Tag == tag {
+			s.items = append(s.items[:i], s.items[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Save writes the store back to disk atomically.
+func (s *Store) Save() error {
+	data, err := json.MarshalIndent(s.All(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(s.path, data, 0o644)
+}
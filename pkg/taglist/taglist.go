@@ -0,0 +1,96 @@
+// Package taglist keeps a local copy of a Stack Exchange site's most
+// popular tags, so they can be completed without a request per key
+// press.  The copy is a JSON file in the user cache directory, refreshed
+// when it is older than MaxAge.
+package taglist
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/atomicfile"
+)
+
+// MaxAge is how long a fetched list is used before it is refreshed.
+// Popular tags change slowly.
+const MaxAge = 7 * 24 * time.Hour
+
+// List is a cached tag list, most popular first.
+type List struct {
+	Site      string    `json:"site"`
+	FetchedAt time.Time `json:"fetched_at"`
+	Tags      []string  `json:"tags"`
+}
+
+// DefaultPath returns the list file for site inside cacheDir.
+func DefaultPath(cacheDir, site string) string {
+	return filepath.Join(cacheDir, "tags-"+site+".json")
+}
+
+// Load reads the list at path; a missing file is an empty list.
+func Load(path string) (*List, error) {
+	l := &List{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return l, fmt.Errorf("read tag list: %w", err)
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		return &List{}, fmt.Errorf("parse tag list %s: %w", path, err)
+	}
+	return l, nil
+}
+
+// Save writes l to path, replacing it atomically so a concurrent Load
+// never sees half a file.
+func (l *List) Save(path string) error {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(path, data, 0o644)
+}
+
+// Stale reports whether l should be fetched again at now.
+func (l *List) Stale(now time.Time) bool {
+	return len(l.Tags) == 0 || now.Sub(l.FetchedAt) > MaxAge
+}
+
+// Complete returns the tags among candidates that start with prefix,
+// ignoring case, in their original order and without duplicates.
+func Complete(candidates []string, prefix string) []string {
+	prefix = strings.ToLower(prefix)
+	seen := make(map[string]bool)
+	var out []string
+	for _, c := range candidates {
+		if seen[c] || !strings.HasPrefix(strings.ToLower(c), prefix) {
+			continue
+		}
+		seen[c] = true
+		out = append(out, c)
+	}
+	return out
+}
+
+// Merge joins tag lists, keeping the first occurrence of each tag.
+func Merge(lists ...[]string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, l := range lists {
+		for _, t := range l {
+			if t != "" && !seen[t] {
+				seen[t] = true
+				out = append(out, t)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,240 @@
+// Package telemetry keeps anonymous counts of how flo is used and how
+// it fails, for users who opt in: which commands run, which backend
+// calls they make, how long those take, in coarse buckets, and what
+// class of error they end with.  Queries, titles, tags, IDs and
+// addresses are never recorded.
+//
+// The counts of a run are aggregated in memory and merged into
+// <data dir>/telemetry.json once, at exit.  Nothing is sent anywhere:
+// the user exports the file to share it.
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/atomicfile"
+)
+
+// Buckets are the upper bounds of the latency buckets.
+var Buckets = []time.Duration{
+	100 * time.Millisecond,
+	300 * time.Millisecond,
+	time.Second,
+	3 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+}
+
+// Bucket names the latency bucket d falls in: "<1s", or ">=30s" past
+// the last bound.
+func Bucket(d time.Duration) string {
+	for _, b := range Buckets {
+		if d < b {
+			return "<" + b.String()
+		}
+	}
+	return ">=" + Buckets[len(Buckets)-1].String()
+}
+
+// Stat counts the runs of one command or call.
+type Stat struct {
+	Count int `json:"count"`
+	// Errors counts the runs that failed, by error class.
+	Errors map[string]int `json:"errors,omitempty"`
+	// Latency counts the runs by latency bucket (see Bucket).
+	Latency map[string]int `json:"latency"`
+}
+
+func (s *Stat) add(d time.Duration, class string) {
+	s.Count++
+	if s.Latency == nil {
+		s.Latency = make(map[string]int)
+	}
+	s.Latency[Bucket(d)]++
+	if class != "" {
+		if s.Errors == nil {
+			s.Errors = make(map[string]int)
+		}
+		s.Errors[class]++
+	}
+}
+
+func (s *Stat) merge(o *Stat) {
+	s.Count += o.Count
+	for k, n := range o.Latency {
+		if s.Latency == nil {
+			s.Latency = make(map[string]int)
+		}
+		s.Latency[k] += n
+	}
+	for k, n := range o.Errors {
+		if s.Errors == nil {
+			s.Errors = make(map[string]int)
+		}
+		s.Errors[k] += n
+	}
+}
+
+// Failures returns how many runs failed.
+func (s *Stat) Failures() int {
+	n := 0
+	for _, c := range s.Errors {
+		n += c
+	}
+	return n
+}
+
+// Report is the aggregate kept on disk and exported.
+type Report struct {
+	// Since is when counting started; Updated, when it last changed.
+	Since   time.Time `json:"since"`
+	Updated time.Time `json:"updated"`
+	// Version, OS and Arch describe the flo that last added counts.
+	Version string `json:"version"`
+	OS      string `json:"os"`
+	Arch    string `json:"arch"`
+	// Commands are keyed by command path, e.g. "ask" or "cache prune".
+	Commands map[string]*Stat `json:"commands"`
+	// Calls are keyed by backend and operation, e.g. "mcp so_search".
+	Calls map[string]*Stat `json:"calls"`
+}
+
+// Encode returns r as indented JSON, with the buckets' "<" kept
+// readable.
+func (r *Report) Encode() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Names returns the keys of stats, most frequent first.
+func Names(stats map[string]*Stat) []string {
+	names := make([]string, 0, len(stats))
+	for n := range stats {
+		names = append(names, n)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := stats[names[i]], stats[names[j]]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// DefaultPath returns telemetry.json inside dataDir.
+func DefaultPath(dataDir string) string {
+	return filepath.Join(dataDir, "telemetry.json")
+}
+
+// Load reads the report at path; a missing file is an empty report.
+func Load(path string) (*Report, error) {
+	r := &Report{Commands: map[string]*Stat{}, Calls: map[string]*Stat{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read telemetry: %w", err)
+	}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("parse telemetry %s: %w", path, err)
+	}
+	if r.Commands == nil {
+		r.Commands = map[string]*Stat{}
+	}
+	if r.Calls == nil {
+		r.Calls = map[string]*Stat{}
+	}
+	return r, nil
+}
+
+// Recorder aggregates the counts of one run.  It is safe for concurrent
+// use.
+type Recorder struct {
+	mu       sync.Mutex
+	commands map[string]*Stat
+	calls    map[string]*Stat
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{commands: map[string]*Stat{}, calls: map[string]*Stat{}}
+}
+
+// Command counts a run of the command name that took d and ended with
+// the error class, "" for success.
+func (r *Recorder) Command(name string, d time.Duration, class string) {
+	r.add(r.commands, name, d, class)
+}
+
+// Call counts a backend call, like Command.
+func (r *Recorder) Call(name string, d time.Duration, class string) {
+	r.add(r.calls, name, d, class)
+}
+
+func (r *Recorder) add(m map[string]*Stat, name string, d time.Duration, class string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := m[name]
+	if s == nil {
+		s = &Stat{}
+		m[name] = s
+	}
+	s.add(d, class)
+}
+
+// mergeStats adds the counts in src to dst.
+func mergeStats(dst, src map[string]*Stat) {
+	for name, s := range src {
+		if dst[name] == nil {
+			dst[name] = &Stat{}
+		}
+		dst[name].merge(s)
+	}
+}
+
+// Flush merges the counts into the report at path, stamped with
+// version, os and arch, and empties the recorder.
+func (r *Recorder) Flush(path, version, goos, arch string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.commands) == 0 && len(r.calls) == 0 {
+		return nil
+	}
+	rep, err := Load(path)
+	if err != nil {
+		return err
+	}
+	// Hours are precise enough, and say less about the user.
+	now := time.Now().UTC().Truncate(time.Hour)
+	if rep.Since.IsZero() {
+		rep.Since = now
+	}
+	rep.Updated = now
+	rep.Version, rep.OS, rep.Arch = version, goos, arch
+	mergeStats(rep.Commands, r.commands)
+	mergeStats(rep.Calls, r.calls)
+	r.commands, r.calls = map[string]*Stat{}, map[string]*Stat{}
+
+	data, err := rep.Encode()
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,145 @@
+// Package ticker keeps a rolling feed of the hot questions in a set of
+// tags: the daemon (flo serve --ticker) polls each tag for the best
+// scoring questions of the last hours and adds those it has not seen,
+// and `flo ticker` prints the feed, or follows it, one line per
+// question.
+//
+// The feed is a JSON file in the user data directory, so any number of
+// tickers can follow one daemon.
+package ticker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/atomicfile"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+)
+
+// Item is a question in the feed.
+type Item struct {
+	QuestionID int    `json:"question_id"`
+	Title      string `json:"title"`
+	// Tag is the subscribed tag the question was found in.
+	Tag      string    `json:"tag"`
+	Score    int       `json:"score"`
+	Answers  int       `json:"answers"`
+	Answered bool      `json:"answered"`
+	Link     string    `json:"link,omitempty"`
+	Created  time.Time `json:"created"`
+	// Seen is when the question entered the feed.
+	Seen time.Time `json:"seen"`
+}
+
+// Feed is the rolling feed, oldest entry first.
+type Feed struct {
+	// Updated is when the daemon last polled.
+	Updated time.Time `json:"updated"`
+	Items   []Item    `json:"items"`
+}
+
+// DefaultPath returns ticker.json inside dataDir.
+func DefaultPath(dataDir string) string {
+	return filepath.Join(dataDir, "ticker.json")
+}
+
+// Load reads the feed at path; a missing file is an empty feed.
+func Load(path string) (*Feed, error) {
+	f := &Feed{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read ticker feed: %w", err)
+	}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("parse ticker feed %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Save writes the feed to path atomically, so a ticker following it
+// never reads half of it.
+func (f *Feed) Save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(path, data, 0o644)
+}
+
+// Add puts the questions found in tag at now into the feed: those not
+// in it yet are appended, and returned; those already in it get their
+// score and answers updated.
+func (f *Feed) Add(tag string, qs []mcp.QuestionData, now time.Time) []Item {
+	index := make(map[int]int, len(f.Items))
+	for i, it := range f.Items {
+		index[it.QuestionID] = i
+	}
+	var added []Item
+	for _, q := range qs {
+		if q.QuestionID == 0 {
+			continue
+		}
+		if i, ok := index[q.QuestionID]; ok {
+			f.Items[i].Score, f.Items[i].Answers, f.Items[i].Answered = q.Score, q.AnswerCount, q.IsAnswered
+			continue
+		}
+		it := Item{
+			QuestionID: q.QuestionID,
+			Title:      html.UnescapeString(q.Title),
+			Tag:        tag,
+			Score:      q.Score,
+			Answers:    q.AnswerCount,
+			Answered:   q.IsAnswered,
+			Link:       q.Link,
+			Created:    time.Unix(q.CreationDate, 0),
+			Seen:       now,
+		}
+		index[it.QuestionID] = len(f.Items)
+		f.Items = append(f.Items, it)
+		added = append(added, it)
+	}
+	return added
+}
+
+// Prune drops the entries seen before now minus keep, and the oldest
+// past limit entries.
+func (f *Feed) Prune(now time.Time, keep time.Duration, limit int) {
+	sort.SliceStable(f.Items, func(i, j int) bool { return f.Items[i].Seen.Before(f.Items[j].Seen) })
+	cut := 0
+	for cut < len(f.Items) && now.Sub(f.Items[cut].Seen) > keep {
+		cut++
+	}
+	cut = max(cut, len(f.Items)-limit)
+	f.Items = append([]Item(nil), f.Items[cut:]...)
+}
+
+// After returns the entries seen after t.
+func (f *Feed) After(t time.Time) []Item {
+	var out []Item
+	for _, it := range f.Items {
+		if it.Seen.After(t) {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+// Line formats it as one line, in columns: time seen, tag, score,
+// answers and title.
+func (it *Item) Line() string {
+	answers := fmt.Sprintf("%d answers", it.Answers)
+	if it.Answers == 1 {
+		answers = "1 answer"
+	}
+	return fmt.Sprintf("%s  %-14s %+4d  %-10s  %s", it.Seen.Local().Format("15:04"), "["+it.Tag+"]", it.Score, answers, it.Title)
+}
@@ -0,0 +1,69 @@
+package translate
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// batchSize bounds the texts sent in one request; DeepL takes 50.
+const batchSize = 50
+
+// lineMarker matches the Markdown a line starts with — list bullets and
+// numbers, quotes and headings — which is kept out of the translation.
+var lineMarker = regexp.MustCompile(`^\s*(?:(?:[-*+]|\d+[.)]|>+|#{1,6})\s+)*`)
+
+// Markdown translates the prose of the Markdown document md from source
+// ("" to detect it) into target, line by line.  Code blocks, fenced or
+// indented, and lines without letters are kept as they are, and so is
+// the Markdown each line starts with.
+func Markdown(ctx context.Context, t Translator, md, source, target string) (string, error) {
+	lines := strings.Split(md, "\n")
+	var (
+		texts []string
+		at    []int // the line each text came from
+		fence string
+		prev  string
+	)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+		case (strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")) && isCode(prev):
+			// Indented code; a list item's continuation follows text.
+		case strings.IndexFunc(trimmed, unicode.IsLetter) < 0, strings.HasPrefix(trimmed, "<!--"):
+		default:
+			marker := lineMarker.FindString(line)
+			texts = append(texts, line[len(marker):])
+			at = append(at, i)
+		}
+		prev = line
+		if trimmed == "" {
+			prev = ""
+		}
+	}
+	for start := 0; start < len(texts); start += batchSize {
+		end := min(start+batchSize, len(texts))
+		out, _, err := t.Translate(ctx, texts[start:end], source, target)
+		if err != nil {
+			return "", err
+		}
+		for j, text := range out {
+			i := at[start+j]
+			lines[i] = lineMarker.FindString(lines[i]) + text
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// isCode reports whether an indented line after prev, the last line
+// before it ("" after a blank line), starts or continues a code block.
+func isCode(prev string) bool {
+	return prev == "" || strings.HasPrefix(prev, "    ") || strings.HasPrefix(prev, "\t")
+}
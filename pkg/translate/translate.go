@@ -0,0 +1,191 @@
+// Package translate translates text through a translation service, for
+// searching Stack Overflow in English with a question asked in another
+// language, and reading the answer back in that language.
+//
+// flo bundles no model.  It talks to a LibreTranslate server, which can
+// run locally, or to DeepL with an API key.
+package translate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Providers flo can translate with.
+const (
+	LibreTranslate = "libretranslate"
+	DeepL          = "deepl"
+)
+
+// Default addresses of the providers.  DeepL keys ending in ":fx" are
+// for its free API, which has its own address.
+const (
+	DefaultLibreTranslateURL = "http://localhost:5000"
+	DefaultDeepLURL          = "https://api.deepl.com"
+	DefaultDeepLFreeURL      = "https://api-free.deepl.com"
+)
+
+// English is the language Stack Overflow is searched in.
+const English = "en"
+
+// Translator translates texts in one request.
+type Translator interface {
+	// Translate translates texts from source, "" to detect it, into
+	// target.  Languages are ISO 639-1 codes such as "ja".  It returns
+	// the translations in order, and the language of the first text.
+	Translate(ctx context.Context, texts []string, source, target string) ([]string, string, error)
+	// Name names the service for the user.
+	Name() string
+}
+
+// New returns a translator for provider at url, using key; an empty url
+// means the provider's default.
+func New(provider, url, key string, hc *http.Client) (Translator, error) {
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	switch strings.ToLower(provider) {
+	case LibreTranslate:
+		if url == "" {
+			url = DefaultLibreTranslateURL
+		}
+		return &libre{url: strings.TrimSuffix(url, "/"), key: key, hc: hc}, nil
+	case DeepL:
+		if key == "" {
+			return nil, fmt.Errorf("translate.key: DeepL needs an API key")
+		}
+		if url == "" {
+			url = DefaultDeepLURL
+			if strings.HasSuffix(key, ":fx") {
+				url = DefaultDeepLFreeURL
+			}
+		}
+		return &deepl{url: strings.TrimSuffix(url, "/"), key: key, hc: hc}, nil
+	}
+	return nil, fmt.Errorf("translate.provider: unknown provider %q (want %s or %s)", provider, LibreTranslate, DeepL)
+}
+
+// libre calls LibreTranslate's POST /translate.
+type libre struct {
+	url, key string
+	hc       *http.Client
+}
+
+func (l *libre) Name() string { return "LibreTranslate" }
+
+func (l *libre) Translate(ctx context.Context, texts []string, source, target string) ([]string, string, error) {
+	if source == "" {
+		source = "auto"
+	}
+	req := map[string]any{"q": texts, "source": source, "target": target, "format": "text"}
+	if l.key != "" {
+		req["api_key"] = l.key
+	}
+	var out struct {
+		TranslatedText []string `json:"translatedText"`
+		// DetectedLanguage is one object per text when q is a list,
+		// but a single object on older servers.
+		DetectedLanguage json.RawMessage `json:"detectedLanguage"`
+	}
+	if err := post(ctx, l.hc, l.url+"/translate", nil, req, &out); err != nil {
+		return nil, "", fmt.Errorf("LibreTranslate: %w", err)
+	}
+	if len(out.TranslatedText) != len(texts) {
+		return nil, "", fmt.Errorf("LibreTranslate: %d translations for %d texts", len(out.TranslatedText), len(texts))
+	}
+	if source != "auto" {
+		return out.TranslatedText, source, nil
+	}
+	type detected struct {
+		Language string `json:"language"`
+	}
+	var many []detected
+	var one detected
+	switch {
+	case json.Unmarshal(out.DetectedLanguage, &many) == nil && len(many) > 0:
+		source = many[0].Language
+	case json.Unmarshal(out.DetectedLanguage, &one) == nil:
+		source = one.Language
+	}
+	return out.TranslatedText, source, nil
+}
+
+// deepl calls DeepL's POST /v2/translate.
+type deepl struct {
+	url, key string
+	hc       *http.Client
+}
+
+func (d *deepl) Name() string { return "DeepL" }
+
+func (d *deepl) Translate(ctx context.Context, texts []string, source, target string) ([]string, string, error) {
+	// DeepL wants a variant for English and Portuguese targets.
+	switch target = strings.ToUpper(target); target {
+	case "EN":
+		target = "EN-US"
+	case "PT":
+		target = "PT-BR"
+	}
+	req := map[string]any{"text": texts, "target_lang": target}
+	if source != "" {
+		req["source_lang"] = strings.ToUpper(source)
+	}
+	var out struct {
+		Translations []struct {
+			DetectedSourceLanguage string `json:"detected_source_language"`
+			Text                   string `json:"text"`
+		} `json:"translations"`
+	}
+	header := http.Header{"Authorization": {"DeepL-Auth-Key " + d.key}}
+	if err := post(ctx, d.hc, d.url+"/v2/translate", header, req, &out); err != nil {
+		return nil, "", fmt.Errorf("DeepL: %w", err)
+	}
+	if len(out.Translations) != len(texts) {
+		return nil, "", fmt.Errorf("DeepL: %d translations for %d texts", len(out.Translations), len(texts))
+	}
+	translated := make([]string, len(texts))
+	for i, t := range out.Translations {
+		translated[i] = t.Text
+	}
+	if source == "" {
+		source = strings.ToLower(out.Translations[0].DetectedSourceLanguage)
+	}
+	return translated, source, nil
+}
+
+// post sends body as JSON to url and decodes the JSON reply into out.
+func post(ctx context.Context, hc *http.Client, url string, header http.Header, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		if m := strings.TrimSpace(string(msg)); m != "" {
+			return fmt.Errorf("%s: %s", resp.Status, m)
+		}
+		return fmt.Errorf("%s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode reply: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+)
+
+// voteBarWidth is the number of cells in a full VoteBar.
+const voteBarWidth = 8
+
+// Badge and bar styles; built from the current palette (see palette.go).
+var (
+	acceptedBadgeStyle lipgloss.Style
+	negativeBadgeStyle lipgloss.Style
+	scoreBadgeStyle    lipgloss.Style
+	unansweredStyle    lipgloss.Style
+	voteBarStyle       lipgloss.Style
+	voteBarTailStyle   lipgloss.Style
+)
+
+// ScoreBadge renders an answer's score as a colored badge marking
+// accepted answers (with a check mark) and negative scores.  Badges are padded to a common width so lists line up.
+func ScoreBadge(score int, accepted bool) string {
+	text := fmt.Sprintf("%5d", score)
+	switch {
+	case accepted:
+		return acceptedBadgeStyle.Render(PlainText(" ✓") + text + " ")
+	case score < 0:
+		return negativeBadgeStyle.Render("  " + text + " ")
+	default:
+		return scoreBadgeStyle.Render("  " + text + " ")
+	}
+}
+
+// UnansweredBadge marks a question with no accepted or upvoted answer.
+func UnansweredBadge() string {
+	return unansweredStyle.Render(i18n.T("unanswered"))
+}
+
+// VoteBar renders score as a small bar proportional to top, the highest
+// score in the list.  Scores of zero or less draw an empty bar.
+func VoteBar(score, top int) string {
+	filled := 0
+	if score > 0 && top > 0 {
+		filled = (score*voteBarWidth + top - 1) / top
+		filled = min(filled, voteBarWidth)
+	}
+	return voteBarStyle.Render(PlainText(strings.Repeat("█", filled))) +
+		voteBarTailStyle.Render(PlainText(strings.Repeat("░", voteBarWidth-filled)))
+}
@@ -0,0 +1,214 @@
+package ui
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// langSignals are content patterns characteristic of a language.  Names
+// are chroma lexer names, which glamour uses for highlighting.
+var langSignals = map[string][]*regexp.Regexp{
+	"go": {
+		regexp.MustCompile(`(?m)^\s*func \w*\(|^\s*func \(\w+ \*?\w+\)`),
+		regexp.MustCompile(`:=`),
+		regexp.MustCompile(`(?m)^package \w+|\bfmt\.\w+\(|\berr != nil\b`),
+		regexp.MustCompile(`\bchan\b|\bgo func\b|\bdefer\b|\[\](?:byte|rune|string|int)\b|\bfor \w+(?:, \w+)? := range\b`),
+	},
+	"python": {
+		regexp.MustCompile(`(?m)^\s*def \w+\(.*\):\s*$`),
+		regexp.MustCompile(`(?m)^\s*(?:from [\w.]+ )?import [\w.]+(?: as \w+)?\s*$`),
+		regexp.MustCompile(`\bprint\(|\bself\.\w|\belif\b|\bNone\b|\bTrue\b|\bFalse\b`),
+		regexp.MustCompile(`(?m)^\s*(?:if|for|while|with|class|try|except)\b.*:\s*$`),
+	},
+	"javascript": {
+		regexp.MustCompile(`\b(?:const|let|var) \w+ = `),
+		regexp.MustCompile(`=>|\bfunction\s*\w*\(`),
+		regexp.MustCompile(`\bconsole\.log\(|\bdocument\.\w|\brequire\(|\bawait\b|\bexport (?:default|const|function)\b`),
+		regexp.MustCompile(`===|!==`),
+	},
+	"typescript": {
+		regexp.MustCompile(`\binterface \w+ \{|\btype \w+ = `),
+		regexp.MustCompile(`\b\w+: (?:string|number|boolean|any|void)\b`),
+		regexp.MustCompile(`\bimport .* from ['"]`),
+	},
+	"java": {
+		regexp.MustCompile(`\bpublic (?:static )?(?:class|void|int|String)\b`),
+		regexp.MustCompile(`\bSystem\.out\.print`),
+		regexp.MustCompile(`\bnew \w+(?:<.*>)?\(|\bString\[\]`),
+		regexp.MustCompile(`(?m)^import java\.|@Override`),
+	},
+	"csharp": {
+		regexp.MustCompile(`(?m)^using System|\bConsole\.Write`),
+		regexp.MustCompile(`\bpublic (?:static )?(?:class|void|int|string|async Task)\b`),
+		regexp.MustCompile(`\bvar \w+ = new\b|\bnamespace \w+`),
+	},
+	"cpp": {
+		regexp.MustCompile(`#include\s*<\w+>`),
+		regexp.MustCompile(`\bstd::|\bcout\s*<<|\btemplate\s*<`),
+		regexp.MustCompile(`\bint main\(`),
+	},
+	"c": {
+		regexp.MustCompile(`#include\s*<\w+\.h>`),
+		regexp.MustCompile(`\bprintf\(|\bmalloc\(|\bfree\(`),
+		regexp.MustCompile(`\bint main\(`),
+	},
+	"rust": {
+		regexp.MustCompile(`\bfn \w+\(|\blet mut\b`),
+		regexp.MustCompile(`\bimpl\b|\bpub fn\b|\b(?:println|vec)!`),
+		regexp.MustCompile(`::new\(|&str\b|\bOption<|\bResult<`),
+	},
+	"ruby": {
+		regexp.MustCompile(`(?m)^\s*def \w+[?!]?(?:\(.*\))?\s*$`),
+		regexp.MustCompile(`(?m)^\s*end\s*$`),
+		regexp.MustCompile(`\bputs\b|\.each do\b|\bdo \|\w+\||\brequire '`),
+	},
+	"php": {
+		regexp.MustCompile(`<\?php`),
+		regexp.MustCompile(`\$\w+\s*=|\$this->`),
+		regexp.MustCompile(`\becho\b|->\w+\(|\bfunction \w+\(\$`),
+	},
+	"bash": {
+		regexp.MustCompile(`(?m)^\s*\$ \w`),
+		regexp.MustCompile(`(?m)^#!/bin/(?:ba)?sh|\b(?:sudo|apt-get|brew|npm|pip|git|cd|ls|echo|export|grep) `),
+		regexp.MustCompile(`\$\{?\w+\}?|\bfi\b|\bdone\b|\|\s*\w+`),
+	},
+	"sql": {
+		regexp.MustCompile(`(?i)\bSELECT\b.+\bFROM\b`),
+		regexp.MustCompile(`(?i)\b(?:INSERT INTO|UPDATE \w+ SET|DELETE FROM|CREATE TABLE|ALTER TABLE)\b`),
+		regexp.MustCompile(`(?i)\b(?:WHERE|JOIN|GROUP BY|ORDER BY)\b`),
+	},
+	"html": {
+		regexp.MustCompile(`(?i)<(?:div|span|html|body|head|p|a|ul|li|input|form|button|script)\b[^>]*>`),
+		regexp.MustCompile(`</\w+>`),
+	},
+	"css": {
+		regexp.MustCompile(`(?m)^\s*[.#]?[\w-]+(?:\s*[.#:>]?[\w-]+)*\s*\{\s*$`),
+		regexp.MustCompile(`(?m)^\s*[\w-]+:\s*[^;]+;\s*$`),
+	},
+	"yaml": {
+		regexp.MustCompile(`(?m)^[\w-]+:\s*$`),
+		regexp.MustCompile(`(?m)^\s+- \w`),
+		regexp.MustCompile(`(?m)^\s*(?:apiVersion|kind|services|steps|version):`),
+	},
+}
+
+// tagLangs maps Stack Overflow tags to the lexer their code is usually in.
+var tagLangs = map[string]string{
+	"go": "go", "python": "python", "python-3.x": "python", "django": "python",
+	"pandas": "python", "numpy": "python", "flask": "python",
+	"javascript": "javascript", "node.js": "javascript", "reactjs": "javascript",
+	"jquery": "javascript", "vue.js": "javascript",
+	"typescript": "typescript", "angular": "typescript",
+	"java": "java", "spring": "java", "android": "java",
+	"c#": "csharp", ".net": "csharp", "asp.net": "csharp",
+	"c++": "cpp", "c": "c", "rust": "rust",
+	"ruby": "ruby", "ruby-on-rails": "ruby", "php": "php", "laravel": "php",
+	"bash": "bash", "shell": "bash", "linux": "bash", "sh": "bash", "zsh": "bash",
+	"sql": "sql", "mysql": "sql", "postgresql": "sql", "sql-server": "sql", "sqlite": "sql",
+	"html": "html", "css": "css", "yaml": "yaml", "json": "json",
+	"kotlin": "kotlin", "swift": "swift",
+}
+
+// reFence matches an opening or closing code fence and its info string.
+var reFence = regexp.MustCompile("^(\\s*)(```+|~~~+)\\s*(\\S*)")
+
+// AnnotateCode adds a language to code fences that lack one, so glamour
+// can highlight them.  The language is guessed from the code itself,
+// favouring languages implied by tags (the question's tags); a fence is
+// left alone when nothing points anywhere.
+func AnnotateCode(md string, tags []string) string {
+	if !strings.Contains(md, "```") && !strings.Contains(md, "~~~") {
+		return md
+	}
+	tagged := taggedLangs(tags)
+
+	lines := strings.Split(md, "\n")
+	for i := 0; i < len(lines); i++ {
+		m := reFence.FindStringSubmatch(lines[i])
+		if m == nil {
+			continue
+		}
+		// Find the closing fence: same marker, at least as long.
+		end := -1
+		for j := i + 1; j < len(lines); j++ {
+			if c := reFence.FindStringSubmatch(lines[j]); c != nil && c[3] == "" &&
+				c[2][0] == m[2][0] && len(c[2]) >= len(m[2]) {
+				end = j
+				break
+			}
+		}
+		if end < 0 {
+			break
+		}
+		if m[3] == "" {
+			if lang := GuessLanguage(strings.Join(lines[i+1:end], "\n"), tagged); lang != "" {
+				lines[i] = m[1] + m[2] + lang
+			}
+		}
+		i = end
+	}
+	return strings.Join(lines, "\n")
+}
+
+// CodeLanguage guesses the language of a code sample from a post with
+// the given tags, or returns "".
+func CodeLanguage(code string, tags []string) string {
+	return GuessLanguage(code, taggedLangs(tags))
+}
+
+// taggedLangs is the set of lexers the tags name.
+func taggedLangs(tags []string) map[string]bool {
+	tagged := make(map[string]bool)
+	for _, t := range tags {
+		if lang, ok := tagLangs[strings.ToLower(t)]; ok {
+			tagged[lang] = true
+		}
+	}
+	return tagged
+}
+
+// GuessLanguage returns the lexer name that best fits code, or "".
+// Each matching signal scores two points and a language in tagged gets
+// three more; it takes two signals, one plus a tag, or one that no other
+// language shares.  When the code is inconclusive a single tagged
+// language wins outright.
+func GuessLanguage(code string, tagged map[string]bool) string {
+	trimmed := strings.TrimSpace(code)
+	if trimmed == "" {
+		return ""
+	}
+	if (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid([]byte(trimmed)) {
+		return "json"
+	}
+
+	best, bestScore, matched := "", 0, 0
+	for lang, signals := range langSignals {
+		score := 0
+		for _, re := range signals {
+			if re.MatchString(code) {
+				score += 2
+			}
+		}
+		if score == 0 {
+			continue
+		}
+		matched++
+		if tagged[lang] {
+			score += 3
+		}
+		if score > bestScore || (score == bestScore && lang < best) {
+			best, bestScore = lang, score
+		}
+	}
+	if best != "" && (bestScore >= 4 || matched == 1) {
+		return best
+	}
+	// Nothing conclusive: trust the tags if they name one language.
+	if len(tagged) == 1 {
+		for lang := range tagged {
+			return lang
+		}
+	}
+	return ""
+}
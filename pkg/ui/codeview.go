@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/x/ansi"
+	"golang.org/x/term"
+)
+
+// CodeView shows a code block on its own and unwrapped, through a
+// window as wide as the terminal that scrolls sideways.  Wrapping a long
+// line breaks the code where it is read and again where it is pasted;
+// scrolling leaves every line whole.
+type CodeView struct {
+	lines  []string // rendered, one per line of code
+	width  int      // of the widest line, in columns
+	offset int      // the first column shown
+}
+
+// NewCodeView renders code in lang ("" for none), highlighted like
+// RenderCode but never wrapped.
+func NewCodeView(code, lang string) (*CodeView, error) {
+	style := "dark"
+	if plain {
+		style = "ascii"
+	}
+	r, err := glamour.NewTermRenderer(glamour.WithStandardStyle(style), glamour.WithWordWrap(0))
+	if err != nil {
+		return nil, err
+	}
+	rendered, err := r.Render("```" + lang + "\n" + expandTabs(code) + "\n```\n")
+	if err != nil {
+		return nil, fmt.Errorf("glamour render failed: %w", err)
+	}
+	v := &CodeView{lines: strings.Split(strings.TrimRight(rendered, "\n"), "\n")}
+	for _, l := range v.lines {
+		v.width = max(v.width, ansi.StringWidth(l))
+	}
+	return v, nil
+}
+
+// expandTabs replaces tabs with spaces to the next multiple of four
+// columns: a terminal puts its own tab stops where a scrolled line
+// no longer starts.
+func expandTabs(code string) string {
+	if !strings.Contains(code, "\t") {
+		return code
+	}
+	var b strings.Builder
+	col := 0
+	for _, r := range code {
+		switch r {
+		case '\t':
+			n := 4 - col%4
+			b.WriteString(strings.Repeat(" ", n))
+			col += n
+		case '\n':
+			b.WriteRune(r)
+			col = 0
+		default:
+			b.WriteRune(r)
+			col += ansi.StringWidth(string(r))
+		}
+	}
+	return b.String()
+}
+
+// viewWidth is how many columns of code fit the terminal.
+func viewWidth() int {
+	if cols, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && cols > 0 {
+		return max(cols-1, 20)
+	}
+	return glamourWrap
+}
+
+// Wide reports whether the code is wider than the terminal, so that
+// scrolling shows more of it.
+func (v *CodeView) Wide() bool { return v.width > viewWidth() }
+
+// Step is how far one scroll moves: half the window.
+func (v *CodeView) Step() int { return max(viewWidth()/2, 1) }
+
+// Scroll moves the window cols columns right (left when negative),
+// keeping it on the code, and reports whether it moved.
+func (v *CodeView) Scroll(cols int) bool {
+	off := min(max(v.offset+cols, 0), max(v.width-viewWidth(), 0))
+	moved := off != v.offset
+	v.offset = off
+	return moved
+}
+
+// Columns returns the columns in the window, counted from 1, and the
+// width of the code.
+func (v *CodeView) Columns() (from, to, total int) {
+	return v.offset + 1, min(v.offset+viewWidth(), v.width), v.width
+}
+
+// View returns the lines in the window, ending in a newline.
+func (v *CodeView) View() string {
+	w := viewWidth()
+	var b strings.Builder
+	for _, l := range v.lines {
+		if v.offset > 0 || ansi.StringWidth(l) > w {
+			l = ansi.Cut(l, v.offset, v.offset+w)
+			if !plain {
+				l += "\x1b[0m"
+			}
+		}
+		b.WriteString(strings.TrimRight(l, " "))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
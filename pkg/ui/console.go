@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// plain is set when the terminal cannot interpret escape sequences
+// (legacy Windows consoles): output is then uncoloured, boxes use ASCII
+// borders and emoji are replaced by text.
+var plain bool
+
+// Setup prepares the console for flo's output and must run before
+// anything is printed.  On Windows it enables virtual terminal
+// processing and UTF-8 output; where that is unavailable it switches to
+// plain output.  It reports whether plain output is in effect.
+func Setup() bool {
+	if !prepareConsole() {
+		plain = true
+		lipgloss.SetColorProfile(termenv.Ascii)
+		buildStyles()
+	}
+	return plain
+}
+
+// Plain reports whether plain output is in effect (see Setup).
+func Plain() bool {
+	return plain
+}
+
+// Border is the border for boxed output: rounded normally, ASCII in
+// plain mode.
+func Border() lipgloss.Border {
+	if plain {
+		return lipgloss.ASCIIBorder()
+	}
+	return lipgloss.RoundedBorder()
+}
+
+// plainSymbols maps the emoji and symbols flo prints to ASCII, for
+// consoles whose fonts lack them.
+var plainSymbols = strings.NewReplacer(
+	"⚡ ", "", "⏳", "...", "✅", "[ok]", "✖", "x", "❓", "?", "🔍", ">",
+	"🔎", ">", "📖", "-", "👋", "", "🔗", "Link:", "🖼", "[image]",
+	"⏱ ", "", "✓", "*", "▸", ">", "⋯", "...", "█", "#", "░", ".",
+	"📝", "", "•", "*", "⚠", "!", "📤 ", "", "📰 ", "",
+)
+
+// PlainText replaces emoji and symbols in s with ASCII in plain mode,
+// and returns s unchanged otherwise.
+func PlainText(s string) string {
+	if !plain {
+		return s
+	}
+	return plainSymbols.Replace(s)
+}
+
+// reEscape matches terminal escape sequences: colours and other CSI
+// sequences, and OSC ones such as hyperlinks.
+var reEscape = regexp.MustCompile("\x1b\\[[0-9;:?]*[ -/]*[@-~]|\x1b\\][^\x07\x1b]*(?:\x07|\x1b\\\\)")
+
+// StripEscapes removes the escape sequences from rendered text, leaving
+// what the terminal shows.
+func StripEscapes(s string) string {
+	return reEscape.ReplaceAllString(s, "")
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package ui
+
+// prepareConsole has nothing to do outside Windows: terminals interpret
+// escape sequences natively.
+func prepareConsole() bool {
+	return true
+}
@@ -0,0 +1,34 @@
+//go:build windows
+
+package ui
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// cpUTF8 is the UTF-8 console code page.
+const cpUTF8 = 65001
+
+// prepareConsole switches the console to UTF-8 output and turns on
+// virtual terminal processing for stdout and stderr, so ANSI colours and
+// cursor movement work in cmd.exe and Windows PowerShell.  It returns
+// false on consoles that predate VT support (before Windows 10 1511).
+// Redirected handles are not consoles and are left alone.
+func prepareConsole() bool {
+	_ = windows.SetConsoleOutputCP(cpUTF8)
+	ok := true
+	for _, f := range []*os.File{os.Stdout, os.Stderr} {
+		h := windows.Handle(f.Fd())
+		var mode uint32
+		if err := windows.GetConsoleMode(h, &mode); err != nil {
+			continue // not a console
+		}
+		mode |= windows.ENABLE_PROCESSED_OUTPUT | windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING
+		if err := windows.SetConsoleMode(h, mode); err != nil {
+			ok = false
+		}
+	}
+	return ok
+}
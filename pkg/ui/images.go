@@ -0,0 +1,238 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // decoders for Stack Overflow screenshots
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Image protocols understood by Thumbnail.
+const (
+	ImagesKitty = "kitty"
+	ImagesITerm = "iterm"
+	ImagesSixel = "sixel"
+)
+
+// thumbWidth is the largest width, in pixels, of an inline thumbnail.
+const thumbWidth = 320
+
+// maxImagePixels is the largest image, in pixels, Thumbnail decodes: a
+// small file can declare dimensions that would take gigabytes to hold.
+const maxImagePixels = 4096 * 4096
+
+// Image is a picture referenced from a post.
+type Image struct {
+	Alt string
+	URL string
+}
+
+var (
+	// [![alt](src)](href) — Stack Overflow's usual screenshot markup —
+	// or a plain ![alt](src).
+	reImage = regexp.MustCompile(`\[!\[([^\]]*)\]\(([^)\s]+)[^)]*\)\]\([^)]*\)|!\[([^\]]*)\]\(([^)\s]+)[^)]*\)`)
+	// A bare link to the Stack Overflow image host.
+	reImageURL = regexp.MustCompile(`(?m)(?:^|\s)(https?://i\.(?:stack\.imgur\.com|sstatic\.net)/\S+\.(?:png|jpe?g|gif))\b`)
+)
+
+// ExtractImages replaces the images in md with numbered placeholders
+// ("🖼 Image 1: alt") and returns them in order.  md is returned
+// unchanged when it has none.
+func ExtractImages(md string) (string, []Image) {
+	var imgs []Image
+	md = reImage.ReplaceAllStringFunc(md, func(m string) string {
+		sm := reImage.FindStringSubmatch(m)
+		alt, src := sm[1], sm[2]
+		if src == "" {
+			alt, src = sm[3], sm[4]
+		}
+		imgs = append(imgs, Image{Alt: strings.TrimSpace(alt), URL: src})
+		return placeholder(len(imgs), alt)
+	})
+	md = reImageURL.ReplaceAllStringFunc(md, func(m string) string {
+		lead := m[:len(m)-len(strings.TrimLeft(m, " \t\n"))]
+		imgs = append(imgs, Image{URL: strings.TrimSpace(m)})
+		return lead + placeholder(len(imgs), "")
+	})
+	return md, imgs
+}
+
+// placeholder is the text left where image n was.
+func placeholder(n int, alt string) string {
+	alt = strings.TrimSpace(alt)
+	if alt == "" || strings.EqualFold(alt, "enter image description here") {
+		return fmt.Sprintf("*🖼 Image %d*", n)
+	}
+	return fmt.Sprintf("*🖼 Image %d: %s*", n, alt)
+}
+
+// ImagesSection is a Markdown list of imgs, numbered to match the
+// placeholders, with their full URLs so they can be opened in a browser.
+func ImagesSection(imgs []Image) string {
+	var b strings.Builder
+	b.WriteString("\n---\n\n**Images**\n\n")
+	for i, img := range imgs {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, img.URL)
+	}
+	return b.String()
+}
+
+// DetectImageProtocol guesses which inline image protocol the terminal
+// speaks from its environment, or returns "" when it probably speaks
+// none.  Terminal multiplexers swallow the escape sequences, so inside
+// tmux or screen the answer is always "".
+func DetectImageProtocol() string {
+	if os.Getenv("TMUX") != "" || strings.HasPrefix(os.Getenv("TERM"), "screen") {
+		return ""
+	}
+	term := os.Getenv("TERM")
+	prog := os.Getenv("TERM_PROGRAM")
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" || prog == "ghostty":
+		return ImagesKitty
+	case prog == "iTerm.app" || prog == "WezTerm" || os.Getenv("LC_TERMINAL") == "iTerm2":
+		return ImagesITerm
+	case strings.HasPrefix(term, "foot") || strings.HasPrefix(term, "mlterm") || strings.Contains(term, "sixel"):
+		return ImagesSixel
+	}
+	return ""
+}
+
+// Thumbnail decodes a PNG, JPEG or GIF image, shrinks it to thumbnail
+// size and writes it to w using protocol (one of the Images* constants).
+// Images larger than maxImagePixels are refused before decoding.
+func Thumbnail(w io.Writer, protocol string, data []byte) error {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("decode image: %w", err)
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > maxImagePixels {
+		return fmt.Errorf("image too large for a thumbnail: %d×%d pixels", cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("decode image: %w", err)
+	}
+	img = shrink(img, thumbWidth)
+
+	if protocol == ImagesSixel {
+		return writeSixel(w, img)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return err
+	}
+	enc := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	switch protocol {
+	case ImagesKitty:
+		// Payloads are sent in chunks of at most 4096 bytes.
+		for first := true; enc != ""; first = false {
+			chunk := enc[:min(4096, len(enc))]
+			enc = enc[len(chunk):]
+			more := 0
+			if enc != "" {
+				more = 1
+			}
+			if first {
+				fmt.Fprintf(w, "\x1b_Ga=T,f=100,m=%d;%s\x1b\\", more, chunk)
+			} else {
+				fmt.Fprintf(w, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+			}
+		}
+	case ImagesITerm:
+		fmt.Fprintf(w, "\x1b]1337;File=inline=1;size=%d;preserveAspectRatio=1:%s\a", buf.Len(), enc)
+	default:
+		return fmt.Errorf("unknown image protocol %q", protocol)
+	}
+	_, err = fmt.Fprintln(w)
+	return err
+}
+
+// shrink scales img down (nearest neighbour) so it is at most width
+// pixels wide; smaller images are returned as they are.
+func shrink(img image.Image, width int) image.Image {
+	b := img.Bounds()
+	if b.Dx() <= width {
+		return img
+	}
+	height := max(1, b.Dy()*width/b.Dx())
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		sy := b.Min.Y + y*b.Dy()/height
+		for x := 0; x < width; x++ {
+			out.Set(x, y, img.At(b.Min.X+x*b.Dx()/width, sy))
+		}
+	}
+	return out
+}
+
+// writeSixel encodes img as DEC sixel graphics using a fixed 6×6×6
+// colour cube — plenty for a thumbnail, and no palette search needed.
+func writeSixel(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	var out bytes.Buffer
+	out.WriteString("\x1bPq")
+	for i := 0; i < 216; i++ {
+		r, g, bl := i/36, (i/6)%6, i%6
+		fmt.Fprintf(&out, "#%d;2;%d;%d;%d", i, r*20, g*20, bl*20)
+	}
+
+	index := func(c color.Color) int {
+		r, g, bl, _ := c.RGBA()
+		return int(r*5/0xffff)*36 + int(g*5/0xffff)*6 + int(bl*5/0xffff)
+	}
+	for y0 := b.Min.Y; y0 < b.Max.Y; y0 += 6 {
+		// One sixel row covers six pixel rows; draw it colour by colour.
+		bands := make(map[int][]byte)
+		var order []int
+		for x := b.Min.X; x < b.Max.X; x++ {
+			for dy := 0; dy < 6 && y0+dy < b.Max.Y; dy++ {
+				c := index(img.At(x, y0+dy))
+				row, ok := bands[c]
+				if !ok {
+					row = make([]byte, b.Dx())
+					bands[c] = row
+					order = append(order, c)
+				}
+				row[x-b.Min.X] |= 1 << dy
+			}
+		}
+		for i, c := range order {
+			if i > 0 {
+				out.WriteByte('$')
+			}
+			fmt.Fprintf(&out, "#%d", c)
+			writeSixelRun(&out, bands[c])
+		}
+		out.WriteByte('-')
+	}
+	out.WriteString("\x1b\\\n")
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// writeSixelRun writes one colour's sixels, run-length encoded.
+func writeSixelRun(out *bytes.Buffer, row []byte) {
+	for i := 0; i < len(row); {
+		j := i
+		for j < len(row) && row[j] == row[i] {
+			j++
+		}
+		ch := byte(63 + row[i])
+		if n := j - i; n > 3 {
+			fmt.Fprintf(out, "!%d%c", n, ch)
+		} else {
+			out.Write(bytes.Repeat([]byte{ch}, n))
+		}
+		i = j
+	}
+}
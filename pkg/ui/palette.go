@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Palette is the set of colours every styled element is drawn with.
+type Palette struct {
+	Brand    lipgloss.Color // banner, prompts
+	Progress lipgloss.Color // in-flight status lines
+	Success  lipgloss.Color // completed status lines
+	Dim      lipgloss.Color // hints, footers
+	Error    lipgloss.Color // error text
+	ErrorBox lipgloss.Color // error panel border
+	Border   lipgloss.Color // result box border
+
+	AcceptedFg, AcceptedBg lipgloss.Color // accepted answer badge
+	NegativeFg, NegativeBg lipgloss.Color // negative score badge
+	ScoreFg, ScoreBg       lipgloss.Color // other score badges
+	Bar, BarTail           lipgloss.Color // vote bar, filled and empty
+}
+
+// palettes are the built-in palettes.  "deuteranopia" is built from the
+// Okabe–Ito colours, which stay distinct with red–green colour
+// blindness: accepted is blue and negative is vermillion.
+// "high-contrast" uses only saturated colours on black and white.
+var palettes = map[string]Palette{
+	"default": {
+		Brand: "#FF6600", Progress: "#FFD700", Success: "#00FF00", Dim: "#888888",
+		Error: "#FF0000", ErrorBox: "#FF4444", Border: "#444444",
+		AcceptedFg: "#000000", AcceptedBg: "#2EA043",
+		NegativeFg: "#FFFFFF", NegativeBg: "#CF222E",
+		ScoreFg: "#FFFFFF", ScoreBg: "#555555",
+		Bar: "#FFD700", BarTail: "#444444",
+	},
+	"deuteranopia": {
+		Brand: "#E69F00", Progress: "#F0E442", Success: "#56B4E9", Dim: "#999999",
+		Error: "#D55E00", ErrorBox: "#D55E00", Border: "#555555",
+		AcceptedFg: "#FFFFFF", AcceptedBg: "#0072B2",
+		NegativeFg: "#000000", NegativeBg: "#D55E00",
+		ScoreFg: "#FFFFFF", ScoreBg: "#555555",
+		Bar: "#56B4E9", BarTail: "#555555",
+	},
+	"high-contrast": {
+		Brand: "#FFFF00", Progress: "#FFFF00", Success: "#00FFFF", Dim: "#FFFFFF",
+		Error: "#FF5555", ErrorBox: "#FFFFFF", Border: "#FFFFFF",
+		AcceptedFg: "#000000", AcceptedBg: "#00FFFF",
+		NegativeFg: "#000000", NegativeBg: "#FFFF00",
+		ScoreFg: "#000000", ScoreBg: "#FFFFFF",
+		Bar: "#FFFFFF", BarTail: "#666666",
+	},
+}
+
+// current is the palette in use.
+var current = palettes["default"]
+
+func init() { buildStyles() }
+
+// PaletteNames lists the built-in palettes.
+func PaletteNames() []string {
+	names := make([]string, 0, len(palettes))
+	for n := range palettes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetPalette switches every style in this package to the named palette.
+// Callers with styles of their own rebuild them from CurrentPalette.
+func SetPalette(name string) error {
+	p, ok := palettes[name]
+	if !ok {
+		return fmt.Errorf("unknown palette %q (choose from %s)", name, strings.Join(PaletteNames(), ", "))
+	}
+	current = p
+	buildStyles()
+	return nil
+}
+
+// CurrentPalette returns the palette in use.
+func CurrentPalette() Palette {
+	return current
+}
+
+// buildStyles derives the package's styles from the current palette.
+func buildStyles() {
+	resultBoxStyle = lipgloss.NewStyle().
+		Border(Border()).
+		BorderForeground(current.Border).
+		Padding(1, 2).
+		MarginTop(1).
+		MarginBottom(1).
+		Width(termWidth + 6)
+	footerStyle = lipgloss.NewStyle().
+		Foreground(current.Dim).
+		Italic(true).
+		MarginTop(1)
+
+	acceptedBadgeStyle = lipgloss.NewStyle().Foreground(current.AcceptedFg).Background(current.AcceptedBg).Bold(true)
+	negativeBadgeStyle = lipgloss.NewStyle().Foreground(current.NegativeFg).Background(current.NegativeBg).Bold(true)
+	scoreBadgeStyle = lipgloss.NewStyle().Foreground(current.ScoreFg).Background(current.ScoreBg)
+	unansweredStyle = lipgloss.NewStyle().Foreground(current.NegativeBg).Bold(true)
+	voteBarStyle = lipgloss.NewStyle().Foreground(current.Bar)
+	voteBarTailStyle = lipgloss.NewStyle().Foreground(current.BarTail)
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"strings"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// qrQuiet is the margin of light modules around a QR code; the spec asks
+// for four, but two scan fine on a screen and save terminal rows.
+const qrQuiet = 2
+
+// QRCode renders text as a QR code for the terminal.  Each character
+// cell holds two modules stacked with a half block, coloured black and
+// white explicitly so the code scans on dark and light themes alike.  In
+// plain mode, where colours are unavailable, dark modules are drawn as
+// "##".
+func QRCode(text string) (string, error) {
+	code, err := qrcode.New(text, qrcode.Medium)
+	if err != nil {
+		return "", err
+	}
+	code.DisableBorder = true
+	bits := code.Bitmap()
+
+	n := len(bits) + 2*qrQuiet
+	dark := func(row, col int) bool {
+		row, col = row-qrQuiet, col-qrQuiet
+		return row >= 0 && row < len(bits) && col >= 0 && col < len(bits) && bits[row][col]
+	}
+
+	var b strings.Builder
+	if plain {
+		for row := 0; row < n; row++ {
+			for col := 0; col < n; col++ {
+				if dark(row, col) {
+					b.WriteString("##")
+				} else {
+					b.WriteString("  ")
+				}
+			}
+			b.WriteString("\n")
+		}
+		return b.String(), nil
+	}
+
+	for row := 0; row < n; row += 2 {
+		for col := 0; col < n; col++ {
+			// The upper module is the foreground of "▀" and the lower one
+			// its background; an odd last row leaves the terminal's own
+			// background below it.
+			fg, bg := "97", "107" // white
+			if dark(row, col) {
+				fg = "30"
+			}
+			switch {
+			case row+1 >= n:
+				bg = "49"
+			case dark(row+1, col):
+				bg = "40"
+			}
+			b.WriteString("\x1b[" + fg + ";" + bg + "m▀")
+		}
+		b.WriteString("\x1b[0m\n")
+	}
+	return b.String(), nil
+}
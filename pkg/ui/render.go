@@ -3,6 +3,8 @@
 // glamour converts Markdown → ANSI escape sequences for rich terminal
 // output (syntax-highlighted code blocks, bold/italic, etc.).
 // lipgloss adds structural styling: rounded borders, padding, colors.
+// Other formats — Markdown, HTML, JSON — are Renderers beside the ANSI
+// one (see renderer.go).
 package ui
 
 import (
@@ -10,27 +12,24 @@ import (
 
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
 )
 
 const termWidth = 100
 
+// Styles are built from the current palette (see palette.go).
 var (
 	// resultBoxStyle wraps the entire rendered output in a rounded border.
-	resultBoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#444444")).
-			Padding(1, 2).
-			MarginTop(1).
-			MarginBottom(1).
-			Width(termWidth + 6)
+	resultBoxStyle lipgloss.Style
 
 	// footerStyle renders the attribution line below the result box.
-	footerStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")).
-			Italic(true).
-			MarginTop(1)
+	footerStyle lipgloss.Style
 )
 
+// Footer is the attribution line printed below rendered content; set
+// it to name the backend the content came from.
+var Footer = "Powered by Stack Overflow via MCP"
+
 // RenderContent renders Markdown text beautifully for the terminal.
 // The input is expected to be valid Markdown (e.g., from
 // mcp.FormatQuestionMarkdown); glamour converts it to ANSI and
@@ -40,32 +39,48 @@ func RenderContent(text string) (string, error) {
 		return "", fmt.Errorf("empty content")
 	}
 
+	// Tables wider than the terminal wrap mid-row; reshape them first.
+	text = FitTables(text, tableWidth(), WideTables)
+
 	// glamour.Render processes Markdown with the "dark" terminal theme,
-	// producing syntax-highlighted code, styled headers, and more.
-	rendered, err := glamour.Render(text, "dark")
+	// producing syntax-highlighted code, styled headers, and more.  Plain
+	// consoles get the "ascii" theme, which uses no escape sequences.
+	style := "dark"
+	if plain {
+		style = "ascii"
+		text = PlainText(text)
+	}
+	rendered, err := glamour.Render(text, style)
 	if err != nil {
 		return "", fmt.Errorf("glamour render failed: %w", err)
 	}
 
 	output := resultBoxStyle.Render(rendered)
-	footer := footerStyle.Render("  Powered by Stack Overflow via MCP")
+	footer := footerStyle.Render("  " + i18n.T(Footer))
 	output += "\n" + footer + "\n"
 
 	return output, nil
 }
 
+// RenderCode renders a code block in lang ("" for none) on its own with
+// the renderer in use: highlighted like RenderContent's on a terminal,
+// but without the frame and footer.
+func RenderCode(code, lang string) (string, error) {
+	return Render(Document{Markdown: "```" + lang + "\n" + code + "\n```\n", Bare: true})
+}
+
 // RenderError produces a styled error panel for terminal display.
 func RenderError(title, body string) string {
 	errorBox := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FF0000")).
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#FF4444")).
+		Foreground(current.Error).
+		Border(Border()).
+		BorderForeground(current.ErrorBox).
 		Padding(1, 2).
 		MarginTop(1).
 		MarginBottom(1).
 		Width(termWidth + 6)
 
-	msg := fmt.Sprintf("✖ %s\n\n%s", title, body)
+	msg := PlainText(fmt.Sprintf("✖ %s\n\n%s", title, body))
 	return errorBox.Render(msg)
 }
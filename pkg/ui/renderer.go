@@ -0,0 +1,153 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/ratnesh-maurya/flo/pkg/i18n"
+	"github.com/yuin/goldmark"
+)
+
+// Document is one piece of output: the Markdown flo shows people and,
+// for renderers of data, the value it was built from.
+type Document struct {
+	Markdown string
+	// Data is what the document shows, such as a question with its
+	// answers; the JSON renderer writes it alongside the Markdown.  Nil
+	// leaves it out.
+	Data any
+	// Bare leaves out the frame and the footer, as for a code block
+	// printed on its own.
+	Bare bool
+}
+
+// Renderer turns documents into output.  Every command's rendered
+// output goes through the one selected with SetFormat, so a new format
+// needs only a new Renderer.
+type Renderer interface {
+	// Render renders doc, ending in a newline.
+	Render(doc Document) (string, error)
+	// Styled reports whether the output is decorated for a person at a
+	// terminal, so that the interactive extras — pickers, folding,
+	// image thumbnails — make sense with it.
+	Styled() bool
+}
+
+// The formats SetFormat knows, by name.
+var renderers = map[string]Renderer{
+	"ansi":     ANSI{},
+	"markdown": Markdown{},
+	"html":     HTML{},
+	"json":     JSON{},
+}
+
+// renderer is the renderer in use; ANSI unless SetFormat chose another.
+var renderer Renderer = ANSI{}
+
+// Formats returns the names SetFormat takes, sorted.
+func Formats() []string {
+	names := make([]string, 0, len(renderers))
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetFormat selects the renderer for the format name; empty keeps ANSI.
+func SetFormat(name string) error {
+	if name == "" {
+		return nil
+	}
+	r, ok := renderers[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf("unknown format %q (want %s)", name, strings.Join(Formats(), ", "))
+	}
+	renderer = r
+	return nil
+}
+
+// Current returns the renderer in use.
+func Current() Renderer { return renderer }
+
+// Render renders doc with the renderer in use.
+func Render(doc Document) (string, error) {
+	return renderer.Render(doc)
+}
+
+// ANSI renders for the terminal with glamour, framed and with the
+// footer (see RenderContent); on plain consoles it uses no escape
+// sequences.
+type ANSI struct{}
+
+func (ANSI) Render(doc Document) (string, error) {
+	if !doc.Bare {
+		return RenderContent(doc.Markdown)
+	}
+	style := "dark"
+	if plain {
+		style = "ascii"
+	}
+	return glamour.Render(doc.Markdown, style)
+}
+
+func (ANSI) Styled() bool { return true }
+
+// Markdown writes the Markdown as it is, for files and other programs,
+// the footer last as an italic line, and a blank line after each
+// document so the next starts a block of its own.
+type Markdown struct{}
+
+func (Markdown) Render(doc Document) (string, error) {
+	md := strings.TrimRight(doc.Markdown, "\n") + "\n"
+	if !doc.Bare {
+		md += "\n*" + i18n.T(Footer) + "*\n"
+	}
+	return md + "\n", nil
+}
+
+func (Markdown) Styled() bool { return false }
+
+// HTML renders an HTML fragment, the footer last in a paragraph of
+// class "flo-footer".  Raw HTML in the Markdown is dropped.
+type HTML struct{}
+
+func (HTML) Render(doc Document) (string, error) {
+	var buf bytes.Buffer
+	if err := goldmark.Convert([]byte(doc.Markdown), &buf); err != nil {
+		return "", fmt.Errorf("render markdown: %w", err)
+	}
+	if !doc.Bare {
+		fmt.Fprintf(&buf, "<p class=\"flo-footer\">%s</p>\n", html.EscapeString(i18n.T(Footer)))
+	}
+	return buf.String(), nil
+}
+
+func (HTML) Styled() bool { return false }
+
+// JSON writes each document as one line of JSON: the Markdown, the data
+// it shows and the footer as "source".
+type JSON struct{}
+
+func (JSON) Render(doc Document) (string, error) {
+	out := struct {
+		Markdown string `json:"markdown"`
+		Data     any    `json:"data,omitempty"`
+		Source   string `json:"source,omitempty"`
+	}{Markdown: doc.Markdown, Data: doc.Data}
+	if !doc.Bare {
+		out.Source = Footer
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+func (JSON) Styled() bool { return false }
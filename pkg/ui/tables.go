@@ -0,0 +1,223 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
+)
+
+// Wide table layouts; see WideTables.
+const (
+	TablesAuto     = "auto"
+	TablesTruncate = "truncate"
+	TablesRotate   = "rotate"
+)
+
+// WideTables controls Markdown tables too wide for the terminal:
+// TablesTruncate shortens cells to fit, TablesRotate turns each row into
+// a record of "column: value" lines, and TablesAuto (the default)
+// truncates unless that would leave columns too narrow to read.
+var WideTables = TablesAuto
+
+const (
+	// glamourWrap is glamour's default word-wrap width; tables wider
+	// than this are wrapped mid-row.
+	glamourWrap = 80
+	// minCellWidth is the narrowest column TablesAuto will truncate to
+	// before rotating instead.
+	minCellWidth = 10
+	// cellGap is the width glamour puts between columns (" │ ").
+	cellGap = 3
+)
+
+var (
+	reTableSep = regexp.MustCompile(`^\s*\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?\s*$`)
+	reFenceTok = regexp.MustCompile("^\\s*(```|~~~)")
+)
+
+// tableWidth is the room a table has inside the result box: glamour's
+// wrap width, less its margins, or less if the terminal is narrower.
+func tableWidth() int {
+	w := glamourWrap
+	if cols, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && cols > 0 {
+		// Border, padding and glamour's own margins take about 10 columns.
+		w = min(w, cols-10)
+	}
+	return max(w-4, 20)
+}
+
+// FitTables rewrites the Markdown tables in md that would be wider than
+// width according to mode (one of the Tables* constants).  Tables that
+// fit, and anything inside code fences, are left alone.
+func FitTables(md string, width int, mode string) string {
+	if !strings.Contains(md, "|") {
+		return md
+	}
+	lines := strings.Split(md, "\n")
+	var out []string
+	inFence := false
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if reFenceTok.MatchString(line) {
+			inFence = !inFence
+		}
+		if inFence || !strings.Contains(line, "|") || i+1 >= len(lines) || !reTableSep.MatchString(lines[i+1]) {
+			out = append(out, line)
+			continue
+		}
+		header := splitRow(line)
+		var rows [][]string
+		j := i + 2
+		for ; j < len(lines) && strings.Contains(lines[j], "|") && strings.TrimSpace(lines[j]) != ""; j++ {
+			rows = append(rows, splitRow(lines[j]))
+		}
+		out = append(out, fitTable(header, rows, lines[i:j], width, mode)...)
+		i = j - 1
+	}
+	return strings.Join(out, "\n")
+}
+
+// fitTable returns the lines to use for one table; orig is the table as
+// written.
+func fitTable(header []string, rows [][]string, orig []string, width int, mode string) []string {
+	n := len(header)
+	natural := make([]int, n)
+	for _, row := range append([][]string{header}, rows...) {
+		for c := 0; c < n && c < len(row); c++ {
+			natural[c] = max(natural[c], lipgloss.Width(row[c]))
+		}
+	}
+	total := cellGap * (n - 1)
+	for _, w := range natural {
+		total += w
+	}
+	if total <= width {
+		return orig
+	}
+
+	widths := shareWidths(natural, width-cellGap*(n-1))
+	narrowest := width
+	for c, w := range widths {
+		if w < natural[c] {
+			narrowest = min(narrowest, w)
+		}
+	}
+	if mode == TablesRotate || (mode != TablesTruncate && narrowest < minCellWidth) {
+		return rotateTable(header, rows)
+	}
+
+	out := []string{joinRow(truncateRow(header, widths))}
+	seps := make([]string, n)
+	for c := range seps {
+		seps[c] = strings.Repeat("-", max(3, min(widths[c], natural[c])))
+	}
+	out = append(out, joinRow(seps))
+	for _, row := range rows {
+		out = append(out, joinRow(truncateRow(row, widths)))
+	}
+	return out
+}
+
+// shareWidths divides budget between columns: columns that fit in an
+// equal share keep their natural width and the rest split what is left.
+func shareWidths(natural []int, budget int) []int {
+	widths := make([]int, len(natural))
+	idx := make([]int, len(natural))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(a, b int) bool { return natural[idx[a]] < natural[idx[b]] })
+	left := len(idx)
+	for _, c := range idx {
+		share := max(budget/left, 1)
+		widths[c] = min(natural[c], share)
+		budget -= widths[c]
+		left--
+	}
+	return widths
+}
+
+// rotateTable lays a table out as one record per row: the first column
+// as a heading, the others as "column: value" bullets.
+func rotateTable(header []string, rows [][]string) []string {
+	var out []string
+	for r, row := range rows {
+		title := fmt.Sprintf("Row %d", r+1)
+		if len(row) > 0 && strings.TrimSpace(row[0]) != "" {
+			title = row[0]
+		}
+		if len(header) > 0 && strings.TrimSpace(header[0]) != "" {
+			title = header[0] + ": " + title
+		}
+		out = append(out, "**"+title+"**", "")
+		for c := 1; c < len(header); c++ {
+			val := ""
+			if c < len(row) {
+				val = row[c]
+			}
+			out = append(out, fmt.Sprintf("- **%s:** %s", header[c], val))
+		}
+		out = append(out, "")
+	}
+	return out
+}
+
+// splitRow splits a table row into trimmed cells, honouring \| escapes.
+func splitRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	var cells []string
+	var cur strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cur.WriteString(`\|`)
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cur.String()))
+			cur.Reset()
+		default:
+			cur.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cur.String()))
+}
+
+func joinRow(cells []string) string {
+	return "| " + strings.Join(cells, " | ") + " |"
+}
+
+// truncateRow shortens each cell to its column width, ending cut cells
+// with "…" and closing any inline code span the cut left open.
+func truncateRow(row []string, widths []int) []string {
+	out := make([]string, len(widths))
+	for c := range widths {
+		if c >= len(row) {
+			continue
+		}
+		cell := row[c]
+		if lipgloss.Width(cell) <= widths[c] {
+			out[c] = cell
+			continue
+		}
+		runes := []rune(cell)
+		cut := string(runes[:max(0, min(len(runes), widths[c]-1))])
+		for lipgloss.Width(cut) > widths[c]-1 && cut != "" {
+			cut = string([]rune(cut)[:len([]rune(cut))-1])
+		}
+		cut = strings.TrimRight(cut, `\ `)
+		if strings.Count(cut, "`")%2 == 1 {
+			cut += "`"
+		}
+		out[c] = cut + "…"
+	}
+	return out
+}
@@ -0,0 +1,77 @@
+// Package update – notice.go implements the passive "new version
+// available" notice.  Both the GitHub lookup and the notice itself
+// happen at most once per day; the state is cached on disk in between.
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/atomicfile"
+)
+
+// checkInterval is how often the passive check may reach GitHub, and
+// how often the notice may be shown.
+const checkInterval = 24 * time.Hour
+
+// checkState is persisted between runs in the user cache directory.
+type checkState struct {
+	CheckedAt  time.Time `json:"checked_at"`
+	NotifiedAt time.Time `json:"notified_at"`
+	Latest     string    `json:"latest"`
+}
+
+// statePath returns the location of the cached check result.
+func statePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "flo", "update-check.json"), nil
+}
+
+// CheckForNotice returns the latest released version if it is newer than
+// current and the user has not been told about it in the last
+// checkInterval, or "" otherwise.  All failures are silent because the
+// notice is purely informational.
+func CheckForNotice(ctx context.Context, hc *http.Client, current string) string {
+	path, err := statePath()
+	if err != nil {
+		return ""
+	}
+
+	var st checkState
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &st)
+	}
+
+	if time.Since(st.CheckedAt) >= checkInterval {
+		rel, err := Latest(ctx, hc)
+		if err != nil {
+			return ""
+		}
+		st.CheckedAt = time.Now()
+		st.Latest = rel.Version()
+		saveState(path, st)
+	}
+
+	if !Newer(st.Latest, current) || time.Since(st.NotifiedAt) < checkInterval {
+		return ""
+	}
+	st.NotifiedAt = time.Now()
+	saveState(path, st)
+	return st.Latest
+}
+
+// saveState writes st to path atomically, ignoring errors.
+func saveState(path string, st checkState) {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return
+	}
+	_ = atomicfile.WriteFile(path, data, 0o644)
+}
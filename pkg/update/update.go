@@ -0,0 +1,307 @@
+// Package update implements flo's self-update mechanism.
+//
+// Releases are produced by GoReleaser (see .goreleaser.yaml) and published
+// on GitHub with one archive per platform plus a checksums.txt file:
+//
+//	flo_<version>_<os>_<arch>.tar.gz   (zip on Windows)
+//	checksums.txt                      ("<sha256>  <archive name>" lines)
+//
+// Apply downloads the archive for the running platform, verifies its
+// SHA-256 against checksums.txt, extracts the flo binary and swaps it in
+// place of the running executable.  checksums.txt is not signed, so the
+// check guards against corrupted downloads only, not a tampered release.
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Repo is the GitHub repository releases are fetched from.
+const Repo = "ratnesh-maurya/flo"
+
+// apiBase is the GitHub REST API root.
+const apiBase = "https://api.github.com"
+
+// Release is the subset of the GitHub release payload flo needs.
+type Release struct {
+	TagName string  `json:"tag_name"`
+	HTMLURL string  `json:"html_url"`
+	Assets  []Asset `json:"assets"`
+}
+
+// Asset is a single downloadable file attached to a release.
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Version returns the release version without the leading "v".
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.TagName, "v")
+}
+
+// asset returns the asset with the given name, or nil.
+func (r *Release) asset(name string) *Asset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// Latest fetches the most recent published release from GitHub.
+func Latest(ctx context.Context, hc *http.Client) (*Release, error) {
+	url := fmt.Sprintf("%s/repos/%s/releases/latest", apiBase, Repo)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("query latest release: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("query latest release: %s", resp.Status)
+	}
+
+	var rel Release
+	if err := json.NewDecoder(resp.Body).Decode(&rel); err != nil {
+		return nil, fmt.Errorf("decode release: %w", err)
+	}
+	return &rel, nil
+}
+
+// ArchiveName returns the GoReleaser archive name for a version and
+// platform, mirroring name_template in .goreleaser.yaml.
+func ArchiveName(version, goos, goarch string) string {
+	ext := "tar.gz"
+	if goos == "windows" {
+		ext = "zip"
+	}
+	return fmt.Sprintf("flo_%s_%s_%s.%s", strings.TrimPrefix(version, "v"), goos, goarch, ext)
+}
+
+// Newer reports whether version a is strictly newer than b.  Both are
+// dotted numeric versions with an optional "v" prefix; pre-release
+// suffixes ("-rc1") are ignored.  Unparseable versions (e.g. "dev")
+// are never considered newer.
+func Newer(a, b string) bool {
+	pa, okA := parseVersion(a)
+	pb, okB := parseVersion(b)
+	if !okA || !okB {
+		return false
+	}
+	for i := 0; i < 3; i++ {
+		if pa[i] != pb[i] {
+			return pa[i] > pb[i]
+		}
+	}
+	return false
+}
+
+// parseVersion splits "v1.2.3-rc1" into [1 2 3].
+func parseVersion(v string) ([3]int, bool) {
+	var out [3]int
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return out, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return out, false
+		}
+		out[i] = n
+	}
+	return out, true
+}
+
+// Apply downloads the release archive for the running platform, verifies
+// it against checksums.txt and replaces the executable at exePath.
+func Apply(ctx context.Context, hc *http.Client, rel *Release, exePath string) error {
+	name := ArchiveName(rel.Version(), runtime.GOOS, runtime.GOARCH)
+	archive := rel.asset(name)
+	if archive == nil {
+		return fmt.Errorf("release %s has no archive for %s/%s", rel.TagName, runtime.GOOS, runtime.GOARCH)
+	}
+	sums := rel.asset("checksums.txt")
+	if sums == nil {
+		return fmt.Errorf("release %s has no checksums.txt; refusing to install unverified binary", rel.TagName)
+	}
+
+	sumData, err := download(ctx, hc, sums.URL)
+	if err != nil {
+		return err
+	}
+	want, err := lookupChecksum(sumData, name)
+	if err != nil {
+		return err
+	}
+
+	data, err := download(ctx, hc, archive.URL)
+	if err != nil {
+		return err
+	}
+	got := sha256.Sum256(data)
+	if hex.EncodeToString(got[:]) != want {
+		return fmt.Errorf("checksum mismatch for %s: got %x, want %s", name, got, want)
+	}
+
+	binName := "flo"
+	if runtime.GOOS == "windows" {
+		binName = "flo.exe"
+	}
+	var bin []byte
+	if strings.HasSuffix(name, ".zip") {
+		bin, err = extractZip(data, binName)
+	} else {
+		bin, err = extractTarGz(data, binName)
+	}
+	if err != nil {
+		return err
+	}
+
+	return replaceExecutable(exePath, bin)
+}
+
+// download fetches url fully into memory.
+func download(ctx context.Context, hc *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// lookupChecksum finds the hex SHA-256 for name in a checksums.txt body.
+func lookupChecksum(sums []byte, name string) (string, error) {
+	sc := bufio.NewScanner(bytes.NewReader(sums))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 2 && fields[1] == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("no checksum listed for %s", name)
+}
+
+// extractTarGz returns the contents of the file called name inside a
+// gzipped tarball.
+func extractTarGz(data []byte, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read archive: %w", err)
+		}
+		if filepath.Base(hdr.Name) == name && hdr.Typeflag == tar.TypeReg {
+			return io.ReadAll(tr)
+		}
+	}
+	return nil, fmt.Errorf("%s not found in archive", name)
+}
+
+// extractZip returns the contents of the file called name inside a zip.
+func extractZip(data []byte, name string) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+	for _, f := range zr.File {
+		if filepath.Base(f.Name) != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+	return nil, fmt.Errorf("%s not found in archive", name)
+}
+
+// replaceExecutable writes bin next to exePath and swaps it in.
+//
+// On Unix a rename over the running binary is atomic and safe.  Windows
+// refuses to overwrite a running executable but does allow renaming it,
+// so the old binary is moved aside to "<exe>.old" first (and cleaned up
+// on the next update).
+func replaceExecutable(exePath string, bin []byte) error {
+	dir := filepath.Dir(exePath)
+	tmp, err := os.CreateTemp(dir, ".flo-update-*")
+	if err != nil {
+		return fmt.Errorf("cannot write to %s (try running with elevated permissions): %w", dir, err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // no-op once renamed
+
+	if _, err := tmp.Write(bin); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0o755); err != nil {
+		return err
+	}
+
+	if runtime.GOOS == "windows" {
+		old := exePath + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(exePath, old); err != nil {
+			return fmt.Errorf("move old binary aside: %w", err)
+		}
+		if err := os.Rename(tmpPath, exePath); err != nil {
+			_ = os.Rename(old, exePath) // roll back
+			return fmt.Errorf("install new binary: %w", err)
+		}
+		return nil
+	}
+
+	if err := os.Rename(tmpPath, exePath); err != nil {
+		return fmt.Errorf("install new binary: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+// Package usage keeps a local log of what the user searched for and
+// read, for `flo stats`.  Events are appended as JSON lines to
+// <data dir>/usage.jsonl and never leave the machine.
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kind is the kind of an Event.
+type Kind string
+
+const (
+	// KindSearch is a search, served from the cache or not.
+	KindSearch Kind = "search"
+	// KindQuestion is a question opened.
+	KindQuestion Kind = "question"
+	// KindAnswer is an answer read.
+	KindAnswer Kind = "answer"
+)
+
+// Event is one logged action.
+type Event struct {
+	Time       time.Time `json:"time"`
+	Kind       Kind      `json:"kind"`
+	Query      string    `json:"query,omitempty"`
+	Cached     bool      `json:"cached,omitempty"`
+	QuestionID int       `json:"question_id,omitempty"`
+	AnswerID   int       `json:"answer_id,omitempty"`
+	Title      string    `json:"title,omitempty"`
+	Tags       []string  `json:"tags,omitempty"`
+}
+
+// DefaultPath returns usage.jsonl inside dataDir.
+func DefaultPath(dataDir string) string {
+	return filepath.Join(dataDir, "usage.jsonl")
+}
+
+// Append adds ev to the log at path, stamping it with the current time
+// when it has none.
+func Append(path string, ev Event) error {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	line, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Load reads the log at path, oldest first; a missing file is an empty
+// log.  Lines that do not parse — a write cut short — are skipped.
+func Load(path string) ([]Event, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read usage log: %w", err)
+	}
+	defer f.Close()
+	var events []Event
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		var ev Event
+		if json.Unmarshal(sc.Bytes(), &ev) == nil && ev.Kind != "" {
+			events = append(events, ev)
+		}
+	}
+	return events, sc.Err()
+}
+
+// Count is a label with how often it occurred.
+type Count struct {
+	Label string
+	N     int
+}
+
+// Week is the number of searches in the week starting on Start, a
+// Monday.
+type Week struct {
+	Start    time.Time
+	Searches int
+}
+
+// Summary is what `flo stats` reports.
+type Summary struct {
+	// Since is the time of the oldest event.
+	Since     time.Time
+	Searches  int
+	Questions int
+	Answers   int
+	// CacheHits is how many searches the response cache served.
+	CacheHits int
+	// TopTags are the tags of the questions opened, most frequent first.
+	TopTags []Count
+	// Weeks are the searches per week, oldest first, ending with the
+	// current week.
+	Weeks []Week
+	// Repeated are the queries searched, and questions opened, on more
+	// than one day: what the user keeps coming back to.
+	Repeated []Count
+}
+
+// CacheHitRate is the share of searches served from the cache.
+func (s *Summary) CacheHitRate() float64 {
+	if s.Searches == 0 {
+		return 0
+	}
+	return float64(s.CacheHits) / float64(s.Searches)
+}
+
+// AnswersPerSearch is the average number of answers read per search.
+func (s *Summary) AnswersPerSearch() float64 {
+	if s.Searches == 0 {
+		return 0
+	}
+	return float64(s.Answers) / float64(s.Searches)
+}
+
+// Summarize aggregates events into a summary with the last weeks weeks
+// before now and at most top tags and repeated items.
+func Summarize(events []Event, weeks, top int, now time.Time) Summary {
+	var s Summary
+	thisWeek := weekStart(now)
+	s.Weeks = make([]Week, weeks)
+	for i := range s.Weeks {
+		s.Weeks[i].Start = thisWeek.AddDate(0, 0, -7*(weeks-1-i))
+	}
+
+	tags := make(map[string]int)
+	days := make(map[string]map[string]bool) // item → days seen
+	seen := func(item string, t time.Time) {
+		if days[item] == nil {
+			days[item] = make(map[string]bool)
+		}
+		days[item][t.Format(time.DateOnly)] = true
+	}
+	for _, ev := range events {
+		if s.Since.IsZero() || ev.Time.Before(s.Since) {
+			s.Since = ev.Time
+		}
+		switch ev.Kind {
+		case KindSearch:
+			s.Searches++
+			if ev.Cached {
+				s.CacheHits++
+			}
+			if i := weeks - 1 - int(math.Round(thisWeek.Sub(weekStart(ev.Time)).Hours()/(24*7))); i >= 0 && i < weeks {
+				s.Weeks[i].Searches++
+			}
+			if q := strings.ToLower(strings.TrimSpace(ev.Query)); q != "" {
+				seen(fmt.Sprintf("%q", q), ev.Time)
+			}
+		case KindQuestion:
+			s.Questions++
+			for _, t := range ev.Tags {
+				tags[strings.ToLower(t)]++
+			}
+			if ev.Title != "" {
+				seen(ev.Title, ev.Time)
+			}
+		case KindAnswer:
+			s.Answers++
+		}
+	}
+
+	s.TopTags = topCounts(tags, top)
+	repeated := make(map[string]int)
+	for item, d := range days {
+		if len(d) > 1 {
+			repeated[item] = len(d)
+		}
+	}
+	s.Repeated = topCounts(repeated, top)
+	return s
+}
+
+// weekStart returns midnight on the Monday of t's week, in t's location.
+func weekStart(t time.Time) time.Time {
+	y, m, d := t.Date()
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(y, m, d-offset, 0, 0, 0, 0, t.Location())
+}
+
+// topCounts returns the n largest counts, ties broken by label.
+func topCounts(m map[string]int, n int) []Count {
+	out := make([]Count, 0, len(m))
+	for k, v := range m {
+		out = append(out, Count{k, v})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].N != out[j].N {
+			return out[i].N > out[j].N
+		}
+		return out[i].Label < out[j].Label
+	})
+	return out[:min(n, len(out))]
+}
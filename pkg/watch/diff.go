@@ -0,0 +1,126 @@
+package watch
+
+import "strings"
+
+// maxDiffLines bounds the lines Diff compares: past it the LCS table
+// grows too big for a poll, and the whole text is shown replaced.
+const maxDiffLines = 2000
+
+// Diff returns a line diff of a to b in unified style: removed lines
+// start "-", added ones "+", and up to context unchanged lines around
+// each change start " ".  Runs of unchanged lines further from a change
+// are cut to "@@ … @@".  The result ends in a newline unless empty.
+func Diff(a, b string, context int) string {
+	x, y := lines(a), lines(b)
+	ops := diffOps(x, y)
+
+	// keep marks the ops printed: every change and its context.
+	keep := make([]bool, len(ops))
+	for i, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+		for j := max(i-context, 0); j <= min(i+context, len(ops)-1); j++ {
+			keep[j] = true
+		}
+	}
+	var out strings.Builder
+	skipped := false
+	for i, op := range ops {
+		if !keep[i] {
+			skipped = true
+			continue
+		}
+		if skipped {
+			out.WriteString("@@ … @@\n")
+			skipped = false
+		}
+		out.WriteByte(op.kind)
+		out.WriteString(op.line)
+		out.WriteByte('\n')
+	}
+	return out.String()
+}
+
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	line string
+}
+
+// diffOps lines up x and y on their longest common subsequence.
+func diffOps(x, y []string) []diffOp {
+	// Trim what the two share at either end; edits are usually small.
+	pre := 0
+	for pre < len(x) && pre < len(y) && x[pre] == y[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(x)-pre && suf < len(y)-pre && x[len(x)-1-suf] == y[len(y)-1-suf] {
+		suf++
+	}
+	var ops []diffOp
+	for _, l := range x[:pre] {
+		ops = append(ops, diffOp{' ', l})
+	}
+	mx, my := x[pre:len(x)-suf], y[pre:len(y)-suf]
+	if len(mx) > maxDiffLines || len(my) > maxDiffLines {
+		for _, l := range mx {
+			ops = append(ops, diffOp{'-', l})
+		}
+		for _, l := range my {
+			ops = append(ops, diffOp{'+', l})
+		}
+	} else {
+		ops = append(ops, lcsOps(mx, my)...)
+	}
+	for _, l := range x[len(x)-suf:] {
+		ops = append(ops, diffOp{' ', l})
+	}
+	return ops
+}
+
+// lcsOps diffs x and y by dynamic programming, removals before
+// additions where both fit.
+func lcsOps(x, y []string) []diffOp {
+	n, m := len(x), len(y)
+	// lcs[i][j] is the length of the LCS of x[i:] and y[j:].
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var ops []diffOp
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && x[i] == y[j]:
+			ops = append(ops, diffOp{' ', x[i]})
+			i++
+			j++
+		case j == m || (i < n && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{'-', x[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', y[j]})
+			j++
+		}
+	}
+	return ops
+}
+
+// lines splits s into lines, without the line breaks.
+func lines(s string) []string {
+	s = strings.TrimRight(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
@@ -0,0 +1,219 @@
+// Package watch follows one question between polls and says exactly
+// what changed: answers posted, edited or removed, the accepted answer
+// changing, the question edited and comments added, with edits shown
+// as line diffs.
+//
+// Each poll is a Snapshot, kept in <data dir>/watch/<id>.json so that
+// the next watch of the question starts with what changed meanwhile.
+package watch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ratnesh-maurya/flo/pkg/atomicfile"
+	"github.com/ratnesh-maurya/flo/pkg/mcp"
+)
+
+// Snapshot is a question as one poll found it.
+type Snapshot struct {
+	Taken    time.Time        `json:"taken"`
+	Question mcp.QuestionData `json:"question"`
+	// Comments are the comments on the question and its answers.
+	Comments []mcp.CommentData `json:"comments,omitempty"`
+	// NoComments is set when the backend could not list comments, so
+	// none are compared.
+	NoComments bool `json:"no_comments,omitempty"`
+}
+
+// DefaultPath returns the snapshot file of question id inside dataDir.
+func DefaultPath(dataDir string, id int) string {
+	return filepath.Join(dataDir, "watch", strconv.Itoa(id)+".json")
+}
+
+// Load reads the snapshot at path; a missing file is nil, without an
+// error.
+func Load(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read watch snapshot: %w", err)
+	}
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse watch snapshot %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// Save writes s to path atomically.
+func (s *Snapshot) Save(path string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(path, data, 0o644)
+}
+
+// Kind is the kind of a Change.
+type Kind int
+
+// The kinds of change Compare reports.
+const (
+	QuestionEdited Kind = iota
+	NewAnswer
+	AnswerEdited
+	AnswerRemoved
+	AcceptedChanged
+	NewComment
+)
+
+// Change is one difference between two snapshots.
+type Change struct {
+	Kind Kind
+	// Answer is the answer concerned: the new or edited one, the one
+	// removed, the one now accepted (nil when none is), or the one
+	// commented on.
+	Answer *mcp.AnswerData
+	// Comment is the new comment.
+	Comment *mcp.CommentData
+	// Diff is the line diff of an edit (see Diff).
+	Diff string
+}
+
+// Compare lists what changed from old to fresh: the question first,
+// then answers in fresh's order, then comments.
+func Compare(old, fresh *Snapshot) []Change {
+	var out []Change
+	oq, fq := &old.Question, &fresh.Question
+	if oq.Title != fq.Title || oq.BodyMarkdown != fq.BodyMarkdown {
+		out = append(out, Change{Kind: QuestionEdited, Diff: Diff(questionText(oq), questionText(fq), 2)})
+	}
+
+	before := make(map[int]*mcp.AnswerData, len(oq.Answers))
+	for i := range oq.Answers {
+		before[oq.Answers[i].AnswerID] = &oq.Answers[i]
+	}
+	after := make(map[int]bool, len(fq.Answers))
+	for i := range fq.Answers {
+		a := &fq.Answers[i]
+		after[a.AnswerID] = true
+		prev, ok := before[a.AnswerID]
+		switch {
+		case !ok:
+			out = append(out, Change{Kind: NewAnswer, Answer: a})
+		case prev.BodyMarkdown != "" && a.BodyMarkdown != "" && prev.BodyMarkdown != a.BodyMarkdown:
+			out = append(out, Change{Kind: AnswerEdited, Answer: a, Diff: Diff(decode(prev.BodyMarkdown), decode(a.BodyMarkdown), 2)})
+		}
+	}
+	// Answers are only known gone when fresh lists them all.
+	if len(fq.Answers) >= fq.AnswerCount {
+		for i := range oq.Answers {
+			if a := &oq.Answers[i]; !after[a.AnswerID] {
+				out = append(out, Change{Kind: AnswerRemoved, Answer: a})
+			}
+		}
+	}
+	if oq.AcceptedAnswerID != fq.AcceptedAnswerID {
+		out = append(out, Change{Kind: AcceptedChanged, Answer: answer(fq, fq.AcceptedAnswerID)})
+	}
+
+	if !old.NoComments && !fresh.NoComments {
+		seen := make(map[int]bool, len(old.Comments))
+		for _, c := range old.Comments {
+			seen[c.CommentID] = true
+		}
+		for i := range fresh.Comments {
+			if c := &fresh.Comments[i]; !seen[c.CommentID] {
+				out = append(out, Change{Kind: NewComment, Comment: c, Answer: answer(fq, c.PostID)})
+			}
+		}
+	}
+	return out
+}
+
+// answer returns q's answer id, or nil.
+func answer(q *mcp.QuestionData, id int) *mcp.AnswerData {
+	for i := range q.Answers {
+		if q.Answers[i].AnswerID == id && id != 0 {
+			return &q.Answers[i]
+		}
+	}
+	return nil
+}
+
+// questionText is what a question edit is diffed on: its title and
+// body.
+func questionText(q *mcp.QuestionData) string {
+	return "# " + decode(q.Title) + "\n\n" + decode(q.BodyMarkdown)
+}
+
+func decode(s string) string {
+	return html.UnescapeString(s)
+}
+
+// Markdown describes changes to the question q as Markdown, edits as
+// fenced diff blocks.
+func Markdown(q *mcp.QuestionData, changes []Change) string {
+	var b strings.Builder
+	for _, c := range changes {
+		switch c.Kind {
+		case QuestionEdited:
+			b.WriteString("### ✏️ The question was edited\n\n")
+			writeDiff(&b, c.Diff)
+		case NewAnswer:
+			fmt.Fprintf(&b, "### 🆕 New answer by %s (score %d)\n\n", author(c.Answer.Owner), c.Answer.Score)
+			b.WriteString(decode(c.Answer.BodyMarkdown) + "\n\n")
+		case AnswerEdited:
+			what := "An answer"
+			if c.Answer.AnswerID == q.AcceptedAnswerID {
+				what = "The accepted answer"
+			}
+			fmt.Fprintf(&b, "### ✏️ %s by %s was edited\n\n", what, author(c.Answer.Owner))
+			writeDiff(&b, c.Diff)
+		case AnswerRemoved:
+			fmt.Fprintf(&b, "### 🗑 The answer by %s was removed\n\n", author(c.Answer.Owner))
+		case AcceptedChanged:
+			if c.Answer == nil {
+				b.WriteString("### ✅ No answer is accepted any more\n\n")
+			} else {
+				fmt.Fprintf(&b, "### ✅ The answer by %s is now accepted\n\n", author(c.Answer.Owner))
+			}
+		case NewComment:
+			on := "the question"
+			if c.Answer != nil {
+				on = "the answer by " + author(c.Answer.Owner)
+			}
+			fmt.Fprintf(&b, "### 💬 %s commented on %s\n\n", author(c.Comment.Owner), on)
+			b.WriteString("> " + strings.ReplaceAll(decode(c.Comment.BodyMarkdown), "\n", "\n> ") + "\n\n")
+		}
+	}
+	return b.String()
+}
+
+// writeDiff writes diff as a diff code block, its fence longer than any
+// run of backticks inside.
+func writeDiff(b *strings.Builder, diff string) {
+	fence := "```"
+	for strings.Contains(diff, fence) {
+		fence += "`"
+	}
+	b.WriteString(fence + "diff\n" + diff + fence + "\n\n")
+}
+
+func author(o mcp.OwnerData) string {
+	if o.DisplayName == "" {
+		return "an anonymous user"
+	}
+	return "**" + decode(o.DisplayName) + "**"
+}